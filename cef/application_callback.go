@@ -32,11 +32,17 @@ func appOnContextCreated(browser *ICefBrowser, frame *ICefFrame, context *ICefV8
 // appMainRunCallback 应用运行 - 默认实现
 func appMainRunCallback() {
 	ipcBrowser.registerEvent() // browser ipc
+	i18nBrowserInit()          // i18n switch sync
+	runtimeBrowserInit()       // energy.runtime
+	contextMenuBrowserInit()   // energy.contextmenu
+	devReloadBrowserInit()     // energy dev reload
 }
 
 // appWebKitInitialized - webkit - 默认实现
 func appWebKitInitialized() {
-	dragExtensionHandler() // drag extension handler
+	dragExtensionHandler()    // drag extension handler
+	i18nExtensionHandler()    // i18n extension handler
+	runtimeExtensionHandler() // energy.runtime extension handler, 在 i18n 之后
 }
 
 // renderProcessMessageReceived 渲染进程消息 - 默认实现
@@ -45,12 +51,15 @@ func renderProcessMessageReceived(browser *ICefBrowser, frame *ICefFrame, source
 		result = ipcRender.ipcJSExecuteGoEventMessageReply(browser, frame, sourceProcess, message)
 	} else if message.Name() == internalIPCGoExecuteJSEvent {
 		result = ipcRender.ipcGoExecuteJSEvent(browser, frame, sourceProcess, message)
+	} else if message.Name() == internalI18nSwitch {
+		result = i18nSwitchMessage(frame, message)
 	}
 	return
 }
 
 // regCustomSchemes 注册自定义协议 - 默认实现
 func regCustomSchemes(registrar *TCefSchemeRegistrarRef) {
+	defer regSchemeHTTPHandlerSchemes(registrar)
 	if localLoadRes.enable() {
 		// 以下几种默认的协议不去注册
 		switch strings.ToUpper(localLoadRes.Scheme) {
@@ -310,6 +310,12 @@ func (m *TCEFApplication) SetLocale(value LANGUAGE) {
 	imports.Proc(def.CEFAppConfig_SetLocale).Call(value.ToPtr())
 }
 
+// SetLocaleAuto
+//	根据操作系统首选语言设置 CEF 和 i18n 语言
+func (m *TCEFApplication) SetLocaleAuto() {
+	m.SetLocale(i18n.Detect())
+}
+
 func (m *TCEFApplication) LogFile() string {
 	r1, _, _ := imports.Proc(def.CEFAppConfig_LogFile).Call()
 	return api.GoStr(r1)
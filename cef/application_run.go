@@ -16,6 +16,7 @@ import (
 	"github.com/energye/energy/v2/cef/internal/ipc"
 	"github.com/energye/energy/v2/cef/process"
 	"github.com/energye/energy/v2/common"
+	"github.com/energye/energy/v2/pkgs/crash"
 	"github.com/energye/golcl/lcl"
 	"github.com/energye/golcl/lcl/api"
 )
@@ -98,6 +99,8 @@ func Run(app *TCEFApplication) {
 				}
 				app.Destroy()
 				app.Free()
+				// 正常退出, 结束崩溃报告会话
+				crash.Close()
 			})
 			// 主进程启动成功之后回调
 			if browserProcessStartAfterCallback != nil {
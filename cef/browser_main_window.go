@@ -158,7 +158,10 @@ func (m *browserWindow) createNextLCLPopupWindow() {
 			//	owner = enableMainWindow // 未开启多窗口
 			//}
 			// 创建 LCL 窗口对象
-			m.popupWindow = NewLCLWindow(m.Config.WindowProperty, nil)
+			// 弹出窗口不使用主窗口的状态保存名称
+			wp := m.Config.WindowProperty
+			wp.WindowStateName = ""
+			m.popupWindow = NewLCLWindow(wp, nil)
 			// 创建 Chromium 对象
 			m.popupWindow.AsLCLBrowserWindow().BrowserWindow().ChromiumCreate(NewChromiumConfig(), "")
 		}
@@ -330,7 +333,12 @@ func (m *BrowserEvent) SetOnBeforeDownload(event chromiumEventOnBeforeDownloadEx
 // SetOnDownloadUpdated
 func (m *BrowserEvent) SetOnDownloadUpdated(event chromiumEventOnDownloadUpdated) {
 	if Args.IsMain() {
-		m.chromium.SetOnDownloadUpdated(event)
+		m.chromium.SetOnDownloadUpdated(func(sender lcl.IObject, browser *ICefBrowser, downloadItem *ICefDownloadItem, callback *ICefDownloadItemCallback) {
+			if downloadManager != nil {
+				downloadManager.downloadUpdated(browser, downloadItem, callback)
+			}
+			event(sender, browser, downloadItem, callback)
+		})
 	}
 }
 
@@ -34,8 +34,9 @@ type auxTools struct {
 
 // 窗口当前状态属性
 type windowCurrentProperty struct {
-	ws         types.TWindowState // 仅记录最大化和全屏状态
-	x, y, w, h int32              // 仅在最大化和全屏时记录窗口位置和宽高
+	ws            types.TWindowState // 仅记录最大化和全屏状态
+	x, y, w, h    int32              // 仅在最大化和全屏时记录窗口位置和宽高
+	stateRestored bool               // 已恢复保存的窗口状态, 之后开始记录窗口状态
 }
 
 // WindowProperty
@@ -75,6 +76,7 @@ type WindowProperty struct {
 	MinHeight                 types.TConstraintSize // 窗口 最小高, EnableResize = true 与 MinWidth > 0 生效
 	MaxWidth                  types.TConstraintSize // 窗口 最大宽, EnableResize = true 与 MaxHeight > 0 生效
 	MaxHeight                 types.TConstraintSize // 窗口 最大高, EnableResize = true 与 MaxWidth > 0 生效
+	WindowStateName           string                // 窗口 状态保存名称, 不为空时保存窗口坐标、宽高和最大化、全屏状态, 创建窗口时恢复
 	current                   windowCurrentProperty // 窗口 当前属性
 }
 
@@ -22,6 +22,7 @@ import (
 	"github.com/energye/energy/v2/consts"
 	"github.com/energye/energy/v2/consts/messages"
 	"github.com/energye/energy/v2/logger"
+	"github.com/energye/energy/v2/pkgs/crash"
 	et "github.com/energye/energy/v2/types"
 	"github.com/energye/golcl/energy/tools"
 	"github.com/energye/golcl/lcl"
@@ -156,6 +157,8 @@ func (m *LCLBrowserWindow) AsTargetWindow() target.IWindow {
 
 // SetProperty 设置属性, 根据当前窗口的自定义 WindowProperty
 func (m *LCLBrowserWindow) SetProperty() {
+	// 恢复保存的窗口状态
+	restoreWindowState(m)
 	wp := m.WindowProperty()
 	m.SetTitle(wp.Title)
 	if wp.IconFS != "" {
@@ -850,6 +853,7 @@ func (m *LCLBrowserWindow) resize(sender lcl.IObject) {
 			return
 		}
 		m.setCurrentProperty()
+		saveWindowState(m, false)
 		if m.windowResize != nil {
 			m.windowResize(sender)
 		}
@@ -877,6 +881,9 @@ func (m *LCLBrowserWindow) setCurrentProperty() {
 
 // activate 内部调用
 func (m *LCLBrowserWindow) activate(sender lcl.IObject) {
+	if !m.isClosing {
+		WindowManager.focused(m)
+	}
 	var ret bool
 	if m.onActivate != nil {
 		ret = m.onActivate(sender)
@@ -962,10 +969,14 @@ func (m *LCLBrowserWindow) CloseBrowserWindow() {
 // EnableMainWindow = false
 //
 //	如果禁用主窗口, 存在多窗口时只在最后一个窗口关闭时才退出整个应用进程
+//	关闭策略 WindowManager.SetClosePolicy
 func (m *LCLBrowserWindow) TryCloseWindowAndTerminate() {
-	if !BrowserWindow.Config.EnableMainWindow {
-		count := len(BrowserWindow.GetWindowInfos())
-		if count < 1 {
+	if WindowManager.closeHiddenMainWindow() {
+		return
+	}
+	// 启用主窗口时, 主窗口关闭后LCL应用自动结束
+	if !BrowserWindow.Config.EnableMainWindow || m.WindowType() != consts.WT_MAIN_BROWSER {
+		if WindowManager.terminateOnClose(m) {
 			if len(m.tray) > 0 {
 				for _, tray := range m.tray {
 					tray.close()
@@ -1004,8 +1015,16 @@ func (m *LCLBrowserWindow) closeQuery(sender lcl.IObject, close *bool) {
 	if m.onCloseQuery != nil {
 		ret = m.onCloseQuery(sender, close)
 	}
+	if !m.isClosing {
+		saveWindowState(m, true)
+	}
 	if !ret {
 		logger.Debug("window.onCloseQuery windowType:", m.WindowType())
+		// 关闭策略, 主窗口只隐藏
+		if WindowManager.hideMainWindow(m) {
+			*close = false
+			return
+		}
 		if IsDarwin() {
 			//main window close
 			if m.WindowType() == consts.WT_MAIN_BROWSER {
@@ -1128,6 +1147,9 @@ func (m *LCLBrowserWindow) onFormMessages() {
 	})
 	m.setOnWMMove(func(message *et.TMove) {
 		m.setCurrentProperty()
+		if !m.isClosing {
+			saveWindowState(m, false)
+		}
 		if m.Chromium() != nil {
 			m.Chromium().NotifyMoveOrResizeStarted()
 		}
@@ -1186,7 +1208,10 @@ func (m *LCLBrowserWindow) SetOnWMWindowPosChanged(fn wmWindowPosChanged) {
 }
 
 func init() {
-	lcl.RegisterExtEventCallback(func(fn interface{}, getVal func(idx int) uintptr) bool {
+	lcl.RegisterExtEventCallback(func(fn interface{}, getVal func(idx int) uintptr) (handled bool) {
+		// panic 恢复后返回 true, golcl 不再调用一次事件, 不是这里的事件时 default 返回 false
+		handled = true
+		defer crash.Recover("event")
 		getPtr := func(i int) unsafe.Pointer {
 			return unsafe.Pointer(getVal(i))
 		}
@@ -156,11 +156,17 @@ func appContextInitialized() {
 		// 主窗口关闭时触发该函数
 		// EnableClose=true时关闭窗口, false时不关闭窗口
 		vfMainWindow.WindowComponent().SetOnCanClose(func(window *ICefWindow, canClose *bool) {
+			saveWindowState(vfMainWindow, true)
 			var flag bool
 			if vfMainWindow.doOnCloseQuery != nil {
 				flag = vfMainWindow.doOnCloseQuery(window, vfMainWindow, canClose)
 			}
 			if !flag {
+				// 关闭策略, 主窗口只隐藏
+				if WindowManager.hideMainWindow(vfMainWindow) {
+					*canClose = false
+					return
+				}
 				*canClose = m.Config.WindowProperty.EnableClose
 				if m.Config.WindowProperty.EnableClose {
 					//*aResult = vfMainWindow.Chromium().TryCloseBrowser()
@@ -188,6 +194,7 @@ func appContextInitialized() {
 //
 //	如果禁用主窗口, 存在多窗口时只在最后一个窗口关闭时才退出整个应用进程
 //	如果启用主窗口, 关闭主窗口时退出整个应用进程
+//	关闭策略 WindowManager.SetClosePolicy
 func (m *ViewsFrameworkBrowserWindow) TryCloseWindowAndTerminate() {
 	var closeWindowAndTerminate = func() {
 		if m.tray != nil {
@@ -199,15 +206,13 @@ func (m *ViewsFrameworkBrowserWindow) TryCloseWindowAndTerminate() {
 			os.Exit(0)
 		}
 	}
+	if WindowManager.closeHiddenMainWindow() {
+		return
+	}
 	// 启用主窗口，当前关闭窗口为主浏览器窗口直接退出进程
-	if BrowserWindow.Config.EnableMainWindow && m.WindowType() == consts.WT_MAIN_BROWSER {
+	// 禁用主窗口，无窗口列表时退出进程
+	if WindowManager.terminateOnClose(m) {
 		closeWindowAndTerminate()
-	} else {
-		// 禁用主窗口，无窗口列表时退出进程
-		count := len(BrowserWindow.GetWindowInfos())
-		if count < 1 {
-			closeWindowAndTerminate()
-		}
 	}
 }
 
@@ -263,6 +268,7 @@ func (m *ViewsFrameworkBrowserWindow) doBeforePopup(sender lcl.IObject, browser
 
 	wp := *m.windowProperty //clone
 	wp.Url = beforePopupInfo.TargetUrl
+	wp.WindowStateName = "" // 弹出窗口不使用状态保存名称
 	wp.WindowType = consts.WT_POPUP_SUB_BROWSER
 	var vFrameBrowserWindow = NewViewsFrameworkBrowserWindow(NewChromiumConfig(), wp, BrowserWindow.MainWindow().AsViewsFrameworkBrowserWindow().Component())
 	var result = false
@@ -284,7 +290,19 @@ func (m *ViewsFrameworkBrowserWindow) doBeforePopup(sender lcl.IObject, browser
 //	VF窗口初始化时通过回调事件设置一些默认行为，而不像LCL窗口直接通过属性设置
 //	在初始化之后部分属性可直接设置
 func (m *ViewsFrameworkBrowserWindow) ResetWindowPropertyForEvent() {
+	// 恢复保存的窗口状态
+	restoreWindowState(m)
 	wp := m.WindowProperty()
+	if wp.WindowStateName != "" {
+		m.WindowComponent().SetOnLayoutChanged(func(view *ICefView, newBounds *TCefRect) {
+			saveWindowState(m, false)
+		})
+	}
+	m.WindowComponent().SetOnWindowActivationChanged(func(window *ICefWindow, active bool) {
+		if active {
+			WindowManager.focused(m)
+		}
+	})
 	m.WindowComponent().SetOnGetInitialShowState(func(window *ICefWindow, aResult *consts.TCefShowState) {
 		*aResult = consts.TCefShowState(wp.WindowInitState + 1) // CEF 要 + 1
 	})
@@ -313,6 +331,7 @@ func (m *ViewsFrameworkBrowserWindow) ResetWindowPropertyForEvent() {
 		*aResult = wp.EnableMaximize
 	})
 	m.WindowComponent().SetOnCanClose(func(window *ICefWindow, canClose *bool) {
+		saveWindowState(m, true)
 		var flag bool
 		if m.doOnCloseQuery != nil {
 			flag = m.doOnCloseQuery(window, m, canClose)
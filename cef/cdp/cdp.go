@@ -0,0 +1,407 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// Package cdp Chrome DevTools Protocol 客户端
+//
+// 在 ExecuteDevToolsMethod/SendDevToolsMessage 之上
+// 自动关联消息ID, 返回类型化结果和错误, 协议事件通过 Go 通道订阅
+//
+// 常用域: Page, Network, Runtime, Emulation, Input
+// 常用域的类型和方法是手写的, 不是生成代码, 只包含常用的方法、事件和字段,
+// 对应 Chromium 109 (CEF 109) 的 tip-of-tree 协议, 见 README.md
+//
+// 协议文档: https://chromedevtools.github.io/devtools-protocol/
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrClosed 客户端已关闭
+var ErrClosed = errors.New("cdp: client closed")
+
+// Transport 消息传输
+//
+//	SendMessage 发送一条 JSON 协议消息
+//	接收到的消息通过 Client.Dispatch 分发
+type Transport interface {
+	SendMessage(message []byte) error
+}
+
+// Error 协议方法返回的错误
+type Error struct {
+	Code    int64  `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (m *Error) Error() string {
+	if m.Data != "" {
+		return fmt.Sprintf("cdp: %s (%d): %s", m.Message, m.Code, m.Data)
+	}
+	return fmt.Sprintf("cdp: %s (%d)", m.Message, m.Code)
+}
+
+// Event 协议事件
+type Event struct {
+	Method string          // 事件名, 例: Network.requestWillBeSent
+	Params json.RawMessage // 事件参数
+}
+
+// request 发送的方法调用消息
+type request struct {
+	ID     int32       `json:"id"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+// message 接收的消息, 方法结果或事件
+type message struct {
+	ID     int32           `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// 方法结果
+type response struct {
+	result json.RawMessage
+	err    error
+}
+
+// Client DevTools 协议客户端
+type Client struct {
+	transport   Transport
+	lock        sync.Mutex
+	id          int32
+	pending     map[int32]chan *response
+	subscribers map[string][]*Subscription
+	closed      bool
+}
+
+// 消息ID起始值, 和 ExecuteDevToolsMethod 的自增ID区分
+const idBase int32 = 1 << 20
+
+// NewClient 创建客户端
+func NewClient(transport Transport) *Client {
+	return &Client{
+		transport:   transport,
+		id:          idBase,
+		pending:     make(map[int32]chan *response),
+		subscribers: make(map[string][]*Subscription),
+	}
+}
+
+// send 发送方法调用, 返回结果通道
+func (m *Client) send(method string, params interface{}) (int32, chan *response, error) {
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return 0, nil, ErrClosed
+	}
+	m.id++
+	id := m.id
+	ch := make(chan *response, 1)
+	m.pending[id] = ch
+	m.lock.Unlock()
+	if v := reflect.ValueOf(params); v.Kind() == reflect.Ptr && v.IsNil() {
+		params = nil // 参数为 nil 指针时不发送 params
+	}
+	data, err := json.Marshal(&request{ID: id, Method: method, Params: params})
+	if err == nil {
+		err = m.transport.SendMessage(data)
+	}
+	if err != nil {
+		m.remove(id)
+		return 0, nil, err
+	}
+	return id, ch, nil
+}
+
+func (m *Client) remove(id int32) {
+	m.lock.Lock()
+	delete(m.pending, id)
+	m.lock.Unlock()
+}
+
+// Call
+//
+//	调用协议方法并等待结果, result 为 nil 时忽略结果
+//	阻塞直到返回结果或 ctx 结束, 不能在 CEF UI 线程事件中调用, 否则结果无法返回
+func (m *Client) Call(ctx context.Context, method string, params, result interface{}) error {
+	id, ch, err := m.send(method, params)
+	if err != nil {
+		return err
+	}
+	select {
+	case resp := <-ch:
+		if resp.err != nil {
+			return resp.err
+		}
+		if result != nil && len(resp.result) > 0 {
+			return json.Unmarshal(resp.result, result)
+		}
+		return nil
+	case <-ctx.Done():
+		m.remove(id)
+		return ctx.Err()
+	}
+}
+
+// CallAsync
+//
+//	异步调用协议方法, 结果在新的协程中回调, 可在 CEF UI 线程事件中使用
+//	callback 可以为 nil
+func (m *Client) CallAsync(method string, params interface{}, callback func(result json.RawMessage, err error)) {
+	_, ch, err := m.send(method, params)
+	if callback == nil {
+		return
+	}
+	if err != nil {
+		go callback(nil, err)
+		return
+	}
+	go func() {
+		resp := <-ch
+		callback(resp.result, resp.err)
+	}()
+}
+
+// Dispatch
+//
+//	分发接收到的协议消息
+//	返回 true: 消息是当前客户端的方法结果, false: 事件或其它消息
+func (m *Client) Dispatch(data []byte) bool {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return false
+	}
+	if msg.Method != "" {
+		m.lock.Lock()
+		subs := make([]*Subscription, 0, len(m.subscribers[msg.Method])+len(m.subscribers[""]))
+		subs = append(subs, m.subscribers[msg.Method]...)
+		subs = append(subs, m.subscribers[""]...)
+		m.lock.Unlock()
+		event := Event{Method: msg.Method, Params: msg.Params}
+		for _, sub := range subs {
+			sub.push(event)
+		}
+		return false
+	}
+	m.lock.Lock()
+	ch, ok := m.pending[msg.ID]
+	delete(m.pending, msg.ID)
+	m.lock.Unlock()
+	if !ok {
+		return false
+	}
+	if msg.Error != nil {
+		ch <- &response{err: msg.Error}
+	} else {
+		ch <- &response{result: msg.Result}
+	}
+	return true
+}
+
+// Subscribe
+//
+//	订阅协议事件, method 为空时订阅所有事件
+//	事件在内部排队, 不会阻塞消息分发, 不再使用时调用 Subscription.Close
+func (m *Client) Subscribe(method string) *Subscription {
+	events := make(chan Event)
+	sub := m.subscribe(method, func(event Event, done <-chan struct{}) {
+		select {
+		case events <- event:
+		case <-done:
+		}
+	}, func() {
+		close(events)
+	})
+	sub.events = events
+	return sub
+}
+
+// subscribe 订阅事件, deliver 在订阅协程中按顺序调用, finish 在订阅结束时调用
+func (m *Client) subscribe(method string, deliver func(event Event, done <-chan struct{}), finish func()) *Subscription {
+	sub := &Subscription{
+		client:  m,
+		method:  method,
+		done:    make(chan struct{}),
+		deliver: deliver,
+		finish:  finish,
+	}
+	sub.cond = sync.NewCond(&sub.lock)
+	m.lock.Lock()
+	if m.closed {
+		sub.closed = true
+		close(sub.done)
+	} else {
+		m.subscribers[method] = append(m.subscribers[method], sub)
+	}
+	m.lock.Unlock()
+	go sub.loop()
+	return sub
+}
+
+// unsubscribe 移除订阅
+func (m *Client) unsubscribe(sub *Subscription) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	subs := m.subscribers[sub.method]
+	for i, s := range subs {
+		if s == sub {
+			m.subscribers[sub.method] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(m.subscribers[sub.method]) == 0 {
+		delete(m.subscribers, sub.method)
+	}
+}
+
+// Close
+//
+//	关闭客户端, 等待中的调用返回 ErrClosed, 关闭所有订阅
+func (m *Client) Close() {
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return
+	}
+	m.closed = true
+	pending := m.pending
+	subscribers := m.subscribers
+	m.pending = make(map[int32]chan *response)
+	m.subscribers = make(map[string][]*Subscription)
+	m.lock.Unlock()
+	for _, ch := range pending {
+		ch <- &response{err: ErrClosed}
+	}
+	for _, subs := range subscribers {
+		for _, sub := range subs {
+			sub.close()
+		}
+	}
+}
+
+// Page 页面域
+func (m *Client) Page() *PageDomain {
+	return &PageDomain{client: m}
+}
+
+// Network 网络域
+func (m *Client) Network() *NetworkDomain {
+	return &NetworkDomain{client: m}
+}
+
+// Runtime JS 运行时域
+func (m *Client) Runtime() *RuntimeDomain {
+	return &RuntimeDomain{client: m}
+}
+
+// Emulation 仿真域
+func (m *Client) Emulation() *EmulationDomain {
+	return &EmulationDomain{client: m}
+}
+
+// Input 输入域
+func (m *Client) Input() *InputDomain {
+	return &InputDomain{client: m}
+}
+
+// Subscription 事件订阅
+type Subscription struct {
+	client  *Client
+	method  string
+	events  chan Event
+	lock    sync.Mutex
+	cond    *sync.Cond
+	queue   []Event
+	closed  bool
+	done    chan struct{}
+	deliver func(event Event, done <-chan struct{})
+	finish  func()
+}
+
+// Events 事件通道, 订阅关闭后通道关闭
+//
+//	只有 Client.Subscribe 返回的订阅有效, 类型化订阅返回 nil
+func (m *Subscription) Events() <-chan Event {
+	return m.events
+}
+
+// Close 取消订阅, 未处理的事件被丢弃
+func (m *Subscription) Close() {
+	m.client.unsubscribe(m)
+	m.close()
+}
+
+func (m *Subscription) close() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.closed {
+		return
+	}
+	m.closed = true
+	m.queue = nil
+	close(m.done)
+	m.cond.Signal()
+}
+
+// push 事件入队
+func (m *Subscription) push(event Event) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.closed {
+		return
+	}
+	m.queue = append(m.queue, event)
+	m.cond.Signal()
+}
+
+// loop 按顺序分发队列中的事件
+func (m *Subscription) loop() {
+	defer m.finish()
+	for {
+		m.lock.Lock()
+		for len(m.queue) == 0 && !m.closed {
+			m.cond.Wait()
+		}
+		if m.closed {
+			m.lock.Unlock()
+			return
+		}
+		event := m.queue[0]
+		m.queue = m.queue[1:]
+		m.lock.Unlock()
+		m.deliver(event, m.done)
+	}
+}
+
+// subscribeEvent
+//
+//	类型化事件订阅, 解码事件参数后通过 send 发送, 解码失败的事件被忽略
+func subscribeEvent(client *Client, method string, newValue func() interface{}, send func(value interface{}, done <-chan struct{}), finish func()) *Subscription {
+	return client.subscribe(method, func(event Event, done <-chan struct{}) {
+		value := newValue()
+		if len(event.Params) > 0 {
+			if err := json.Unmarshal(event.Params, value); err != nil {
+				return
+			}
+		}
+		send(value, done)
+	}, finish)
+}
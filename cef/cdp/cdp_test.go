@@ -0,0 +1,198 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTransport 模拟 DevTools, 按方法名返回结果
+type fakeTransport struct {
+	client  *Client
+	lock    sync.Mutex
+	sent    []request
+	handler func(id int32, method string, params json.RawMessage) string
+}
+
+func (m *fakeTransport) SendMessage(message []byte) error {
+	var req struct {
+		ID     int32           `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &req); err != nil {
+		return err
+	}
+	m.lock.Lock()
+	m.sent = append(m.sent, request{ID: req.ID, Method: req.Method, Params: req.Params})
+	m.lock.Unlock()
+	if reply := m.handler(req.ID, req.Method, req.Params); reply != "" {
+		go m.client.Dispatch([]byte(reply))
+	}
+	return nil
+}
+
+func newFakeClient(handler func(id int32, method string, params json.RawMessage) string) (*Client, *fakeTransport) {
+	transport := &fakeTransport{handler: handler}
+	transport.client = NewClient(transport)
+	return transport.client, transport
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestCallResult(t *testing.T) {
+	client, transport := newFakeClient(func(id int32, method string, params json.RawMessage) string {
+		switch method {
+		case "Page.navigate":
+			var p NavigateParams
+			json.Unmarshal(params, &p)
+			return fmt.Sprintf(`{"id":%d,"result":{"frameId":"F1","loaderId":"%s"}}`, id, p.URL)
+		case "Page.captureScreenshot":
+			return fmt.Sprintf(`{"id":%d,"result":{"data":"iVBORw=="}}`, id)
+		case "Runtime.evaluate":
+			return fmt.Sprintf(`{"id":%d,"result":{"result":{"type":"number","value":42}}}`, id)
+		}
+		return fmt.Sprintf(`{"id":%d,"error":{"code":-32601,"message":"'%s' wasn't found"}}`, id, method)
+	})
+	ctx := testContext(t)
+	// 并发调用, 结果按ID关联
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			url := fmt.Sprintf("https://energy.yanghy.cn/%d", i)
+			result, err := client.Page().Navigate(ctx, &NavigateParams{URL: url})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if string(result.LoaderID) != url {
+				t.Error("result not correlated", result.LoaderID, url)
+			}
+		}(i)
+	}
+	wg.Wait()
+	data, err := client.Page().CaptureScreenshot(ctx, nil)
+	if err != nil || string(data[:4]) != "\x89PNG" {
+		t.Fatal("unexpected screenshot", data, err)
+	}
+	var value int
+	if err = client.Runtime().EvaluateValue(ctx, "6*7", &value); err != nil || value != 42 {
+		t.Fatal("unexpected evaluate", value, err)
+	}
+	err = client.Call(ctx, "Unknown.method", nil, nil)
+	var cdpErr *Error
+	if !errors.As(err, &cdpErr) || cdpErr.Code != -32601 {
+		t.Fatal("expected protocol error", err)
+	}
+	// nil 参数不发送 params
+	transport.lock.Lock()
+	defer transport.lock.Unlock()
+	for _, req := range transport.sent {
+		if req.Method == "Page.captureScreenshot" && req.Params != nil && len(req.Params.(json.RawMessage)) > 0 {
+			t.Fatal("nil params sent", string(req.Params.(json.RawMessage)))
+		}
+	}
+}
+
+func TestEvaluateException(t *testing.T) {
+	client, _ := newFakeClient(func(id int32, method string, params json.RawMessage) string {
+		return fmt.Sprintf(`{"id":%d,"result":{"result":{"type":"object","subtype":"error"},"exceptionDetails":{"exceptionId":1,"text":"Uncaught","lineNumber":0,"columnNumber":0,"exception":{"type":"object","description":"ReferenceError: x is not defined"}}}}`, id)
+	})
+	err := client.Runtime().EvaluateValue(testContext(t), "x", nil)
+	var details *ExceptionDetails
+	if !errors.As(err, &details) || details.Exception.Description != "ReferenceError: x is not defined" {
+		t.Fatal("expected exception details", err)
+	}
+}
+
+func TestCallCancelAndClose(t *testing.T) {
+	client, _ := newFakeClient(func(id int32, method string, params json.RawMessage) string {
+		return "" // 不返回结果
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Call(ctx, "Page.enable", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected deadline exceeded", err)
+	}
+	if len(client.pending) != 0 {
+		t.Fatal("pending call not removed")
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- client.Call(context.Background(), "Page.enable", nil, nil)
+	}()
+	for {
+		client.lock.Lock()
+		n := len(client.pending)
+		client.lock.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	client.Close()
+	if err := <-result; err != ErrClosed {
+		t.Fatal("expected ErrClosed", err)
+	}
+	if err := client.Call(context.Background(), "Page.enable", nil, nil); err != ErrClosed {
+		t.Fatal("expected ErrClosed", err)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	client, _ := newFakeClient(nil)
+	requests, sub := client.Network().OnRequestWillBeSent()
+	all := client.Subscribe("")
+	// 分发不阻塞, 事件按顺序排队
+	for i := 0; i < 100; i++ {
+		event := fmt.Sprintf(`{"method":"Network.requestWillBeSent","params":{"requestId":"%d","request":{"url":"https://energy.yanghy.cn","method":"GET","headers":{}}}}`, i)
+		if client.Dispatch([]byte(event)) {
+			t.Fatal("event reported as handled")
+		}
+	}
+	client.Dispatch([]byte(`{"method":"Page.loadEventFired","params":{"timestamp":1}}`))
+	for i := 0; i < 100; i++ {
+		event := <-requests
+		if string(event.RequestID) != fmt.Sprint(i) || event.Request.Method != "GET" {
+			t.Fatal("unexpected event", event.RequestID)
+		}
+	}
+	for i := 0; i < 101; i++ {
+		event := <-all.Events()
+		if i == 100 && event.Method != "Page.loadEventFired" {
+			t.Fatal("unexpected event", event.Method)
+		}
+	}
+	sub.Close()
+	if _, ok := <-requests; ok {
+		t.Fatal("channel not closed")
+	}
+	client.Close()
+	if _, ok := <-all.Events(); ok {
+		t.Fatal("channel not closed")
+	}
+	// 不属于客户端的结果
+	if client.Dispatch([]byte(`{"id":1,"result":{}}`)) {
+		t.Fatal("unknown result reported as handled")
+	}
+}
@@ -0,0 +1,54 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cdp
+
+import (
+	"github.com/energye/energy/v2/cef"
+	"github.com/energye/golcl/lcl"
+	"unsafe"
+)
+
+// chromiumTransport 通过 Chromium SendDevToolsMessage 发送消息
+type chromiumTransport struct {
+	chromium cef.IChromium
+}
+
+// SendMessage
+//
+//	非 CEF UI 线程调用时, CEF 会投递到 UI 线程发送并返回 false, 所以不使用返回值判断失败
+func (m *chromiumTransport) SendMessage(message []byte) error {
+	m.chromium.SendDevToolsMessage(string(message))
+	return nil
+}
+
+// New
+//
+//	创建 Chromium DevTools 协议客户端
+//	使用 SetOnDevToolsRawMessage 接收消息, 会替换已设置的该事件
+//	需要同时处理该事件时, 使用 NewClient 和自定义的 Transport, 在事件中调用 Client.Dispatch
+func New(chromium cef.IChromium) *Client {
+	client := NewClient(&chromiumTransport{chromium: chromium})
+	chromium.SetOnDevToolsRawMessage(func(sender lcl.IObject, browser *cef.ICefBrowser, message uintptr, messageSize uint32) (handled bool) {
+		if message == 0 || messageSize == 0 {
+			return false
+		}
+		return client.Dispatch(readMessage(message, messageSize))
+	})
+	return client
+}
+
+// readMessage 复制消息内容, 事件返回后消息内存失效
+func readMessage(ptr uintptr, size uint32) []byte {
+	result := make([]byte, size)
+	data := *(*unsafe.Pointer)(unsafe.Pointer(&ptr))
+	copy(result, (*[1 << 30]byte)(data)[:size:size])
+	return result
+}
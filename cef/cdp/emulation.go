@@ -0,0 +1,124 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cdp
+
+import (
+	"context"
+)
+
+// EmulationDomain Emulation 域
+//
+//	https://chromedevtools.github.io/devtools-protocol/tot/Emulation/
+type EmulationDomain struct {
+	client *Client
+}
+
+// ScreenOrientation 屏幕方向
+type ScreenOrientation struct {
+	Type  string `json:"type"` // portraitPrimary, portraitSecondary, landscapePrimary, landscapeSecondary
+	Angle int64  `json:"angle"`
+}
+
+// DeviceMetrics Emulation.setDeviceMetricsOverride 参数
+type DeviceMetrics struct {
+	Width             int64              `json:"width"`             // 0 不覆盖
+	Height            int64              `json:"height"`            // 0 不覆盖
+	DeviceScaleFactor float64            `json:"deviceScaleFactor"` // 0 不覆盖
+	Mobile            bool               `json:"mobile"`
+	Scale             float64            `json:"scale,omitempty"`
+	ScreenWidth       int64              `json:"screenWidth,omitempty"`
+	ScreenHeight      int64              `json:"screenHeight,omitempty"`
+	PositionX         int64              `json:"positionX,omitempty"`
+	PositionY         int64              `json:"positionY,omitempty"`
+	ScreenOrientation *ScreenOrientation `json:"screenOrientation,omitempty"`
+}
+
+// UserAgentOverride Emulation.setUserAgentOverride 参数
+type UserAgentOverride struct {
+	UserAgent      string `json:"userAgent"`
+	AcceptLanguage string `json:"acceptLanguage,omitempty"`
+	Platform       string `json:"platform,omitempty"`
+}
+
+// Geolocation 地理位置
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy"`
+}
+
+// MediaFeature CSS 媒体特性, 例: prefers-color-scheme: dark
+type MediaFeature struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SetDeviceMetricsOverride 覆盖设备尺寸, 仿真移动端
+func (m *EmulationDomain) SetDeviceMetricsOverride(ctx context.Context, metrics *DeviceMetrics) error {
+	return m.client.Call(ctx, "Emulation.setDeviceMetricsOverride", metrics, nil)
+}
+
+// ClearDeviceMetricsOverride 清除设备尺寸覆盖
+func (m *EmulationDomain) ClearDeviceMetricsOverride(ctx context.Context) error {
+	return m.client.Call(ctx, "Emulation.clearDeviceMetricsOverride", nil, nil)
+}
+
+// SetUserAgentOverride 覆盖 UserAgent
+func (m *EmulationDomain) SetUserAgentOverride(ctx context.Context, override *UserAgentOverride) error {
+	return m.client.Call(ctx, "Emulation.setUserAgentOverride", override, nil)
+}
+
+// SetTouchEmulationEnabled 启用触摸仿真
+func (m *EmulationDomain) SetTouchEmulationEnabled(ctx context.Context, enabled bool, maxTouchPoints int) error {
+	params := map[string]interface{}{"enabled": enabled}
+	if maxTouchPoints > 0 {
+		params["maxTouchPoints"] = maxTouchPoints
+	}
+	return m.client.Call(ctx, "Emulation.setTouchEmulationEnabled", params, nil)
+}
+
+// SetGeolocationOverride 覆盖地理位置, geolocation 为 nil 时模拟位置不可用
+func (m *EmulationDomain) SetGeolocationOverride(ctx context.Context, geolocation *Geolocation) error {
+	return m.client.Call(ctx, "Emulation.setGeolocationOverride", geolocation, nil)
+}
+
+// ClearGeolocationOverride 清除地理位置覆盖
+func (m *EmulationDomain) ClearGeolocationOverride(ctx context.Context) error {
+	return m.client.Call(ctx, "Emulation.clearGeolocationOverride", nil, nil)
+}
+
+// SetEmulatedMedia 模拟 CSS 媒体类型和特性, media: screen, print, 空为不覆盖
+func (m *EmulationDomain) SetEmulatedMedia(ctx context.Context, media string, features ...*MediaFeature) error {
+	params := map[string]interface{}{"media": media}
+	if len(features) > 0 {
+		params["features"] = features
+	}
+	return m.client.Call(ctx, "Emulation.setEmulatedMedia", params, nil)
+}
+
+// SetTimezoneOverride 覆盖时区, 例: Asia/Shanghai, 空为恢复
+func (m *EmulationDomain) SetTimezoneOverride(ctx context.Context, timezoneId string) error {
+	return m.client.Call(ctx, "Emulation.setTimezoneOverride", map[string]interface{}{"timezoneId": timezoneId}, nil)
+}
+
+// SetLocaleOverride 覆盖 ICU 区域, 例: zh_CN, 空为恢复
+func (m *EmulationDomain) SetLocaleOverride(ctx context.Context, locale string) error {
+	var params interface{}
+	if locale != "" {
+		params = map[string]interface{}{"locale": locale}
+	}
+	return m.client.Call(ctx, "Emulation.setLocaleOverride", params, nil)
+}
+
+// SetScriptExecutionDisabled 禁用 JS 执行
+func (m *EmulationDomain) SetScriptExecutionDisabled(ctx context.Context, disabled bool) error {
+	return m.client.Call(ctx, "Emulation.setScriptExecutionDisabled", map[string]interface{}{"value": disabled}, nil)
+}
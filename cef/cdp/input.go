@@ -0,0 +1,132 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cdp
+
+import (
+	"context"
+)
+
+// InputDomain Input 域
+//
+//	https://chromedevtools.github.io/devtools-protocol/tot/Input/
+type InputDomain struct {
+	client *Client
+}
+
+// 事件修饰键, 可组合
+const (
+	ModifierAlt   = 1
+	ModifierCtrl  = 2
+	ModifierMeta  = 4
+	ModifierShift = 8
+)
+
+// MouseEvent Input.dispatchMouseEvent 参数
+type MouseEvent struct {
+	Type        string  `json:"type"` // mousePressed, mouseReleased, mouseMoved, mouseWheel
+	X           float64 `json:"x"`
+	Y           float64 `json:"y"`
+	Modifiers   int     `json:"modifiers,omitempty"`
+	Timestamp   float64 `json:"timestamp,omitempty"`
+	Button      string  `json:"button,omitempty"` // none, left, middle, right, back, forward
+	Buttons     int     `json:"buttons,omitempty"`
+	ClickCount  int     `json:"clickCount,omitempty"`
+	DeltaX      float64 `json:"deltaX,omitempty"`
+	DeltaY      float64 `json:"deltaY,omitempty"`
+	PointerType string  `json:"pointerType,omitempty"` // mouse, pen
+}
+
+// KeyEvent Input.dispatchKeyEvent 参数
+type KeyEvent struct {
+	Type                  string  `json:"type"` // keyDown, keyUp, rawKeyDown, char
+	Modifiers             int     `json:"modifiers,omitempty"`
+	Timestamp             float64 `json:"timestamp,omitempty"`
+	Text                  string  `json:"text,omitempty"`
+	UnmodifiedText        string  `json:"unmodifiedText,omitempty"`
+	KeyIdentifier         string  `json:"keyIdentifier,omitempty"`
+	Code                  string  `json:"code,omitempty"` // 例: KeyA, Enter
+	Key                   string  `json:"key,omitempty"`  // 例: a, Enter
+	WindowsVirtualKeyCode int     `json:"windowsVirtualKeyCode,omitempty"`
+	NativeVirtualKeyCode  int     `json:"nativeVirtualKeyCode,omitempty"`
+	AutoRepeat            bool    `json:"autoRepeat,omitempty"`
+	IsKeypad              bool    `json:"isKeypad,omitempty"`
+	IsSystemKey           bool    `json:"isSystemKey,omitempty"`
+}
+
+// TouchPoint 触摸点
+type TouchPoint struct {
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	RadiusX float64 `json:"radiusX,omitempty"`
+	RadiusY float64 `json:"radiusY,omitempty"`
+	Force   float64 `json:"force,omitempty"`
+	ID      float64 `json:"id,omitempty"`
+}
+
+// TouchEvent Input.dispatchTouchEvent 参数
+type TouchEvent struct {
+	Type        string        `json:"type"` // touchStart, touchEnd, touchMove, touchCancel
+	TouchPoints []*TouchPoint `json:"touchPoints"`
+	Modifiers   int           `json:"modifiers,omitempty"`
+	Timestamp   float64       `json:"timestamp,omitempty"`
+}
+
+// DispatchMouseEvent 发送鼠标事件
+func (m *InputDomain) DispatchMouseEvent(ctx context.Context, event *MouseEvent) error {
+	return m.client.Call(ctx, "Input.dispatchMouseEvent", event, nil)
+}
+
+// DispatchKeyEvent 发送键盘事件
+func (m *InputDomain) DispatchKeyEvent(ctx context.Context, event *KeyEvent) error {
+	return m.client.Call(ctx, "Input.dispatchKeyEvent", event, nil)
+}
+
+// DispatchTouchEvent 发送触摸事件, touchEnd touchCancel 时 TouchPoints 为空
+func (m *InputDomain) DispatchTouchEvent(ctx context.Context, event *TouchEvent) error {
+	if event.TouchPoints == nil {
+		event.TouchPoints = []*TouchPoint{}
+	}
+	return m.client.Call(ctx, "Input.dispatchTouchEvent", event, nil)
+}
+
+// InsertText 在焦点元素插入文本, 不产生键盘事件
+func (m *InputDomain) InsertText(ctx context.Context, text string) error {
+	return m.client.Call(ctx, "Input.insertText", map[string]interface{}{"text": text}, nil)
+}
+
+// Click 在页面坐标 x y 点击鼠标左键
+func (m *InputDomain) Click(ctx context.Context, x, y float64) error {
+	for _, typ := range []string{"mouseMoved", "mousePressed", "mouseReleased"} {
+		event := &MouseEvent{Type: typ, X: x, Y: y}
+		if typ != "mouseMoved" {
+			event.Button = "left"
+			event.ClickCount = 1
+		}
+		if err := m.DispatchMouseEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Type 逐字符输入文本, 每个字符产生 keyDown keyUp 事件
+func (m *InputDomain) Type(ctx context.Context, text string) error {
+	for _, r := range text {
+		s := string(r)
+		if err := m.DispatchKeyEvent(ctx, &KeyEvent{Type: "keyDown", Text: s, UnmodifiedText: s, Key: s}); err != nil {
+			return err
+		}
+		if err := m.DispatchKeyEvent(ctx, &KeyEvent{Type: "keyUp", Key: s}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
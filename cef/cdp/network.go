@@ -0,0 +1,304 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cdp
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+// NetworkDomain Network 域
+//
+//	https://chromedevtools.github.io/devtools-protocol/tot/Network/
+type NetworkDomain struct {
+	client *Client
+}
+
+// RequestID 请求ID
+type RequestID string
+
+// ResourceType 资源类型, 例: Document, Stylesheet, Image, XHR, Fetch, Script
+type ResourceType string
+
+// Headers 请求或响应头
+type Headers map[string]string
+
+// Request 请求信息
+type Request struct {
+	URL              string  `json:"url"`
+	URLFragment      string  `json:"urlFragment,omitempty"`
+	Method           string  `json:"method"`
+	Headers          Headers `json:"headers"`
+	PostData         string  `json:"postData,omitempty"`
+	HasPostData      bool    `json:"hasPostData,omitempty"`
+	MixedContentType string  `json:"mixedContentType,omitempty"`
+	InitialPriority  string  `json:"initialPriority"`
+	ReferrerPolicy   string  `json:"referrerPolicy"`
+}
+
+// Response 响应信息
+type Response struct {
+	URL               string  `json:"url"`
+	Status            int64   `json:"status"`
+	StatusText        string  `json:"statusText"`
+	Headers           Headers `json:"headers"`
+	MimeType          string  `json:"mimeType"`
+	RequestHeaders    Headers `json:"requestHeaders,omitempty"`
+	ConnectionReused  bool    `json:"connectionReused"`
+	ConnectionID      float64 `json:"connectionId"`
+	RemoteIPAddress   string  `json:"remoteIPAddress,omitempty"`
+	RemotePort        int64   `json:"remotePort,omitempty"`
+	FromDiskCache     bool    `json:"fromDiskCache,omitempty"`
+	FromServiceWorker bool    `json:"fromServiceWorker,omitempty"`
+	EncodedDataLength float64 `json:"encodedDataLength"`
+	Protocol          string  `json:"protocol,omitempty"`
+	SecurityState     string  `json:"securityState"`
+}
+
+// Initiator 请求发起者
+type Initiator struct {
+	Type       string  `json:"type"` // parser, script, preload, SignedExchange, preflight, other
+	URL        string  `json:"url,omitempty"`
+	LineNumber float64 `json:"lineNumber,omitempty"`
+}
+
+// Cookie 浏览器 Cookie
+type Cookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"` // 秒, 会话 Cookie 为 -1
+	Size     int64   `json:"size"`
+	HTTPOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	Session  bool    `json:"session"`
+	SameSite string  `json:"sameSite,omitempty"` // Strict, Lax, None
+}
+
+// CookieParam Network.setCookie 参数
+type CookieParam struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	URL      string  `json:"url,omitempty"`
+	Domain   string  `json:"domain,omitempty"`
+	Path     string  `json:"path,omitempty"`
+	Secure   bool    `json:"secure,omitempty"`
+	HTTPOnly bool    `json:"httpOnly,omitempty"`
+	SameSite string  `json:"sameSite,omitempty"`
+	Expires  float64 `json:"expires,omitempty"`
+}
+
+// NetworkConditions Network.emulateNetworkConditions 参数
+type NetworkConditions struct {
+	Offline            bool    `json:"offline"`
+	Latency            float64 `json:"latency"`            // 毫秒
+	DownloadThroughput float64 `json:"downloadThroughput"` // 字节/秒, -1 不限制
+	UploadThroughput   float64 `json:"uploadThroughput"`   // 字节/秒, -1 不限制
+	ConnectionType     string  `json:"connectionType,omitempty"`
+}
+
+// RequestWillBeSentEvent Network.requestWillBeSent
+type RequestWillBeSentEvent struct {
+	RequestID        RequestID    `json:"requestId"`
+	LoaderID         LoaderID     `json:"loaderId"`
+	DocumentURL      string       `json:"documentURL"`
+	Request          Request      `json:"request"`
+	Timestamp        float64      `json:"timestamp"`
+	WallTime         float64      `json:"wallTime"`
+	Initiator        Initiator    `json:"initiator"`
+	RedirectResponse *Response    `json:"redirectResponse,omitempty"`
+	Type             ResourceType `json:"type,omitempty"`
+	FrameID          FrameID      `json:"frameId,omitempty"`
+}
+
+// ResponseReceivedEvent Network.responseReceived
+type ResponseReceivedEvent struct {
+	RequestID RequestID    `json:"requestId"`
+	LoaderID  LoaderID     `json:"loaderId"`
+	Timestamp float64      `json:"timestamp"`
+	Type      ResourceType `json:"type"`
+	Response  Response     `json:"response"`
+	FrameID   FrameID      `json:"frameId,omitempty"`
+}
+
+// LoadingFinishedEvent Network.loadingFinished
+type LoadingFinishedEvent struct {
+	RequestID         RequestID `json:"requestId"`
+	Timestamp         float64   `json:"timestamp"`
+	EncodedDataLength float64   `json:"encodedDataLength"`
+}
+
+// LoadingFailedEvent Network.loadingFailed
+type LoadingFailedEvent struct {
+	RequestID     RequestID    `json:"requestId"`
+	Timestamp     float64      `json:"timestamp"`
+	Type          ResourceType `json:"type"`
+	ErrorText     string       `json:"errorText"`
+	Canceled      bool         `json:"canceled,omitempty"`
+	BlockedReason string       `json:"blockedReason,omitempty"`
+}
+
+// Enable 启用 Network 域事件
+func (m *NetworkDomain) Enable(ctx context.Context) error {
+	return m.client.Call(ctx, "Network.enable", nil, nil)
+}
+
+// Disable 禁用 Network 域事件
+func (m *NetworkDomain) Disable(ctx context.Context) error {
+	return m.client.Call(ctx, "Network.disable", nil, nil)
+}
+
+// GetResponseBody 获取响应内容, 需要在 LoadingFinished 之后调用
+func (m *NetworkDomain) GetResponseBody(ctx context.Context, requestId RequestID) ([]byte, error) {
+	var result struct {
+		Body          string `json:"body"`
+		Base64Encoded bool   `json:"base64Encoded"`
+	}
+	if err := m.client.Call(ctx, "Network.getResponseBody", map[string]interface{}{"requestId": requestId}, &result); err != nil {
+		return nil, err
+	}
+	if result.Base64Encoded {
+		return base64.StdEncoding.DecodeString(result.Body)
+	}
+	return []byte(result.Body), nil
+}
+
+// GetRequestPostData 获取请求 POST 数据
+func (m *NetworkDomain) GetRequestPostData(ctx context.Context, requestId RequestID) (string, error) {
+	var result struct {
+		PostData string `json:"postData"`
+	}
+	if err := m.client.Call(ctx, "Network.getRequestPostData", map[string]interface{}{"requestId": requestId}, &result); err != nil {
+		return "", err
+	}
+	return result.PostData, nil
+}
+
+// SetExtraHTTPHeaders 设置每个请求附加的请求头
+func (m *NetworkDomain) SetExtraHTTPHeaders(ctx context.Context, headers Headers) error {
+	return m.client.Call(ctx, "Network.setExtraHTTPHeaders", map[string]interface{}{"headers": headers}, nil)
+}
+
+// SetCacheDisabled 禁用缓存
+func (m *NetworkDomain) SetCacheDisabled(ctx context.Context, disabled bool) error {
+	return m.client.Call(ctx, "Network.setCacheDisabled", map[string]interface{}{"cacheDisabled": disabled}, nil)
+}
+
+// SetBlockedURLs 阻止匹配的 URL 加载, 支持通配符 *
+func (m *NetworkDomain) SetBlockedURLs(ctx context.Context, urls []string) error {
+	return m.client.Call(ctx, "Network.setBlockedURLs", map[string]interface{}{"urls": urls}, nil)
+}
+
+// EmulateNetworkConditions 模拟网络状态
+func (m *NetworkDomain) EmulateNetworkConditions(ctx context.Context, conditions *NetworkConditions) error {
+	return m.client.Call(ctx, "Network.emulateNetworkConditions", conditions, nil)
+}
+
+// GetCookies 获取 Cookie, urls 为空时返回当前页面的 Cookie
+func (m *NetworkDomain) GetCookies(ctx context.Context, urls ...string) ([]*Cookie, error) {
+	var result struct {
+		Cookies []*Cookie `json:"cookies"`
+	}
+	var params interface{}
+	if len(urls) > 0 {
+		params = map[string]interface{}{"urls": urls}
+	}
+	if err := m.client.Call(ctx, "Network.getCookies", params, &result); err != nil {
+		return nil, err
+	}
+	return result.Cookies, nil
+}
+
+// SetCookie 设置 Cookie
+func (m *NetworkDomain) SetCookie(ctx context.Context, cookie *CookieParam) error {
+	var result struct {
+		Success bool `json:"success"`
+	}
+	return m.client.Call(ctx, "Network.setCookie", cookie, &result)
+}
+
+// DeleteCookies 删除匹配名称的 Cookie, url domain path 至少一个不为空
+func (m *NetworkDomain) DeleteCookies(ctx context.Context, name, url, domain, path string) error {
+	params := map[string]interface{}{"name": name}
+	if url != "" {
+		params["url"] = url
+	}
+	if domain != "" {
+		params["domain"] = domain
+	}
+	if path != "" {
+		params["path"] = path
+	}
+	return m.client.Call(ctx, "Network.deleteCookies", params, nil)
+}
+
+// ClearBrowserCookies 清除所有 Cookie
+func (m *NetworkDomain) ClearBrowserCookies(ctx context.Context) error {
+	return m.client.Call(ctx, "Network.clearBrowserCookies", nil, nil)
+}
+
+// ClearBrowserCache 清除缓存
+func (m *NetworkDomain) ClearBrowserCache(ctx context.Context) error {
+	return m.client.Call(ctx, "Network.clearBrowserCache", nil, nil)
+}
+
+// OnRequestWillBeSent 订阅 Network.requestWillBeSent, 需要先 Enable
+func (m *NetworkDomain) OnRequestWillBeSent() (<-chan *RequestWillBeSentEvent, *Subscription) {
+	ch := make(chan *RequestWillBeSentEvent)
+	sub := subscribeEvent(m.client, "Network.requestWillBeSent", func() interface{} { return new(RequestWillBeSentEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*RequestWillBeSentEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnResponseReceived 订阅 Network.responseReceived, 需要先 Enable
+func (m *NetworkDomain) OnResponseReceived() (<-chan *ResponseReceivedEvent, *Subscription) {
+	ch := make(chan *ResponseReceivedEvent)
+	sub := subscribeEvent(m.client, "Network.responseReceived", func() interface{} { return new(ResponseReceivedEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*ResponseReceivedEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnLoadingFinished 订阅 Network.loadingFinished, 需要先 Enable
+func (m *NetworkDomain) OnLoadingFinished() (<-chan *LoadingFinishedEvent, *Subscription) {
+	ch := make(chan *LoadingFinishedEvent)
+	sub := subscribeEvent(m.client, "Network.loadingFinished", func() interface{} { return new(LoadingFinishedEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*LoadingFinishedEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnLoadingFailed 订阅 Network.loadingFailed, 需要先 Enable
+func (m *NetworkDomain) OnLoadingFailed() (<-chan *LoadingFailedEvent, *Subscription) {
+	ch := make(chan *LoadingFailedEvent)
+	sub := subscribeEvent(m.client, "Network.loadingFailed", func() interface{} { return new(LoadingFailedEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*LoadingFailedEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
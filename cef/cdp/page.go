@@ -0,0 +1,328 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cdp
+
+import (
+	"context"
+)
+
+// PageDomain Page 域
+//
+//	https://chromedevtools.github.io/devtools-protocol/tot/Page/
+type PageDomain struct {
+	client *Client
+}
+
+// FrameID 框架ID
+type FrameID string
+
+// LoaderID 加载器ID
+type LoaderID string
+
+// Frame 框架信息
+type Frame struct {
+	ID             FrameID  `json:"id"`
+	ParentID       FrameID  `json:"parentId,omitempty"`
+	LoaderID       LoaderID `json:"loaderId"`
+	Name           string   `json:"name,omitempty"`
+	URL            string   `json:"url"`
+	URLFragment    string   `json:"urlFragment,omitempty"`
+	SecurityOrigin string   `json:"securityOrigin"`
+	MimeType       string   `json:"mimeType"`
+}
+
+// Viewport 截图区域
+type Viewport struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Scale  float64 `json:"scale"`
+}
+
+// LayoutViewport 布局视口
+type LayoutViewport struct {
+	PageX        int64 `json:"pageX"`
+	PageY        int64 `json:"pageY"`
+	ClientWidth  int64 `json:"clientWidth"`
+	ClientHeight int64 `json:"clientHeight"`
+}
+
+// VisualViewport 可视视口
+type VisualViewport struct {
+	OffsetX      float64 `json:"offsetX"`
+	OffsetY      float64 `json:"offsetY"`
+	PageX        float64 `json:"pageX"`
+	PageY        float64 `json:"pageY"`
+	ClientWidth  float64 `json:"clientWidth"`
+	ClientHeight float64 `json:"clientHeight"`
+	Scale        float64 `json:"scale"`
+	Zoom         float64 `json:"zoom,omitempty"`
+}
+
+// Rect 矩形
+type Rect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// NavigateParams Page.navigate 参数
+type NavigateParams struct {
+	URL            string  `json:"url"`
+	Referrer       string  `json:"referrer,omitempty"`
+	TransitionType string  `json:"transitionType,omitempty"`
+	FrameID        FrameID `json:"frameId,omitempty"`
+}
+
+// NavigateResult Page.navigate 结果
+type NavigateResult struct {
+	FrameID   FrameID  `json:"frameId"`
+	LoaderID  LoaderID `json:"loaderId,omitempty"`
+	ErrorText string   `json:"errorText,omitempty"`
+}
+
+// ReloadParams Page.reload 参数
+type ReloadParams struct {
+	IgnoreCache            bool   `json:"ignoreCache,omitempty"`
+	ScriptToEvaluateOnLoad string `json:"scriptToEvaluateOnLoad,omitempty"`
+}
+
+// CaptureScreenshotParams Page.captureScreenshot 参数
+type CaptureScreenshotParams struct {
+	Format                string    `json:"format,omitempty"`  // jpeg, png, webp, 默认 png
+	Quality               int       `json:"quality,omitempty"` // 0-100, 仅 jpeg webp
+	Clip                  *Viewport `json:"clip,omitempty"`
+	FromSurface           bool      `json:"fromSurface,omitempty"`
+	CaptureBeyondViewport bool      `json:"captureBeyondViewport,omitempty"`
+}
+
+// PrintToPDFParams Page.printToPDF 参数, 纸张单位: 英寸
+type PrintToPDFParams struct {
+	Landscape           bool    `json:"landscape,omitempty"`
+	DisplayHeaderFooter bool    `json:"displayHeaderFooter,omitempty"`
+	PrintBackground     bool    `json:"printBackground,omitempty"`
+	Scale               float64 `json:"scale,omitempty"`
+	PaperWidth          float64 `json:"paperWidth,omitempty"`
+	PaperHeight         float64 `json:"paperHeight,omitempty"`
+	MarginTop           float64 `json:"marginTop,omitempty"`
+	MarginBottom        float64 `json:"marginBottom,omitempty"`
+	MarginLeft          float64 `json:"marginLeft,omitempty"`
+	MarginRight         float64 `json:"marginRight,omitempty"`
+	PageRanges          string  `json:"pageRanges,omitempty"`
+	HeaderTemplate      string  `json:"headerTemplate,omitempty"`
+	FooterTemplate      string  `json:"footerTemplate,omitempty"`
+	PreferCSSPageSize   bool    `json:"preferCSSPageSize,omitempty"`
+}
+
+// LayoutMetrics Page.getLayoutMetrics 结果, CSS 像素
+type LayoutMetrics struct {
+	LayoutViewport LayoutViewport `json:"cssLayoutViewport"`
+	VisualViewport VisualViewport `json:"cssVisualViewport"`
+	ContentSize    Rect           `json:"cssContentSize"`
+}
+
+// FrameTree 框架树
+type FrameTree struct {
+	Frame       Frame        `json:"frame"`
+	ChildFrames []*FrameTree `json:"childFrames,omitempty"`
+}
+
+// LoadEventFiredEvent Page.loadEventFired
+type LoadEventFiredEvent struct {
+	Timestamp float64 `json:"timestamp"`
+}
+
+// DomContentEventFiredEvent Page.domContentEventFired
+type DomContentEventFiredEvent struct {
+	Timestamp float64 `json:"timestamp"`
+}
+
+// FrameNavigatedEvent Page.frameNavigated
+type FrameNavigatedEvent struct {
+	Frame Frame  `json:"frame"`
+	Type  string `json:"type,omitempty"`
+}
+
+// LifecycleEvent Page.lifecycleEvent
+type LifecycleEvent struct {
+	FrameID   FrameID  `json:"frameId"`
+	LoaderID  LoaderID `json:"loaderId"`
+	Name      string   `json:"name"`
+	Timestamp float64  `json:"timestamp"`
+}
+
+// JavascriptDialogOpeningEvent Page.javascriptDialogOpening
+type JavascriptDialogOpeningEvent struct {
+	URL               string `json:"url"`
+	Message           string `json:"message"`
+	Type              string `json:"type"` // alert, confirm, prompt, beforeunload
+	HasBrowserHandler bool   `json:"hasBrowserHandler"`
+	DefaultPrompt     string `json:"defaultPrompt,omitempty"`
+}
+
+// Enable 启用 Page 域事件
+func (m *PageDomain) Enable(ctx context.Context) error {
+	return m.client.Call(ctx, "Page.enable", nil, nil)
+}
+
+// Disable 禁用 Page 域事件
+func (m *PageDomain) Disable(ctx context.Context) error {
+	return m.client.Call(ctx, "Page.disable", nil, nil)
+}
+
+// Navigate 导航到 URL, 导航失败时返回 ErrorText
+func (m *PageDomain) Navigate(ctx context.Context, params *NavigateParams) (*NavigateResult, error) {
+	result := new(NavigateResult)
+	if err := m.client.Call(ctx, "Page.navigate", params, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Reload 重新加载页面, params 可以为 nil
+func (m *PageDomain) Reload(ctx context.Context, params *ReloadParams) error {
+	return m.client.Call(ctx, "Page.reload", params, nil)
+}
+
+// CaptureScreenshot 截图, 返回图片数据, params 可以为 nil
+func (m *PageDomain) CaptureScreenshot(ctx context.Context, params *CaptureScreenshotParams) ([]byte, error) {
+	var result struct {
+		Data []byte `json:"data"`
+	}
+	if err := m.client.Call(ctx, "Page.captureScreenshot", params, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// PrintToPDF 打印为 PDF, 返回 PDF 数据, params 可以为 nil
+func (m *PageDomain) PrintToPDF(ctx context.Context, params *PrintToPDFParams) ([]byte, error) {
+	var result struct {
+		Data []byte `json:"data"`
+	}
+	if err := m.client.Call(ctx, "Page.printToPDF", params, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetLayoutMetrics 获取页面布局尺寸, 用于整页截图
+func (m *PageDomain) GetLayoutMetrics(ctx context.Context) (*LayoutMetrics, error) {
+	result := new(LayoutMetrics)
+	if err := m.client.Call(ctx, "Page.getLayoutMetrics", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetFrameTree 获取框架树
+func (m *PageDomain) GetFrameTree(ctx context.Context) (*FrameTree, error) {
+	var result struct {
+		FrameTree *FrameTree `json:"frameTree"`
+	}
+	if err := m.client.Call(ctx, "Page.getFrameTree", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.FrameTree, nil
+}
+
+// AddScriptToEvaluateOnNewDocument 在每个新文档加载前执行脚本, 返回脚本标识
+func (m *PageDomain) AddScriptToEvaluateOnNewDocument(ctx context.Context, source string) (string, error) {
+	var result struct {
+		Identifier string `json:"identifier"`
+	}
+	params := map[string]interface{}{"source": source}
+	if err := m.client.Call(ctx, "Page.addScriptToEvaluateOnNewDocument", params, &result); err != nil {
+		return "", err
+	}
+	return result.Identifier, nil
+}
+
+// SetLifecycleEventsEnabled 启用 Page.lifecycleEvent 事件
+func (m *PageDomain) SetLifecycleEventsEnabled(ctx context.Context, enabled bool) error {
+	return m.client.Call(ctx, "Page.setLifecycleEventsEnabled", map[string]interface{}{"enabled": enabled}, nil)
+}
+
+// HandleJavaScriptDialog 接受或取消 JS 对话框, promptText 仅 prompt 对话框
+func (m *PageDomain) HandleJavaScriptDialog(ctx context.Context, accept bool, promptText string) error {
+	params := map[string]interface{}{"accept": accept}
+	if promptText != "" {
+		params["promptText"] = promptText
+	}
+	return m.client.Call(ctx, "Page.handleJavaScriptDialog", params, nil)
+}
+
+// OnLoadEventFired 订阅 Page.loadEventFired, 需要先 Enable
+func (m *PageDomain) OnLoadEventFired() (<-chan *LoadEventFiredEvent, *Subscription) {
+	ch := make(chan *LoadEventFiredEvent)
+	sub := subscribeEvent(m.client, "Page.loadEventFired", func() interface{} { return new(LoadEventFiredEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*LoadEventFiredEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnDomContentEventFired 订阅 Page.domContentEventFired, 需要先 Enable
+func (m *PageDomain) OnDomContentEventFired() (<-chan *DomContentEventFiredEvent, *Subscription) {
+	ch := make(chan *DomContentEventFiredEvent)
+	sub := subscribeEvent(m.client, "Page.domContentEventFired", func() interface{} { return new(DomContentEventFiredEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*DomContentEventFiredEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnFrameNavigated 订阅 Page.frameNavigated, 需要先 Enable
+func (m *PageDomain) OnFrameNavigated() (<-chan *FrameNavigatedEvent, *Subscription) {
+	ch := make(chan *FrameNavigatedEvent)
+	sub := subscribeEvent(m.client, "Page.frameNavigated", func() interface{} { return new(FrameNavigatedEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*FrameNavigatedEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnLifecycleEvent 订阅 Page.lifecycleEvent, 需要先 SetLifecycleEventsEnabled
+func (m *PageDomain) OnLifecycleEvent() (<-chan *LifecycleEvent, *Subscription) {
+	ch := make(chan *LifecycleEvent)
+	sub := subscribeEvent(m.client, "Page.lifecycleEvent", func() interface{} { return new(LifecycleEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*LifecycleEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnJavascriptDialogOpening 订阅 Page.javascriptDialogOpening, 需要先 Enable
+func (m *PageDomain) OnJavascriptDialogOpening() (<-chan *JavascriptDialogOpeningEvent, *Subscription) {
+	ch := make(chan *JavascriptDialogOpeningEvent)
+	sub := subscribeEvent(m.client, "Page.javascriptDialogOpening", func() interface{} { return new(JavascriptDialogOpeningEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*JavascriptDialogOpeningEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
@@ -0,0 +1,273 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RuntimeDomain Runtime 域
+//
+//	https://chromedevtools.github.io/devtools-protocol/tot/Runtime/
+type RuntimeDomain struct {
+	client *Client
+}
+
+// RemoteObjectID 远程对象ID
+type RemoteObjectID string
+
+// ExecutionContextID 执行上下文ID
+type ExecutionContextID int64
+
+// RemoteObject JS 对象的镜像
+type RemoteObject struct {
+	Type                string          `json:"type"` // object, function, undefined, string, number, boolean, symbol, bigint
+	Subtype             string          `json:"subtype,omitempty"`
+	ClassName           string          `json:"className,omitempty"`
+	Value               json.RawMessage `json:"value,omitempty"` // 基本类型或 returnByValue 时的值
+	UnserializableValue string          `json:"unserializableValue,omitempty"`
+	Description         string          `json:"description,omitempty"`
+	ObjectID            RemoteObjectID  `json:"objectId,omitempty"`
+}
+
+// Unmarshal 将 Value 解码到 v
+func (m *RemoteObject) Unmarshal(v interface{}) error {
+	if len(m.Value) == 0 {
+		return fmt.Errorf("cdp: remote object %s has no value", m.Type)
+	}
+	return json.Unmarshal(m.Value, v)
+}
+
+// CallFrame 调用栈帧
+type CallFrame struct {
+	FunctionName string `json:"functionName"`
+	ScriptID     string `json:"scriptId"`
+	URL          string `json:"url"`
+	LineNumber   int64  `json:"lineNumber"`
+	ColumnNumber int64  `json:"columnNumber"`
+}
+
+// StackTrace 调用栈
+type StackTrace struct {
+	Description string       `json:"description,omitempty"`
+	CallFrames  []*CallFrame `json:"callFrames"`
+}
+
+// ExceptionDetails JS 异常信息
+type ExceptionDetails struct {
+	ExceptionID        int64              `json:"exceptionId"`
+	Text               string             `json:"text"`
+	LineNumber         int64              `json:"lineNumber"`
+	ColumnNumber       int64              `json:"columnNumber"`
+	URL                string             `json:"url,omitempty"`
+	StackTrace         *StackTrace        `json:"stackTrace,omitempty"`
+	Exception          *RemoteObject      `json:"exception,omitempty"`
+	ExecutionContextID ExecutionContextID `json:"executionContextId,omitempty"`
+}
+
+func (m *ExceptionDetails) Error() string {
+	if m.Exception != nil && m.Exception.Description != "" {
+		return "cdp: " + m.Exception.Description
+	}
+	return fmt.Sprintf("cdp: %s (%d:%d)", m.Text, m.LineNumber, m.ColumnNumber)
+}
+
+// CallArgument CallFunctionOn 参数, Value ObjectID 二选一
+type CallArgument struct {
+	Value    interface{}    `json:"value,omitempty"`
+	ObjectID RemoteObjectID `json:"objectId,omitempty"`
+}
+
+// EvaluateParams Runtime.evaluate 参数
+type EvaluateParams struct {
+	Expression            string             `json:"expression"`
+	ObjectGroup           string             `json:"objectGroup,omitempty"`
+	IncludeCommandLineAPI bool               `json:"includeCommandLineAPI,omitempty"`
+	Silent                bool               `json:"silent,omitempty"`
+	ContextID             ExecutionContextID `json:"contextId,omitempty"`
+	ReturnByValue         bool               `json:"returnByValue,omitempty"`
+	UserGesture           bool               `json:"userGesture,omitempty"`
+	AwaitPromise          bool               `json:"awaitPromise,omitempty"`
+	Timeout               float64            `json:"timeout,omitempty"` // 毫秒
+}
+
+// CallFunctionOnParams Runtime.callFunctionOn 参数
+type CallFunctionOnParams struct {
+	FunctionDeclaration string             `json:"functionDeclaration"`
+	ObjectID            RemoteObjectID     `json:"objectId,omitempty"`
+	Arguments           []*CallArgument    `json:"arguments,omitempty"`
+	Silent              bool               `json:"silent,omitempty"`
+	ReturnByValue       bool               `json:"returnByValue,omitempty"`
+	UserGesture         bool               `json:"userGesture,omitempty"`
+	AwaitPromise        bool               `json:"awaitPromise,omitempty"`
+	ExecutionContextID  ExecutionContextID `json:"executionContextId,omitempty"`
+	ObjectGroup         string             `json:"objectGroup,omitempty"`
+}
+
+// ExecutionContextDescription 执行上下文
+type ExecutionContextDescription struct {
+	ID      ExecutionContextID     `json:"id"`
+	Origin  string                 `json:"origin"`
+	Name    string                 `json:"name"`
+	AuxData map[string]interface{} `json:"auxData,omitempty"`
+}
+
+// ConsoleAPICalledEvent Runtime.consoleAPICalled
+type ConsoleAPICalledEvent struct {
+	Type               string             `json:"type"` // log, debug, info, error, warning ...
+	Args               []*RemoteObject    `json:"args"`
+	ExecutionContextID ExecutionContextID `json:"executionContextId"`
+	Timestamp          float64            `json:"timestamp"`
+	StackTrace         *StackTrace        `json:"stackTrace,omitempty"`
+}
+
+// ExceptionThrownEvent Runtime.exceptionThrown
+type ExceptionThrownEvent struct {
+	Timestamp        float64          `json:"timestamp"`
+	ExceptionDetails ExceptionDetails `json:"exceptionDetails"`
+}
+
+// BindingCalledEvent Runtime.bindingCalled
+type BindingCalledEvent struct {
+	Name               string             `json:"name"`
+	Payload            string             `json:"payload"`
+	ExecutionContextID ExecutionContextID `json:"executionContextId"`
+}
+
+// ExecutionContextCreatedEvent Runtime.executionContextCreated
+type ExecutionContextCreatedEvent struct {
+	Context ExecutionContextDescription `json:"context"`
+}
+
+// Enable 启用 Runtime 域事件
+func (m *RuntimeDomain) Enable(ctx context.Context) error {
+	return m.client.Call(ctx, "Runtime.enable", nil, nil)
+}
+
+// Disable 禁用 Runtime 域事件
+func (m *RuntimeDomain) Disable(ctx context.Context) error {
+	return m.client.Call(ctx, "Runtime.disable", nil, nil)
+}
+
+// evaluateResult evaluate callFunctionOn 结果
+type evaluateResult struct {
+	Result           *RemoteObject     `json:"result"`
+	ExceptionDetails *ExceptionDetails `json:"exceptionDetails,omitempty"`
+}
+
+func (m *evaluateResult) object() (*RemoteObject, error) {
+	if m.ExceptionDetails != nil {
+		return m.Result, m.ExceptionDetails
+	}
+	return m.Result, nil
+}
+
+// Evaluate
+//
+//	执行 JS 表达式, JS 异常返回 *ExceptionDetails 错误
+func (m *RuntimeDomain) Evaluate(ctx context.Context, params *EvaluateParams) (*RemoteObject, error) {
+	result := new(evaluateResult)
+	if err := m.client.Call(ctx, "Runtime.evaluate", params, result); err != nil {
+		return nil, err
+	}
+	return result.object()
+}
+
+// EvaluateValue
+//
+//	执行 JS 表达式并将结果值解码到 v, Promise 等待完成, v 为 nil 时忽略结果
+func (m *RuntimeDomain) EvaluateValue(ctx context.Context, expression string, v interface{}) error {
+	result, err := m.Evaluate(ctx, &EvaluateParams{Expression: expression, ReturnByValue: true, AwaitPromise: true})
+	if err != nil || v == nil || result.Type == "undefined" {
+		return err
+	}
+	return result.Unmarshal(v)
+}
+
+// CallFunctionOn 以指定对象为 this 调用函数, JS 异常返回 *ExceptionDetails 错误
+func (m *RuntimeDomain) CallFunctionOn(ctx context.Context, params *CallFunctionOnParams) (*RemoteObject, error) {
+	result := new(evaluateResult)
+	if err := m.client.Call(ctx, "Runtime.callFunctionOn", params, result); err != nil {
+		return nil, err
+	}
+	return result.object()
+}
+
+// ReleaseObject 释放远程对象
+func (m *RuntimeDomain) ReleaseObject(ctx context.Context, objectId RemoteObjectID) error {
+	return m.client.Call(ctx, "Runtime.releaseObject", map[string]interface{}{"objectId": objectId}, nil)
+}
+
+// ReleaseObjectGroup 释放对象组内的所有远程对象
+func (m *RuntimeDomain) ReleaseObjectGroup(ctx context.Context, objectGroup string) error {
+	return m.client.Call(ctx, "Runtime.releaseObjectGroup", map[string]interface{}{"objectGroup": objectGroup}, nil)
+}
+
+// AddBinding
+//
+//	添加全局函数 name(payload string), JS 调用时触发 Runtime.bindingCalled
+func (m *RuntimeDomain) AddBinding(ctx context.Context, name string) error {
+	return m.client.Call(ctx, "Runtime.addBinding", map[string]interface{}{"name": name}, nil)
+}
+
+// OnConsoleAPICalled 订阅 Runtime.consoleAPICalled, 需要先 Enable
+func (m *RuntimeDomain) OnConsoleAPICalled() (<-chan *ConsoleAPICalledEvent, *Subscription) {
+	ch := make(chan *ConsoleAPICalledEvent)
+	sub := subscribeEvent(m.client, "Runtime.consoleAPICalled", func() interface{} { return new(ConsoleAPICalledEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*ConsoleAPICalledEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnExceptionThrown 订阅 Runtime.exceptionThrown, 需要先 Enable
+func (m *RuntimeDomain) OnExceptionThrown() (<-chan *ExceptionThrownEvent, *Subscription) {
+	ch := make(chan *ExceptionThrownEvent)
+	sub := subscribeEvent(m.client, "Runtime.exceptionThrown", func() interface{} { return new(ExceptionThrownEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*ExceptionThrownEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnBindingCalled 订阅 Runtime.bindingCalled
+func (m *RuntimeDomain) OnBindingCalled() (<-chan *BindingCalledEvent, *Subscription) {
+	ch := make(chan *BindingCalledEvent)
+	sub := subscribeEvent(m.client, "Runtime.bindingCalled", func() interface{} { return new(BindingCalledEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*BindingCalledEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
+
+// OnExecutionContextCreated 订阅 Runtime.executionContextCreated, 需要先 Enable
+func (m *RuntimeDomain) OnExecutionContextCreated() (<-chan *ExecutionContextCreatedEvent, *Subscription) {
+	ch := make(chan *ExecutionContextCreatedEvent)
+	sub := subscribeEvent(m.client, "Runtime.executionContextCreated", func() interface{} { return new(ExecutionContextCreatedEvent) },
+		func(value interface{}, done <-chan struct{}) {
+			select {
+			case ch <- value.(*ExecutionContextCreatedEvent):
+			case <-done:
+			}
+		}, func() { close(ch) })
+	return ch, sub
+}
@@ -63,6 +63,10 @@ func (m *TCEFChromium) initDefault() {
 	m.SetWebRTCIPHandlingPolicy(HpDisableNonProxiedUDP)
 	m.SetWebRTCMultipleRoutes(STATE_DISABLED)
 	m.SetWebRTCNonproxiedUDP(STATE_DISABLED)
+	if crashReportChromium {
+		// 崩溃报告记录渲染进程终止
+		m.SetOnRenderProcessTerminated(nil)
+	}
 }
 
 // Instance 组件实例指针
@@ -189,11 +189,26 @@ func (m *TCEFChromiumBrowser) RegisterDefaultEvent() {
 	m.Chromium().SetOnBeforeDownload(func(sender lcl.IObject, browser *ICefBrowser, beforeDownloadItem *ICefDownloadItem, suggestedName string, callback *ICefBeforeDownloadCallback) {
 		if bwEvent.onBeforeDownload != nil {
 			bwEvent.onBeforeDownload(sender, browser, beforeDownloadItem, suggestedName, callback, m.window)
+		} else if downloadManager != nil {
+			downloadManager.beforeDownload(browser, beforeDownloadItem, suggestedName, callback)
 		} else {
 			// 默认保存到当前执行文件所在目录
 			callback.Cont(consts.ExeDir+consts.Separator+suggestedName, true)
 		}
 	})
+	if downloadManager != nil {
+		m.Chromium().SetOnDownloadUpdated(func(sender lcl.IObject, browser *ICefBrowser, downloadItem *ICefDownloadItem, callback *ICefDownloadItemCallback) {
+			downloadManager.downloadUpdated(browser, downloadItem, callback)
+		})
+	}
+	if permissionManager != nil {
+		m.Chromium().SetOnRequestMediaAccessPermission(func(sender lcl.IObject, browser *ICefBrowser, frame *ICefFrame, requestingOrigin string, requestedPermissions uint32, callback *ICefMediaAccessCallback) bool {
+			return permissionManager.requestMediaAccess(m.window, browser, requestingOrigin, requestedPermissions, callback)
+		})
+		m.Chromium().SetOnShowPermissionPrompt(func(sender lcl.IObject, browser *ICefBrowser, promptId uint64, requestingOrigin string, requestedPermissions uint32, callback *ICefPermissionPromptCallback) bool {
+			return permissionManager.showPermissionPrompt(m.window, browser, requestingOrigin, requestedPermissions, callback)
+		})
+	}
 	m.Chromium().SetOnLoadEnd(func(sender lcl.IObject, browser *ICefBrowser, frame *ICefFrame, httpStatusCode int32) {
 		if bwEvent.onLoadEnd != nil {
 			bwEvent.onLoadEnd(sender, browser, frame, httpStatusCode, m.window)
@@ -219,6 +234,7 @@ func (m *TCEFChromiumBrowser) RegisterDefaultEvent() {
 			}
 			if !flag {
 				chromiumOnBeforeContextMenu(m.window, browser, frame, params, model)
+				contextMenus.apply(m.window, browser, params, model)
 			}
 		})
 		m.Chromium().SetOnContextMenuCommand(func(sender lcl.IObject, browser *ICefBrowser, frame *ICefFrame, params *ICefContextMenuParams, commandId consts.MenuId, eventFlags uint32) bool {
@@ -52,6 +52,8 @@ func chromiumOnAfterCreate(window IBrowserWindow, browser *ICefBrowser) bool {
 	}
 	// 方式二 本地资源加载处理器
 	localLoadRes.getSchemeHandlerFactory(window, browser) // TODO
+	// 自定义协议 http.Handler
+	registerSchemeHTTPHandlerFactory(browser)
 	return false
 }
 
@@ -63,10 +65,13 @@ func chromiumOnBeforeBrowser(window IBrowserWindow, browser *ICefBrowser, frame
 		return
 	}
 	BrowserWindow.PutWindowInfo(browser, window)
+	WindowManager.opened(window, browser)
 	// 只LCL窗口使用自定义的窗口拖拽
 	if window.IsLCL() {
 		dragExtensionJS(frame, window.WindowProperty().EnableWebkitAppRegion) // drag extension
 	}
+	// 主进程切换过语言, 同步到渲染进程
+	i18nSyncOnBeforeBrowser(browser, frame)
 	// 方式二 本地资源加载处理器
 	//localLoadRes.getSchemeHandlerFactory(window, browser) // TODO
 }
@@ -75,6 +80,8 @@ func chromiumOnBeforeBrowser(window IBrowserWindow, browser *ICefBrowser, frame
 func chromiumOnBeforeClose(m IBrowserWindow, browser *ICefBrowser) {
 	// 移除当前关闭所在的集合窗口维护
 	BrowserWindow.removeWindowInfo(browser.Identifier())
+	WindowManager.closed(m, browser.Identifier())
+	contextMenus.remove(browser.Identifier())
 }
 
 var (
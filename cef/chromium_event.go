@@ -15,6 +15,7 @@ package cef
 import (
 	"github.com/energye/energy/v2/common"
 	"github.com/energye/energy/v2/consts"
+	"github.com/energye/energy/v2/pkgs/crash"
 	"github.com/energye/golcl/lcl"
 	"github.com/energye/golcl/lcl/api"
 	"github.com/energye/golcl/lcl/types"
@@ -36,7 +37,10 @@ func init() {
 		}
 		return lcl.AsObject(senderPtr), browser, frame, request, response
 	}
-	lcl.RegisterExtEventCallback(func(fn interface{}, getVal func(idx int) uintptr) bool {
+	lcl.RegisterExtEventCallback(func(fn interface{}, getVal func(idx int) uintptr) (handled bool) {
+		// panic 恢复后返回 true, golcl 不再调用一次事件, 不是这里的事件时 default 返回 false
+		handled = true
+		defer crash.Recover("event")
 		getPtr := func(i int) unsafe.Pointer {
 			return unsafe.Pointer(getVal(i))
 		}
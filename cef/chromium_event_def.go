@@ -15,6 +15,8 @@ package cef
 import (
 	"github.com/energye/energy/v2/cef/internal/def"
 	"github.com/energye/energy/v2/common/imports"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/golcl/lcl"
 	"github.com/energye/golcl/lcl/api"
 )
 
@@ -308,11 +310,22 @@ func (m *TCEFChromium) SetOnBrowserCompMsg(fn chromiumEventOnCompMsg) {
 	_CEFChromium_SetOnBrowserCompMsg(m.Instance(), fn)
 }
 
+// SetOnRenderProcessTerminated
+//	渲染进程终止, 启用崩溃报告时先写入报告
 func (m *TCEFChromium) SetOnRenderProcessTerminated(fn chromiumEventOnRenderProcessTerminated) {
 	if !m.IsValid() {
 		return
 	}
-	_CEFChromium_SetOnRenderProcessTerminated(m.Instance(), fn)
+	if !crashReportChromium {
+		_CEFChromium_SetOnRenderProcessTerminated(m.Instance(), fn)
+		return
+	}
+	_CEFChromium_SetOnRenderProcessTerminated(m.Instance(), chromiumEventOnRenderProcessTerminated(func(sender lcl.IObject, browser *ICefBrowser, status consts.TCefTerminationStatus) {
+		crashRenderProcessTerminated(browser, status)
+		if fn != nil {
+			fn(sender, browser, status)
+		}
+	}))
 }
 
 func (m *TCEFChromium) SetOnRenderViewReady(fn chromiumEventOnRenderViewReady) {
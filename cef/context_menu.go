@@ -0,0 +1,617 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 声明式右键菜单
+
+package cef
+
+import (
+	"encoding/json"
+	"github.com/energye/energy/v2/cef/internal/ipc"
+	"github.com/energye/energy/v2/cef/ipc/context"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/energy/v2/logger"
+	"strings"
+	"sync"
+)
+
+// JS 定义右键菜单的 IPC 事件名
+const (
+	internalContextMenuSet   = "energy.contextmenu.set"   // JS -> Go 设置当前页面的菜单
+	internalContextMenuClick = "energy.contextmenu.click" // Go -> JS 点击 JS 定义的菜单项
+)
+
+// ContextMenuMode 自定义菜单和默认菜单的组合方式
+type ContextMenuMode int8
+
+const (
+	ContextMenuAppend  ContextMenuMode = iota // 添加到默认菜单之后
+	ContextMenuReplace                        // 替换默认菜单
+)
+
+// ContextMenuItemType 菜单项类型
+type ContextMenuItemType string
+
+const (
+	ContextMenuItemNormal    ContextMenuItemType = "item"
+	ContextMenuItemCheck     ContextMenuItemType = "check"
+	ContextMenuItemRadio     ContextMenuItemType = "radio"
+	ContextMenuItemSeparator ContextMenuItemType = "separator"
+	ContextMenuItemSubMenu   ContextMenuItemType = "submenu"
+)
+
+// ContextMenuContext
+//	右键菜单显示时的上下文, 从 ICefContextMenuParams 复制, 可在回调之外使用
+type ContextMenuContext struct {
+	X             int32                                `json:"x"`
+	Y             int32                                `json:"y"`
+	SelectionText string                               `json:"selectionText"`
+	LinkURL       string                               `json:"linkUrl"`
+	SourceURL     string                               `json:"sourceUrl"`
+	PageURL       string                               `json:"pageUrl"`
+	FrameURL      string                               `json:"frameUrl"`
+	Editable      bool                                 `json:"editable"`
+	MediaType     consts.TCefContextMenuMediaType      `json:"mediaType"`
+	TypeFlags     consts.TCefContextMenuTypeFlags      `json:"typeFlags"`
+	EditFlags     consts.TCefContextMenuEditStateFlags `json:"editFlags"`
+}
+
+// newContextMenuContext 复制菜单参数, params 为空时返回空上下文(快捷键触发)
+func newContextMenuContext(params *ICefContextMenuParams) *ContextMenuContext {
+	ctx := &ContextMenuContext{}
+	if params == nil || !params.IsValid() {
+		return ctx
+	}
+	ctx.X, ctx.Y = params.XCoord(), params.YCoord()
+	ctx.SelectionText = params.SelectionText()
+	ctx.LinkURL = params.LinkUrl()
+	ctx.SourceURL = params.SourceUrl()
+	ctx.PageURL = params.PageUrl()
+	ctx.FrameURL = params.FrameUrl()
+	ctx.Editable = params.IsEditable()
+	ctx.MediaType = params.MediaType()
+	ctx.TypeFlags = params.TypeFlags()
+	ctx.EditFlags = params.EditStateFlags()
+	return ctx
+}
+
+// HasSelection 有选中文本
+func (m *ContextMenuContext) HasSelection() bool {
+	return strings.TrimSpace(m.SelectionText) != ""
+}
+
+// IsLink 在链接上
+func (m *ContextMenuContext) IsLink() bool {
+	return m.LinkURL != "" || m.TypeFlags&consts.CM_TYPEFLAG_LINK != 0
+}
+
+// IsMedia 在指定类型的媒体上, 未指定类型时任意媒体
+func (m *ContextMenuContext) IsMedia(mediaTypes ...consts.TCefContextMenuMediaType) bool {
+	if m.MediaType == consts.CM_MEDIATYPE_NONE {
+		return false
+	}
+	if len(mediaTypes) == 0 {
+		return true
+	}
+	for _, mediaType := range mediaTypes {
+		if m.MediaType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// ContextMenuCondition 菜单项显示、可用、选中的条件
+type ContextMenuCondition func(ctx *ContextMenuContext) bool
+
+// ContextMenuClick
+//	菜单项点击或快捷键触发的回调
+//	快捷键在菜单项声明后即可使用, 不需要先显示菜单, 上下文为空, 显示和可用条件使用空上下文
+type ContextMenuClick func(window IBrowserWindow, browser *ICefBrowser, ctx *ContextMenuContext)
+
+// WhenSelection 有选中文本时
+func WhenSelection(ctx *ContextMenuContext) bool {
+	return ctx.HasSelection()
+}
+
+// WhenLink 在链接上时
+func WhenLink(ctx *ContextMenuContext) bool {
+	return ctx.IsLink()
+}
+
+// WhenEditable 在可编辑区域时
+func WhenEditable(ctx *ContextMenuContext) bool {
+	return ctx.Editable
+}
+
+// WhenImage 在图片上时
+func WhenImage(ctx *ContextMenuContext) bool {
+	return ctx.IsMedia(consts.CM_MEDIATYPE_IMAGE)
+}
+
+// WhenMedia 在指定类型的媒体上时, 未指定类型时任意媒体
+func WhenMedia(mediaTypes ...consts.TCefContextMenuMediaType) ContextMenuCondition {
+	return func(ctx *ContextMenuContext) bool {
+		return ctx.IsMedia(mediaTypes...)
+	}
+}
+
+// WhenNot 条件取反
+func WhenNot(condition ContextMenuCondition) ContextMenuCondition {
+	return func(ctx *ContextMenuContext) bool {
+		return !condition(ctx)
+	}
+}
+
+// ContextMenuItem 菜单项, 通过 ContextMenuBuilder 创建
+type ContextMenuItem struct {
+	itemType    ContextMenuItemType
+	text        string
+	label       string
+	accelerator string
+	keyCode     string // 快捷键编码, 与按键事件比较
+	groupId     int32
+	visible     ContextMenuCondition
+	enabled     ContextMenuCondition
+	checked     ContextMenuCondition
+	onClick     ContextMenuClick
+	subMenu     *ContextMenuBuilder
+}
+
+// Label 设置菜单项标签
+func (m *ContextMenuItem) Label(label string) *ContextMenuItem {
+	m.label = label
+	return m
+}
+
+// Accelerator 设置快捷键, 例: ctrl+shift+n
+func (m *ContextMenuItem) Accelerator(accelerator string) *ContextMenuItem {
+	m.accelerator, m.keyCode = accelerator, ""
+	if keyCode, shift, ctrl, alt, ok := parseAccelerator(accelerator); ok {
+		m.keyCode = acceleratorCode(shift, ctrl, alt, keyCode)
+	}
+	return m
+}
+
+// Visible 设置显示条件, 多个条件同时满足时显示
+func (m *ContextMenuItem) Visible(conditions ...ContextMenuCondition) *ContextMenuItem {
+	m.visible = contextMenuAll(m.visible, conditions)
+	return m
+}
+
+// Enabled 设置可用条件, 多个条件同时满足时可用
+func (m *ContextMenuItem) Enabled(conditions ...ContextMenuCondition) *ContextMenuItem {
+	m.enabled = contextMenuAll(m.enabled, conditions)
+	return m
+}
+
+// Checked 设置复选框和单选框的选中状态
+func (m *ContextMenuItem) Checked(checked ContextMenuCondition) *ContextMenuItem {
+	m.checked = checked
+	return m
+}
+
+// SubMenu 子菜单项返回子菜单, 其它返回 nil
+func (m *ContextMenuItem) SubMenu() *ContextMenuBuilder {
+	return m.subMenu
+}
+
+// contextMenuAll 合并条件
+func contextMenuAll(current ContextMenuCondition, conditions []ContextMenuCondition) ContextMenuCondition {
+	if current != nil {
+		conditions = append([]ContextMenuCondition{current}, conditions...)
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	return func(ctx *ContextMenuContext) bool {
+		for _, condition := range conditions {
+			if condition != nil && !condition(ctx) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// test 条件为空时为 def
+func (m ContextMenuCondition) test(ctx *ContextMenuContext, def bool) bool {
+	if m == nil {
+		return def
+	}
+	return m(ctx)
+}
+
+// ContextMenuBuilder 菜单构建器, 菜单项按添加顺序显示
+type ContextMenuBuilder struct {
+	lock  sync.Mutex
+	mode  ContextMenuMode
+	items []*ContextMenuItem
+}
+
+// NewContextMenu 创建菜单构建器, 默认添加到默认菜单之后
+func NewContextMenu() *ContextMenuBuilder {
+	return &ContextMenuBuilder{}
+}
+
+// SetMode 设置和默认菜单的组合方式
+func (m *ContextMenuBuilder) SetMode(mode ContextMenuMode) *ContextMenuBuilder {
+	m.mode = mode
+	return m
+}
+
+func (m *ContextMenuBuilder) add(item *ContextMenuItem) *ContextMenuItem {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.items = append(m.items, item)
+	return item
+}
+
+// Item 添加菜单项
+func (m *ContextMenuBuilder) Item(text string, onClick ContextMenuClick) *ContextMenuItem {
+	return m.add(&ContextMenuItem{itemType: ContextMenuItemNormal, text: text, onClick: onClick})
+}
+
+// Check 添加复选框菜单项, 选中状态由 Checked 条件决定
+func (m *ContextMenuBuilder) Check(text string, onClick ContextMenuClick) *ContextMenuItem {
+	return m.add(&ContextMenuItem{itemType: ContextMenuItemCheck, text: text, onClick: onClick})
+}
+
+// Radio 添加单选框菜单项, 相同 groupId 为一组
+func (m *ContextMenuBuilder) Radio(groupId int32, text string, onClick ContextMenuClick) *ContextMenuItem {
+	return m.add(&ContextMenuItem{itemType: ContextMenuItemRadio, groupId: groupId, text: text, onClick: onClick})
+}
+
+// Separator 添加分隔线, 开头、结尾和连续的分隔线不显示
+func (m *ContextMenuBuilder) Separator() *ContextMenuBuilder {
+	m.add(&ContextMenuItem{itemType: ContextMenuItemSeparator})
+	return m
+}
+
+// SubMenu 添加子菜单, 返回子菜单构建器, 子菜单没有可见项时不显示
+func (m *ContextMenuBuilder) SubMenu(text string) *ContextMenuBuilder {
+	item := m.add(&ContextMenuItem{itemType: ContextMenuItemSubMenu, text: text, subMenu: NewContextMenu()})
+	return item.subMenu
+}
+
+// SubMenuItem 添加子菜单, 返回子菜单项, 用于设置子菜单的显示条件
+func (m *ContextMenuBuilder) SubMenuItem(text string) *ContextMenuItem {
+	return m.add(&ContextMenuItem{itemType: ContextMenuItemSubMenu, text: text, subMenu: NewContextMenu()})
+}
+
+// Clear 清空菜单项
+func (m *ContextMenuBuilder) Clear() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.items = nil
+}
+
+// visibleItems 返回当前上下文显示的菜单项, 去掉多余的分隔线和空的子菜单
+func (m *ContextMenuBuilder) visibleItems(ctx *ContextMenuContext) []*ContextMenuItem {
+	m.lock.Lock()
+	items := make([]*ContextMenuItem, len(m.items))
+	copy(items, m.items)
+	m.lock.Unlock()
+	var result []*ContextMenuItem
+	for _, item := range items {
+		if item.itemType == ContextMenuItemSeparator {
+			if len(result) > 0 && result[len(result)-1].itemType != ContextMenuItemSeparator {
+				result = append(result, item)
+			}
+			continue
+		}
+		if !item.visible.test(ctx, true) {
+			continue
+		}
+		if item.itemType == ContextMenuItemSubMenu && len(item.subMenu.visibleItems(ctx)) == 0 {
+			continue
+		}
+		result = append(result, item)
+	}
+	if len(result) > 0 && result[len(result)-1].itemType == ContextMenuItemSeparator {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+// acceleratorItem 返回快捷键对应的显示且可用的菜单项, 包含子菜单
+func (m *ContextMenuBuilder) acceleratorItem(keyCode string, ctx *ContextMenuContext) *ContextMenuItem {
+	for _, item := range m.visibleItems(ctx) {
+		if item.itemType == ContextMenuItemSeparator || !item.enabled.test(ctx, true) {
+			continue
+		}
+		if item.itemType == ContextMenuItemSubMenu {
+			if result := item.subMenu.acceleratorItem(keyCode, ctx); result != nil {
+				return result
+			}
+		} else if item.keyCode != "" && item.keyCode == keyCode {
+			return item
+		}
+	}
+	return nil
+}
+
+// build 添加当前上下文显示的菜单项到 model
+func (m *ContextMenuBuilder) build(window IBrowserWindow, model *ICefMenuModel, ctx *ContextMenuContext) {
+	for _, item := range m.visibleItems(ctx) {
+		switch item.itemType {
+		case ContextMenuItemSeparator:
+			model.AddSeparator()
+		case ContextMenuItemSubMenu:
+			commandId := KeyAccelerator.NextCommandId()
+			if subModel := model.AddSubMenu(commandId, item.text); subModel != nil {
+				item.subMenu.build(window, subModel, ctx)
+			}
+			model.SetEnabled(commandId, item.enabled.test(ctx, true))
+		default:
+			var menuType = consts.CMT_NONE
+			if item.itemType == ContextMenuItemCheck {
+				menuType = consts.CMT_CHECK
+			} else if item.itemType == ContextMenuItemRadio {
+				menuType = consts.CMT_RADIO
+			}
+			onClick := item.onClick
+			// 快捷键由 contextMenus.accelerator 处理, 菜单只显示快捷键
+			menuItem := &MenuItem{
+				Text:     item.text,
+				Label:    item.label,
+				GroupId:  item.groupId,
+				MenuType: menuType,
+				Callback: func(browser *ICefBrowser, commandId consts.MenuId, params *ICefContextMenuParams, menuType consts.TCefContextMenuType, eventFlags uint32, result *bool) {
+					*result = true
+					if onClick != nil {
+						onClick(window, browser, newContextMenuContext(params))
+					}
+				},
+			}
+			if !model.AddMenuItem(menuItem) {
+				continue
+			}
+			if keyCode, shift, ctrl, alt, ok := parseAccelerator(item.accelerator); ok {
+				model.SetAccelerator(menuItem.CommandId, keyCode, shift, ctrl, alt)
+			}
+			model.SetEnabled(menuItem.CommandId, item.enabled.test(ctx, true))
+			if menuType != consts.CMT_NONE {
+				model.SetChecked(menuItem.CommandId, item.checked.test(ctx, false))
+			}
+		}
+	}
+}
+
+// contextMenuRegistry 全局、窗口和 JS 定义的右键菜单
+type contextMenuRegistry struct {
+	lock     sync.Mutex
+	global   *ContextMenuBuilder
+	browsers map[int32]*ContextMenuBuilder
+	scripts  map[int32]*ContextMenuBuilder
+}
+
+var contextMenus = &contextMenuRegistry{
+	browsers: make(map[int32]*ContextMenuBuilder),
+	scripts:  make(map[int32]*ContextMenuBuilder),
+}
+
+// SetContextMenu
+//	设置所有窗口的右键菜单, nil 时取消
+//	窗口设置了 SetBrowserContextMenu 时使用窗口的菜单
+func SetContextMenu(menu *ContextMenuBuilder) {
+	contextMenus.lock.Lock()
+	defer contextMenus.lock.Unlock()
+	contextMenus.global = menu
+}
+
+// SetBrowserContextMenu 设置指定浏览器的右键菜单, nil 时取消
+func SetBrowserContextMenu(browserId int32, menu *ContextMenuBuilder) {
+	contextMenus.lock.Lock()
+	defer contextMenus.lock.Unlock()
+	if menu == nil {
+		delete(contextMenus.browsers, browserId)
+	} else {
+		contextMenus.browsers[browserId] = menu
+	}
+}
+
+// menus 返回浏览器使用的 Go 菜单和 JS 菜单
+func (m *contextMenuRegistry) menus(browserId int32) (menu, script *ContextMenuBuilder) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if menu = m.browsers[browserId]; menu == nil {
+		menu = m.global
+	}
+	return menu, m.scripts[browserId]
+}
+
+// remove 浏览器关闭时移除菜单
+func (m *contextMenuRegistry) remove(browserId int32) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.browsers, browserId)
+	delete(m.scripts, browserId)
+}
+
+// accelerator
+//	按键事件, 触发浏览器使用的菜单中快捷键对应的菜单项, 不需要先显示菜单
+//	开发者工具和显示源代码窗口不处理
+func (m *contextMenuRegistry) accelerator(browser *ICefBrowser, keyCode string, result *bool) bool {
+	window := BrowserWindow.GetWindowInfo(browser.Identifier())
+	if window == nil || window.WindowType() == consts.WT_DEV_TOOLS || window.WindowType() == consts.WT_VIEW_SOURCE {
+		return false
+	}
+	menu, script := m.menus(browser.Identifier())
+	ctx := newContextMenuContext(nil)
+	for _, builder := range []*ContextMenuBuilder{menu, script} {
+		if builder == nil {
+			continue
+		}
+		if item := builder.acceleratorItem(keyCode, ctx); item != nil {
+			*result = true
+			if item.onClick != nil {
+				item.onClick(window, browser, ctx)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// apply 在默认菜单创建之后添加自定义菜单, 开发者工具和显示源代码窗口不添加
+func (m *contextMenuRegistry) apply(window IBrowserWindow, browser *ICefBrowser, params *ICefContextMenuParams, model *ICefMenuModel) {
+	if window.WindowType() == consts.WT_DEV_TOOLS || window.WindowType() == consts.WT_VIEW_SOURCE {
+		return
+	}
+	menu, script := m.menus(browser.Identifier())
+	if menu == nil && script == nil {
+		return
+	}
+	ctx := newContextMenuContext(params)
+	for _, builder := range []*ContextMenuBuilder{menu, script} {
+		if builder == nil {
+			continue
+		}
+		switch builder.mode {
+		case ContextMenuReplace:
+			model.Clear()
+			builder.build(window, model, ctx)
+		default:
+			if model.GetCount() > 0 && len(builder.visibleItems(ctx)) > 0 {
+				model.AddSeparator()
+			}
+			builder.build(window, model, ctx)
+		}
+	}
+}
+
+// contextMenuScriptItem JS 定义的菜单项
+type contextMenuScriptItem struct {
+	Id          string                  `json:"id"`
+	Type        ContextMenuItemType     `json:"type"`
+	Label       string                  `json:"label"`
+	Accelerator string                  `json:"accelerator"`
+	Group       int32                   `json:"group"`
+	Checked     bool                    `json:"checked"`
+	Disabled    bool                    `json:"disabled"`
+	When        []string                `json:"when"`
+	Items       []contextMenuScriptItem `json:"items"`
+}
+
+// contextMenuScript JS 定义的菜单
+type contextMenuScript struct {
+	Mode  string                  `json:"mode"`
+	Items []contextMenuScriptItem `json:"items"`
+}
+
+// contextMenuScriptCondition JS 菜单项的显示条件: selection link editable image video audio media, 前缀 ! 取反
+func contextMenuScriptCondition(when []string) ContextMenuCondition {
+	var conditions []ContextMenuCondition
+	for _, name := range when {
+		name = strings.ToLower(strings.TrimSpace(name))
+		not := strings.HasPrefix(name, "!")
+		name = strings.TrimPrefix(name, "!")
+		var condition ContextMenuCondition
+		switch name {
+		case "selection":
+			condition = WhenSelection
+		case "link":
+			condition = WhenLink
+		case "editable":
+			condition = WhenEditable
+		case "image":
+			condition = WhenImage
+		case "video":
+			condition = WhenMedia(consts.CM_MEDIATYPE_VIDEO)
+		case "audio":
+			condition = WhenMedia(consts.CM_MEDIATYPE_AUDIO)
+		case "media":
+			condition = WhenMedia()
+		default:
+			continue
+		}
+		if not {
+			condition = WhenNot(condition)
+		}
+		conditions = append(conditions, condition)
+	}
+	return contextMenuAll(nil, conditions)
+}
+
+// parseContextMenuScript 解析 JS 定义的菜单, 点击时调用 onClick(id)
+func parseContextMenuScript(data string, onClick func(id string, window IBrowserWindow, ctx *ContextMenuContext)) (*ContextMenuBuilder, error) {
+	var script contextMenuScript
+	if err := json.Unmarshal([]byte(data), &script); err != nil {
+		return nil, err
+	}
+	builder := NewContextMenu()
+	if script.Mode == "replace" {
+		builder.SetMode(ContextMenuReplace)
+	}
+	var add func(builder *ContextMenuBuilder, items []contextMenuScriptItem)
+	add = func(builder *ContextMenuBuilder, items []contextMenuScriptItem) {
+		for _, si := range items {
+			var item *ContextMenuItem
+			id := si.Id
+			click := func(window IBrowserWindow, browser *ICefBrowser, ctx *ContextMenuContext) {
+				onClick(id, window, ctx)
+			}
+			switch si.Type {
+			case ContextMenuItemSeparator:
+				builder.Separator()
+				continue
+			case ContextMenuItemSubMenu:
+				item = builder.SubMenuItem(si.Label)
+				add(item.subMenu, si.Items)
+			case ContextMenuItemCheck:
+				item = builder.Check(si.Label, click)
+			case ContextMenuItemRadio:
+				item = builder.Radio(si.Group, si.Label, click)
+			default:
+				item = builder.Item(si.Label, click)
+			}
+			checked, disabled := si.Checked, si.Disabled
+			item.Accelerator(si.Accelerator).Visible(contextMenuScriptCondition(si.When))
+			item.Checked(func(ctx *ContextMenuContext) bool { return checked })
+			item.Enabled(func(ctx *ContextMenuContext) bool { return !disabled })
+		}
+	}
+	add(builder, script.Items)
+	return builder, nil
+}
+
+// contextMenuBrowserInit
+//	主进程, 注册 JS 定义菜单的事件
+//	JS: ipc.emit("energy.contextmenu.set", [JSON.stringify({mode: "append|replace", items: [...]})]), 空字符串时取消
+//	在右键之前设置, 例如页面加载完成后, 浏览器关闭前一直有效
+//	菜单项: {id, type: item|check|radio|separator|submenu, label, accelerator, group, checked, disabled, when: ["selection", "!link"], items}
+//	点击时触发 JS 事件 ipc.on("energy.contextmenu.click", function(id, context){})
+func contextMenuBrowserInit() {
+	ipc.On(internalContextMenuSet, func(ctx context.IContext) {
+		argument := ctx.ArgumentList()
+		browserId := ctx.BrowserId()
+		var data string
+		if argument != nil && argument.Size() > 0 {
+			data = argument.GetStringByIndex(0)
+		}
+		contextMenus.lock.Lock()
+		defer contextMenus.lock.Unlock()
+		if strings.TrimSpace(data) == "" {
+			delete(contextMenus.scripts, browserId)
+			return
+		}
+		builder, err := parseContextMenuScript(data, func(id string, window IBrowserWindow, menuCtx *ContextMenuContext) {
+			if target := window.Target(); target != nil {
+				ipc.EmitTarget(internalContextMenuClick, target, id, menuCtx)
+			}
+		})
+		if err != nil {
+			logger.Error("context menu:", err)
+			return
+		}
+		contextMenus.scripts[browserId] = builder
+	})
+}
@@ -0,0 +1,89 @@
+package cef
+
+import (
+	"github.com/energye/energy/v2/consts"
+	"testing"
+)
+
+func TestContextMenuVisibleItems(t *testing.T) {
+	menu := NewContextMenu()
+	menu.Separator()
+	menu.Item("copy", nil).Visible(WhenSelection)
+	menu.Separator()
+	menu.Separator()
+	menu.Item("open link", nil).Visible(WhenLink)
+	sub := menu.SubMenu("image")
+	sub.Item("save image", nil).Visible(WhenImage)
+	menu.Separator()
+	texts := func(ctx *ContextMenuContext) (result []string) {
+		for _, item := range menu.visibleItems(ctx) {
+			result = append(result, string(item.itemType)+":"+item.text)
+		}
+		return
+	}
+	if result := texts(&ContextMenuContext{}); len(result) != 0 {
+		t.Fatal("unexpected items", result)
+	}
+	result := texts(&ContextMenuContext{SelectionText: "a", LinkURL: "https://energy.yanghy.cn"})
+	if len(result) != 3 || result[0] != "item:copy" || result[1] != "separator:" || result[2] != "item:open link" {
+		t.Fatal("unexpected items", result)
+	}
+	result = texts(&ContextMenuContext{MediaType: consts.CM_MEDIATYPE_IMAGE})
+	if len(result) != 1 || result[0] != "submenu:image" {
+		t.Fatal("unexpected items", result)
+	}
+}
+
+func TestParseContextMenuScript(t *testing.T) {
+	var clicked string
+	menu, err := parseContextMenuScript(`{"mode":"replace","items":[
+		{"id":"a","label":"A","when":["editable","!selection"]},
+		{"type":"separator"},
+		{"id":"b","type":"check","label":"B","checked":true,"disabled":true},
+		{"type":"submenu","label":"C","items":[{"id":"c1","type":"radio","group":1,"label":"C1"}]}
+	]}`, func(id string, window IBrowserWindow, ctx *ContextMenuContext) {
+		clicked = id
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if menu.mode != ContextMenuReplace {
+		t.Fatal("unexpected mode")
+	}
+	ctx := &ContextMenuContext{Editable: true}
+	items := menu.visibleItems(ctx)
+	if len(items) != 4 || items[2].checked.test(ctx, false) != true || items[2].enabled.test(ctx, true) != false {
+		t.Fatal("unexpected items", len(items))
+	}
+	if items = menu.visibleItems(&ContextMenuContext{Editable: true, SelectionText: "x"}); len(items) != 2 {
+		t.Fatal("unexpected items", len(items))
+	}
+	radio := menu.items[3].subMenu.items[0]
+	if radio.itemType != ContextMenuItemRadio || radio.groupId != 1 {
+		t.Fatal("unexpected radio item")
+	}
+	radio.onClick(nil, nil, ctx)
+	if clicked != "c1" {
+		t.Fatal("unexpected click", clicked)
+	}
+}
+
+func TestContextMenuAccelerator(t *testing.T) {
+	menu := NewContextMenu()
+	menu.Item("new", nil).Accelerator("ctrl + shift + n")
+	menu.Item("copy", nil).Accelerator("ctrl+c").Visible(WhenSelection)
+	menu.Item("save", nil).Accelerator("ctrl+s").Enabled(func(ctx *ContextMenuContext) bool { return false })
+	menu.SubMenu("tools").Item("reload", nil).Accelerator("alt+shift+r")
+	ctx := newContextMenuContext(nil)
+	for accelerator, text := range map[string]string{
+		acceleratorCode(true, true, false, 'N'):  "new",
+		acceleratorCode(true, false, true, 'R'):  "reload",
+		acceleratorCode(false, true, false, 'C'): "",
+		acceleratorCode(false, true, false, 'S'): "",
+	} {
+		item := menu.acceleratorItem(accelerator, ctx)
+		if text == "" && item != nil || text != "" && (item == nil || item.text != text) {
+			t.Errorf("%s: %+v", accelerator, item)
+		}
+	}
+}
@@ -0,0 +1,528 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cef
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/consts"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CookieFormat Cookie 导入导出格式
+type CookieFormat int8
+
+const (
+	CookieFormatJSON     CookieFormat = iota // JSON 数组
+	CookieFormatNetscape                     // Netscape cookies.txt, 不包含 SameSite 和 Priority
+)
+
+// Cookie 信息, 可以和 http.Cookie 相互转换
+type Cookie struct {
+	Name       string                    `json:"name"`
+	Value      string                    `json:"value"`
+	Domain     string                    `json:"domain"` // 以 . 开头时包含子域名, 否则只匹配该主机
+	Path       string                    `json:"path"`
+	Expires    time.Time                 `json:"expires,omitempty"` // 零值为会话 Cookie
+	Secure     bool                      `json:"secure"`
+	HttpOnly   bool                      `json:"httpOnly"`
+	SameSite   consts.TCefCookieSameSite `json:"sameSite"`
+	Priority   consts.TCefCookiePriority `json:"priority"`
+	Creation   time.Time                 `json:"creation,omitempty"`
+	LastAccess time.Time                 `json:"lastAccess,omitempty"`
+}
+
+// CookieSnapshot Cookie 快照, 恢复时替换所有 Cookie
+type CookieSnapshot struct {
+	Time    time.Time `json:"time"`
+	Cookies []Cookie  `json:"cookies"`
+}
+
+// CookieStore
+//	在 ICefCookieManager 之上的同步 Cookie 操作, 使用 context 设置超时
+//	等待 CEF 回调完成, 不能在 CEF UI 线程中调用, 在协程中使用
+//	回调在 CEF 完成时释放, 超时返回后 CEF 仍可以调用
+type CookieStore struct {
+	manager *ICefCookieManager
+}
+
+// ErrCookieStore Cookie 管理不可用
+var ErrCookieStore = errors.New("cookie manager is not available")
+
+// NewCookieStore 创建请求上下文的 Cookie 存储, context 为 nil 时使用全局请求上下文
+func NewCookieStore(context *ICefRequestContext) *CookieStore {
+	if context == nil {
+		context = RequestContextRef.Global()
+	}
+	return &CookieStore{manager: context.GetCookieManager(nil)}
+}
+
+// NewCookieStoreForManager 使用 ICefCookieManager 创建 Cookie 存储
+func NewCookieStoreForManager(manager *ICefCookieManager) *CookieStore {
+	return &CookieStore{manager: manager}
+}
+
+// cookieVisit 一次 VisitAllCookies, 在 CEF UI 线程中访问, 在调用协程中读取
+type cookieVisit struct {
+	lock     sync.Mutex
+	cookies  []Cookie
+	finished bool
+	done     chan bool
+}
+
+func (m *cookieVisit) visit(cookie *ICefCookie) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.finished {
+		return false
+	}
+	m.cookies = append(m.cookies, cookieFromCef(cookie))
+	if cookie.Count+1 >= cookie.Total {
+		m.finish()
+		return false
+	}
+	return true
+}
+
+// finish 访问结束, 调用时已加锁
+func (m *cookieVisit) finish() {
+	if !m.finished {
+		m.finished = true
+		close(m.done)
+	}
+}
+
+func (m *cookieVisit) result() []Cookie {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]Cookie(nil), m.cookies...)
+}
+
+// All
+//	返回所有 Cookie
+//	没有 Cookie 时 CEF 不会调用 ICefCookieVisitor, 访问后调用 FlushStore,
+//	CEF 按顺序执行 Cookie 操作, FlushStore 完成时访问已结束, 不修改 Cookie
+func (m *CookieStore) All(ctx context.Context) ([]Cookie, error) {
+	if !m.manager.IsValid() {
+		return nil, ErrCookieStore
+	}
+	visit := &cookieVisit{done: make(chan bool)}
+	visitor := CookieVisitorRef.New()
+	visitor.SetOnVisit(func(cookie *ICefCookie) (deleteCookie, result bool) {
+		return false, visit.visit(cookie)
+	})
+	if !m.manager.VisitAllCookies(visitor) {
+		visitor.Free()
+		return nil, errors.New("visit cookies: cookies are not accessible")
+	}
+	flushed := CompletionCallbackRef.New()
+	flushed.SetOnComplete(func() {
+		visit.lock.Lock()
+		visit.finish()
+		visit.lock.Unlock()
+		// CEF 不再使用访问器后释放, 超时返回时也不提前释放
+		visitor.Free()
+		flushed.Free()
+	})
+	if !m.manager.FlushStore(flushed) {
+		// 无法确定访问结束, 访问器由 CEF 持有, 只释放 Go 的引用
+		visitor.Free()
+		flushed.Free()
+		return nil, errors.New("visit cookies: cookies are not accessible")
+	}
+	select {
+	case <-visit.done:
+		return visit.result(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// URL 返回发送到 rawURL 的 Cookie
+func (m *CookieStore) URL(ctx context.Context, rawURL string, includeHttpOnly bool) ([]Cookie, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	cookies, err := m.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Cookie
+	for _, cookie := range cookies {
+		if (includeHttpOnly || !cookie.HttpOnly) && cookie.matchURL(target) {
+			result = append(result, cookie)
+		}
+	}
+	return result, nil
+}
+
+// Set 设置 Cookie, rawURL 为空时使用 Cookie 的 Domain 和 Path
+func (m *CookieStore) Set(ctx context.Context, rawURL string, cookie Cookie) error {
+	if !m.manager.IsValid() {
+		return ErrCookieStore
+	}
+	if rawURL == "" {
+		rawURL = cookie.URL()
+	}
+	return m.set(ctx, rawURL, cookie)
+}
+
+func (m *CookieStore) set(ctx context.Context, rawURL string, cookie Cookie) error {
+	now := time.Now()
+	creation, lastAccess, expires := cookie.Creation, cookie.LastAccess, cookie.Expires
+	if creation.IsZero() {
+		creation = now
+	}
+	if lastAccess.IsZero() {
+		lastAccess = now
+	}
+	hasExpires := !expires.IsZero()
+	if !hasExpires {
+		expires = now
+	}
+	// 只有以 . 开头的 Domain 才是域 Cookie, 其它为主机 Cookie
+	var domain string
+	if strings.HasPrefix(cookie.Domain, ".") {
+		domain = cookie.Domain
+	}
+	done := make(chan bool, 1)
+	callback := SetCookieHandlerRef.New()
+	callback.SetOnComplete(func(success int32) {
+		callback.Free()
+		done <- success != 0
+	})
+	if !m.manager.SetCookie(rawURL, cookie.Name, cookie.Value, domain, cookie.Path, cookie.Secure, cookie.HttpOnly, hasExpires,
+		creation, lastAccess, expires, cookie.SameSite, cookie.Priority, callback) {
+		callback.Free()
+		return fmt.Errorf("set cookie %s: invalid cookie or url %s", cookie.Name, rawURL)
+	}
+	select {
+	case success := <-done:
+		if !success {
+			return fmt.Errorf("set cookie %s: rejected for %s", cookie.Name, rawURL)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Delete 删除 Cookie, rawURL 和 name 为空时删除所有 Cookie, name 为空时删除 rawURL 的所有 Cookie
+func (m *CookieStore) Delete(ctx context.Context, rawURL, name string) error {
+	if !m.manager.IsValid() {
+		return ErrCookieStore
+	}
+	done := make(chan bool, 1)
+	callback := DeleteCookiesHandlerRef.New()
+	callback.SetOnComplete(func(success bool) {
+		callback.Free()
+		done <- success
+	})
+	if !m.manager.DeleteCookies(rawURL, name, callback) {
+		callback.Free()
+		return fmt.Errorf("delete cookies: invalid url %s", rawURL)
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush 将 Cookie 写入磁盘
+func (m *CookieStore) Flush(ctx context.Context) error {
+	if !m.manager.IsValid() {
+		return ErrCookieStore
+	}
+	done := make(chan bool, 1)
+	callback := CompletionCallbackRef.New()
+	callback.SetOnComplete(func() {
+		callback.Free()
+		done <- true
+	})
+	if !m.manager.FlushStore(callback) {
+		callback.Free()
+		return errors.New("flush cookies: cookies are not accessible")
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Snapshot 返回所有 Cookie 的快照
+func (m *CookieStore) Snapshot(ctx context.Context) (*CookieSnapshot, error) {
+	cookies, err := m.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &CookieSnapshot{Time: time.Now(), Cookies: cookies}, nil
+}
+
+// Restore 删除所有 Cookie 后恢复快照中的 Cookie
+func (m *CookieStore) Restore(ctx context.Context, snapshot *CookieSnapshot) error {
+	if err := m.Delete(ctx, "", ""); err != nil {
+		return err
+	}
+	return m.setAll(ctx, snapshot.Cookies)
+}
+
+// Export 导出所有 Cookie
+func (m *CookieStore) Export(ctx context.Context, w io.Writer, format CookieFormat) error {
+	cookies, err := m.All(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := MarshalCookies(cookies, format)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Import 导入 Cookie, 已过期的 Cookie 被忽略
+func (m *CookieStore) Import(ctx context.Context, r io.Reader, format CookieFormat) error {
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return err
+	}
+	cookies, err := UnmarshalCookies(buf.Bytes(), format)
+	if err != nil {
+		return err
+	}
+	return m.setAll(ctx, cookies)
+}
+
+func (m *CookieStore) setAll(ctx context.Context, cookies []Cookie) error {
+	now := time.Now()
+	for _, cookie := range cookies {
+		if !cookie.Expires.IsZero() && cookie.Expires.Before(now) {
+			continue
+		}
+		if err := m.Set(ctx, "", cookie); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// URL 返回设置 Cookie 使用的 URL
+func (m *Cookie) URL() string {
+	scheme := "http"
+	if m.Secure {
+		scheme = "https"
+	}
+	path := m.Path
+	if path == "" {
+		path = "/"
+	}
+	return scheme + "://" + strings.TrimPrefix(m.Domain, ".") + path
+}
+
+// HTTPCookie 转换为 http.Cookie
+func (m *Cookie) HTTPCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     m.Name,
+		Value:    m.Value,
+		Path:     m.Path,
+		Domain:   m.Domain,
+		Expires:  m.Expires,
+		Secure:   m.Secure,
+		HttpOnly: m.HttpOnly,
+	}
+	switch m.SameSite {
+	case consts.Ccss_CEF_COOKIE_SAME_SITE_NO_RESTRICTION:
+		cookie.SameSite = http.SameSiteNoneMode
+	case consts.Ccss_CEF_COOKIE_SAME_SITE_LAX_MODE:
+		cookie.SameSite = http.SameSiteLaxMode
+	case consts.Ccss_CEF_COOKIE_SAME_SITE_STRICT_MODE:
+		cookie.SameSite = http.SameSiteStrictMode
+	}
+	return cookie
+}
+
+// CookieFromHTTP 从 http.Cookie 转换
+func CookieFromHTTP(cookie *http.Cookie) Cookie {
+	result := Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Domain:   cookie.Domain,
+		Path:     cookie.Path,
+		Expires:  cookie.Expires,
+		Secure:   cookie.Secure,
+		HttpOnly: cookie.HttpOnly,
+		Priority: consts.CEF_COOKIE_PRIORITY_MEDIUM,
+	}
+	if cookie.MaxAge > 0 {
+		result.Expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+	}
+	switch cookie.SameSite {
+	case http.SameSiteNoneMode:
+		result.SameSite = consts.Ccss_CEF_COOKIE_SAME_SITE_NO_RESTRICTION
+	case http.SameSiteLaxMode:
+		result.SameSite = consts.Ccss_CEF_COOKIE_SAME_SITE_LAX_MODE
+	case http.SameSiteStrictMode:
+		result.SameSite = consts.Ccss_CEF_COOKIE_SAME_SITE_STRICT_MODE
+	}
+	return result
+}
+
+func cookieFromCef(cookie *ICefCookie) Cookie {
+	result := Cookie{
+		Name:       cookie.Name,
+		Value:      cookie.Value,
+		Domain:     cookie.Domain,
+		Path:       cookie.Path,
+		Secure:     cookie.Secure,
+		HttpOnly:   cookie.Httponly,
+		SameSite:   cookie.SameSite,
+		Priority:   cookie.Priority,
+		Creation:   cookie.Creation,
+		LastAccess: cookie.LastAccess,
+	}
+	if cookie.HasExpires {
+		result.Expires = cookie.Expires
+	}
+	return result
+}
+
+// matchURL Cookie 是否发送到 target
+func (m *Cookie) matchURL(target *url.URL) bool {
+	if m.Secure && target.Scheme != "https" && target.Scheme != "wss" {
+		return false
+	}
+	host := strings.ToLower(target.Hostname())
+	domain := strings.ToLower(m.Domain)
+	if strings.HasPrefix(domain, ".") {
+		if host != domain[1:] && !strings.HasSuffix(host, domain) {
+			return false
+		}
+	} else if host != domain {
+		return false
+	}
+	path := target.Path
+	if path == "" {
+		path = "/"
+	}
+	if m.Path == "" || m.Path == "/" || path == m.Path {
+		return true
+	}
+	return strings.HasPrefix(path, m.Path) && (strings.HasSuffix(m.Path, "/") || path[len(m.Path)] == '/')
+}
+
+// MarshalCookies 按格式序列化 Cookie
+func MarshalCookies(cookies []Cookie, format CookieFormat) ([]byte, error) {
+	switch format {
+	case CookieFormatJSON:
+		if cookies == nil {
+			cookies = []Cookie{}
+		}
+		return json.MarshalIndent(cookies, "", "  ")
+	case CookieFormatNetscape:
+		var buf bytes.Buffer
+		buf.WriteString("# Netscape HTTP Cookie File\n\n")
+		for _, cookie := range cookies {
+			domain := cookie.Domain
+			if cookie.HttpOnly {
+				domain = "#HttpOnly_" + domain
+			}
+			var expires int64
+			if !cookie.Expires.IsZero() {
+				expires = cookie.Expires.Unix()
+			}
+			path := cookie.Path
+			if path == "" {
+				path = "/"
+			}
+			fmt.Fprintf(&buf, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", domain, netscapeBool(strings.HasPrefix(cookie.Domain, ".")),
+				path, netscapeBool(cookie.Secure), expires, cookie.Name, cookie.Value)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported cookie format %d", format)
+}
+
+// UnmarshalCookies 按格式解析 Cookie
+func UnmarshalCookies(data []byte, format CookieFormat) ([]Cookie, error) {
+	switch format {
+	case CookieFormatJSON:
+		var cookies []Cookie
+		if err := json.Unmarshal(data, &cookies); err != nil {
+			return nil, err
+		}
+		return cookies, nil
+	case CookieFormatNetscape:
+		var cookies []Cookie
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimRight(scanner.Text(), "\r")
+			var httpOnly bool
+			if strings.HasPrefix(text, "#HttpOnly_") {
+				text = strings.TrimPrefix(text, "#HttpOnly_")
+				httpOnly = true
+			} else if text == "" || strings.HasPrefix(text, "#") {
+				continue
+			}
+			fields := strings.Split(text, "\t")
+			if len(fields) == 6 {
+				// 没有值
+				fields = append(fields, "")
+			}
+			if len(fields) != 7 {
+				return nil, fmt.Errorf("cookies.txt line %d: expected 7 fields, got %d", line, len(fields))
+			}
+			expires, err := strconv.ParseInt(fields[4], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cookies.txt line %d: invalid expires %q", line, fields[4])
+			}
+			cookie := Cookie{
+				Domain:   fields[0],
+				Path:     fields[2],
+				Secure:   strings.EqualFold(fields[3], "TRUE"),
+				Name:     fields[5],
+				Value:    fields[6],
+				HttpOnly: httpOnly,
+				Priority: consts.CEF_COOKIE_PRIORITY_MEDIUM,
+			}
+			if strings.EqualFold(fields[1], "TRUE") && !strings.HasPrefix(cookie.Domain, ".") {
+				cookie.Domain = "." + cookie.Domain
+			}
+			if expires > 0 {
+				cookie.Expires = time.Unix(expires, 0)
+			}
+			cookies = append(cookies, cookie)
+		}
+		return cookies, scanner.Err()
+	}
+	return nil, fmt.Errorf("unsupported cookie format %d", format)
+}
+
+func netscapeBool(v bool) string {
+	if v {
+		return "TRUE"
+	}
+	return "FALSE"
+}
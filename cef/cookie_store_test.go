@@ -0,0 +1,108 @@
+package cef
+
+import (
+	"github.com/energye/energy/v2/consts"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieFormat(t *testing.T) {
+	expires := time.Unix(1900000000, 0)
+	cookies := []Cookie{
+		{Name: "sid", Value: "abc", Domain: ".example.com", Path: "/", Expires: expires, Secure: true, HttpOnly: true, Priority: consts.CEF_COOKIE_PRIORITY_MEDIUM},
+		{Name: "lang", Value: "zh", Domain: "www.example.com", Path: "/app", Priority: consts.CEF_COOKIE_PRIORITY_MEDIUM},
+		{Name: "empty", Domain: "localhost", Path: "/", Priority: consts.CEF_COOKIE_PRIORITY_MEDIUM},
+	}
+	data, err := MarshalCookies(cookies, CookieFormatNetscape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "#HttpOnly_.example.com\tTRUE\t/\tTRUE\t1900000000\tsid\tabc\n") {
+		t.Fatal("unexpected cookies.txt", string(data))
+	}
+	result, err := UnmarshalCookies(data, CookieFormatNetscape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result, cookies) {
+		t.Fatal("unexpected cookies", result)
+	}
+	if data, err = MarshalCookies(cookies, CookieFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = UnmarshalCookies(data, CookieFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 3 || !result[0].Expires.Equal(expires) || !result[1].Expires.IsZero() || result[2].Name != "empty" {
+		t.Fatal("unexpected cookies", result)
+	}
+	// curl 格式, 没有 #HttpOnly_ 前缀, 包含子域名但 Domain 没有 .
+	if result, err = UnmarshalCookies([]byte("# comment\r\nexample.com\tTRUE\t/\tFALSE\t0\ta\tb\r\n"), CookieFormatNetscape); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || result[0].Domain != ".example.com" || !result[0].Expires.IsZero() || result[0].Value != "b" {
+		t.Fatal("unexpected cookies", result)
+	}
+	if _, err = UnmarshalCookies([]byte("example.com\tTRUE\t/\n"), CookieFormatNetscape); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCookieMatchURL(t *testing.T) {
+	cases := []struct {
+		cookie Cookie
+		url    string
+		match  bool
+	}{
+		{Cookie{Domain: ".example.com", Path: "/"}, "http://example.com/", true},
+		{Cookie{Domain: ".example.com", Path: "/"}, "http://a.b.example.com/x", true},
+		{Cookie{Domain: ".example.com", Path: "/"}, "http://badexample.com/", false},
+		{Cookie{Domain: "example.com", Path: "/"}, "http://www.example.com/", false},
+		{Cookie{Domain: "example.com", Path: "/api"}, "http://example.com/api/v1", true},
+		{Cookie{Domain: "example.com", Path: "/api"}, "http://example.com/apis", false},
+		{Cookie{Domain: "example.com", Path: "/", Secure: true}, "http://example.com/", false},
+		{Cookie{Domain: "example.com", Path: "/", Secure: true}, "https://example.com:8443/", true},
+	}
+	for _, c := range cases {
+		target, _ := url.Parse(c.url)
+		if c.cookie.matchURL(target) != c.match {
+			t.Fatal("unexpected match", c.cookie, c.url)
+		}
+	}
+}
+
+func TestCookieHTTP(t *testing.T) {
+	cookie := CookieFromHTTP(&http.Cookie{Name: "a", Value: "b", Domain: ".example.com", Path: "/", Secure: true, SameSite: http.SameSiteLaxMode})
+	if cookie.SameSite != consts.Ccss_CEF_COOKIE_SAME_SITE_LAX_MODE || cookie.URL() != "https://example.com/" {
+		t.Fatal("unexpected cookie", cookie)
+	}
+	if httpCookie := cookie.HTTPCookie(); httpCookie.SameSite != http.SameSiteLaxMode || httpCookie.String() != "a=b; Path=/; Domain=example.com; Secure; SameSite=Lax" {
+		t.Fatal("unexpected http cookie", httpCookie.String())
+	}
+}
+
+func TestCookieVisit(t *testing.T) {
+	visit := &cookieVisit{done: make(chan bool)}
+	if !visit.visit(&ICefCookie{Name: "a", Count: 0, Total: 2}) {
+		t.Fatal("expected next cookie")
+	}
+	result := visit.result()
+	if visit.visit(&ICefCookie{Name: "b", Count: 1, Total: 2}) {
+		t.Fatal("expected last cookie")
+	}
+	<-visit.done
+	// 结束后的访问和屏障不再修改结果
+	if visit.visit(&ICefCookie{Name: "c", Count: 0, Total: 1}) {
+		t.Fatal("visit after finish")
+	}
+	visit.lock.Lock()
+	visit.finish()
+	visit.lock.Unlock()
+	if len(result) != 1 || len(visit.result()) != 2 || visit.result()[1].Name != "b" {
+		t.Fatal("unexpected cookies", visit.result())
+	}
+}
@@ -0,0 +1,109 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 崩溃报告
+//  Go panic: IPC 事件、Chromium 和窗口事件、LCL 通知和关闭事件
+//  底层库异常、渲染进程终止
+
+package cef
+
+import (
+	"fmt"
+	"github.com/energye/energy/v2/cef/internal/exception"
+	"github.com/energye/energy/v2/cef/process"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/energy/v2/pkgs/crash"
+	"github.com/energye/golcl/lcl"
+	"github.com/energye/golcl/lcl/types"
+	"unsafe"
+)
+
+// renderProcessTerminationStatus 渲染进程终止原因
+var renderProcessTerminationStatus = map[consts.TCefTerminationStatus]string{
+	consts.TS_ABNORMAL_TERMINATION: "abnormal termination",
+	consts.TS_PROCESS_WAS_KILLED:   "process was killed",
+	consts.TS_PROCESS_CRASHED:      "process crashed",
+	consts.TS_PROCESS_OOM:          "out of memory",
+}
+
+// crashReportChromium EnableCrashReport 设置, 之后创建的 Chromium 记录渲染进程终止
+var crashReportChromium bool
+
+// EnableCrashReport
+//	启用崩溃报告, 在 cef.Run 之前调用, 主进程和子进程都需要
+//	config.ProcessType 为当前进程类型, 报告写入 config.Dir, 默认 [用户缓存目录]/[应用名称]/crash
+//	主进程正常退出时结束会话, 下次启动时上次没有正常退出则调用 config.OnPreviousCrash
+func EnableCrashReport(config crash.Config) error {
+	config.ProcessType = string(process.Args.ProcessType())
+	versions := config.Versions
+	config.Versions = func() map[string]string {
+		result := make(map[string]string)
+		if application != nil {
+			result["cef"] = application.LibCefVersion()
+			result["chrome"] = application.ChromeVersion()
+		}
+		if versions != nil {
+			for name, value := range versions() {
+				result[name] = value
+			}
+		}
+		return result
+	}
+	if err := crash.Init(config); err != nil {
+		return err
+	}
+	exception.HandlerInit(func(message string) {
+		crash.Capture(crash.KindNative, "liblcl", message, false, nil)
+	})
+	lcl.RegisterExtEventCallback(crashEventCallback)
+	crashReportChromium = true
+	return nil
+}
+
+// crashEventCallback
+//	捕获 LCL 通知和关闭事件中的 panic
+//	调用前 handled 设置为 true, panic 恢复后仍返回 true, golcl 不再调用一次事件
+func crashEventCallback(fn interface{}, getVal func(idx int) uintptr) (handled bool) {
+	handled = true
+	defer crash.Recover("event")
+	getPtr := func(i int) unsafe.Pointer {
+		return unsafe.Pointer(getVal(i))
+	}
+	switch fn.(type) {
+	case lcl.TNotifyEvent:
+		fn.(lcl.TNotifyEvent)(lcl.AsObject(getVal(0)))
+	case lcl.TCloseEvent:
+		fn.(lcl.TCloseEvent)(lcl.AsObject(getVal(0)), (*types.TCloseAction)(getPtr(1)))
+	case lcl.TCloseQueryEvent:
+		fn.(lcl.TCloseQueryEvent)(lcl.AsObject(getVal(0)), (*bool)(getPtr(1)))
+	default:
+		return false
+	}
+	return true
+}
+
+// crashRenderProcessTerminated 渲染进程终止时写入报告
+func crashRenderProcessTerminated(browser *ICefBrowser, status consts.TCefTerminationStatus) {
+	if !crash.Enabled() {
+		return
+	}
+	reason, ok := renderProcessTerminationStatus[status]
+	if !ok {
+		reason = fmt.Sprintf("status %d", status)
+	}
+	extra := map[string]string{"status": fmt.Sprint(status)}
+	if browser != nil && browser.IsValid() {
+		extra["browserId"] = fmt.Sprint(browser.Identifier())
+		if frame := browser.MainFrame(); frame != nil && frame.IsValid() {
+			extra["url"] = frame.Url()
+		}
+	}
+	crash.Capture(crash.KindRenderProcess, "renderer", "render process terminated: "+reason, false, extra)
+}
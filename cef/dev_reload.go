@@ -0,0 +1,61 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// energy dev 开发模式重新加载
+
+package cef
+
+import (
+	"bufio"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/energy/v2/logger"
+	"net"
+	"os"
+	"strings"
+)
+
+// energy dev 启动应用时设置的重新加载服务地址
+const devServerEnvKey = "ENERGY_DEV_SERVER"
+
+// devReloadBrowserInit
+//	主进程, 由 energy dev 启动时连接重新加载服务
+//	前端资源改变时收到 reload, 重新加载所有窗口
+func devReloadBrowserInit() {
+	addr := os.Getenv(devServerEnvKey)
+	if addr == "" {
+		return
+	}
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		logger.Error("energy dev:", err)
+		return
+	}
+	go func() {
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			if strings.TrimSpace(scanner.Text()) == "reload" {
+				RunOnMainThread(devReloadWindows)
+			}
+		}
+	}()
+}
+
+// devReloadWindows 重新加载所有窗口, 开发者工具和显示源代码窗口除外
+func devReloadWindows() {
+	for _, window := range BrowserWindow.GetWindowInfos() {
+		if window.IsClosing() || window.WindowType() == consts.WT_DEV_TOOLS || window.WindowType() == consts.WT_VIEW_SOURCE {
+			continue
+		}
+		if chromium := window.Chromium(); chromium != nil {
+			chromium.ReloadIgnoreCache()
+		}
+	}
+}
@@ -0,0 +1,584 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cef
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/energye/energy/v2/cef/internal/ipc"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/energy/v2/logger"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DownloadState 下载状态
+type DownloadState string
+
+const (
+	DownloadStateQueued      DownloadState = "queued"      // 排队, 超过同时下载数量时等待
+	DownloadStateInProgress  DownloadState = "progress"    // 下载中
+	DownloadStatePaused      DownloadState = "paused"      // 已暂停
+	DownloadStateInterrupted DownloadState = "interrupted" // 已中断, 可以恢复或取消
+	DownloadStateComplete    DownloadState = "complete"    // 下载完成
+	DownloadStateCanceled    DownloadState = "canceled"    // 已取消
+)
+
+// Download 下载信息
+type Download struct {
+	Id            uint32        `json:"id"`
+	BrowserId     int32         `json:"browserId"`
+	URL           string        `json:"url"`
+	FileName      string        `json:"fileName"`
+	FullPath      string        `json:"fullPath"`
+	MimeType      string        `json:"mimeType"`
+	TotalBytes    int64         `json:"totalBytes"`
+	ReceivedBytes int64         `json:"receivedBytes"`
+	Percent       int32         `json:"percent"`
+	Speed         int64         `json:"speed"`
+	State         DownloadState `json:"state"`
+	StartTime     time.Time     `json:"startTime"`
+	EndTime       time.Time     `json:"endTime"`
+}
+
+// Done 下载已结束, 完成或取消
+func (m *Download) Done() bool {
+	return m.State == DownloadStateComplete || m.State == DownloadStateCanceled
+}
+
+// DownloadSavePath
+//	自定义保存路径, 返回空路径时使用默认规则
+//	ask: 是否显示保存对话框
+type DownloadSavePath func(download Download, suggestedName string) (path string, ask bool)
+
+type downloadAction int8
+
+const (
+	downloadActionNone downloadAction = iota
+	downloadActionPause
+	downloadActionResume
+	downloadActionCancel
+)
+
+// downloadEntry 正在进行的下载
+type downloadEntry struct {
+	Download
+	callback *ICefDownloadItemCallback // 第一次更新时保留引用, 下载结束时释放
+	paused   bool                      // 用户暂停
+	queued   bool                      // 排队等待
+	action   downloadAction            // 获得回调之前的操作
+}
+
+// 按 MimeType 保存的目录
+type downloadMimeDir struct {
+	mimeType string
+	dir      string
+}
+
+// DownloadManager
+//	下载管理, 跟踪所有窗口的下载
+//	保存路径规则: 自定义函数 > MimeType 目录 > 默认目录, 可设置显示保存对话框
+//	支持暂停、恢复、取消, 限制同时下载数量, 下载记录保存到文件
+//	下载状态通过 Go 回调和 IPC 事件通知, IPC 事件发送到下载所在的窗口
+type DownloadManager struct {
+	lock          sync.Mutex
+	dir           string
+	ask           bool
+	mimeDirs      []downloadMimeDir
+	savePath      DownloadSavePath
+	maxConcurrent int
+	historyFile   string
+	maxHistory    int
+	ipcEvent      string
+	active        []*downloadEntry
+	history       []Download
+	onUpdated     func(download Download)
+	onDone        func(download Download)
+}
+
+// 启用的下载管理, 在默认的下载事件中使用
+var downloadManager *DownloadManager
+
+// NewDownloadManager 创建下载管理, 调用 Enable 后生效
+func NewDownloadManager() *DownloadManager {
+	return &DownloadManager{ipcEvent: "energy.download", maxHistory: 100}
+}
+
+// Enable
+//	启用下载管理, 所有窗口的默认下载事件使用该下载管理, 在创建窗口之前调用
+//	设置了 BrowserEvent.SetOnBeforeDownload 时由该事件决定保存路径, 下载仍然会被跟踪
+//	IPC 事件名不为空时注册 JS 调用的事件:
+//	  事件名.pause(id) 暂停, 事件名.resume(id) 恢复, 事件名.cancel(id) 取消, 事件名.list() 返回所有下载
+func (m *DownloadManager) Enable() {
+	downloadManager = m
+	if m.ipcEvent != "" {
+		ipc.On(m.ipcEvent+".pause", func(id int) bool {
+			return m.Pause(uint32(id))
+		})
+		ipc.On(m.ipcEvent+".resume", func(id int) bool {
+			return m.Resume(uint32(id))
+		})
+		ipc.On(m.ipcEvent+".cancel", func(id int) bool {
+			return m.Cancel(uint32(id))
+		})
+		ipc.On(m.ipcEvent+".list", func() []Download {
+			return m.Downloads()
+		})
+	}
+}
+
+// SetDir 设置默认保存目录, 默认当前执行文件所在目录
+func (m *DownloadManager) SetDir(dir string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.dir = dir
+}
+
+// SetAsk 设置是否显示保存对话框
+func (m *DownloadManager) SetAsk(ask bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.ask = ask
+}
+
+// AddMimeDir 按 MimeType 设置保存目录, 按添加顺序匹配, 例: image/*, application/pdf
+func (m *DownloadManager) AddMimeDir(mimeType, dir string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.mimeDirs = append(m.mimeDirs, downloadMimeDir{mimeType: strings.ToLower(mimeType), dir: dir})
+}
+
+// SetSavePath 设置自定义保存路径
+func (m *DownloadManager) SetSavePath(fn DownloadSavePath) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.savePath = fn
+}
+
+// SetMaxConcurrent 设置同时下载数量, 超过时排队等待, 0 不限制
+func (m *DownloadManager) SetMaxConcurrent(max int) {
+	m.lock.Lock()
+	m.maxConcurrent = max
+	ops, changed := m.schedule()
+	m.lock.Unlock()
+	m.run(ops, changed)
+}
+
+// SetMaxHistory 设置保存的下载记录数量, 默认 100
+func (m *DownloadManager) SetMaxHistory(max int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.maxHistory = max
+	m.trimHistory()
+}
+
+// SetIPCEvent 设置 IPC 事件名, 默认 energy.download, 空不发送 IPC 事件, 在 Enable 之前设置
+func (m *DownloadManager) SetIPCEvent(name string) {
+	m.ipcEvent = name
+}
+
+// SetOnUpdated 下载状态更新回调
+func (m *DownloadManager) SetOnUpdated(fn func(download Download)) {
+	m.onUpdated = fn
+}
+
+// SetOnDone 下载完成或取消回调
+func (m *DownloadManager) SetOnDone(fn func(download Download)) {
+	m.onDone = fn
+}
+
+// SetHistoryFile 设置下载记录文件, 文件存在时加载下载记录
+func (m *DownloadManager) SetHistoryFile(path string) error {
+	var history []Download
+	if data, err := ioutil.ReadFile(path); err == nil {
+		if err = json.Unmarshal(data, &history); err != nil {
+			return fmt.Errorf("download history %s: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.historyFile = path
+	m.history = history
+	m.trimHistory()
+	return nil
+}
+
+// Downloads 返回正在进行的下载和下载记录, 下载记录在后
+func (m *DownloadManager) Downloads() []Download {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	result := make([]Download, 0, len(m.active)+len(m.history))
+	for _, entry := range m.active {
+		result = append(result, entry.Download)
+	}
+	return append(result, m.history...)
+}
+
+// Get 返回正在进行的下载
+func (m *DownloadManager) Get(id uint32) (Download, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if entry := m.find(id); entry != nil {
+		return entry.Download, true
+	}
+	return Download{}, false
+}
+
+// ClearHistory 清空下载记录
+func (m *DownloadManager) ClearHistory() error {
+	m.lock.Lock()
+	m.history = nil
+	file := m.historyFile
+	m.lock.Unlock()
+	return saveDownloadHistory(file, nil)
+}
+
+// Pause 暂停下载
+func (m *DownloadManager) Pause(id uint32) bool {
+	return m.control(id, downloadActionPause)
+}
+
+// Resume 恢复暂停或中断的下载
+func (m *DownloadManager) Resume(id uint32) bool {
+	return m.control(id, downloadActionResume)
+}
+
+// Cancel 取消下载
+func (m *DownloadManager) Cancel(id uint32) bool {
+	return m.control(id, downloadActionCancel)
+}
+
+func (m *DownloadManager) control(id uint32, action downloadAction) bool {
+	m.lock.Lock()
+	entry := m.find(id)
+	if entry == nil {
+		m.lock.Unlock()
+		return false
+	}
+	var ops []func()
+	if entry.callback == nil {
+		// 还没有开始下载, 第一次更新时执行
+		entry.action = action
+	} else {
+		ops = m.apply(entry, action)
+	}
+	changed := []Download{entry.Download}
+	scheduleOps, scheduled := m.schedule()
+	m.lock.Unlock()
+	m.run(append(ops, scheduleOps...), append(changed, scheduled...))
+	return true
+}
+
+// apply
+//	执行操作, 返回在锁外执行的回调函数
+//	回调函数可能同步触发下载更新事件, 不能在锁内执行
+func (m *DownloadManager) apply(entry *downloadEntry, action downloadAction) (ops []func()) {
+	callback := entry.callback
+	switch action {
+	case downloadActionPause:
+		if entry.State == DownloadStateInterrupted {
+			return
+		}
+		entry.paused, entry.queued = true, false
+		entry.State = DownloadStatePaused
+		ops = append(ops, callback.Pause)
+	case downloadActionResume:
+		entry.paused = false
+		if entry.State != DownloadStateInterrupted && m.full(entry) {
+			// 已经是暂停状态, 等待调度
+			entry.queued = true
+			entry.State = DownloadStateQueued
+		} else {
+			entry.queued = false
+			entry.State = DownloadStateInProgress
+			ops = append(ops, callback.Resume)
+		}
+	case downloadActionCancel:
+		ops = append(ops, callback.Cancel)
+	}
+	return
+}
+
+// full 除 except 外正在下载的数量已达到同时下载数量
+func (m *DownloadManager) full(except *downloadEntry) bool {
+	if m.maxConcurrent <= 0 {
+		return false
+	}
+	var running int
+	for _, entry := range m.active {
+		if entry != except && entry.State == DownloadStateInProgress {
+			running++
+		}
+	}
+	return running >= m.maxConcurrent
+}
+
+// schedule 恢复排队的下载
+func (m *DownloadManager) schedule() (ops []func(), changed []Download) {
+	for _, entry := range m.active {
+		if !entry.queued || entry.callback == nil || m.full(nil) {
+			continue
+		}
+		entry.queued = false
+		entry.State = DownloadStateInProgress
+		ops = append(ops, entry.callback.Resume)
+		changed = append(changed, entry.Download)
+	}
+	return
+}
+
+func (m *DownloadManager) find(id uint32) *downloadEntry {
+	for _, entry := range m.active {
+		if entry.Id == id {
+			return entry
+		}
+	}
+	return nil
+}
+
+// entry 返回或创建正在进行的下载
+func (m *DownloadManager) entry(browserId int32, id uint32) *downloadEntry {
+	entry := m.find(id)
+	if entry == nil {
+		entry = &downloadEntry{Download: Download{Id: id, BrowserId: browserId}}
+		m.active = append(m.active, entry)
+	}
+	return entry
+}
+
+// resolvePath 按保存规则返回保存路径
+func (m *DownloadManager) resolvePath(download Download, suggestedName string) (string, bool) {
+	m.lock.Lock()
+	savePath, ask, dir := m.savePath, m.ask, m.dir
+	mimeType := strings.ToLower(download.MimeType)
+	for _, mimeDir := range m.mimeDirs {
+		if wildcardMatch(mimeDir.mimeType, mimeType) {
+			dir = mimeDir.dir
+			break
+		}
+	}
+	m.lock.Unlock()
+	if savePath != nil {
+		if path, ok := savePath(download, suggestedName); path != "" {
+			return path, ok
+		}
+	}
+	if dir == "" {
+		dir = consts.ExeDir
+	}
+	if suggestedName == "" {
+		suggestedName = "download"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logger.Error("download mkdir:", err)
+	}
+	return m.uniquePath(filepath.Join(dir, filepath.Base(suggestedName))), ask
+}
+
+// uniquePath 文件已存在或正在下载到该路径时, 添加序号 name (1).ext
+func (m *DownloadManager) uniquePath(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 1; ; i++ {
+		if !m.pathUsed(path) {
+			return path
+		}
+		path = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+}
+
+func (m *DownloadManager) pathUsed(path string) bool {
+	if _, err := os.Stat(path); err == nil {
+		return true
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, entry := range m.active {
+		if entry.FullPath == path {
+			return true
+		}
+	}
+	return false
+}
+
+// beforeDownload 按保存规则设置保存路径
+func (m *DownloadManager) beforeDownload(browser *ICefBrowser, item *ICefDownloadItem, suggestedName string, callback *ICefBeforeDownloadCallback) {
+	m.lock.Lock()
+	entry := m.entry(browser.Identifier(), item.Id())
+	entry.read(item)
+	download := entry.Download
+	m.lock.Unlock()
+	path, ask := m.resolvePath(download, suggestedName)
+	m.lock.Lock()
+	entry.FullPath = path
+	entry.FileName = filepath.Base(path)
+	entry.State = DownloadStateInProgress
+	download = entry.Download
+	m.lock.Unlock()
+	m.run(nil, []Download{download})
+	callback.Cont(path, ask)
+}
+
+// downloadUpdated 下载状态更新
+func (m *DownloadManager) downloadUpdated(browser *ICefBrowser, item *ICefDownloadItem, callback *ICefDownloadItemCallback) {
+	if !item.IsValid() {
+		return
+	}
+	var (
+		ops     []func()
+		changed []Download
+		done    *Download
+		history []Download
+		file    string
+	)
+	m.lock.Lock()
+	entry := m.entry(browser.Identifier(), item.Id())
+	entry.read(item)
+	first := entry.callback == nil
+	if first && callback.IsValid() {
+		entry.callback = DownloadItemCallbackRef.UnWrap(callback)
+	}
+	switch {
+	case item.IsComplete():
+		entry.State = DownloadStateComplete
+	case item.IsCanceled():
+		entry.State = DownloadStateCanceled
+	case item.IsInterrupted():
+		entry.State = DownloadStateInterrupted
+	case first && entry.callback != nil:
+		action := entry.action
+		entry.action = downloadActionNone
+		if action == downloadActionNone && m.full(entry) {
+			entry.queued = true
+			entry.State = DownloadStateQueued
+			ops = append(ops, entry.callback.Pause)
+		} else {
+			entry.State = DownloadStateInProgress
+			ops = m.apply(entry, action)
+		}
+	case entry.paused:
+		entry.State = DownloadStatePaused
+	case entry.queued:
+		entry.State = DownloadStateQueued
+	default:
+		entry.State = DownloadStateInProgress
+	}
+	changed = append(changed, entry.Download)
+	if entry.Done() {
+		m.remove(entry)
+		if entry.callback != nil {
+			entry.callback.Free()
+			entry.callback = nil
+		}
+		download := entry.Download
+		done = &download
+		m.history = append([]Download{download}, m.history...)
+		m.trimHistory()
+		history, file = append([]Download(nil), m.history...), m.historyFile
+	}
+	scheduleOps, scheduled := m.schedule()
+	m.lock.Unlock()
+	m.run(append(ops, scheduleOps...), append(changed, scheduled...))
+	if done != nil {
+		if err := saveDownloadHistory(file, history); err != nil {
+			logger.Error("download history:", err)
+		}
+		if m.onDone != nil {
+			m.onDone(*done)
+		}
+	}
+}
+
+func (m *DownloadManager) remove(entry *downloadEntry) {
+	for i, e := range m.active {
+		if e == entry {
+			m.active = append(m.active[:i], m.active[i+1:]...)
+			return
+		}
+	}
+}
+
+func (m *DownloadManager) trimHistory() {
+	if m.maxHistory > 0 && len(m.history) > m.maxHistory {
+		m.history = m.history[:m.maxHistory]
+	}
+}
+
+// run 在锁外执行回调函数并通知状态更新
+func (m *DownloadManager) run(ops []func(), changed []Download) {
+	for _, op := range ops {
+		op()
+	}
+	for _, download := range changed {
+		m.emit(download)
+	}
+}
+
+// emit 通知 Go 回调和 IPC 事件, IPC 事件发送到下载所在的窗口, 窗口关闭时发送到主窗口
+func (m *DownloadManager) emit(download Download) {
+	if m.onUpdated != nil {
+		m.onUpdated(download)
+	}
+	if m.ipcEvent == "" {
+		return
+	}
+	if window := BrowserWindow.GetWindowInfo(download.BrowserId); window != nil && !window.IsClosing() {
+		if target := window.Target(); target != nil {
+			ipc.EmitTarget(m.ipcEvent, target, download)
+			return
+		}
+	}
+	ipc.Emit(m.ipcEvent, download)
+}
+
+// read 读取下载项信息
+func (m *downloadEntry) read(item *ICefDownloadItem) {
+	m.URL = item.Url()
+	if mimeType := item.MimeType(); mimeType != "" {
+		m.MimeType = mimeType
+	}
+	if fullPath := item.FullPath(); fullPath != "" {
+		m.FullPath = fullPath
+		m.FileName = filepath.Base(fullPath)
+	} else if m.FileName == "" {
+		m.FileName = item.SuggestedFileName()
+	}
+	m.TotalBytes = item.TotalBytes()
+	m.ReceivedBytes = item.ReceivedBytes()
+	m.Percent = item.PercentComplete()
+	m.Speed = item.CurrentSpeed()
+	m.StartTime = item.StartTime()
+	m.EndTime = item.EndTime()
+}
+
+// saveDownloadHistory 保存下载记录, file 为空不保存
+func saveDownloadHistory(file string, history []Download) error {
+	if file == "" {
+		return nil
+	}
+	if history == nil {
+		history = []Download{}
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
@@ -0,0 +1,115 @@
+package cef
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDownloadManagerSavePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "energy-download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	manager := NewDownloadManager()
+	manager.SetDir(dir)
+	manager.AddMimeDir("image/*", filepath.Join(dir, "images"))
+	path, ask := manager.resolvePath(Download{MimeType: "image/PNG"}, "logo.png")
+	if path != filepath.Join(dir, "images", "logo.png") || ask {
+		t.Fatal("unexpected path", path, ask)
+	}
+	// 文件已存在或正在下载时添加序号
+	if err = ioutil.WriteFile(filepath.Join(dir, "app.zip"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager.active = append(manager.active, &downloadEntry{Download: Download{Id: 1, FullPath: filepath.Join(dir, "app (1).zip")}})
+	if path, _ = manager.resolvePath(Download{MimeType: "application/zip"}, "app.zip"); path != filepath.Join(dir, "app (2).zip") {
+		t.Fatal("unexpected path", path)
+	}
+	manager.SetAsk(true)
+	manager.SetSavePath(func(download Download, suggestedName string) (string, bool) {
+		if download.MimeType == "application/pdf" {
+			return filepath.Join(dir, "docs", suggestedName), false
+		}
+		return "", false
+	})
+	if path, ask = manager.resolvePath(Download{MimeType: "application/pdf"}, "a.pdf"); path != filepath.Join(dir, "docs", "a.pdf") || ask {
+		t.Fatal("unexpected path", path, ask)
+	}
+	if path, ask = manager.resolvePath(Download{MimeType: "text/plain"}, "../a.txt"); path != filepath.Join(dir, "a.txt") || !ask {
+		t.Fatal("unexpected path", path, ask)
+	}
+}
+
+func TestDownloadManagerQueue(t *testing.T) {
+	manager := NewDownloadManager()
+	var updated []Download
+	manager.SetIPCEvent("")
+	manager.SetOnUpdated(func(download Download) {
+		updated = append(updated, download)
+	})
+	manager.SetMaxConcurrent(1)
+	running := &downloadEntry{Download: Download{Id: 1, State: DownloadStateInProgress}, callback: &ICefDownloadItemCallback{}}
+	queued := &downloadEntry{Download: Download{Id: 2, State: DownloadStateQueued}, callback: &ICefDownloadItemCallback{}, queued: true}
+	manager.active = append(manager.active, running, queued)
+	// 暂停正在下载的, 排队的开始下载
+	if !manager.Pause(1) {
+		t.Fatal("pause failed")
+	}
+	if running.State != DownloadStatePaused || queued.State != DownloadStateInProgress || len(updated) != 2 {
+		t.Fatal("unexpected state", running.State, queued.State, len(updated))
+	}
+	// 恢复时超过同时下载数量, 排队
+	manager.Resume(1)
+	if running.State != DownloadStateQueued || !running.queued {
+		t.Fatal("unexpected state", running.State)
+	}
+	manager.SetMaxConcurrent(0)
+	if running.State != DownloadStateInProgress {
+		t.Fatal("unexpected state", running.State)
+	}
+	// 还没有回调时等待第一次更新
+	manager.active = append(manager.active, &downloadEntry{Download: Download{Id: 3}})
+	manager.Cancel(3)
+	if manager.find(3).action != downloadActionCancel {
+		t.Fatal("action not pending")
+	}
+	if manager.Pause(4) {
+		t.Fatal("unknown download")
+	}
+}
+
+func TestDownloadManagerHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "energy-download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "history", "downloads.json")
+	history := []Download{{Id: 2, FileName: "b.zip", State: DownloadStateComplete}, {Id: 1, FileName: "a.zip", State: DownloadStateCanceled}}
+	if err = saveDownloadHistory(file, history); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewDownloadManager()
+	manager.SetMaxHistory(1)
+	if err = manager.SetHistoryFile(file); err != nil {
+		t.Fatal(err)
+	}
+	if downloads := manager.Downloads(); len(downloads) != 1 || downloads[0].FileName != "b.zip" || !downloads[0].Done() {
+		t.Fatal("unexpected history", downloads)
+	}
+	if err = manager.ClearHistory(); err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.SetHistoryFile(file); err != nil || len(manager.Downloads()) != 0 {
+		t.Fatal("history not cleared", err)
+	}
+	if err = ioutil.WriteFile(file, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.SetHistoryFile(file); err == nil {
+		t.Fatal("expected error")
+	}
+}
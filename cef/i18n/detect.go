@@ -0,0 +1,117 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package i18n
+
+import (
+	"github.com/energye/energy/v2/consts"
+	"strings"
+)
+
+// CEF 支持的所有语言
+var languages = []consts.LANGUAGE{
+	consts.LANGUAGE_zh_CN, consts.LANGUAGE_zh_TW, consts.LANGUAGE_am, consts.LANGUAGE_ar, consts.LANGUAGE_bg,
+	consts.LANGUAGE_bn, consts.LANGUAGE_ca, consts.LANGUAGE_cs, consts.LANGUAGE_da, consts.LANGUAGE_de,
+	consts.LANGUAGE_el, consts.LANGUAGE_en_GB, consts.LANGUAGE_en_US, consts.LANGUAGE_es, consts.LANGUAGE_es_419,
+	consts.LANGUAGE_et, consts.LANGUAGE_fa, consts.LANGUAGE_fi, consts.LANGUAGE_fil, consts.LANGUAGE_fr,
+	consts.LANGUAGE_gu, consts.LANGUAGE_he, consts.LANGUAGE_hi, consts.LANGUAGE_hr, consts.LANGUAGE_hu,
+	consts.LANGUAGE_id, consts.LANGUAGE_it, consts.LANGUAGE_ja, consts.LANGUAGE_kn, consts.LANGUAGE_ko,
+	consts.LANGUAGE_lt, consts.LANGUAGE_lv, consts.LANGUAGE_ml, consts.LANGUAGE_mr, consts.LANGUAGE_ms,
+	consts.LANGUAGE_nb, consts.LANGUAGE_nl, consts.LANGUAGE_pl, consts.LANGUAGE_pt_BR, consts.LANGUAGE_pt_PT,
+	consts.LANGUAGE_ro, consts.LANGUAGE_ru, consts.LANGUAGE_sk, consts.LANGUAGE_sl, consts.LANGUAGE_sr,
+	consts.LANGUAGE_sv, consts.LANGUAGE_sw, consts.LANGUAGE_ta, consts.LANGUAGE_te, consts.LANGUAGE_th,
+	consts.LANGUAGE_tr, consts.LANGUAGE_uk, consts.LANGUAGE_vi,
+}
+
+// 旧的或同义的语言代码
+var languageAlias = map[string]string{
+	"iw": "he",
+	"in": "id",
+	"tl": "fil",
+	"no": "nb",
+	"nn": "nb",
+}
+
+// Languages
+//	返回 CEF 支持的所有语言
+func Languages() []consts.LANGUAGE {
+	return append([]consts.LANGUAGE{}, languages...)
+}
+
+// Match
+//	将系统或 Chromium 的语言标签匹配为 CEF 支持的语言, 未匹配返回空
+//	支持格式: zh_CN.UTF-8, zh-Hant-TW, en, pt-BR, es-MX ...
+func Match(tag string) consts.LANGUAGE {
+	tag = strings.TrimSpace(tag)
+	// 去掉编码和修饰符 zh_CN.UTF-8@xxx
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.Replace(tag, "_", "-", -1)
+	if tag == "" || tag == "C" || tag == "POSIX" {
+		return ""
+	}
+	parts := strings.Split(tag, "-")
+	lang := strings.ToLower(parts[0])
+	if alias, ok := languageAlias[lang]; ok {
+		lang = alias
+	}
+	var script, region string
+	for _, p := range parts[1:] {
+		if len(p) == 4 {
+			script = strings.ToLower(p)
+		} else if len(p) == 2 || len(p) == 3 {
+			region = strings.ToUpper(p)
+		}
+	}
+	switch lang {
+	case "zh":
+		if script == "hant" || region == "TW" || region == "HK" || region == "MO" {
+			return consts.LANGUAGE_zh_TW
+		}
+		return consts.LANGUAGE_zh_CN
+	case "en":
+		switch region {
+		case "GB", "AU", "NZ", "IE", "IN", "ZA":
+			return consts.LANGUAGE_en_GB
+		}
+		return consts.LANGUAGE_en_US
+	case "es":
+		if region == "" || region == "ES" {
+			return consts.LANGUAGE_es
+		}
+		return consts.LANGUAGE_es_419
+	case "pt":
+		if region == "" || region == "BR" {
+			return consts.LANGUAGE_pt_BR
+		}
+		return consts.LANGUAGE_pt_PT
+	}
+	for _, l := range languages {
+		if string(l) == lang {
+			return l
+		}
+	}
+	return ""
+}
+
+// Detect
+//	返回操作系统首选语言对应的 CEF 语言, 无法识别时返回回退语言
+//	使用: i18n.Switch(i18n.Detect())
+func Detect() consts.LANGUAGE {
+	for _, tag := range systemLocales() {
+		if lang := Match(tag); lang != "" {
+			return lang
+		}
+	}
+	lock.RLock()
+	defer lock.RUnlock()
+	return fallbackLang
+}
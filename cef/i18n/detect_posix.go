@@ -0,0 +1,45 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+//go:build !windows
+// +build !windows
+
+package i18n
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// systemLocales 环境变量 LANGUAGE LC_ALL LC_MESSAGES LANG
+//	MacOS 从 Finder 启动时没有环境变量, 读取 AppleLanguages
+func systemLocales() (result []string) {
+	if v := os.Getenv("LANGUAGE"); v != "" {
+		result = append(result, strings.Split(v, ":")...)
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			result = append(result, v)
+		}
+	}
+	if runtime.GOOS == "darwin" {
+		if out, err := exec.Command("defaults", "read", "-g", "AppleLanguages").Output(); err == nil {
+			for _, line := range strings.Split(string(out), "\n") {
+				line = strings.Trim(strings.TrimSpace(line), `(),"`)
+				if line != "" {
+					result = append(result, line)
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,24 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+//go:build windows
+// +build windows
+
+package i18n
+
+import "golang.org/x/sys/windows"
+
+// systemLocales 用户首选界面语言
+func systemLocales() []string {
+	if langs, err := windows.GetUserPreferredUILanguages(windows.MUI_LANGUAGE_NAME); err == nil {
+		return langs
+	}
+	return nil
+}
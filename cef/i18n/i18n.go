@@ -11,24 +11,37 @@
 // Package i18n Multilingual resources
 //  Resource usage file loading
 //  File name format: locale.[lang].json | locale.[lang].ini => locale.en-US.json | locale.zh-CN.ini
+//  Fallback chain: zh-TW -> zh -> en-US
 package i18n
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/energye/energy/v2/consts"
 	"github.com/energye/golcl/energy/emfs"
 	"io/ioutil"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
+// Params 资源插值参数, 替换资源中的 {name}
+type Params map[string]interface{}
+
+// SwitchListener 语言切换监听函数
+type SwitchListener func(lang consts.LANGUAGE)
+
 var (
-	currentLang  consts.LANGUAGE
-	resources    map[string]string             // 资源-优先
-	resourcesVar = make(map[string]*string, 0) // 变量资源-切换时被同步
-	lPath        string                        // 本地加载
-	lFS          emfs.IEmbedFS                 // 内置加载-优先
-	lFSPath      string                        // 内置加载-资源所在目录
+	currentLang     consts.LANGUAGE
+	fallbackLang    = consts.LANGUAGE_en_US                       // 最终回退语言
+	fallbackChains  = make(map[consts.LANGUAGE][]consts.LANGUAGE) // 自定义回退链
+	resources       = make(map[string]string, 0)                  // 资源-优先
+	resourcesVar    = make(map[string]*string, 0)                 // 变量资源-切换时被同步
+	switchListeners []SwitchListener                              // 语言切换监听
+	lPath           string                                        // 本地加载
+	lFS             emfs.IEmbedFS                                 // 内置加载-优先
+	lFSPath         string                                        // 内置加载-资源所在目录
+	lock            sync.RWMutex
 )
 
 // SetLocalPath
@@ -46,25 +59,117 @@ func SetLocalFS(localFS emfs.IEmbedFS, localFSPath string) {
 	lFSPath = localFSPath
 }
 
+// SetFallback
+//	设置最终回退语言, 默认 en-US
+func SetFallback(lang consts.LANGUAGE) {
+	lock.Lock()
+	defer lock.Unlock()
+	fallbackLang = lang
+}
+
+// SetFallbackChain
+//	自定义指定语言的回退链, 未设置时按语言标签逐级截取
+//	例: SetFallbackChain(LANGUAGE_pt_PT, LANGUAGE_pt_BR) => pt-PT -> pt-BR -> en-US
+func SetFallbackChain(lang consts.LANGUAGE, chain ...consts.LANGUAGE) {
+	lock.Lock()
+	defer lock.Unlock()
+	fallbackChains[lang] = chain
+}
+
+// FallbackChain
+//	返回语言的查找顺序, 例: zh-TW -> zh -> en-US
+func FallbackChain(lang consts.LANGUAGE) []consts.LANGUAGE {
+	lock.RLock()
+	defer lock.RUnlock()
+	return fallbackChain(lang)
+}
+
+func fallbackChain(lang consts.LANGUAGE) []consts.LANGUAGE {
+	var chain []consts.LANGUAGE
+	var add = func(l consts.LANGUAGE) {
+		if l == "" {
+			return
+		}
+		for _, v := range chain {
+			if v == l {
+				return
+			}
+		}
+		chain = append(chain, l)
+	}
+	var tags = []consts.LANGUAGE{lang}
+	if custom, ok := fallbackChains[lang]; ok {
+		tags = append(tags, custom...)
+	}
+	for _, l := range tags {
+		tag := string(l)
+		for tag != "" {
+			add(consts.LANGUAGE(tag))
+			if i := strings.LastIndex(tag, "-"); i > 0 {
+				tag = tag[:i]
+			} else {
+				break
+			}
+		}
+	}
+	add(fallbackLang)
+	return chain
+}
+
+// Language
+//	返回当前语言
+func Language() consts.LANGUAGE {
+	lock.RLock()
+	defer lock.RUnlock()
+	return currentLang
+}
+
+// AddSwitchListener
+//	添加语言切换监听, 语言切换并加载资源后被调用
+func AddSwitchListener(fn SwitchListener) {
+	if fn == nil {
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	switchListeners = append(switchListeners, fn)
+}
+
 // Switch
 //  切换语言
-//	默认先在内置FS中加载
+//	按回退链从后向前加载, 先加载的资源被后加载的覆盖
+//	每种语言依次加载 内置资源 -> json -> ini, 默认先在内置FS中加载
 func Switch(lang consts.LANGUAGE) {
+	lock.Lock()
 	if currentLang == lang {
 		// 如果当前语言和切换语言一样
+		lock.Unlock()
 		return
 	}
+	res := make(map[string]string, 0)
+	chain := fallbackChain(lang)
+	for i := len(chain) - 1; i >= 0; i-- {
+		// 内置资源
+		loadDefaultResource(res, chain[i])
+		// 在json文件中加载资源
+		loadJSONConvertResource(res, chain[i])
+		// 在ini文件中加载资源
+		loadINIConvertResource(res, chain[i])
+	}
+	lock.Unlock()
+	SetResources(lang, res)
+}
+
+// SetResources
+//	直接设置当前语言和资源, 不在文件中加载
+//	渲染进程用于同步主进程切换后的资源
+func SetResources(lang consts.LANGUAGE, res map[string]string) {
+	lock.Lock()
 	currentLang = lang
-	resources = make(map[string]string, 0)
-	if lang == consts.LANGUAGE_zh_CN {
-		initZhCn()
-	} else {
-		initEnUs()
-	}
-	// 在json文件中加载资源
-	loadJSONConvertResource()
-	// 在ini文件中加载资源
-	loadINIConvertResource()
+	resources = make(map[string]string, len(res))
+	for name, value := range res {
+		resources[name] = value
+	}
 	// 加载后的资源同步到变量资源
 	if len(resources) > 0 {
 		for name, value := range resourcesVar {
@@ -73,46 +178,121 @@ func Switch(lang consts.LANGUAGE) {
 			}
 		}
 	}
+	listeners := switchListeners
+	lock.Unlock()
+	for _, fn := range listeners {
+		fn(lang)
+	}
+}
+
+// Resources
+//	返回当前语言所有静态资源的副本
+func Resources() map[string]string {
+	lock.RLock()
+	defer lock.RUnlock()
+	res := make(map[string]string, len(resources))
+	for name, value := range resources {
+		res[name] = value
+	}
+	return res
+}
+
+// loadDefaultResource
+//	加载内置资源
+func loadDefaultResource(res map[string]string, lang consts.LANGUAGE) {
+	if table, ok := defaultResources[lang]; ok {
+		for name, value := range table {
+			res[name] = value
+		}
+	}
 }
 
 // loadJSONConvertResource
 //	加载JSON格式并转换资源
-func loadJSONConvertResource() bool {
-	jsonFileName := "locale." + string(currentLang) + ".json"
+//	嵌套对象的 key 以 "." 连接, {"menu": {"copy": "Copy"}} => menu.copy
+func loadJSONConvertResource(res map[string]string, lang consts.LANGUAGE) bool {
+	jsonFileName := "locale." + string(lang) + ".json"
 	//加载资源
 	if contentBytes := loadResource(jsonFileName); contentBytes != nil {
-		var temp interface{} // map
-		if json.Unmarshal(contentBytes, &temp) == nil {
-			if v, ok := temp.(map[string]interface{}); ok {
-				for name, value := range v {
-					resources[name] = value.(string)
-				}
-				return true
-			}
-		}
+		return parseJSON(contentBytes, res) == nil
 	}
 	return false
 }
 
 // loadINIConvertResource
 //	加载INI格式并转换资源
-func loadINIConvertResource() bool {
-	iniFileName := "locale." + string(currentLang) + ".ini"
+func loadINIConvertResource(res map[string]string, lang consts.LANGUAGE) bool {
+	iniFileName := "locale." + string(lang) + ".ini"
 	//加载资源
 	if contentBytes := loadResource(iniFileName); contentBytes != nil {
-		var temp = string(contentBytes)
-		res := strings.Split(temp, "\r\n")
-		for _, line := range res {
-			nv := strings.Split(line, "=")
-			if len(nv) == 2 {
-				resources[nv[0]] = nv[1]
-			}
-		}
+		parseINI(contentBytes, res)
 		return true
 	}
 	return false
 }
 
+// parseJSON
+//	解析JSON资源, 嵌套对象展开为 a.b.c
+func parseJSON(content []byte, res map[string]string) error {
+	var temp map[string]interface{}
+	if err := json.Unmarshal(trimBOM(content), &temp); err != nil {
+		return err
+	}
+	flattenJSON("", temp, res)
+	return nil
+}
+
+func flattenJSON(prefix string, object map[string]interface{}, res map[string]string) {
+	for name, value := range object {
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		switch v := value.(type) {
+		case string:
+			res[name] = v
+		case map[string]interface{}:
+			flattenJSON(name, v, res)
+		case nil:
+		default:
+			res[name] = fmt.Sprint(v)
+		}
+	}
+}
+
+// parseINI
+//	解析INI资源, 支持 \r\n 和 \n 换行, ; # 注释
+//	[section] 下的 key 展开为 section.key
+func parseINI(content []byte, res map[string]string) {
+	var section string
+	lines := strings.Split(string(trimBOM(content)), "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			continue
+		}
+		name := strings.TrimSpace(line[:idx])
+		if section != "" {
+			name = section + "." + name
+		}
+		res[name] = strings.TrimSpace(line[idx+1:])
+	}
+}
+
+func trimBOM(content []byte) []byte {
+	if len(content) >= 3 && content[0] == 0xEF && content[1] == 0xBB && content[2] == 0xBF {
+		return content[3:]
+	}
+	return content
+}
+
 // loadResource
 //	加载资源-优先在内置FS中加载
 func loadResource(fileName string) []byte {
@@ -139,18 +319,24 @@ func loadResource(fileName string) []byte {
 // RegisterResource
 //	注册资源, 在代码中手动设置在静态资源
 func RegisterResource(name, value string) {
+	lock.Lock()
+	defer lock.Unlock()
 	resources[name] = value
 }
 
 // RegisterVarResource
 //	注册变量资源, 在代码中手动设置, 切换资源时变量值会被同步
 func RegisterVarResource(name string, value *string) {
+	lock.Lock()
+	defer lock.Unlock()
 	resourcesVar[name] = value
 }
 
 // Resource
 //  返回资源-优先在静态资源中查找
 func Resource(name string) string {
+	lock.RLock()
+	defer lock.RUnlock()
 	if v, ok := resources[name]; ok {
 		return v
 	} else if v, ok := resourcesVar[name]; ok {
@@ -158,3 +344,70 @@ func Resource(name string) string {
 	}
 	return ""
 }
+
+// T
+//	返回资源并替换插值参数 {name}, 资源不存在时返回 name
+func T(name string, params ...Params) string {
+	value := Resource(name)
+	if value == "" {
+		value = name
+	}
+	if len(params) > 0 {
+		return Format(value, params[0])
+	}
+	return value
+}
+
+// Plural
+//	根据数量和当前语言的 CLDR 复数规则返回资源, 并替换插值参数, {count} 为数量
+//	资源名查找顺序: name.[zero|one|two|few|many|other] -> name.other -> name
+//	例: {"apple": {"one": "{count} apple", "other": "{count} apples"}}
+func Plural(name string, count int, params ...Params) string {
+	category := PluralCategory(Language(), count)
+	value := Resource(name + "." + category)
+	if value == "" && category != PluralOther {
+		value = Resource(name + "." + PluralOther)
+	}
+	if value == "" {
+		value = Resource(name)
+	}
+	if value == "" {
+		value = name
+	}
+	var args = Params{"count": count}
+	if len(params) > 0 {
+		for k, v := range params[0] {
+			args[k] = v
+		}
+	}
+	return Format(value, args)
+}
+
+// Format
+//	替换文本中的 {name} 插值参数, 未提供的参数保持原样
+func Format(text string, params Params) string {
+	if len(params) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	var buf strings.Builder
+	for {
+		start := strings.Index(text, "{")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(text[start:], "}")
+		if end == -1 {
+			break
+		}
+		end += start
+		buf.WriteString(text[:start])
+		if v, ok := params[text[start+1:end]]; ok {
+			buf.WriteString(fmt.Sprint(v))
+		} else {
+			buf.WriteString(text[start : end+1])
+		}
+		text = text[end+1:]
+	}
+	buf.WriteString(text)
+	return buf.String()
+}
@@ -0,0 +1,110 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package i18n
+
+import (
+	"github.com/energye/energy/v2/consts"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestFallbackChain(t *testing.T) {
+	chain := FallbackChain(consts.LANGUAGE_zh_TW)
+	if len(chain) != 3 || chain[0] != "zh-TW" || chain[1] != "zh" || chain[2] != consts.LANGUAGE_en_US {
+		t.Fatal("zh-TW chain:", chain)
+	}
+	chain = FallbackChain(consts.LANGUAGE_en_US)
+	if len(chain) != 2 || chain[0] != consts.LANGUAGE_en_US || chain[1] != "en" {
+		t.Fatal("en-US chain:", chain)
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	dir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(dir, "locale.zh.ini"), []byte("; comment\nhello = 你好 {name}\n[menu]\nfile=文件\r\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "locale.en-US.json"), []byte(`{"hello": "Hello {name}", "menu": {"file": "File", "edit": "Edit"}, "apple": {"one": "{count} apple", "other": "{count} apples"}}`), 0644)
+	SetLocalPath(dir)
+	defer SetLocalPath("")
+	Switch(consts.LANGUAGE_zh_TW)
+	if v := T("hello", Params{"name": "energy"}); v != "你好 energy" {
+		t.Fatal("hello:", v)
+	}
+	if v := Resource("menu.file"); v != "文件" {
+		t.Fatal("menu.file:", v)
+	}
+	// 回退到 en-US
+	if v := Resource("menu.edit"); v != "Edit" {
+		t.Fatal("menu.edit:", v)
+	}
+	// 内置资源
+	if v := Resource("copy"); v != "複製" {
+		t.Fatal("copy:", v)
+	}
+	Switch(consts.LANGUAGE_en_US)
+	if v := Plural("apple", 1); v != "1 apple" {
+		t.Fatal("plural one:", v)
+	}
+	if v := Plural("apple", 5); v != "5 apples" {
+		t.Fatal("plural other:", v)
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	var cases = []struct {
+		lang     consts.LANGUAGE
+		n        int
+		category string
+	}{
+		{consts.LANGUAGE_en_US, 1, PluralOne},
+		{consts.LANGUAGE_en_US, 0, PluralOther},
+		{consts.LANGUAGE_zh_CN, 1, PluralOther},
+		{consts.LANGUAGE_fr, 0, PluralOne},
+		{consts.LANGUAGE_ru, 21, PluralOne},
+		{consts.LANGUAGE_ru, 22, PluralFew},
+		{consts.LANGUAGE_ru, 11, PluralMany},
+		{consts.LANGUAGE_pl, 5, PluralMany},
+		{consts.LANGUAGE_ar, 0, PluralZero},
+		{consts.LANGUAGE_ar, 2, PluralTwo},
+		{consts.LANGUAGE_ar, 105, PluralFew},
+		{consts.LANGUAGE_ar, 111, PluralMany},
+		{consts.LANGUAGE_lv, 10, PluralZero},
+		{consts.LANGUAGE_cs, 3, PluralFew},
+		{consts.LANGUAGE_ro, 1, PluralOne},
+		{consts.LANGUAGE_ro, 101, PluralFew},
+		{consts.LANGUAGE_ro, 20, PluralOther},
+	}
+	for _, c := range cases {
+		if v := PluralCategory(c.lang, c.n); v != c.category {
+			t.Fatal(c.lang, c.n, v, "!=", c.category)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	var cases = map[string]consts.LANGUAGE{
+		"zh_CN.UTF-8": consts.LANGUAGE_zh_CN,
+		"zh-Hant-HK":  consts.LANGUAGE_zh_TW,
+		"en":          consts.LANGUAGE_en_US,
+		"en_AU":       consts.LANGUAGE_en_GB,
+		"es-MX":       consts.LANGUAGE_es_419,
+		"pt":          consts.LANGUAGE_pt_BR,
+		"iw":          consts.LANGUAGE_he,
+		"de-DE":       consts.LANGUAGE_de,
+		"C":           "",
+		"xx":          "",
+	}
+	for tag, lang := range cases {
+		if v := Match(tag); v != lang {
+			t.Fatal(tag, v, "!=", lang)
+		}
+	}
+}
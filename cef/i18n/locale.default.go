@@ -10,46 +10,998 @@
 
 package i18n
 
-// initZhCn
-//	初始 zh_CN
-func initZhCn() {
-	resources["undo"] = "撤销"
-	resources["redo"] = "恢复"
-	resources["cut"] = "剪切"
-	resources["copy"] = "复制"
-	resources["paste"] = "粘贴"
-	resources["selectAll"] = "全选"
-	resources["copyLink"] = "复制链接"
-	resources["copyImageLink"] = "复制图片链接"
-	resources["imageSaveAs"] = "图片另存为"
-	resources["back"] = "返回"
-	resources["forward"] = "前进"
-	resources["print"] = "打印"
-	resources["closeBrowser"] = "关闭页面"
-	resources["refresh"] = "刷新"
-	resources["forcedRefresh"] = "强制刷新"
-	resources["viewPageSource"] = "查看网面源代码"
-	resources["devTools"] = "开发者工具(F12)"
-}
+import "github.com/energye/energy/v2/consts"
 
-// initEnUs
-//	初始 en_US
-func initEnUs() {
-	resources["undo"] = "Undo"
-	resources["redo"] = "Redo"
-	resources["cut"] = "Cut"
-	resources["copy"] = "Copy"
-	resources["paste"] = "Paste"
-	resources["selectAll"] = "Select All"
-	resources["copyLink"] = "Copy Link"
-	resources["copyImageLink"] = "Copy Image Link"
-	resources["imageSaveAs"] = "Image Save AS"
-	resources["back"] = "Back"
-	resources["forward"] = "Forward"
-	resources["print"] = "Print"
-	resources["closeBrowser"] = "Close Browser"
-	resources["refresh"] = "Refresh"
-	resources["forcedRefresh"] = "Forced Refresh"
-	resources["viewPageSource"] = "View Source"
-	resources["devTools"] = "Dev Tools(F12)"
+// defaultResources
+//	内置资源, 默认右键菜单等使用
+//	未列出的语言(es-419 等)通过回退链查找, es-419 -> es -> en-US
+var defaultResources = map[consts.LANGUAGE]map[string]string{
+	consts.LANGUAGE_zh_CN: {
+		"undo":           "撤销",
+		"redo":           "恢复",
+		"cut":            "剪切",
+		"copy":           "复制",
+		"paste":          "粘贴",
+		"selectAll":      "全选",
+		"copyLink":       "复制链接",
+		"copyImageLink":  "复制图片链接",
+		"imageSaveAs":    "图片另存为",
+		"back":           "返回",
+		"forward":        "前进",
+		"print":          "打印",
+		"closeBrowser":   "关闭页面",
+		"refresh":        "刷新",
+		"forcedRefresh":  "强制刷新",
+		"viewPageSource": "查看网面源代码",
+		"devTools":       "开发者工具(F12)",
+	},
+	consts.LANGUAGE_zh_TW: {
+		"undo":           "復原",
+		"redo":           "重做",
+		"cut":            "剪下",
+		"copy":           "複製",
+		"paste":          "貼上",
+		"selectAll":      "全選",
+		"copyLink":       "複製連結",
+		"copyImageLink":  "複製圖片連結",
+		"imageSaveAs":    "另存圖片",
+		"back":           "上一頁",
+		"forward":        "下一頁",
+		"print":          "列印",
+		"closeBrowser":   "關閉頁面",
+		"refresh":        "重新整理",
+		"forcedRefresh":  "強制重新整理",
+		"viewPageSource": "檢視網頁原始碼",
+		"devTools":       "開發人員工具(F12)",
+	},
+	consts.LANGUAGE_en_US: {
+		"undo":           "Undo",
+		"redo":           "Redo",
+		"cut":            "Cut",
+		"copy":           "Copy",
+		"paste":          "Paste",
+		"selectAll":      "Select All",
+		"copyLink":       "Copy Link",
+		"copyImageLink":  "Copy Image Link",
+		"imageSaveAs":    "Image Save AS",
+		"back":           "Back",
+		"forward":        "Forward",
+		"print":          "Print",
+		"closeBrowser":   "Close Browser",
+		"refresh":        "Refresh",
+		"forcedRefresh":  "Forced Refresh",
+		"viewPageSource": "View Source",
+		"devTools":       "Dev Tools(F12)",
+	},
+	consts.LANGUAGE_am: {
+		"undo":           "ቀልብስ",
+		"redo":           "ድገም",
+		"cut":            "ቁረጥ",
+		"copy":           "ቅዳ",
+		"paste":          "ለጥፍ",
+		"selectAll":      "ሁሉንም ምረጥ",
+		"copyLink":       "አገናኝ ቅዳ",
+		"copyImageLink":  "የምስል አገናኝ ቅዳ",
+		"imageSaveAs":    "ምስል አስቀምጥ እንደ",
+		"back":           "ተመለስ",
+		"forward":        "ወደፊት",
+		"print":          "አትም",
+		"closeBrowser":   "ገጹን ዝጋ",
+		"refresh":        "አድስ",
+		"forcedRefresh":  "በግድ አድስ",
+		"viewPageSource": "የገጹን ምንጭ ተመልከት",
+		"devTools":       "የገንቢ መሳሪያዎች(F12)",
+	},
+	consts.LANGUAGE_ar: {
+		"undo":           "تراجع",
+		"redo":           "إعادة",
+		"cut":            "قص",
+		"copy":           "نسخ",
+		"paste":          "لصق",
+		"selectAll":      "تحديد الكل",
+		"copyLink":       "نسخ الرابط",
+		"copyImageLink":  "نسخ رابط الصورة",
+		"imageSaveAs":    "حفظ الصورة باسم",
+		"back":           "رجوع",
+		"forward":        "تقدم",
+		"print":          "طباعة",
+		"closeBrowser":   "إغلاق الصفحة",
+		"refresh":        "إعادة تحميل",
+		"forcedRefresh":  "إعادة تحميل إجبارية",
+		"viewPageSource": "عرض مصدر الصفحة",
+		"devTools":       "أدوات المطور(F12)",
+	},
+	consts.LANGUAGE_bg: {
+		"undo":           "Отмяна",
+		"redo":           "Повторение",
+		"cut":            "Изрязване",
+		"copy":           "Копиране",
+		"paste":          "Поставяне",
+		"selectAll":      "Избиране на всички",
+		"copyLink":       "Копиране на връзката",
+		"copyImageLink":  "Копиране на връзката към изображението",
+		"imageSaveAs":    "Запазване на изображението като",
+		"back":           "Назад",
+		"forward":        "Напред",
+		"print":          "Печат",
+		"closeBrowser":   "Затваряне на страницата",
+		"refresh":        "Презареждане",
+		"forcedRefresh":  "Принудително презареждане",
+		"viewPageSource": "Преглед на източника",
+		"devTools":       "Инструменти за програмисти(F12)",
+	},
+	consts.LANGUAGE_bn: {
+		"undo":           "পূর্বাবস্থায় ফেরান",
+		"redo":           "আবার করুন",
+		"cut":            "কাটুন",
+		"copy":           "কপি করুন",
+		"paste":          "পেস্ট করুন",
+		"selectAll":      "সব বেছে নিন",
+		"copyLink":       "লিঙ্ক কপি করুন",
+		"copyImageLink":  "ছবির লিঙ্ক কপি করুন",
+		"imageSaveAs":    "ছবি এই হিসাবে সেভ করুন",
+		"back":           "পিছনে যান",
+		"forward":        "সামনে যান",
+		"print":          "প্রিন্ট করুন",
+		"closeBrowser":   "পৃষ্ঠা বন্ধ করুন",
+		"refresh":        "রিফ্রেশ করুন",
+		"forcedRefresh":  "জোর করে রিফ্রেশ করুন",
+		"viewPageSource": "পৃষ্ঠার উৎস দেখুন",
+		"devTools":       "ডেভেলপার টুল(F12)",
+	},
+	consts.LANGUAGE_ca: {
+		"undo":           "Desfés",
+		"redo":           "Refés",
+		"cut":            "Retalla",
+		"copy":           "Copia",
+		"paste":          "Enganxa",
+		"selectAll":      "Selecciona-ho tot",
+		"copyLink":       "Copia l'enllaç",
+		"copyImageLink":  "Copia l'enllaç de la imatge",
+		"imageSaveAs":    "Desa la imatge com a",
+		"back":           "Enrere",
+		"forward":        "Endavant",
+		"print":          "Imprimeix",
+		"closeBrowser":   "Tanca la pàgina",
+		"refresh":        "Actualitza",
+		"forcedRefresh":  "Força l'actualització",
+		"viewPageSource": "Mostra el codi font",
+		"devTools":       "Eines per a desenvolupadors(F12)",
+	},
+	consts.LANGUAGE_cs: {
+		"undo":           "Zpět",
+		"redo":           "Znovu",
+		"cut":            "Vyjmout",
+		"copy":           "Kopírovat",
+		"paste":          "Vložit",
+		"selectAll":      "Vybrat vše",
+		"copyLink":       "Kopírovat odkaz",
+		"copyImageLink":  "Kopírovat odkaz na obrázek",
+		"imageSaveAs":    "Uložit obrázek jako",
+		"back":           "Zpět",
+		"forward":        "Vpřed",
+		"print":          "Tisk",
+		"closeBrowser":   "Zavřít stránku",
+		"refresh":        "Obnovit",
+		"forcedRefresh":  "Vynutit obnovení",
+		"viewPageSource": "Zobrazit zdrojový kód stránky",
+		"devTools":       "Nástroje pro vývojáře(F12)",
+	},
+	consts.LANGUAGE_da: {
+		"undo":           "Fortryd",
+		"redo":           "Annuller fortryd",
+		"cut":            "Klip",
+		"copy":           "Kopiér",
+		"paste":          "Indsæt",
+		"selectAll":      "Markér alt",
+		"copyLink":       "Kopiér link",
+		"copyImageLink":  "Kopiér billedlink",
+		"imageSaveAs":    "Gem billede som",
+		"back":           "Tilbage",
+		"forward":        "Frem",
+		"print":          "Udskriv",
+		"closeBrowser":   "Luk side",
+		"refresh":        "Genindlæs",
+		"forcedRefresh":  "Gennemtving genindlæsning",
+		"viewPageSource": "Vis sidens kilde",
+		"devTools":       "Udviklerværktøjer(F12)",
+	},
+	consts.LANGUAGE_de: {
+		"undo":           "Rückgängig",
+		"redo":           "Wiederholen",
+		"cut":            "Ausschneiden",
+		"copy":           "Kopieren",
+		"paste":          "Einfügen",
+		"selectAll":      "Alles auswählen",
+		"copyLink":       "Link kopieren",
+		"copyImageLink":  "Bildlink kopieren",
+		"imageSaveAs":    "Bild speichern unter",
+		"back":           "Zurück",
+		"forward":        "Vorwärts",
+		"print":          "Drucken",
+		"closeBrowser":   "Seite schließen",
+		"refresh":        "Neu laden",
+		"forcedRefresh":  "Neu laden erzwingen",
+		"viewPageSource": "Seitenquelltext anzeigen",
+		"devTools":       "Entwicklertools(F12)",
+	},
+	consts.LANGUAGE_el: {
+		"undo":           "Αναίρεση",
+		"redo":           "Επανάληψη",
+		"cut":            "Αποκοπή",
+		"copy":           "Αντιγραφή",
+		"paste":          "Επικόλληση",
+		"selectAll":      "Επιλογή όλων",
+		"copyLink":       "Αντιγραφή συνδέσμου",
+		"copyImageLink":  "Αντιγραφή συνδέσμου εικόνας",
+		"imageSaveAs":    "Αποθήκευση εικόνας ως",
+		"back":           "Πίσω",
+		"forward":        "Εμπρός",
+		"print":          "Εκτύπωση",
+		"closeBrowser":   "Κλείσιμο σελίδας",
+		"refresh":        "Ανανέωση",
+		"forcedRefresh":  "Αναγκαστική ανανέωση",
+		"viewPageSource": "Προβολή πηγαίου κώδικα",
+		"devTools":       "Εργαλεία για προγραμματιστές(F12)",
+	},
+	consts.LANGUAGE_en_GB: {
+		"undo":           "Undo",
+		"redo":           "Redo",
+		"cut":            "Cut",
+		"copy":           "Copy",
+		"paste":          "Paste",
+		"selectAll":      "Select All",
+		"copyLink":       "Copy Link",
+		"copyImageLink":  "Copy Image Link",
+		"imageSaveAs":    "Save Image As",
+		"back":           "Back",
+		"forward":        "Forward",
+		"print":          "Print",
+		"closeBrowser":   "Close Browser",
+		"refresh":        "Refresh",
+		"forcedRefresh":  "Forced Refresh",
+		"viewPageSource": "View Source",
+		"devTools":       "Dev Tools(F12)",
+	},
+	consts.LANGUAGE_es: {
+		"undo":           "Deshacer",
+		"redo":           "Rehacer",
+		"cut":            "Cortar",
+		"copy":           "Copiar",
+		"paste":          "Pegar",
+		"selectAll":      "Seleccionar todo",
+		"copyLink":       "Copiar enlace",
+		"copyImageLink":  "Copiar enlace de la imagen",
+		"imageSaveAs":    "Guardar imagen como",
+		"back":           "Atrás",
+		"forward":        "Adelante",
+		"print":          "Imprimir",
+		"closeBrowser":   "Cerrar página",
+		"refresh":        "Volver a cargar",
+		"forcedRefresh":  "Forzar recarga",
+		"viewPageSource": "Ver código fuente",
+		"devTools":       "Herramientas para desarrolladores(F12)",
+	},
+	consts.LANGUAGE_et: {
+		"undo":           "Võta tagasi",
+		"redo":           "Tee uuesti",
+		"cut":            "Lõika",
+		"copy":           "Kopeeri",
+		"paste":          "Kleebi",
+		"selectAll":      "Vali kõik",
+		"copyLink":       "Kopeeri link",
+		"copyImageLink":  "Kopeeri pildi link",
+		"imageSaveAs":    "Salvesta pilt nimega",
+		"back":           "Tagasi",
+		"forward":        "Edasi",
+		"print":          "Prindi",
+		"closeBrowser":   "Sulge leht",
+		"refresh":        "Laadi uuesti",
+		"forcedRefresh":  "Sunnitud uuesti laadimine",
+		"viewPageSource": "Kuva lehe allikas",
+		"devTools":       "Arendaja tööriistad(F12)",
+	},
+	consts.LANGUAGE_fa: {
+		"undo":           "واگرد",
+		"redo":           "ازنو انجام دادن",
+		"cut":            "برش",
+		"copy":           "کپی",
+		"paste":          "جای‌گذاری",
+		"selectAll":      "انتخاب همه",
+		"copyLink":       "کپی پیوند",
+		"copyImageLink":  "کپی پیوند تصویر",
+		"imageSaveAs":    "ذخیره تصویر با نام",
+		"back":           "برگشت",
+		"forward":        "جلو",
+		"print":          "چاپ",
+		"closeBrowser":   "بستن صفحه",
+		"refresh":        "بارگیری مجدد",
+		"forcedRefresh":  "بارگیری مجدد اجباری",
+		"viewPageSource": "مشاهده منبع صفحه",
+		"devTools":       "ابزارهای توسعه‌دهنده(F12)",
+	},
+	consts.LANGUAGE_fi: {
+		"undo":           "Kumoa",
+		"redo":           "Tee uudelleen",
+		"cut":            "Leikkaa",
+		"copy":           "Kopioi",
+		"paste":          "Liitä",
+		"selectAll":      "Valitse kaikki",
+		"copyLink":       "Kopioi linkki",
+		"copyImageLink":  "Kopioi kuvan linkki",
+		"imageSaveAs":    "Tallenna kuva nimellä",
+		"back":           "Edellinen",
+		"forward":        "Seuraava",
+		"print":          "Tulosta",
+		"closeBrowser":   "Sulje sivu",
+		"refresh":        "Päivitä",
+		"forcedRefresh":  "Pakota päivitys",
+		"viewPageSource": "Näytä sivun lähdekoodi",
+		"devTools":       "Kehittäjätyökalut(F12)",
+	},
+	consts.LANGUAGE_fil: {
+		"undo":           "I-undo",
+		"redo":           "I-redo",
+		"cut":            "I-cut",
+		"copy":           "Kopyahin",
+		"paste":          "I-paste",
+		"selectAll":      "Piliin lahat",
+		"copyLink":       "Kopyahin ang link",
+		"copyImageLink":  "Kopyahin ang link ng larawan",
+		"imageSaveAs":    "I-save ang larawan bilang",
+		"back":           "Bumalik",
+		"forward":        "Sumulong",
+		"print":          "I-print",
+		"closeBrowser":   "Isara ang page",
+		"refresh":        "I-reload",
+		"forcedRefresh":  "Puwersahang i-reload",
+		"viewPageSource": "Tingnan ang source ng page",
+		"devTools":       "Mga tool ng developer(F12)",
+	},
+	consts.LANGUAGE_fr: {
+		"undo":           "Annuler",
+		"redo":           "Rétablir",
+		"cut":            "Couper",
+		"copy":           "Copier",
+		"paste":          "Coller",
+		"selectAll":      "Tout sélectionner",
+		"copyLink":       "Copier le lien",
+		"copyImageLink":  "Copier le lien de l'image",
+		"imageSaveAs":    "Enregistrer l'image sous",
+		"back":           "Retour",
+		"forward":        "Suivant",
+		"print":          "Imprimer",
+		"closeBrowser":   "Fermer la page",
+		"refresh":        "Actualiser",
+		"forcedRefresh":  "Forcer l'actualisation",
+		"viewPageSource": "Afficher le code source",
+		"devTools":       "Outils de développement(F12)",
+	},
+	consts.LANGUAGE_gu: {
+		"undo":           "પૂર્વવત્ કરો",
+		"redo":           "ફરીથી કરો",
+		"cut":            "કાપો",
+		"copy":           "કૉપિ કરો",
+		"paste":          "પેસ્ટ કરો",
+		"selectAll":      "બધું પસંદ કરો",
+		"copyLink":       "લિંક કૉપિ કરો",
+		"copyImageLink":  "છબીની લિંક કૉપિ કરો",
+		"imageSaveAs":    "છબીને આ રીતે સાચવો",
+		"back":           "પાછળ",
+		"forward":        "આગળ",
+		"print":          "છાપો",
+		"closeBrowser":   "પેજ બંધ કરો",
+		"refresh":        "રિફ્રેશ કરો",
+		"forcedRefresh":  "ફરજિયાત રિફ્રેશ કરો",
+		"viewPageSource": "પેજનો સ્રોત જુઓ",
+		"devTools":       "ડેવલપર ટૂલ(F12)",
+	},
+	consts.LANGUAGE_he: {
+		"undo":           "ביטול",
+		"redo":           "ביצוע מחדש",
+		"cut":            "גזירה",
+		"copy":           "העתקה",
+		"paste":          "הדבקה",
+		"selectAll":      "בחירת הכול",
+		"copyLink":       "העתקת הקישור",
+		"copyImageLink":  "העתקת קישור התמונה",
+		"imageSaveAs":    "שמירת התמונה בשם",
+		"back":           "הקודם",
+		"forward":        "הבא",
+		"print":          "הדפסה",
+		"closeBrowser":   "סגירת הדף",
+		"refresh":        "טעינה מחדש",
+		"forcedRefresh":  "אילוץ טעינה מחדש",
+		"viewPageSource": "הצגת מקור הדף",
+		"devTools":       "כלים למפתחים(F12)",
+	},
+	consts.LANGUAGE_hi: {
+		"undo":           "पहले जैसा करें",
+		"redo":           "फिर से करें",
+		"cut":            "काटें",
+		"copy":           "कॉपी करें",
+		"paste":          "चिपकाएं",
+		"selectAll":      "सभी चुनें",
+		"copyLink":       "लिंक कॉपी करें",
+		"copyImageLink":  "इमेज का लिंक कॉपी करें",
+		"imageSaveAs":    "इमेज इस रूप में सेव करें",
+		"back":           "पीछे जाएं",
+		"forward":        "आगे जाएं",
+		"print":          "प्रिंट करें",
+		"closeBrowser":   "पेज बंद करें",
+		"refresh":        "रीफ़्रेश करें",
+		"forcedRefresh":  "ज़बरदस्ती रीफ़्रेश करें",
+		"viewPageSource": "पेज का सोर्स देखें",
+		"devTools":       "डेवलपर टूल(F12)",
+	},
+	consts.LANGUAGE_hr: {
+		"undo":           "Poništi",
+		"redo":           "Ponovi",
+		"cut":            "Izreži",
+		"copy":           "Kopiraj",
+		"paste":          "Zalijepi",
+		"selectAll":      "Odaberi sve",
+		"copyLink":       "Kopiraj vezu",
+		"copyImageLink":  "Kopiraj vezu slike",
+		"imageSaveAs":    "Spremi sliku kao",
+		"back":           "Natrag",
+		"forward":        "Naprijed",
+		"print":          "Ispis",
+		"closeBrowser":   "Zatvori stranicu",
+		"refresh":        "Ponovno učitaj",
+		"forcedRefresh":  "Prisilno ponovno učitaj",
+		"viewPageSource": "Prikaži izvor stranice",
+		"devTools":       "Alati za razvojne programere(F12)",
+	},
+	consts.LANGUAGE_hu: {
+		"undo":           "Visszavonás",
+		"redo":           "Mégis",
+		"cut":            "Kivágás",
+		"copy":           "Másolás",
+		"paste":          "Beillesztés",
+		"selectAll":      "Összes kijelölése",
+		"copyLink":       "Link másolása",
+		"copyImageLink":  "Kép linkjének másolása",
+		"imageSaveAs":    "Kép mentése másként",
+		"back":           "Vissza",
+		"forward":        "Előre",
+		"print":          "Nyomtatás",
+		"closeBrowser":   "Oldal bezárása",
+		"refresh":        "Újratöltés",
+		"forcedRefresh":  "Újratöltés kényszerítése",
+		"viewPageSource": "Oldal forrásának megtekintése",
+		"devTools":       "Fejlesztői eszközök(F12)",
+	},
+	consts.LANGUAGE_id: {
+		"undo":           "Urungkan",
+		"redo":           "Ulangi",
+		"cut":            "Potong",
+		"copy":           "Salin",
+		"paste":          "Tempel",
+		"selectAll":      "Pilih semua",
+		"copyLink":       "Salin link",
+		"copyImageLink":  "Salin link gambar",
+		"imageSaveAs":    "Simpan gambar sebagai",
+		"back":           "Kembali",
+		"forward":        "Maju",
+		"print":          "Cetak",
+		"closeBrowser":   "Tutup halaman",
+		"refresh":        "Muat ulang",
+		"forcedRefresh":  "Paksa muat ulang",
+		"viewPageSource": "Lihat sumber halaman",
+		"devTools":       "Alat developer(F12)",
+	},
+	consts.LANGUAGE_it: {
+		"undo":           "Annulla",
+		"redo":           "Ripeti",
+		"cut":            "Taglia",
+		"copy":           "Copia",
+		"paste":          "Incolla",
+		"selectAll":      "Seleziona tutto",
+		"copyLink":       "Copia link",
+		"copyImageLink":  "Copia link immagine",
+		"imageSaveAs":    "Salva immagine con nome",
+		"back":           "Indietro",
+		"forward":        "Avanti",
+		"print":          "Stampa",
+		"closeBrowser":   "Chiudi pagina",
+		"refresh":        "Ricarica",
+		"forcedRefresh":  "Forza ricaricamento",
+		"viewPageSource": "Visualizza sorgente pagina",
+		"devTools":       "Strumenti per sviluppatori(F12)",
+	},
+	consts.LANGUAGE_ja: {
+		"undo":           "元に戻す",
+		"redo":           "やり直し",
+		"cut":            "切り取り",
+		"copy":           "コピー",
+		"paste":          "貼り付け",
+		"selectAll":      "すべて選択",
+		"copyLink":       "リンクをコピー",
+		"copyImageLink":  "画像リンクをコピー",
+		"imageSaveAs":    "名前を付けて画像を保存",
+		"back":           "戻る",
+		"forward":        "進む",
+		"print":          "印刷",
+		"closeBrowser":   "ページを閉じる",
+		"refresh":        "再読み込み",
+		"forcedRefresh":  "強制再読み込み",
+		"viewPageSource": "ページのソースを表示",
+		"devTools":       "デベロッパー ツール(F12)",
+	},
+	consts.LANGUAGE_kn: {
+		"undo":           "ರದ್ದುಮಾಡು",
+		"redo":           "ಮತ್ತೆಮಾಡು",
+		"cut":            "ಕತ್ತರಿಸು",
+		"copy":           "ನಕಲಿಸು",
+		"paste":          "ಅಂಟಿಸು",
+		"selectAll":      "ಎಲ್ಲವನ್ನೂ ಆಯ್ಕೆಮಾಡು",
+		"copyLink":       "ಲಿಂಕ್ ನಕಲಿಸು",
+		"copyImageLink":  "ಚಿತ್ರದ ಲಿಂಕ್ ನಕಲಿಸು",
+		"imageSaveAs":    "ಚಿತ್ರವನ್ನು ಹೀಗೆ ಉಳಿಸು",
+		"back":           "ಹಿಂದೆ",
+		"forward":        "ಮುಂದೆ",
+		"print":          "ಮುದ್ರಿಸು",
+		"closeBrowser":   "ಪುಟ ಮುಚ್ಚು",
+		"refresh":        "ರಿಫ್ರೆಶ್",
+		"forcedRefresh":  "ಬಲವಂತವಾಗಿ ರಿಫ್ರೆಶ್",
+		"viewPageSource": "ಪುಟದ ಮೂಲ ವೀಕ್ಷಿಸು",
+		"devTools":       "ಡೆವಲಪರ್ ಪರಿಕರಗಳು(F12)",
+	},
+	consts.LANGUAGE_ko: {
+		"undo":           "실행 취소",
+		"redo":           "다시 실행",
+		"cut":            "잘라내기",
+		"copy":           "복사",
+		"paste":          "붙여넣기",
+		"selectAll":      "모두 선택",
+		"copyLink":       "링크 복사",
+		"copyImageLink":  "이미지 링크 복사",
+		"imageSaveAs":    "다른 이름으로 이미지 저장",
+		"back":           "뒤로",
+		"forward":        "앞으로",
+		"print":          "인쇄",
+		"closeBrowser":   "페이지 닫기",
+		"refresh":        "새로고침",
+		"forcedRefresh":  "강력 새로고침",
+		"viewPageSource": "페이지 소스 보기",
+		"devTools":       "개발자 도구(F12)",
+	},
+	consts.LANGUAGE_lt: {
+		"undo":           "Anuliuoti",
+		"redo":           "Perdaryti",
+		"cut":            "Iškirpti",
+		"copy":           "Kopijuoti",
+		"paste":          "Įklijuoti",
+		"selectAll":      "Pasirinkti viską",
+		"copyLink":       "Kopijuoti nuorodą",
+		"copyImageLink":  "Kopijuoti vaizdo nuorodą",
+		"imageSaveAs":    "Išsaugoti vaizdą kaip",
+		"back":           "Atgal",
+		"forward":        "Pirmyn",
+		"print":          "Spausdinti",
+		"closeBrowser":   "Uždaryti puslapį",
+		"refresh":        "Įkelti iš naujo",
+		"forcedRefresh":  "Priverstinai įkelti iš naujo",
+		"viewPageSource": "Peržiūrėti puslapio šaltinį",
+		"devTools":       "Kūrėjo įrankiai(F12)",
+	},
+	consts.LANGUAGE_lv: {
+		"undo":           "Atsaukt",
+		"redo":           "Atcelt atsaukšanu",
+		"cut":            "Izgriezt",
+		"copy":           "Kopēt",
+		"paste":          "Ielīmēt",
+		"selectAll":      "Atlasīt visu",
+		"copyLink":       "Kopēt saiti",
+		"copyImageLink":  "Kopēt attēla saiti",
+		"imageSaveAs":    "Saglabāt attēlu kā",
+		"back":           "Atpakaļ",
+		"forward":        "Uz priekšu",
+		"print":          "Drukāt",
+		"closeBrowser":   "Aizvērt lapu",
+		"refresh":        "Pārlādēt",
+		"forcedRefresh":  "Piespiedu pārlāde",
+		"viewPageSource": "Skatīt lapas avotu",
+		"devTools":       "Izstrādātāju rīki(F12)",
+	},
+	consts.LANGUAGE_ml: {
+		"undo":           "പഴയപടിയാക്കുക",
+		"redo":           "വീണ്ടും ചെയ്യുക",
+		"cut":            "മുറിക്കുക",
+		"copy":           "പകർത്തുക",
+		"paste":          "ഒട്ടിക്കുക",
+		"selectAll":      "എല്ലാം തിരഞ്ഞെടുക്കുക",
+		"copyLink":       "ലിങ്ക് പകർത്തുക",
+		"copyImageLink":  "ചിത്രത്തിന്റെ ലിങ്ക് പകർത്തുക",
+		"imageSaveAs":    "ചിത്രം ഇതായി സംരക്ഷിക്കുക",
+		"back":           "പിന്നോട്ട്",
+		"forward":        "മുന്നോട്ട്",
+		"print":          "പ്രിന്റ് ചെയ്യുക",
+		"closeBrowser":   "പേജ് അടയ്ക്കുക",
+		"refresh":        "പുതുക്കുക",
+		"forcedRefresh":  "നിർബന്ധിതമായി പുതുക്കുക",
+		"viewPageSource": "പേജ് ഉറവിടം കാണുക",
+		"devTools":       "ഡെവലപ്പർ ടൂളുകൾ(F12)",
+	},
+	consts.LANGUAGE_mr: {
+		"undo":           "पूर्ववत करा",
+		"redo":           "पुन्हा करा",
+		"cut":            "कट करा",
+		"copy":           "कॉपी करा",
+		"paste":          "पेस्ट करा",
+		"selectAll":      "सर्व निवडा",
+		"copyLink":       "लिंक कॉपी करा",
+		"copyImageLink":  "इमेजची लिंक कॉपी करा",
+		"imageSaveAs":    "इमेज म्हणून सेव्ह करा",
+		"back":           "मागे",
+		"forward":        "पुढे",
+		"print":          "प्रिंट करा",
+		"closeBrowser":   "पेज बंद करा",
+		"refresh":        "रीफ्रेश करा",
+		"forcedRefresh":  "सक्तीने रीफ्रेश करा",
+		"viewPageSource": "पेजचा स्रोत पहा",
+		"devTools":       "डेव्हलपर टूल(F12)",
+	},
+	consts.LANGUAGE_ms: {
+		"undo":           "Buat asal",
+		"redo":           "Buat semula",
+		"cut":            "Potong",
+		"copy":           "Salin",
+		"paste":          "Tampal",
+		"selectAll":      "Pilih semua",
+		"copyLink":       "Salin pautan",
+		"copyImageLink":  "Salin pautan imej",
+		"imageSaveAs":    "Simpan imej sebagai",
+		"back":           "Kembali",
+		"forward":        "Ke hadapan",
+		"print":          "Cetak",
+		"closeBrowser":   "Tutup halaman",
+		"refresh":        "Muat semula",
+		"forcedRefresh":  "Paksa muat semula",
+		"viewPageSource": "Lihat sumber halaman",
+		"devTools":       "Alat pembangun(F12)",
+	},
+	consts.LANGUAGE_nb: {
+		"undo":           "Angre",
+		"redo":           "Gjør om",
+		"cut":            "Klipp ut",
+		"copy":           "Kopiér",
+		"paste":          "Lim inn",
+		"selectAll":      "Merk alt",
+		"copyLink":       "Kopiér link",
+		"copyImageLink":  "Kopiér bildelink",
+		"imageSaveAs":    "Lagre bildet som",
+		"back":           "Tilbake",
+		"forward":        "Frem",
+		"print":          "Skriv ut",
+		"closeBrowser":   "Lukk siden",
+		"refresh":        "Last inn på nytt",
+		"forcedRefresh":  "Tving ny innlasting",
+		"viewPageSource": "Vis sidekilde",
+		"devTools":       "Utviklerverktøy(F12)",
+	},
+	consts.LANGUAGE_nl: {
+		"undo":           "Ongedaan maken",
+		"redo":           "Opnieuw",
+		"cut":            "Knippen",
+		"copy":           "Kopiëren",
+		"paste":          "Plakken",
+		"selectAll":      "Alles selecteren",
+		"copyLink":       "Link kopiëren",
+		"copyImageLink":  "Afbeeldingslink kopiëren",
+		"imageSaveAs":    "Afbeelding opslaan als",
+		"back":           "Terug",
+		"forward":        "Vooruit",
+		"print":          "Afdrukken",
+		"closeBrowser":   "Pagina sluiten",
+		"refresh":        "Vernieuwen",
+		"forcedRefresh":  "Geforceerd vernieuwen",
+		"viewPageSource": "Paginabron bekijken",
+		"devTools":       "Ontwikkelaarstools(F12)",
+	},
+	consts.LANGUAGE_pl: {
+		"undo":           "Cofnij",
+		"redo":           "Ponów",
+		"cut":            "Wytnij",
+		"copy":           "Kopiuj",
+		"paste":          "Wklej",
+		"selectAll":      "Zaznacz wszystko",
+		"copyLink":       "Kopiuj link",
+		"copyImageLink":  "Kopiuj link do grafiki",
+		"imageSaveAs":    "Zapisz grafikę jako",
+		"back":           "Wstecz",
+		"forward":        "Dalej",
+		"print":          "Drukuj",
+		"closeBrowser":   "Zamknij stronę",
+		"refresh":        "Odśwież",
+		"forcedRefresh":  "Wymuś odświeżenie",
+		"viewPageSource": "Wyświetl źródło strony",
+		"devTools":       "Narzędzia dla programistów(F12)",
+	},
+	consts.LANGUAGE_pt_BR: {
+		"undo":           "Desfazer",
+		"redo":           "Refazer",
+		"cut":            "Recortar",
+		"copy":           "Copiar",
+		"paste":          "Colar",
+		"selectAll":      "Selecionar tudo",
+		"copyLink":       "Copiar link",
+		"copyImageLink":  "Copiar link da imagem",
+		"imageSaveAs":    "Salvar imagem como",
+		"back":           "Voltar",
+		"forward":        "Avançar",
+		"print":          "Imprimir",
+		"closeBrowser":   "Fechar página",
+		"refresh":        "Recarregar",
+		"forcedRefresh":  "Forçar recarregamento",
+		"viewPageSource": "Exibir código-fonte",
+		"devTools":       "Ferramentas do desenvolvedor(F12)",
+	},
+	consts.LANGUAGE_pt_PT: {
+		"undo":           "Anular",
+		"redo":           "Refazer",
+		"cut":            "Cortar",
+		"copy":           "Copiar",
+		"paste":          "Colar",
+		"selectAll":      "Selecionar tudo",
+		"copyLink":       "Copiar ligação",
+		"copyImageLink":  "Copiar ligação da imagem",
+		"imageSaveAs":    "Guardar imagem como",
+		"back":           "Anterior",
+		"forward":        "Seguinte",
+		"print":          "Imprimir",
+		"closeBrowser":   "Fechar página",
+		"refresh":        "Atualizar",
+		"forcedRefresh":  "Forçar atualização",
+		"viewPageSource": "Ver origem da página",
+		"devTools":       "Ferramentas para programadores(F12)",
+	},
+	consts.LANGUAGE_ro: {
+		"undo":           "Anulează",
+		"redo":           "Repetă",
+		"cut":            "Decupează",
+		"copy":           "Copiază",
+		"paste":          "Inserează",
+		"selectAll":      "Selectează tot",
+		"copyLink":       "Copiază linkul",
+		"copyImageLink":  "Copiază linkul imaginii",
+		"imageSaveAs":    "Salvează imaginea ca",
+		"back":           "Înapoi",
+		"forward":        "Înainte",
+		"print":          "Printează",
+		"closeBrowser":   "Închide pagina",
+		"refresh":        "Reîncarcă",
+		"forcedRefresh":  "Forțează reîncărcarea",
+		"viewPageSource": "Afișează sursa paginii",
+		"devTools":       "Instrumente pentru dezvoltatori(F12)",
+	},
+	consts.LANGUAGE_ru: {
+		"undo":           "Отменить",
+		"redo":           "Повторить",
+		"cut":            "Вырезать",
+		"copy":           "Копировать",
+		"paste":          "Вставить",
+		"selectAll":      "Выделить все",
+		"copyLink":       "Копировать ссылку",
+		"copyImageLink":  "Копировать ссылку на изображение",
+		"imageSaveAs":    "Сохранить изображение как",
+		"back":           "Назад",
+		"forward":        "Вперед",
+		"print":          "Печать",
+		"closeBrowser":   "Закрыть страницу",
+		"refresh":        "Обновить",
+		"forcedRefresh":  "Принудительно обновить",
+		"viewPageSource": "Просмотр кода страницы",
+		"devTools":       "Инструменты разработчика(F12)",
+	},
+	consts.LANGUAGE_sk: {
+		"undo":           "Späť",
+		"redo":           "Znova",
+		"cut":            "Vystrihnúť",
+		"copy":           "Kopírovať",
+		"paste":          "Prilepiť",
+		"selectAll":      "Vybrať všetko",
+		"copyLink":       "Kopírovať odkaz",
+		"copyImageLink":  "Kopírovať odkaz na obrázok",
+		"imageSaveAs":    "Uložiť obrázok ako",
+		"back":           "Späť",
+		"forward":        "Dopredu",
+		"print":          "Tlačiť",
+		"closeBrowser":   "Zavrieť stránku",
+		"refresh":        "Obnoviť",
+		"forcedRefresh":  "Vynútiť obnovenie",
+		"viewPageSource": "Zobraziť zdrojový kód stránky",
+		"devTools":       "Nástroje pre vývojárov(F12)",
+	},
+	consts.LANGUAGE_sl: {
+		"undo":           "Razveljavi",
+		"redo":           "Uveljavi",
+		"cut":            "Izreži",
+		"copy":           "Kopiraj",
+		"paste":          "Prilepi",
+		"selectAll":      "Izberi vse",
+		"copyLink":       "Kopiraj povezavo",
+		"copyImageLink":  "Kopiraj povezavo do slike",
+		"imageSaveAs":    "Shrani sliko kot",
+		"back":           "Nazaj",
+		"forward":        "Naprej",
+		"print":          "Natisni",
+		"closeBrowser":   "Zapri stran",
+		"refresh":        "Znova naloži",
+		"forcedRefresh":  "Vsili vnovično nalaganje",
+		"viewPageSource": "Prikaži izvorno kodo strani",
+		"devTools":       "Orodja za razvijalce(F12)",
+	},
+	consts.LANGUAGE_sr: {
+		"undo":           "Опозови",
+		"redo":           "Понови",
+		"cut":            "Исеци",
+		"copy":           "Копирај",
+		"paste":          "Налепи",
+		"selectAll":      "Изабери све",
+		"copyLink":       "Копирај линк",
+		"copyImageLink":  "Копирај линк слике",
+		"imageSaveAs":    "Сачувај слику као",
+		"back":           "Назад",
+		"forward":        "Напред",
+		"print":          "Одштампај",
+		"closeBrowser":   "Затвори страницу",
+		"refresh":        "Поново учитај",
+		"forcedRefresh":  "Принудно поново учитај",
+		"viewPageSource": "Прикажи извор странице",
+		"devTools":       "Алатке за програмере(F12)",
+	},
+	consts.LANGUAGE_sv: {
+		"undo":           "Ångra",
+		"redo":           "Gör om",
+		"cut":            "Klipp ut",
+		"copy":           "Kopiera",
+		"paste":          "Klistra in",
+		"selectAll":      "Markera allt",
+		"copyLink":       "Kopiera länk",
+		"copyImageLink":  "Kopiera bildlänk",
+		"imageSaveAs":    "Spara bild som",
+		"back":           "Bakåt",
+		"forward":        "Framåt",
+		"print":          "Skriv ut",
+		"closeBrowser":   "Stäng sidan",
+		"refresh":        "Läs in igen",
+		"forcedRefresh":  "Tvinga omläsning",
+		"viewPageSource": "Visa sidkälla",
+		"devTools":       "Utvecklarverktyg(F12)",
+	},
+	consts.LANGUAGE_sw: {
+		"undo":           "Tendua",
+		"redo":           "Rudia",
+		"cut":            "Kata",
+		"copy":           "Nakili",
+		"paste":          "Bandika",
+		"selectAll":      "Chagua zote",
+		"copyLink":       "Nakili kiungo",
+		"copyImageLink":  "Nakili kiungo cha picha",
+		"imageSaveAs":    "Hifadhi picha kama",
+		"back":           "Rudi nyuma",
+		"forward":        "Mbele",
+		"print":          "Chapisha",
+		"closeBrowser":   "Funga ukurasa",
+		"refresh":        "Pakia upya",
+		"forcedRefresh":  "Lazimisha kupakia upya",
+		"viewPageSource": "Angalia chanzo cha ukurasa",
+		"devTools":       "Zana za wasanidi programu(F12)",
+	},
+	consts.LANGUAGE_ta: {
+		"undo":           "செயல்தவிர்",
+		"redo":           "மீண்டும் செய்",
+		"cut":            "வெட்டு",
+		"copy":           "நகலெடு",
+		"paste":          "ஒட்டு",
+		"selectAll":      "எல்லாம் தேர்ந்தெடு",
+		"copyLink":       "இணைப்பை நகலெடு",
+		"copyImageLink":  "படத்தின் இணைப்பை நகலெடு",
+		"imageSaveAs":    "படத்தை இவ்வாறு சேமி",
+		"back":           "பின்செல்",
+		"forward":        "முன்செல்",
+		"print":          "அச்சிடு",
+		"closeBrowser":   "பக்கத்தை மூடு",
+		"refresh":        "மீண்டும் ஏற்று",
+		"forcedRefresh":  "கட்டாயமாக மீண்டும் ஏற்று",
+		"viewPageSource": "பக்க மூலத்தைக் காட்டு",
+		"devTools":       "டெவெலப்பர் கருவிகள்(F12)",
+	},
+	consts.LANGUAGE_te: {
+		"undo":           "చర్యరద్దు చేయి",
+		"redo":           "పునరావృతం చేయి",
+		"cut":            "కత్తిరించు",
+		"copy":           "కాపీ చేయి",
+		"paste":          "అతికించు",
+		"selectAll":      "అన్నీ ఎంచుకో",
+		"copyLink":       "లింక్‌ను కాపీ చేయి",
+		"copyImageLink":  "చిత్రం లింక్‌ను కాపీ చేయి",
+		"imageSaveAs":    "చిత్రాన్ని ఇలా సేవ్ చేయి",
+		"back":           "వెనుకకు",
+		"forward":        "ముందుకు",
+		"print":          "ముద్రించు",
+		"closeBrowser":   "పేజీని మూసివేయి",
+		"refresh":        "రిఫ్రెష్ చేయి",
+		"forcedRefresh":  "బలవంతంగా రిఫ్రెష్ చేయి",
+		"viewPageSource": "పేజీ సోర్స్‌ను చూడు",
+		"devTools":       "డెవలపర్ సాధనాలు(F12)",
+	},
+	consts.LANGUAGE_th: {
+		"undo":           "เลิกทำ",
+		"redo":           "ทำซ้ำ",
+		"cut":            "ตัด",
+		"copy":           "คัดลอก",
+		"paste":          "วาง",
+		"selectAll":      "เลือกทั้งหมด",
+		"copyLink":       "คัดลอกลิงก์",
+		"copyImageLink":  "คัดลอกลิงก์รูปภาพ",
+		"imageSaveAs":    "บันทึกรูปภาพเป็น",
+		"back":           "ย้อนกลับ",
+		"forward":        "ไปข้างหน้า",
+		"print":          "พิมพ์",
+		"closeBrowser":   "ปิดหน้า",
+		"refresh":        "โหลดซ้ำ",
+		"forcedRefresh":  "บังคับโหลดซ้ำ",
+		"viewPageSource": "ดูซอร์สของหน้า",
+		"devTools":       "เครื่องมือสำหรับนักพัฒนาซอฟต์แวร์(F12)",
+	},
+	consts.LANGUAGE_tr: {
+		"undo":           "Geri al",
+		"redo":           "Yinele",
+		"cut":            "Kes",
+		"copy":           "Kopyala",
+		"paste":          "Yapıştır",
+		"selectAll":      "Tümünü seç",
+		"copyLink":       "Bağlantıyı kopyala",
+		"copyImageLink":  "Resim bağlantısını kopyala",
+		"imageSaveAs":    "Resmi farklı kaydet",
+		"back":           "Geri",
+		"forward":        "İleri",
+		"print":          "Yazdır",
+		"closeBrowser":   "Sayfayı kapat",
+		"refresh":        "Yeniden yükle",
+		"forcedRefresh":  "Yeniden yüklemeye zorla",
+		"viewPageSource": "Sayfa kaynağını görüntüle",
+		"devTools":       "Geliştirici araçları(F12)",
+	},
+	consts.LANGUAGE_uk: {
+		"undo":           "Скасувати",
+		"redo":           "Повторити",
+		"cut":            "Вирізати",
+		"copy":           "Копіювати",
+		"paste":          "Вставити",
+		"selectAll":      "Вибрати все",
+		"copyLink":       "Копіювати посилання",
+		"copyImageLink":  "Копіювати посилання на зображення",
+		"imageSaveAs":    "Зберегти зображення як",
+		"back":           "Назад",
+		"forward":        "Уперед",
+		"print":          "Друк",
+		"closeBrowser":   "Закрити сторінку",
+		"refresh":        "Оновити",
+		"forcedRefresh":  "Примусово оновити",
+		"viewPageSource": "Переглянути код сторінки",
+		"devTools":       "Інструменти розробника(F12)",
+	},
+	consts.LANGUAGE_vi: {
+		"undo":           "Hoàn tác",
+		"redo":           "Làm lại",
+		"cut":            "Cắt",
+		"copy":           "Sao chép",
+		"paste":          "Dán",
+		"selectAll":      "Chọn tất cả",
+		"copyLink":       "Sao chép đường liên kết",
+		"copyImageLink":  "Sao chép đường liên kết hình ảnh",
+		"imageSaveAs":    "Lưu hình ảnh thành",
+		"back":           "Quay lại",
+		"forward":        "Tiến",
+		"print":          "In",
+		"closeBrowser":   "Đóng trang",
+		"refresh":        "Tải lại",
+		"forcedRefresh":  "Buộc tải lại",
+		"viewPageSource": "Xem nguồn trang",
+		"devTools":       "Công cụ cho nhà phát triển(F12)",
+	},
 }
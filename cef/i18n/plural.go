@@ -0,0 +1,156 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package i18n
+
+import (
+	"github.com/energye/energy/v2/consts"
+	"strings"
+)
+
+// CLDR 复数类别
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralCategory
+//	根据 CLDR 复数规则返回整数数量所属的复数类别
+//	https://www.unicode.org/cldr/charts/latest/supplemental/language_plural_rules.html
+func PluralCategory(lang consts.LANGUAGE, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	tag := string(lang)
+	if i := strings.Index(tag, "-"); i > 0 {
+		tag = tag[:i]
+	}
+	n10, n100 := n%10, n%100
+	switch tag {
+	case "zh", "ja", "ko", "th", "vi", "id", "ms":
+		return PluralOther
+	case "am", "bn", "fa", "gu", "hi", "kn":
+		// i = 0 or n = 1
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+	case "fr":
+		// i = 0,1
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+		if n != 0 && n%1000000 == 0 {
+			return PluralMany
+		}
+	case "pt":
+		if lang == consts.LANGUAGE_pt_PT {
+			if n == 1 {
+				return PluralOne
+			}
+		} else if n == 0 || n == 1 {
+			return PluralOne
+		}
+		if n != 0 && n%1000000 == 0 {
+			return PluralMany
+		}
+	case "es", "it", "ca":
+		if n == 1 {
+			return PluralOne
+		}
+		if n != 0 && n%1000000 == 0 {
+			return PluralMany
+		}
+	case "fil":
+		// i = 1,2,3 or i % 10 != 4,6,9
+		if n == 1 || n == 2 || n == 3 || (n10 != 4 && n10 != 6 && n10 != 9) {
+			return PluralOne
+		}
+	case "he":
+		if n == 1 {
+			return PluralOne
+		} else if n == 2 {
+			return PluralTwo
+		}
+	case "lv":
+		if n10 == 0 || (n100 >= 11 && n100 <= 19) {
+			return PluralZero
+		} else if n10 == 1 && n100 != 11 {
+			return PluralOne
+		}
+	case "lt":
+		if n10 == 1 && !(n100 >= 11 && n100 <= 19) {
+			return PluralOne
+		} else if n10 >= 2 && n10 <= 9 && !(n100 >= 11 && n100 <= 19) {
+			return PluralFew
+		}
+	case "ru", "uk":
+		if n10 == 1 && n100 != 11 {
+			return PluralOne
+		} else if n10 >= 2 && n10 <= 4 && !(n100 >= 12 && n100 <= 14) {
+			return PluralFew
+		}
+		return PluralMany
+	case "pl":
+		if n == 1 {
+			return PluralOne
+		} else if n10 >= 2 && n10 <= 4 && !(n100 >= 12 && n100 <= 14) {
+			return PluralFew
+		}
+		return PluralMany
+	case "cs", "sk":
+		if n == 1 {
+			return PluralOne
+		} else if n >= 2 && n <= 4 {
+			return PluralFew
+		}
+	case "hr", "sr":
+		if n10 == 1 && n100 != 11 {
+			return PluralOne
+		} else if n10 >= 2 && n10 <= 4 && !(n100 >= 12 && n100 <= 14) {
+			return PluralFew
+		}
+	case "sl":
+		if n100 == 1 {
+			return PluralOne
+		} else if n100 == 2 {
+			return PluralTwo
+		} else if n100 == 3 || n100 == 4 {
+			return PluralFew
+		}
+	case "ro":
+		if n == 1 {
+			return PluralOne
+		} else if n == 0 || (n != 1 && n100 >= 1 && n100 <= 19) {
+			return PluralFew
+		}
+	case "ar":
+		if n == 0 {
+			return PluralZero
+		} else if n == 1 {
+			return PluralOne
+		} else if n == 2 {
+			return PluralTwo
+		} else if n100 >= 3 && n100 <= 10 {
+			return PluralFew
+		} else if n100 >= 11 && n100 <= 99 {
+			return PluralMany
+		}
+	default:
+		// en, de, nl, sv, da, nb, fi, et, el, bg, hu, tr, sw, ml, mr, ta, te ...
+		if n == 1 {
+			return PluralOne
+		}
+	}
+	return PluralOther
+}
@@ -0,0 +1,187 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// i18n 多语言同步
+//  主进程切换语言后将资源同步到渲染进程
+//  渲染进程提供 JS energy.i18n 扩展
+
+package cef
+
+import (
+	"encoding/json"
+	"github.com/energye/energy/v2/cef/i18n"
+	"github.com/energye/energy/v2/consts"
+	"sync/atomic"
+)
+
+// i18n process message name
+const internalI18nSwitch = "I18nSwitch" // GO 主进程切换语言 - 同步到渲染进程
+
+// i18n JS extension native function name
+const (
+	i18nLang   = "lang"
+	i18nT      = "t"
+	i18nPlural = "plural"
+)
+
+// i18nSwitched 主进程运行后是否切换过语言, 切换后新的页面需要同步
+var i18nSwitched int32
+
+// i18nSwitchData 语言切换同步数据
+type i18nSwitchData struct {
+	Lang      string            `json:"lang"`
+	Resources map[string]string `json:"resources"`
+}
+
+// i18nBrowserInit 主进程, 语言切换后同步到所有窗口的渲染进程
+func i18nBrowserInit() {
+	i18n.AddSwitchListener(func(lang consts.LANGUAGE) {
+		atomic.StoreInt32(&i18nSwitched, 1)
+		for _, window := range BrowserWindow.GetWindowInfos() {
+			if browser := window.Browser(); browser != nil && browser.IsValid() {
+				i18nSendSwitch(browser, browser.MainFrame())
+			}
+		}
+	})
+}
+
+// i18nSyncOnBeforeBrowser 主进程切换过语言, 页面加载前同步到渲染进程
+func i18nSyncOnBeforeBrowser(browser *ICefBrowser, frame *ICefFrame) {
+	if atomic.LoadInt32(&i18nSwitched) == 1 {
+		i18nSendSwitch(browser, frame)
+	}
+}
+
+// i18nSendSwitch 发送当前语言和资源到渲染进程
+func i18nSendSwitch(browser *ICefBrowser, frame *ICefFrame) {
+	data, err := json.Marshal(&i18nSwitchData{Lang: string(i18n.Language()), Resources: i18n.Resources()})
+	if err != nil {
+		return
+	}
+	if application.IsSpecVer49() {
+		// CEF49
+		browser.SendProcessMessageForJSONBytes(internalI18nSwitch, consts.PID_RENDER, data)
+	} else if frame != nil {
+		frame.SendProcessMessageForJSONBytes(internalI18nSwitch, consts.PID_RENDER, data)
+	}
+}
+
+// i18nSwitchMessage 渲染进程, 接收主进程语言切换并通知JS
+func i18nSwitchMessage(frame *ICefFrame, message *ICefProcessMessage) (result bool) {
+	result = true
+	binaryValue := message.ArgumentList().GetBinary(0)
+	if binaryValue == nil {
+		return
+	}
+	var dataBytes []byte
+	if binaryValue.IsValid() {
+		dataBytes = make([]byte, binaryValue.GetSize())
+		c := binaryValue.GetData(dataBytes, 0)
+		binaryValue.Free()
+		if c == 0 {
+			return
+		}
+	}
+	var data i18nSwitchData
+	if json.Unmarshal(dataBytes, &data) != nil {
+		return
+	}
+	i18n.SetResources(consts.LANGUAGE(data.Lang), data.Resources)
+	if frame != nil && frame.IsValid() {
+		lang, _ := json.Marshal(data.Lang)
+		frame.ExecuteJavaScript(`if (typeof energy !== "undefined" && energy.i18n) { energy.i18n.switched(`+string(lang)+`); }`, "", 0)
+	}
+	return
+}
+
+// i18nExtensionHandler 渲染进程, 注册 JS energy.i18n 扩展
+//  energy.i18n.lang()                     当前语言
+//  energy.i18n.t(name, params)            资源, params 替换插值 {name}
+//  energy.i18n.plural(name, count, params) 复数资源
+//  energy.i18n.onSwitch(fn)               语言切换监听
+func i18nExtensionHandler() {
+	handler := V8HandlerRef.New()
+	handler.Execute(func(name string, object *ICefV8Value, arguments *TCefV8ValueArray, retVal *ResultV8Value, exception *ResultString) bool {
+		switch name {
+		case i18nLang:
+			retVal.SetResult(V8ValueRef.NewString(string(i18n.Language())))
+			return true
+		case i18nT, i18nPlural:
+			var (
+				key    string
+				count  int32
+				params i18n.Params
+			)
+			for i := 0; i < arguments.Size(); i++ {
+				arg := arguments.Get(i)
+				if i == 0 {
+					key = arg.GetStringValue()
+				} else if name == i18nPlural && i == 1 {
+					count = arg.GetIntValue()
+				} else if arg.IsString() {
+					if v := arg.GetStringValue(); v != "" {
+						json.Unmarshal([]byte(v), &params)
+					}
+				}
+				arg.Free()
+			}
+			var value string
+			if name == i18nPlural {
+				value = i18n.Plural(key, int(count), params)
+			} else {
+				value = i18n.T(key, params)
+			}
+			retVal.SetResult(V8ValueRef.NewString(value))
+			return true
+		}
+		return false
+	})
+	var code = `
+		let energy;
+		if (!energy) {
+			energy = {};
+		}
+		(function () {
+			let listeners = [];
+			let stringify = function (params) {
+				return params ? JSON.stringify(params) : "";
+			};
+			energy.i18n = {
+				lang: function () {
+					native function lang();
+					return lang();
+				},
+				t: function (name, params) {
+					native function t();
+					return t(name, stringify(params));
+				},
+				plural: function (name, count, params) {
+					native function plural();
+					return plural(name, count, stringify(params));
+				},
+				onSwitch: function (fn) {
+					if (typeof fn === "function") {
+						listeners.push(fn);
+					}
+				},
+				switched: function (lang) {
+					for (let i = 0; i < listeners.length; i++) {
+						try {
+							listeners[i](lang);
+						} catch (e) {
+							console.error(e);
+						}
+					}
+				}
+			};
+		})();
+`
+	RegisterExtension("energyI18n", code, handler)
+}
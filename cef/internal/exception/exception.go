@@ -23,23 +23,24 @@ import (
 type Callback func(message string)
 
 var (
-	exceptionHandler         dllimports.ProcAddr
-	exceptionHandlerCallback Callback
+	exceptionHandler          dllimports.ProcAddr
+	exceptionHandlerCallbacks []Callback
 )
 
 // HandlerInit
-// 底层库异常处理器初始化
+// 底层库异常处理器初始化, 多次调用时按顺序调用所有回调函数
 func HandlerInit(fn Callback) {
-	if exceptionHandlerCallback == nil {
+	if exceptionHandlerCallbacks == nil {
 		exceptionHandler = imports.Proc(def.SetExceptionHandlerCallback)
 		exceptionHandler.Call(exceptionHandlerProcEventAddr)
-		exceptionHandlerCallback = fn
 	}
+	exceptionHandlerCallbacks = append(exceptionHandlerCallbacks, fn)
 }
 
 func exceptionHandlerProc(message uintptr) uintptr {
-	if exceptionHandlerCallback != nil {
-		exceptionHandlerCallback(api.GoStr(message))
+	msg := api.GoStr(message)
+	for _, fn := range exceptionHandlerCallbacks {
+		fn(msg)
 	}
 	return 0
 }
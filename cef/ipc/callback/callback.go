@@ -11,8 +11,9 @@
 package callback
 
 import (
-	goJSON "encoding/json"
+	"fmt"
 	"github.com/energye/energy/v2/cef/ipc/context"
+	"github.com/energye/energy/v2/pkgs/crash"
 	"github.com/energye/energy/v2/pkgs/json"
 	"reflect"
 )
@@ -74,6 +75,7 @@ func (m *Callback) ContextCallback() *ContextCallback {
 
 // Invoke context function
 func (m *ContextCallback) Invoke(context context.IContext) {
+	defer crash.RecoverWith("ipc", recoverResult(context))
 	// call
 	m.Callback(context)
 	resultValues := context.Replay().Result()
@@ -95,6 +97,26 @@ func (m *ContextCallback) Invoke(context context.IContext) {
 	}
 }
 
+// PanicError
+//	The result replied when the listening function panics, instead of the normal result
+//	JSON: {"error": "ipc: panic: ...", "panic": true}, the caller checks the panic field
+type PanicError struct {
+	Message string `json:"error"`
+	Panic   bool   `json:"panic"`
+}
+
+func (m *PanicError) Error() string {
+	return m.Message
+}
+
+// recoverResult
+//	Reply a PanicError after a panic is recovered, the caller does not wait for the result
+func recoverResult(context context.IContext) func(err interface{}) {
+	return func(err interface{}) {
+		context.Result(&PanicError{Message: fmt.Sprintf("ipc: panic: %v", err), Panic: true})
+	}
+}
+
 // ArgumentCallback
 //	return argument list callback function
 func (m *Callback) ArgumentCallback() *ArgumentCallback {
@@ -106,6 +128,7 @@ func (m *Callback) ArgumentCallback() *ArgumentCallback {
 
 // Invoke argument list function
 func (m *ArgumentCallback) Invoke(context context.IContext) {
+	defer crash.RecoverWith("ipc", recoverResult(context))
 	var (
 		argsList     json.JSONArray
 		argsSize     int
@@ -122,79 +145,7 @@ func (m *ArgumentCallback) Invoke(context context.IContext) {
 	for i := 0; i < inArgsCount; i++ {
 		inType := rt.In(i)
 		if inIdx < argsSize {
-			argsValue := argsList.GetByIndex(inIdx)
-			if argsValue != nil {
-				switch inType.Kind() {
-				case reflect.String:
-					inArgsValues[i] = reflect.ValueOf(argsValue.String())
-				case reflect.Int:
-					inArgsValues[i] = reflect.ValueOf(argsValue.Int())
-				case reflect.Int8:
-					inArgsValues[i] = reflect.ValueOf(int8(argsValue.Int()))
-				case reflect.Int16:
-					inArgsValues[i] = reflect.ValueOf(int16(argsValue.Int()))
-				case reflect.Int32:
-					inArgsValues[i] = reflect.ValueOf(int32(argsValue.Int()))
-				case reflect.Int64:
-					inArgsValues[i] = reflect.ValueOf(int64(argsValue.Int()))
-				case reflect.Uint:
-					inArgsValues[i] = reflect.ValueOf(uint(argsValue.Int()))
-				case reflect.Uint8:
-					inArgsValues[i] = reflect.ValueOf(uint8(argsValue.Int()))
-				case reflect.Uint16:
-					inArgsValues[i] = reflect.ValueOf(uint16(argsValue.Int()))
-				case reflect.Uint32:
-					inArgsValues[i] = reflect.ValueOf(uint32(argsValue.Int()))
-				case reflect.Uint64:
-					inArgsValues[i] = reflect.ValueOf(uint64(argsValue.Int()))
-				case reflect.Uintptr:
-					inArgsValues[i] = reflect.ValueOf(uintptr(argsValue.Int()))
-				case reflect.Float32:
-					inArgsValues[i] = reflect.ValueOf(float32(argsValue.Float()))
-				case reflect.Float64:
-					inArgsValues[i] = reflect.ValueOf(argsValue.Float())
-				case reflect.Bool:
-					inArgsValues[i] = reflect.ValueOf(argsValue.Bool())
-				case reflect.Struct:
-					if argsValue.IsObject() {
-						// struct
-						if jsonBytes := argsValue.Bytes(); jsonBytes != nil {
-							v := reflect.New(inType)
-							if err := goJSON.Unmarshal(jsonBytes, v.Interface()); err == nil {
-								inArgsValues[i] = v.Elem()
-							}
-						}
-					}
-				case reflect.Map:
-					if argsValue.IsObject() {
-						// map key=string : value != interface
-						if inType.Elem().Kind() != reflect.Interface {
-							if jsonBytes := argsValue.Bytes(); jsonBytes != nil {
-								vv := reflect.New(inType)
-								if err := goJSON.Unmarshal(jsonBytes, vv.Interface()); err == nil {
-									inArgsValues[i] = vv.Elem()
-								}
-							}
-						} else {
-							inArgsValues[i] = reflect.ValueOf(argsValue.Data())
-						}
-					}
-				case reflect.Slice:
-					if argsValue.IsArray() {
-						// slice value != interface
-						if inType.Elem().Kind() != reflect.Interface {
-							if jsonBytes := argsValue.Bytes(); jsonBytes != nil {
-								vv := reflect.New(inType)
-								if err := goJSON.Unmarshal(jsonBytes, vv.Interface()); err == nil {
-									inArgsValues[i] = vv.Elem()
-								}
-							}
-						} else {
-							inArgsValues[i] = reflect.ValueOf(argsValue.Data())
-						}
-					}
-				}
-			}
+			inArgsValues[i] = json.ReflectValue(argsList.GetByIndex(inIdx), inType)
 		}
 		if !inArgsValues[i].IsValid() {
 			newValue := reflect.New(inType).Elem()
@@ -15,6 +15,7 @@ import (
 	"github.com/energye/energy/v2/cef/process"
 	. "github.com/energye/energy/v2/consts"
 	"github.com/energye/energy/v2/logger"
+	"github.com/energye/energy/v2/pkgs/assetserve"
 	"github.com/energye/golcl/energy/emfs"
 	"io/ioutil"
 	"net/url"
@@ -69,6 +70,7 @@ type source struct {
 	mimeType     string              // 响应的资源 MimeType
 	header       map[string][]string // 响应头
 	resourceType TCefResourceType    // 资源类型
+	version      string              // URL 版本参数, 内容哈希前缀时永久缓存
 }
 
 // 初始化本地加载配置对象
@@ -248,7 +250,7 @@ func (m *LocalLoadResource) checkRequest(request *ICefRequest) (*source, bool) {
 		path = m.Home
 	}
 	ext := m.ext(path)
-	return &source{path: path, fileExt: ext, mimeType: m.getMimeType(ext), resourceType: rt}, true
+	return &source{path: path, fileExt: ext, mimeType: m.getMimeType(ext), resourceType: rt, version: reqUrl.Query().Get(assetserve.VersionQuery)}, true
 }
 
 // 读取本地或内置资源
@@ -297,6 +299,10 @@ func (m *source) open(request *ICefRequest, callback *ICefCallback) (handleReque
 			m.statusText = err.Error()
 		}
 	} else {
+		if m.notModified(request) {
+			callback.Cont()
+			return true, true
+		}
 		m.readFile()
 		if m.err == nil {
 			m.statusCode = 200
@@ -326,6 +332,29 @@ func (m *source) open(request *ICefRequest, callback *ICefCallback) (handleReque
 	return true, true
 }
 
+// notModified
+//  内置资源根据内容哈希设置 ETag 和缓存响应头, 请求的 ETag 未改变时响应 304
+func (m *source) notModified(request *ICefRequest) bool {
+	if localLoadRes.FS == nil {
+		return false
+	}
+	hash := assetserve.ContentHash(localLoadRes.FS, localLoadRes.ResRootDir+m.path)
+	header, notModified := assetserve.CacheHeader(hash, m.version, request.GetHeaderByName("If-None-Match"))
+	if header == nil {
+		return false
+	}
+	m.header = make(map[string][]string, len(header))
+	for key, value := range header {
+		m.header[key] = []string{value}
+	}
+	if notModified {
+		m.bytes = nil
+		m.statusCode = 304
+		m.statusText = "Not Modified"
+	}
+	return notModified
+}
+
 func (m *source) processRequest(request *ICefRequest, callback *ICefCallback) bool {
 	_, _ = m.open(request, callback)
 	return true
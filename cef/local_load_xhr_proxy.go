@@ -205,52 +205,17 @@ func (m *XHRProxy) send(scheme string, request *ICefRequest) (*XHRProxyResponse,
 		targetUrl.WriteString(reqUrl.RawQuery)
 	}
 	// 读取请求数据
-	requestData := new(bytes.Buffer)
-	data := request.GetPostData()
-	if data.IsValid() {
-		dataCount := int(data.GetElementCount())
-		elements := data.GetElements()
-		for i := 0; i < dataCount; i++ {
-			element := elements.Get(uint32(i))
-			switch element.GetType() {
-			case PDE_TYPE_EMPTY:
-			case PDE_TYPE_BYTES:
-				if byt, c := element.GetBytes(); c > 0 {
-					requestData.Write(byt)
-				}
-			case PDE_TYPE_FILE:
-				if f := element.GetFile(); f != "" {
-					if byt, err := ioutil.ReadFile(f); err == nil {
-						requestData.Write(byt)
-					}
-				}
-			}
-			element.Free()
-		}
-		data.Free()
-	}
+	requestData := cefRequestBody(request)
 	tarUrl := targetUrl.String()
 	if logger.Enable() {
-		logger.Debug("XHRProxy URL:", tarUrl, "method:", request.Method(), "data-size:", requestData.Len())
+		logger.Debug("XHRProxy URL:", tarUrl, "method:", request.Method(), "data-size:", len(requestData))
 	}
-	httpRequest, err := http.NewRequest(request.Method(), tarUrl, requestData)
+	httpRequest, err := http.NewRequest(request.Method(), tarUrl, bytes.NewReader(requestData))
 	if err != nil {
 		return nil, err
 	}
 	// 设置请求头
-	header := request.GetHeaderMap()
-	if header.IsValid() {
-		size := header.GetSize()
-		for i := 0; i < int(size); i++ {
-			key := header.GetKey(uint32(i))
-			c := header.FindCount(key)
-			for j := 0; j < int(c); j++ {
-				value := header.GetEnumerate(key, uint32(j))
-				httpRequest.Header.Add(key, value)
-			}
-		}
-		header.Free()
-	}
+	httpRequest.Header = cefRequestHeader(request)
 	//httpRequest.Header.Add("Host", "www.example.com")
 	//httpRequest.Header.Add("Origin", "https://www.example.com")
 	//httpRequest.Header.Add("Referer", "https://www.example.com/")
@@ -0,0 +1,532 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 权限管理, 摄像头、麦克风、定位、通知等权限请求
+
+package cef
+
+import (
+	"encoding/json"
+	"github.com/energye/energy/v2/cef/i18n"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/energy/v2/logger"
+	"github.com/energye/golcl/lcl"
+	"github.com/energye/golcl/lcl/types"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PermissionType 权限类型
+type PermissionType string
+
+const (
+	PermissionAll               PermissionType = "*" // 规则匹配所有权限
+	PermissionCamera            PermissionType = "camera"
+	PermissionMicrophone        PermissionType = "microphone"
+	PermissionScreenCapture     PermissionType = "screen-capture"
+	PermissionGeolocation       PermissionType = "geolocation"
+	PermissionNotifications     PermissionType = "notifications"
+	PermissionClipboard         PermissionType = "clipboard"
+	PermissionMIDI              PermissionType = "midi"
+	PermissionMultipleDownloads PermissionType = "multiple-downloads"
+	PermissionStorageAccess     PermissionType = "storage-access"
+	PermissionLocalFonts        PermissionType = "local-fonts"
+	PermissionIdleDetection     PermissionType = "idle-detection"
+	PermissionWindowManagement  PermissionType = "window-management"
+	PermissionOther             PermissionType = "other" // 其它 CEF 权限请求
+)
+
+// PermissionPolicy 权限策略
+type PermissionPolicy string
+
+const (
+	PermissionAsk   PermissionPolicy = "ask"   // 询问, 未设置询问函数和对话框时使用CEF默认行为
+	PermissionAllow PermissionPolicy = "allow" // 允许
+	PermissionDeny  PermissionPolicy = "deny"  // 拒绝
+)
+
+// 默认询问对话框文本, 可通过 i18n 资源 permissionRequest permissionRemember 设置
+const (
+	permissionRequestText  = "{origin} wants to use: {permissions}. Allow?"
+	permissionRememberText = "Remember my decision"
+)
+
+// CEF 权限请求类型对应的权限
+var permissionPromptTypes = []struct {
+	flag consts.TCefPermissionRequestTypes
+	typ  PermissionType
+}{
+	{consts.CEF_PERMISSION_TYPE_CAMERA_STREAM, PermissionCamera},
+	{consts.CEF_PERMISSION_TYPE_CAMERA_PAN_TILT_ZOOM, PermissionCamera},
+	{consts.CEF_PERMISSION_TYPE_MIC_STREAM, PermissionMicrophone},
+	{consts.CEF_PERMISSION_TYPE_GEOLOCATION, PermissionGeolocation},
+	{consts.CEF_PERMISSION_TYPE_NOTIFICATIONS, PermissionNotifications},
+	{consts.CEF_PERMISSION_TYPE_CLIPBOARD, PermissionClipboard},
+	{consts.CEF_PERMISSION_TYPE_MIDI, PermissionMIDI},
+	{consts.CEF_PERMISSION_TYPE_MIDI_SYSEX, PermissionMIDI},
+	{consts.CEF_PERMISSION_TYPE_MULTIPLE_DOWNLOADS, PermissionMultipleDownloads},
+	{consts.CEF_PERMISSION_TYPE_STORAGE_ACCESS, PermissionStorageAccess},
+	{consts.CEF_PERMISSION_TYPE_TOP_LEVEL_STORAGE_ACCESS, PermissionStorageAccess},
+	{consts.CEF_PERMISSION_TYPE_LOCAL_FONTS, PermissionLocalFonts},
+	{consts.CEF_PERMISSION_TYPE_IDLE_DETECTION, PermissionIdleDetection},
+	{consts.CEF_PERMISSION_TYPE_WINDOW_MANAGEMENT, PermissionWindowManagement},
+}
+
+// CEF 媒体访问类型对应的权限
+var permissionMediaTypes = []struct {
+	flag consts.TCefMediaAccessPermissionTypes
+	typ  PermissionType
+}{
+	{consts.CEF_MEDIA_PERMISSION_DEVICE_VIDEO_CAPTURE, PermissionCamera},
+	{consts.CEF_MEDIA_PERMISSION_DEVICE_AUDIO_CAPTURE, PermissionMicrophone},
+	{consts.CEF_MEDIA_PERMISSION_DESKTOP_VIDEO_CAPTURE, PermissionScreenCapture},
+	{consts.CEF_MEDIA_PERMISSION_DESKTOP_AUDIO_CAPTURE, PermissionScreenCapture},
+}
+
+// PermissionRule
+//	权限规则
+//	Origin: "*" 所有来源, "https://example.com" 指定来源, "*.example.com" 或 "https://*.example.com" 子域名
+//	Type: PermissionAll 或空匹配所有权限
+//	匹配多个规则时, 来源越具体优先, 相同来源时指定权限的优先, 都相同时后添加的优先
+type PermissionRule struct {
+	Origin string           `json:"origin"`
+	Type   PermissionType   `json:"type"`
+	Policy PermissionPolicy `json:"policy"`
+}
+
+// PermissionDecision 保存的用户选择
+type PermissionDecision struct {
+	Origin string         `json:"origin"`
+	Type   PermissionType `json:"type"`
+	Allow  bool           `json:"allow"`
+	Time   time.Time      `json:"time"`
+}
+
+// PermissionRequest 需要询问的权限请求
+type PermissionRequest struct {
+	BrowserId int32
+	Window    IBrowserWindow
+	Origin    string
+	Types     []PermissionType
+}
+
+// PermissionReply 询问结果, 是否允许和是否保存选择, 只调用一次
+type PermissionReply func(allow, remember bool)
+
+// PermissionAskFunc
+//	询问权限, 在CEF回调中调用, 不能阻塞, 可在询问结束后异步调用 reply
+type PermissionAskFunc func(request PermissionRequest, reply PermissionReply)
+
+// PermissionManager
+//	权限管理, 处理所有窗口的摄像头、麦克风、定位、通知等权限请求
+//	决定顺序: 保存的用户选择 > 规则 > 默认策略
+//	询问时使用询问函数或内置对话框, 都没有时使用CEF默认行为
+type PermissionManager struct {
+	lock          sync.Mutex
+	rules         []PermissionRule
+	defaultPolicy PermissionPolicy
+	decisions     map[string]PermissionDecision
+	decisionFile  string
+	ask           PermissionAskFunc
+	dialog        bool
+}
+
+// 启用的权限管理, 在默认的权限请求事件中使用
+var permissionManager *PermissionManager
+
+// NewPermissionManager 创建权限管理, 默认策略为询问, 调用 Enable 后生效
+func NewPermissionManager() *PermissionManager {
+	return &PermissionManager{defaultPolicy: PermissionAsk, decisions: make(map[string]PermissionDecision)}
+}
+
+// Enable 启用权限管理, 所有窗口的默认权限请求事件使用该权限管理, 在创建窗口之前调用
+func (m *PermissionManager) Enable() {
+	permissionManager = m
+}
+
+// SetDefault 设置没有匹配规则时的默认策略
+func (m *PermissionManager) SetDefault(policy PermissionPolicy) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.defaultPolicy = policy
+}
+
+// AddRule 添加权限规则
+func (m *PermissionManager) AddRule(origin string, typ PermissionType, policy PermissionPolicy) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.rules = append(m.rules, PermissionRule{Origin: origin, Type: typ, Policy: policy})
+}
+
+// Rules 返回所有权限规则
+func (m *PermissionManager) Rules() []PermissionRule {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]PermissionRule(nil), m.rules...)
+}
+
+// ClearRules 清空权限规则
+func (m *PermissionManager) ClearRules() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.rules = nil
+}
+
+// SetOnAsk 设置询问函数
+func (m *PermissionManager) SetOnAsk(fn PermissionAskFunc) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.ask = fn
+}
+
+// SetConsentDialog
+//	询问时使用内置的确认对话框, 异步显示, 用户勾选记住时保存选择
+//	对话框文本可通过 i18n 资源 permissionRequest 设置, 插值参数 {origin} {permissions}, 记住选项文本 permissionRemember
+func (m *PermissionManager) SetConsentDialog(enable bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.dialog = enable
+}
+
+// SetDecisionFile 设置用户选择保存文件并加载
+func (m *PermissionManager) SetDecisionFile(file string) error {
+	var decisions []PermissionDecision
+	data, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	} else if err == nil {
+		if err = json.Unmarshal(data, &decisions); err != nil {
+			return err
+		}
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.decisionFile = file
+	m.decisions = make(map[string]PermissionDecision)
+	for _, decision := range decisions {
+		m.decisions[permissionKey(decision.Origin, decision.Type)] = decision
+	}
+	return nil
+}
+
+// Decisions 返回保存的用户选择
+func (m *PermissionManager) Decisions() []PermissionDecision {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.decisionList()
+}
+
+func (m *PermissionManager) decisionList() []PermissionDecision {
+	var decisions = make([]PermissionDecision, 0, len(m.decisions))
+	for _, decision := range m.decisions {
+		decisions = append(decisions, decision)
+	}
+	sort.Slice(decisions, func(i, j int) bool {
+		if decisions[i].Origin != decisions[j].Origin {
+			return decisions[i].Origin < decisions[j].Origin
+		}
+		return decisions[i].Type < decisions[j].Type
+	})
+	return decisions
+}
+
+// Revoke
+//	撤销保存的用户选择, 下次请求时重新决定
+//	typ 为 PermissionAll 或空时撤销该来源的所有选择
+func (m *PermissionManager) Revoke(origin string, typ PermissionType) error {
+	origin = permissionOrigin(origin)
+	m.lock.Lock()
+	for key, decision := range m.decisions {
+		if decision.Origin == origin && (typ == "" || typ == PermissionAll || decision.Type == typ) {
+			delete(m.decisions, key)
+		}
+	}
+	m.lock.Unlock()
+	return m.save()
+}
+
+// RevokeAll 撤销所有保存的用户选择
+func (m *PermissionManager) RevokeAll() error {
+	m.lock.Lock()
+	m.decisions = make(map[string]PermissionDecision)
+	m.lock.Unlock()
+	return m.save()
+}
+
+// Policy 返回来源和权限的当前策略
+func (m *PermissionManager) Policy(origin string, typ PermissionType) PermissionPolicy {
+	origin = permissionOrigin(origin)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.policy(origin, typ)
+}
+
+func (m *PermissionManager) policy(origin string, typ PermissionType) PermissionPolicy {
+	if decision, ok := m.decisions[permissionKey(origin, typ)]; ok {
+		if decision.Allow {
+			return PermissionAllow
+		}
+		return PermissionDeny
+	}
+	var (
+		policy = m.defaultPolicy
+		best   = -1
+	)
+	for _, rule := range m.rules {
+		if rule.Type != "" && rule.Type != PermissionAll && rule.Type != typ {
+			continue
+		}
+		score := permissionOriginScore(rule.Origin, origin)
+		if score < 0 {
+			continue
+		}
+		score *= 2
+		if rule.Type == typ {
+			score++
+		}
+		if score >= best {
+			best, policy = score, rule.Policy
+		}
+	}
+	return policy
+}
+
+// request
+//	决定请求的每个权限是否允许, 结果通过 done 返回
+//	需要询问的权限使用询问函数或对话框, 询问结束后调用 done, 返回 false 时使用CEF默认行为, 不调用 done
+func (m *PermissionManager) request(request PermissionRequest, done func(allowed map[PermissionType]bool)) bool {
+	request.Origin = permissionOrigin(request.Origin)
+	allowed := make(map[PermissionType]bool)
+	var asks []PermissionType
+	m.lock.Lock()
+	for _, typ := range request.Types {
+		switch m.policy(request.Origin, typ) {
+		case PermissionAllow:
+			allowed[typ] = true
+		case PermissionDeny:
+			allowed[typ] = false
+		default:
+			asks = append(asks, typ)
+		}
+	}
+	ask, dialog := m.ask, m.dialog
+	m.lock.Unlock()
+	if len(asks) == 0 {
+		done(allowed)
+		return true
+	}
+	if ask == nil && dialog {
+		ask = permissionConsentDialog
+	}
+	if ask == nil {
+		return false
+	}
+	askRequest := request
+	askRequest.Types = asks
+	var once sync.Once
+	ask(askRequest, func(allow, remember bool) {
+		once.Do(func() {
+			for _, typ := range asks {
+				allowed[typ] = allow
+			}
+			if remember {
+				m.remember(request.Origin, asks, allow)
+			}
+			done(allowed)
+		})
+	})
+	return true
+}
+
+// remember 保存用户选择
+func (m *PermissionManager) remember(origin string, types []PermissionType, allow bool) {
+	m.lock.Lock()
+	for _, typ := range types {
+		m.decisions[permissionKey(origin, typ)] = PermissionDecision{Origin: origin, Type: typ, Allow: allow, Time: time.Now()}
+	}
+	m.lock.Unlock()
+	if err := m.save(); err != nil {
+		logger.Error("permission:", err)
+	}
+}
+
+// save 写入用户选择保存文件
+func (m *PermissionManager) save() error {
+	m.lock.Lock()
+	file := m.decisionFile
+	decisions := m.decisionList()
+	m.lock.Unlock()
+	if file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(decisions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// requestMediaAccess chromium OnRequestMediaAccessPermission, 允许部分媒体权限
+func (m *PermissionManager) requestMediaAccess(window IBrowserWindow, browser *ICefBrowser, origin string, requested uint32, callback *ICefMediaAccessCallback) bool {
+	var permissions []PermissionType
+	for _, media := range permissionMediaTypes {
+		if requested&uint32(media.flag) != 0 {
+			permissions = appendPermissionType(permissions, media.typ)
+		}
+	}
+	if len(permissions) == 0 {
+		return false
+	}
+	return m.request(PermissionRequest{BrowserId: browser.Identifier(), Window: window, Origin: origin, Types: permissions}, func(allowed map[PermissionType]bool) {
+		var result consts.TCefMediaAccessPermissionTypes
+		for _, media := range permissionMediaTypes {
+			if requested&uint32(media.flag) != 0 && allowed[media.typ] {
+				result |= media.flag
+			}
+		}
+		if result == consts.CEF_MEDIA_PERMISSION_NONE {
+			callback.Cancel()
+		} else {
+			callback.Cont(result)
+		}
+	})
+}
+
+// showPermissionPrompt chromium OnShowPermissionPrompt, 全部允许时接受, 否则拒绝
+func (m *PermissionManager) showPermissionPrompt(window IBrowserWindow, browser *ICefBrowser, origin string, requested uint32, callback *ICefPermissionPromptCallback) bool {
+	permissions := permissionPromptTypeList(requested)
+	if len(permissions) == 0 {
+		return false
+	}
+	return m.request(PermissionRequest{BrowserId: browser.Identifier(), Window: window, Origin: origin, Types: permissions}, func(allowed map[PermissionType]bool) {
+		var result = consts.CEF_PERMISSION_RESULT_ACCEPT
+		for _, typ := range permissions {
+			if !allowed[typ] {
+				result = consts.CEF_PERMISSION_RESULT_DENY
+				break
+			}
+		}
+		callback.Cont(result)
+	})
+}
+
+// permissionPromptTypeList CEF 权限请求类型转换为权限列表
+func permissionPromptTypeList(requested uint32) (permissions []PermissionType) {
+	var known uint32
+	for _, prompt := range permissionPromptTypes {
+		known |= uint32(prompt.flag)
+		if requested&uint32(prompt.flag) != 0 {
+			permissions = appendPermissionType(permissions, prompt.typ)
+		}
+	}
+	if requested&^known != 0 {
+		permissions = appendPermissionType(permissions, PermissionOther)
+	}
+	return
+}
+
+func appendPermissionType(permissions []PermissionType, typ PermissionType) []PermissionType {
+	for _, t := range permissions {
+		if t == typ {
+			return permissions
+		}
+	}
+	return append(permissions, typ)
+}
+
+func permissionKey(origin string, typ PermissionType) string {
+	return origin + "|" + string(typ)
+}
+
+// permissionOrigin 来源格式化为 scheme://host[:port]
+func permissionOrigin(origin string) string {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return strings.TrimRight(strings.ToLower(origin), "/")
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+// permissionOriginScore 规则来源匹配程度, 不匹配返回-1
+func permissionOriginScore(pattern, origin string) int {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || pattern == "*" {
+		return 0
+	}
+	var scheme, host string
+	if i := strings.Index(pattern, "://"); i >= 0 {
+		scheme, host = strings.ToLower(pattern[:i]), strings.ToLower(strings.TrimRight(pattern[i+3:], "/"))
+	} else {
+		host = strings.ToLower(pattern)
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return -1
+	}
+	if scheme != "" && scheme != u.Scheme {
+		return -1
+	}
+	if strings.HasPrefix(host, "*.") {
+		suffix := host[1:]
+		if strings.HasSuffix(u.Hostname(), suffix) || u.Hostname() == host[2:] {
+			return len(suffix)
+		}
+		return -1
+	}
+	if host == u.Host || (!strings.Contains(host, ":") && host == u.Hostname()) {
+		return 1 << 16
+	}
+	return -1
+}
+
+// permissionConsentDialog
+//	内置确认对话框, 在主线程异步显示, 不阻塞CEF回调
+//	关闭对话框时回复, 勾选记住时保存选择
+func permissionConsentDialog(request PermissionRequest, reply PermissionReply) {
+	var names = make([]string, len(request.Types))
+	for i, typ := range request.Types {
+		names[i] = string(typ)
+	}
+	text := i18n.Resource("permissionRequest")
+	if text == "" {
+		text = permissionRequestText
+	}
+	text = i18n.Format(text, i18n.Params{"origin": request.Origin, "permissions": strings.Join(names, ", ")})
+	remember := i18n.Resource("permissionRemember")
+	if remember == "" {
+		remember = permissionRememberText
+	}
+	QueueAsyncCall(func(id int) {
+		dialog := lcl.NewTaskDialog(nil)
+		defer dialog.Free()
+		dialog.SetCaption(request.Origin)
+		dialog.SetText(text)
+		dialog.SetMainIcon(types.TdiQuestion)
+		dialog.SetCommonButtons(types.NewSet(types.TcbYes, types.TcbNo))
+		dialog.SetDefaultButton(types.TcbNo)
+		dialog.SetVerificationText(remember)
+		dialog.SetFlags(types.NewSet(types.TfAllowDialogCancellation))
+		// 取消或关闭对话框时拒绝且不保存
+		if !dialog.Execute() {
+			reply(false, false)
+			return
+		}
+		reply(dialog.ModalResult() == types.MrYes, dialog.Flags().In(types.TfVerificationFlagChecked))
+	})
+}
@@ -0,0 +1,100 @@
+package cef
+
+import (
+	"github.com/energye/energy/v2/consts"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPermissionPolicy(t *testing.T) {
+	manager := NewPermissionManager()
+	manager.SetDefault(PermissionDeny)
+	manager.AddRule("*", PermissionNotifications, PermissionAllow)
+	manager.AddRule("*.example.com", PermissionAll, PermissionAsk)
+	manager.AddRule("https://app.example.com", PermissionCamera, PermissionAllow)
+	manager.AddRule("https://app.example.com", PermissionAll, PermissionDeny)
+	cases := []struct {
+		origin string
+		typ    PermissionType
+		policy PermissionPolicy
+	}{
+		{"https://other.com", PermissionCamera, PermissionDeny},
+		{"https://other.com/", PermissionNotifications, PermissionAllow},
+		{"https://www.example.com", PermissionNotifications, PermissionAsk},
+		{"http://example.com", PermissionGeolocation, PermissionAsk},
+		{"https://App.Example.com/", PermissionCamera, PermissionAllow},
+		{"https://app.example.com", PermissionMicrophone, PermissionDeny},
+		{"https://app.example.com:8443", PermissionMicrophone, PermissionDeny},
+		{"https://badexample.com", PermissionCamera, PermissionDeny},
+	}
+	for _, c := range cases {
+		if policy := manager.Policy(c.origin, c.typ); policy != c.policy {
+			t.Fatal("unexpected policy", c.origin, c.typ, policy)
+		}
+	}
+	if types := permissionPromptTypeList(uint32(consts.CEF_PERMISSION_TYPE_CAMERA_STREAM | consts.CEF_PERMISSION_TYPE_CAMERA_PAN_TILT_ZOOM | consts.CEF_PERMISSION_TYPE_AR_SESSION)); !reflect.DeepEqual(types, []PermissionType{PermissionCamera, PermissionOther}) {
+		t.Fatal("unexpected types", types)
+	}
+}
+
+func TestPermissionDecisions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "energy-permission")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "data", "permissions.json")
+	manager := NewPermissionManager()
+	if err = manager.SetDecisionFile(file); err != nil {
+		t.Fatal(err)
+	}
+	request := PermissionRequest{Origin: "https://meet.example.com/room", Types: []PermissionType{PermissionCamera, PermissionMicrophone}}
+	var allowed map[PermissionType]bool
+	done := func(result map[PermissionType]bool) { allowed = result }
+	// 没有询问函数, 使用CEF默认行为
+	if manager.request(request, done) || allowed != nil {
+		t.Fatal("expected unhandled")
+	}
+	manager.AddRule("https://meet.example.com", PermissionMicrophone, PermissionDeny)
+	var (
+		asked []PermissionType
+		reply PermissionReply
+	)
+	manager.SetOnAsk(func(request PermissionRequest, r PermissionReply) {
+		asked, reply = request.Types, r
+	})
+	// 询问不阻塞, 回复后调用 done
+	if !manager.request(request, done) || allowed != nil || !reflect.DeepEqual(asked, []PermissionType{PermissionCamera}) {
+		t.Fatal("unexpected result", allowed, asked)
+	}
+	reply(true, false)
+	reply(false, true)
+	if !allowed[PermissionCamera] || allowed[PermissionMicrophone] || len(manager.Decisions()) != 0 {
+		t.Fatal("unexpected result", allowed, manager.Decisions())
+	}
+	// 只有用户选择记住时保存
+	allowed = nil
+	manager.request(request, done)
+	reply(true, true)
+	// 保存的选择优先, 不再询问
+	allowed, asked = nil, nil
+	if manager.request(request, done); !allowed[PermissionCamera] || asked != nil {
+		t.Fatal("decision not used", allowed, asked)
+	}
+	manager = NewPermissionManager()
+	if err = manager.SetDecisionFile(file); err != nil {
+		t.Fatal(err)
+	}
+	if decisions := manager.Decisions(); len(decisions) != 1 || decisions[0].Origin != "https://meet.example.com" || decisions[0].Type != PermissionCamera || !decisions[0].Allow {
+		t.Fatal("unexpected decisions", decisions)
+	}
+	if err = manager.Revoke("https://meet.example.com/", PermissionAll); err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.SetDecisionFile(file); err != nil || len(manager.Decisions()) != 0 || manager.Policy("https://meet.example.com", PermissionCamera) != PermissionAsk {
+		t.Fatal("decision not revoked", err)
+	}
+}
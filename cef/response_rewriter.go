@@ -0,0 +1,347 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cef
+
+import (
+	"bytes"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/golcl/lcl"
+	"regexp"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// ResponseRewriter
+//
+//	基于 ICefResponseFilter 的响应内容重写
+//	按 URL 和 MimeType 注册规则: 在 </head> 前注入脚本/CSS, 替换字符串或正则, 使用函数转换整个响应内容
+//	字符串替换和注入以流的方式处理, 正则和函数转换缓存完整响应内容后处理
+type ResponseRewriter struct {
+	lock  sync.RWMutex
+	rules []*responseRewriteRule
+}
+
+// 重写规则
+type responseRewriteRule struct {
+	url      string                      // URL 匹配, * 匹配任意字符, 空匹配所有
+	mimeType string                      // MimeType 匹配, 例: text/html, text/*, 空匹配所有
+	stage    func() responseRewriteStage // 每个响应创建一个处理阶段
+}
+
+// responseRewriteStage 响应内容处理阶段
+type responseRewriteStage interface {
+	write(data []byte) []byte // 写入数据, 返回可以输出的数据
+	flush() []byte            // 响应结束, 返回剩余数据
+}
+
+// NewResponseRewriter 创建响应重写
+func NewResponseRewriter() *ResponseRewriter {
+	return &ResponseRewriter{}
+}
+
+func (m *ResponseRewriter) add(url, mimeType string, stage func() responseRewriteStage) *ResponseRewriter {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.rules = append(m.rules, &responseRewriteRule{url: url, mimeType: strings.ToLower(mimeType), stage: stage})
+	return m
+}
+
+// InjectHead 在 text/html 响应 </head> 之前注入 html, 没有 </head> 时添加到末尾
+func (m *ResponseRewriter) InjectHead(url, html string) *ResponseRewriter {
+	return m.add(url, "text/html", func() responseRewriteStage {
+		return &injectHeadStage{html: []byte(html)}
+	})
+}
+
+// InjectScript 在 text/html 响应 </head> 之前注入 JS 脚本
+func (m *ResponseRewriter) InjectScript(url, script string) *ResponseRewriter {
+	return m.InjectHead(url, "<script>"+script+"</script>")
+}
+
+// InjectCSS 在 text/html 响应 </head> 之前注入 CSS 样式
+func (m *ResponseRewriter) InjectCSS(url, css string) *ResponseRewriter {
+	return m.InjectHead(url, "<style>"+css+"</style>")
+}
+
+// Replace 替换所有 old 为 new
+func (m *ResponseRewriter) Replace(url, mimeType, old, new string) *ResponseRewriter {
+	if old == "" {
+		return m
+	}
+	return m.add(url, mimeType, func() responseRewriteStage {
+		return &replaceStage{old: []byte(old), new: []byte(new)}
+	})
+}
+
+// ReplaceRegexp 正则替换, repl 支持 $1 引用, 缓存完整响应内容后处理
+func (m *ResponseRewriter) ReplaceRegexp(url, mimeType string, re *regexp.Regexp, repl string) *ResponseRewriter {
+	return m.Transform(url, mimeType, func(body []byte) []byte {
+		return re.ReplaceAll(body, []byte(repl))
+	})
+}
+
+// Transform 使用函数转换整个响应内容, 缓存完整响应内容后处理
+func (m *ResponseRewriter) Transform(url, mimeType string, fn func(body []byte) []byte) *ResponseRewriter {
+	return m.add(url, mimeType, func() responseRewriteStage {
+		return &transformStage{fn: fn}
+	})
+}
+
+// Bind
+//
+//	设置 chromium SetOnGetResourceResponseFilter 事件
+//	需要同时处理该事件时, 在事件中调用 GetResponseFilter
+func (m *ResponseRewriter) Bind(chromium IChromium) {
+	chromium.SetOnGetResourceResponseFilter(func(sender lcl.IObject, browser *ICefBrowser, frame *ICefFrame, request *ICefRequest, response *ICefResponse) (responseFilter *ICefResponseFilter) {
+		return m.GetResponseFilter(request, response)
+	})
+}
+
+// GetResponseFilter 返回匹配规则的响应过滤器, 没有匹配的规则时返回 nil
+func (m *ResponseRewriter) GetResponseFilter(request *ICefRequest, response *ICefResponse) *ICefResponseFilter {
+	stages := m.match(request.URL(), response.MimeType())
+	if len(stages) == 0 {
+		return nil
+	}
+	rewrite := &responseRewriteFilter{stages: stages}
+	filter := ResponseFilterRef.New()
+	filter.InitFilter(func() bool {
+		return true
+	})
+	filter.Filter(rewrite.filterPtr)
+	return filter
+}
+
+// match 返回匹配规则的处理阶段
+func (m *ResponseRewriter) match(url, mimeType string) (stages []responseRewriteStage) {
+	mimeType = strings.ToLower(mimeType)
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	for _, rule := range m.rules {
+		if rule.url != "" && !wildcardMatch(rule.url, url) {
+			continue
+		}
+		if rule.mimeType != "" && !wildcardMatch(rule.mimeType, mimeType) {
+			continue
+		}
+		stages = append(stages, rule.stage())
+	}
+	return
+}
+
+// wildcardMatch * 匹配任意字符
+func wildcardMatch(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	for i := 1; i < len(parts)-1; i++ {
+		idx := strings.Index(value, parts[i])
+		if idx < 0 {
+			return false
+		}
+		value = value[idx+len(parts[i]):]
+	}
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+// responseRewriteFilter 一个响应的过滤状态
+type responseRewriteFilter struct {
+	stages  []responseRewriteStage
+	pending []byte // 等待输出的数据
+	ended   bool   // 输入已结束
+}
+
+// write 数据依次经过所有处理阶段
+func (m *responseRewriteFilter) write(data []byte) {
+	for _, stage := range m.stages {
+		if len(data) == 0 {
+			return
+		}
+		data = stage.write(data)
+	}
+	m.pending = append(m.pending, data...)
+}
+
+// flush 输入结束, 依次刷新所有处理阶段
+func (m *responseRewriteFilter) flush() {
+	var data []byte
+	for _, stage := range m.stages {
+		if len(data) > 0 {
+			data = stage.write(data)
+		}
+		data = append(data, stage.flush()...)
+	}
+	m.pending = append(m.pending, data...)
+}
+
+// filter
+//
+//	读取全部输入, 输出到 out, in 为 nil 表示输入结束
+//	返回读取和写入的字节数, done: 全部输出完成
+func (m *responseRewriteFilter) filter(in []byte, out []byte) (read, written int, done bool) {
+	if in == nil {
+		if !m.ended {
+			m.ended = true
+			m.flush()
+		}
+	} else {
+		m.write(in)
+		read = len(in)
+	}
+	written = copy(out, m.pending)
+	m.pending = m.pending[written:]
+	return read, written, m.ended && len(m.pending) == 0
+}
+
+// filterPtr ICefResponseFilter Filter 回调
+func (m *responseRewriteFilter) filterPtr(dataIn uintptr, dataInSize uint32, dataInRead *uint32, dataOut uintptr, dataOutSize uint32, dataOutWritten *uint32) (status consts.TCefResponseFilterStatus) {
+	var in, out []byte
+	if dataIn != 0 && dataInSize > 0 {
+		in = ptrBytes(dataIn, dataInSize)
+	}
+	if dataOut != 0 && dataOutSize > 0 {
+		out = ptrBytes(dataOut, dataOutSize)
+	}
+	read, written, done := m.filter(in, out)
+	*dataInRead = uint32(read)
+	*dataOutWritten = uint32(written)
+	if done {
+		return consts.RESPONSE_FILTER_DONE
+	}
+	return consts.RESPONSE_FILTER_NEED_MORE_DATA
+}
+
+// ptrBytes 指针转换为切片, 不复制数据
+func ptrBytes(ptr uintptr, size uint32) []byte {
+	data := *(*unsafe.Pointer)(unsafe.Pointer(&ptr))
+	return (*[1 << 30]byte)(data)[:size:size]
+}
+
+// replaceStage
+//
+//	流式字符串替换, 保留可能是 old 开头的末尾数据
+//	UTF-8 按完整字节序列匹配, 不会在字符中间替换
+type replaceStage struct {
+	old, new []byte
+	carry    []byte
+}
+
+func (m *replaceStage) write(data []byte) []byte {
+	buf := append(m.carry, data...)
+	var out []byte
+	for {
+		i := bytes.Index(buf, m.old)
+		if i < 0 {
+			break
+		}
+		out = append(out, buf[:i]...)
+		out = append(out, m.new...)
+		buf = buf[i+len(m.old):]
+	}
+	keep := len(m.old) - 1
+	if keep > len(buf) {
+		keep = len(buf)
+	}
+	out = append(out, buf[:len(buf)-keep]...)
+	m.carry = append([]byte(nil), buf[len(buf)-keep:]...)
+	return out
+}
+
+func (m *replaceStage) flush() []byte {
+	out := m.carry
+	m.carry = nil
+	return out
+}
+
+var headEndTag = []byte("</head>")
+
+// injectHeadStage 流式在 </head> 前注入, 不区分大小写, 只注入一次
+type injectHeadStage struct {
+	html     []byte
+	carry    []byte
+	injected bool
+}
+
+func (m *injectHeadStage) write(data []byte) []byte {
+	if m.injected {
+		return data
+	}
+	buf := append(m.carry, data...)
+	if i := indexASCIIFold(buf, headEndTag); i >= 0 {
+		m.injected = true
+		m.carry = nil
+		out := make([]byte, 0, len(buf)+len(m.html))
+		out = append(out, buf[:i]...)
+		out = append(out, m.html...)
+		return append(out, buf[i:]...)
+	}
+	keep := len(headEndTag) - 1
+	if keep > len(buf) {
+		keep = len(buf)
+	}
+	m.carry = append([]byte(nil), buf[len(buf)-keep:]...)
+	return buf[:len(buf)-keep]
+}
+
+// indexASCIIFold
+//
+//	在原始数据中查找 sep, 只对 ASCII 字母不区分大小写, 返回的索引可直接用于 data
+//	sep 为小写 ASCII
+func indexASCIIFold(data, sep []byte) int {
+	for i := 0; i+len(sep) <= len(data); i++ {
+		match := true
+		for j, c := range sep {
+			b := data[i+j]
+			if 'A' <= b && b <= 'Z' {
+				b += 'a' - 'A'
+			}
+			if b != c {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *injectHeadStage) flush() []byte {
+	out := m.carry
+	m.carry = nil
+	if !m.injected {
+		m.injected = true
+		out = append(out, m.html...)
+	}
+	return out
+}
+
+// transformStage 缓存完整响应内容, 结束时转换
+type transformStage struct {
+	fn  func(body []byte) []byte
+	buf bytes.Buffer
+}
+
+func (m *transformStage) write(data []byte) []byte {
+	m.buf.Write(data)
+	return nil
+}
+
+func (m *transformStage) flush() []byte {
+	return m.fn(m.buf.Bytes())
+}
@@ -0,0 +1,113 @@
+package cef
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// runRewrite 按 chunk 分块输入, 每次输出最多 outSize 字节
+func runRewrite(t *testing.T, stages []responseRewriteStage, body string, chunk, outSize int) string {
+	filter := &responseRewriteFilter{stages: stages}
+	var result bytes.Buffer
+	out := make([]byte, outSize)
+	data := []byte(body)
+	for len(data) > 0 {
+		n := chunk
+		if n > len(data) {
+			n = len(data)
+		}
+		read, written, done := filter.filter(data[:n], out)
+		if read != n || done {
+			t.Fatal("input not consumed", read, n, done)
+		}
+		result.Write(out[:written])
+		data = data[n:]
+	}
+	for i := 0; ; i++ {
+		_, written, done := filter.filter(nil, out)
+		result.Write(out[:written])
+		if done {
+			break
+		}
+		if i > len(body)+1024 {
+			t.Fatal("filter not done")
+		}
+	}
+	return result.String()
+}
+
+func TestResponseRewriterStream(t *testing.T) {
+	rewriter := NewResponseRewriter().
+		InjectScript("*://energy/*", "window.energy=1").
+		Replace("", "text/*", "Hello", "你好").
+		Replace("*.js", "", "var", "let")
+	body := "<html><HEAD><title>Hello Hello</title></HEAD><body>Hello 世界</body></html>"
+	expected := "<html><HEAD><title>你好 你好</title><script>window.energy=1</script></HEAD><body>你好 世界</body></html>"
+	for _, chunk := range []int{1, 2, 3, 7, 64, 1024} {
+		for _, outSize := range []int{1, 5, 4096} {
+			stages := rewriter.match("fs://energy/index.html", "text/html")
+			if len(stages) != 2 {
+				t.Fatal("unexpected matched rules", len(stages))
+			}
+			if result := runRewrite(t, stages, body, chunk, outSize); result != expected {
+				t.Fatal("chunk", chunk, "out", outSize, "unexpected result", result)
+			}
+		}
+	}
+	if stages := rewriter.match("https://example.com/app.js", "application/javascript"); len(stages) != 1 {
+		t.Fatal("unexpected matched rules", len(stages))
+	}
+	// 没有 </head> 时添加到末尾
+	stages := NewResponseRewriter().InjectCSS("", "body{}").match("fs://energy/", "text/html")
+	if result := runRewrite(t, stages, "<p>energy</p>", 3, 4); result != "<p>energy</p><style>body{}</style>" {
+		t.Fatal("unexpected result", result)
+	}
+	// 小写后字节长度变化的内容 (İ, 无效 UTF-8) 不影响注入位置
+	body = "<html><head><title>İ\xff\xff</title></HeAd><body></body></html>"
+	expected = "<html><head><title>İ\xff\xff</title><style>body{}</style></HeAd><body></body></html>"
+	for _, chunk := range []int{1, 4, 1024} {
+		stages = NewResponseRewriter().InjectCSS("", "body{}").match("fs://energy/", "text/html")
+		if result := runRewrite(t, stages, body, chunk, 16); result != expected {
+			t.Fatal("chunk", chunk, "unexpected result", result)
+		}
+	}
+}
+
+func TestResponseRewriterTransform(t *testing.T) {
+	rewriter := NewResponseRewriter().
+		ReplaceRegexp("", "application/json", regexp.MustCompile(`"version":\s*"(\d+)"`), `"version":"v$1"`).
+		Transform("", "application/json", func(body []byte) []byte {
+			return bytes.ToUpper(body)
+		})
+	stages := rewriter.match("http://localhost/api", "application/json")
+	body := `{"name":"energy","version": "2"}` + strings.Repeat(" ", 100)
+	if result := runRewrite(t, stages, body, 3, 8); result != strings.ToUpper(`{"name":"energy","version":"v2"}`+strings.Repeat(" ", 100)) {
+		t.Fatal("unexpected result", result)
+	}
+	if stages = rewriter.match("http://localhost/api", "text/html"); len(stages) != 0 {
+		t.Fatal("mime type should not match")
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	cases := []struct {
+		pattern, value string
+		match          bool
+	}{
+		{"https://*.example.com/*", "https://www.example.com/index.html", true},
+		{"https://*.example.com/*", "http://www.example.com/index.html", false},
+		{"*.js", "fs://energy/app.js", true},
+		{"*.js", "fs://energy/app.json", false},
+		{"text/*", "text/html", true},
+		{"text/html", "text/html", true},
+		{"*a*b*", "xaxxbx", true},
+		{"*a*b", "xbxa", false},
+	}
+	for _, c := range cases {
+		if wildcardMatch(c.pattern, c.value) != c.match {
+			t.Fatal("unexpected match", c.pattern, c.value)
+		}
+	}
+}
@@ -0,0 +1,731 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// energy.runtime JS 内置API
+//  剪贴板、文件对话框、系统浏览器打开URL、文件管理器显示文件、窗口控制、应用信息、通知、屏幕、应用更新
+//  每个功能需要权限, 未启用的功能JS调用返回错误
+
+package cef
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/cef/internal/ipc"
+	"github.com/energye/energy/v2/cef/ipc/context"
+	"github.com/energye/energy/v2/cef/ipc/target"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/energy/v2/energy/version"
+	"github.com/energye/energy/v2/pkgs/notice"
+	"github.com/energye/energy/v2/pkgs/update"
+	"github.com/energye/golcl/lcl"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// RuntimePermission energy.runtime 功能权限
+type RuntimePermission uint32
+
+const (
+	RuntimePermissionClipboard    RuntimePermission = 1 << iota // 剪贴板 energy.runtime.clipboard
+	RuntimePermissionDialog                                     // 打开、保存文件对话框 energy.runtime.dialog
+	RuntimePermissionShell                                      // 系统浏览器打开URL、文件管理器显示文件 energy.runtime.shell
+	RuntimePermissionWindow                                     // 当前窗口控制 energy.runtime.window
+	RuntimePermissionApp                                        // 应用信息 energy.runtime.app
+	RuntimePermissionNotification                               // 系统通知 energy.runtime.notification
+	RuntimePermissionScreen                                     // 屏幕信息 energy.runtime.screen
+	RuntimePermissionUpdate                                     // 应用更新 energy.runtime.update, 需要 SetRuntimeUpdater
+	RuntimePermissionAll          = RuntimePermissionClipboard | RuntimePermissionDialog | RuntimePermissionShell | RuntimePermissionWindow |
+		RuntimePermissionApp | RuntimePermissionNotification | RuntimePermissionScreen | RuntimePermissionUpdate
+)
+
+// runtime ipc event name
+const (
+	internalRuntimeCall  = "energy.runtime.call"  // JS 调用 Go
+	internalRuntimeReply = "energy.runtime.reply" // Go 返回结果到 JS
+	// Go 发送更新进度到 JS
+	internalRuntimeUpdateProgress = "energy.runtime.update.progress"
+)
+
+// runtime JS extension native function name
+const runtimeEnabled = "enabled"
+
+// runtimePermissionNames JS 功能名称
+var runtimePermissionNames = map[string]RuntimePermission{
+	"clipboard":    RuntimePermissionClipboard,
+	"dialog":       RuntimePermissionDialog,
+	"shell":        RuntimePermissionShell,
+	"window":       RuntimePermissionWindow,
+	"app":          RuntimePermissionApp,
+	"notification": RuntimePermissionNotification,
+	"screen":       RuntimePermissionScreen,
+	"update":       RuntimePermissionUpdate,
+}
+
+// runtimeOpenURLSchemes 允许系统浏览器打开的URL协议
+var runtimeOpenURLSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// runtimeLocalHosts 默认允许的本机地址, http 和 https 任意端口
+var runtimeLocalHosts = map[string]bool{"localhost": true, "127.0.0.1": true, "::1": true}
+
+// runtimeAllowedOrigins SetRuntimeAllowedOrigins 设置的来源
+var runtimeAllowedOrigins map[string]bool
+
+var (
+	runtimePermissions uint32
+	runtimeAppName     string
+	runtimeAppVersion  string
+	runtimeUpdater     *update.Updater
+	runtimeUpdating    int32
+)
+
+// RuntimeAppInfo energy.runtime.app.info() 返回的应用信息
+type RuntimeAppInfo struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	Platform      string `json:"platform"`
+	Arch          string `json:"arch"`
+	CEFVersion    string `json:"cefVersion"`
+	ChromeVersion string `json:"chromeVersion"`
+	Pid           int    `json:"pid"`
+}
+
+// runtimeRect JS 坐标和宽高
+type runtimeRect struct {
+	X      int32 `json:"x"`
+	Y      int32 `json:"y"`
+	Width  int32 `json:"width"`
+	Height int32 `json:"height"`
+}
+
+// runtimeDisplay JS 屏幕信息
+type runtimeDisplay struct {
+	ID          int         `json:"id"`
+	Bounds      runtimeRect `json:"bounds"`
+	WorkArea    runtimeRect `json:"workArea"`
+	ScaleFactor float32     `json:"scaleFactor"`
+	Rotation    int32       `json:"rotation"`
+}
+
+// runtimeFileDialogOptions JS 文件对话框选项
+type runtimeFileDialogOptions struct {
+	Title       string   `json:"title"`
+	DefaultPath string   `json:"defaultPath"`
+	Filters     []string `json:"filters"`   // 例如 [".png", "image/*"]
+	Multiple    bool     `json:"multiple"`  // 多选文件
+	Directory   bool     `json:"directory"` // 选择文件夹
+}
+
+// runtimeArgs JS 调用参数
+type runtimeArgs []json.RawMessage
+
+// decode 解析第 index 个参数, 参数不存在时不修改 v
+func (m runtimeArgs) decode(index int, v interface{}) error {
+	if index >= len(m) || string(m[index]) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(m[index], v); err != nil {
+		return fmt.Errorf("argument %d: %w", index, err)
+	}
+	return nil
+}
+
+// runtimeReply 返回结果到 JS, err 不为空时 Promise reject
+type runtimeReply func(result interface{}, err error)
+
+// runtimeMethod energy.runtime 方法
+type runtimeMethod struct {
+	permission RuntimePermission
+	call       func(window IBrowserWindow, args runtimeArgs, reply runtimeReply)
+}
+
+var runtimeMethods map[string]*runtimeMethod
+
+func init() {
+	runtimeMethods = map[string]*runtimeMethod{
+		"clipboard.readText":     {RuntimePermissionClipboard, runtimeClipboardReadText},
+		"clipboard.writeText":    {RuntimePermissionClipboard, runtimeClipboardWriteText},
+		"dialog.openFile":        {RuntimePermissionDialog, runtimeDialog(false)},
+		"dialog.saveFile":        {RuntimePermissionDialog, runtimeDialog(true)},
+		"shell.openURL":          {RuntimePermissionShell, runtimeShellOpenURL},
+		"shell.showItemInFolder": {RuntimePermissionShell, runtimeShellShowItemInFolder},
+		"window.minimize":        {RuntimePermissionWindow, runtimeWindow(IBrowserWindow.Minimize)},
+		"window.maximize":        {RuntimePermissionWindow, runtimeWindow(IBrowserWindow.Maximize)},
+		"window.restore":         {RuntimePermissionWindow, runtimeWindow(IBrowserWindow.Restore)},
+		"window.show":            {RuntimePermissionWindow, runtimeWindow(IBrowserWindow.Show)},
+		"window.hide":            {RuntimePermissionWindow, runtimeWindow(IBrowserWindow.Hide)},
+		"window.close":           {RuntimePermissionWindow, runtimeWindow(IBrowserWindow.CloseBrowserWindow)},
+		"window.fullScreen":      {RuntimePermissionWindow, runtimeWindow(IBrowserWindow.FullScreen)},
+		"window.exitFullScreen":  {RuntimePermissionWindow, runtimeWindow(IBrowserWindow.ExitFullScreen)},
+		"window.setTitle":        {RuntimePermissionWindow, runtimeWindowSetTitle},
+		"window.setSize":         {RuntimePermissionWindow, runtimeWindowSetBounds(false)},
+		"window.setPosition":     {RuntimePermissionWindow, runtimeWindowSetBounds(true)},
+		"window.bounds":          {RuntimePermissionWindow, runtimeWindowBounds},
+		"app.info":               {RuntimePermissionApp, runtimeAppInfo},
+		"notification.show":      {RuntimePermissionNotification, runtimeNotificationShow},
+		"screen.displays":        {RuntimePermissionScreen, runtimeScreen(false)},
+		"screen.primary":         {RuntimePermissionScreen, runtimeScreen(true)},
+		"update.check":           {RuntimePermissionUpdate, runtimeUpdateCheck},
+		"update.install":         {RuntimePermissionUpdate, runtimeUpdateInstall},
+		"update.restart":         {RuntimePermissionUpdate, runtimeUpdateRestart},
+	}
+}
+
+// EnableRuntime
+//	启用 JS energy.runtime 内置API, permissions 为启用的功能
+//	需要在 cef.Run 之前调用, 主进程和渲染进程都需要
+func EnableRuntime(permissions RuntimePermission) {
+	atomic.StoreUint32(&runtimePermissions, uint32(permissions))
+}
+
+// RuntimePermissions 返回 energy.runtime 启用的功能
+func RuntimePermissions() RuntimePermission {
+	return RuntimePermission(atomic.LoadUint32(&runtimePermissions))
+}
+
+// SetRuntimeAppInfo 设置 energy.runtime.app.info() 返回的应用名称和版本, 默认名称为执行文件名, 版本为 energy build 写入的版本
+func SetRuntimeAppInfo(name, version string) {
+	runtimeAppName, runtimeAppVersion = name, version
+}
+
+// SetRuntimeUpdater
+//	设置 energy.runtime.update 使用的应用更新, 主进程调用
+//	需要启用 RuntimePermissionUpdate
+func SetRuntimeUpdater(updater *update.Updater) {
+	runtimeUpdater = updater
+}
+
+// SetRuntimeAllowedOrigins
+//	设置允许调用 energy.runtime 的页面来源, 格式: scheme://host[:port], 例如 https://app.example.com
+//	默认只允许本地资源加载(LocalLoadConfig)、file、localhost 和 127.0.0.1 页面, 主进程调用
+//	只有主 frame 可以调用, iframe 调用始终返回错误
+func SetRuntimeAllowedOrigins(origins ...string) {
+	var allowed = make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[strings.TrimSuffix(strings.ToLower(origin), "/")] = true
+	}
+	runtimeAllowedOrigins = allowed
+}
+
+// runtimeOriginAllowed 页面地址是否允许调用 energy.runtime
+func runtimeOriginAllowed(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Host)
+	switch {
+	case scheme == "file":
+		return true
+	case (scheme == "http" || scheme == "https") && runtimeLocalHosts[strings.ToLower(u.Hostname())]:
+		return true
+	case localLoadRes != nil && localLoadRes.enable() && scheme == strings.ToLower(localLoadRes.Scheme) && host == strings.ToLower(localLoadRes.Domain):
+		return true
+	}
+	return runtimeAllowedOrigins[scheme+"://"+host]
+}
+
+// runtimeCall 检查权限并执行 energy.runtime 方法
+func runtimeCall(window IBrowserWindow, permissions RuntimePermission, method string, args runtimeArgs, reply runtimeReply) {
+	fn, ok := runtimeMethods[method]
+	if !ok {
+		reply(nil, fmt.Errorf("energy.runtime: unknown method %q", method))
+		return
+	}
+	if permissions&fn.permission == 0 {
+		reply(nil, fmt.Errorf("energy.runtime: %s permission not enabled", method[:strings.Index(method, ".")]))
+		return
+	}
+	fn.call(window, args, reply)
+}
+
+// runtimeBrowserInit 主进程, 注册 energy.runtime 调用事件
+func runtimeBrowserInit() {
+	if RuntimePermissions() == 0 {
+		return
+	}
+	ipc.On(internalRuntimeCall, func(ctx context.IContext) {
+		argument := ctx.ArgumentList()
+		if argument == nil || argument.Size() < 2 {
+			return
+		}
+		var (
+			id        = argument.GetIntByIndex(0)
+			method    = argument.GetStringByIndex(1)
+			params    = argument.GetStringByIndex(2)
+			browserId = ctx.BrowserId()
+			frameId   = ctx.FrameId()
+			args      runtimeArgs
+		)
+		window := BrowserWindow.GetWindowInfo(browserId)
+		if window == nil {
+			return
+		}
+		var replied int32
+		reply := func(result interface{}, err error) {
+			if !atomic.CompareAndSwapInt32(&replied, 0, 1) {
+				return
+			}
+			var errText string
+			data, e := json.Marshal(result)
+			if err == nil {
+				err = e
+			}
+			if err != nil {
+				errText = err.Error()
+			}
+			ipc.EmitTarget(internalRuntimeReply, target.NewTarget(window.AsTargetWindow(), browserId, frameId), id, string(data), errText)
+		}
+		// 只接受主 frame 并且来源允许的页面调用
+		var frame *ICefFrame
+		if browser := window.Browser(); browser != nil && browser.IsValid() {
+			frame = browser.GetFrameById(frameId)
+		}
+		if frame == nil || !frame.IsValid() || !frame.IsMain() {
+			reply(nil, errors.New("energy.runtime: only the main frame can call"))
+			return
+		}
+		if pageURL := frame.Url(); !runtimeOriginAllowed(pageURL) {
+			reply(nil, fmt.Errorf("energy.runtime: origin of %q is not allowed", pageURL))
+			return
+		}
+		if params != "" {
+			if err := json.Unmarshal([]byte(params), &args); err != nil {
+				reply(nil, err)
+				return
+			}
+		}
+		runtimeCall(window, RuntimePermissions(), method, args, reply)
+	})
+}
+
+func runtimeClipboardReadText(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	RunOnMainThread(func() {
+		reply(lcl.Clipboard.AsText(), nil)
+	})
+}
+
+func runtimeClipboardWriteText(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	var text string
+	if err := args.decode(0, &text); err != nil {
+		reply(nil, err)
+		return
+	}
+	RunOnMainThread(func() {
+		lcl.Clipboard.SetAsText(text)
+		reply(nil, nil)
+	})
+}
+
+// runtimeDialog 打开、保存文件对话框
+//	打开返回选择的文件列表, 保存返回文件路径, 取消时为空
+func runtimeDialog(save bool) func(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	return func(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+		var options runtimeFileDialogOptions
+		if err := args.decode(0, &options); err != nil {
+			reply(nil, err)
+			return
+		}
+		var mode consts.FileDialogMode = consts.FILE_DIALOG_OPEN
+		if save {
+			mode = consts.FILE_DIALOG_SAVE
+		} else if options.Directory {
+			mode = consts.FILE_DIALOG_OPEN_FOLDER
+		} else if options.Multiple {
+			mode = consts.FILE_DIALOG_OPEN_MULTIPLE
+		}
+		RunOnMainThread(func() {
+			browser := window.Browser()
+			if browser == nil || !browser.IsValid() {
+				reply(nil, errors.New("energy.runtime: browser is closed"))
+				return
+			}
+			callback := RunFileDialogCallbackRef.New()
+			if callback == nil {
+				reply(nil, errors.New("energy.runtime: file dialog is not available"))
+				return
+			}
+			callback.SetOnFileDialogDismissed(func(filePaths *lcl.TStrings) {
+				// 对话框关闭后不再回调, 释放 Go 端引用
+				defer callback.Free()
+				var paths = make([]string, 0)
+				if filePaths != nil && filePaths.IsValid() {
+					for i := int32(0); i < filePaths.Count(); i++ {
+						paths = append(paths, filePaths.Strings(i))
+					}
+				}
+				if save {
+					var path string
+					if len(paths) > 0 {
+						path = paths[0]
+					}
+					reply(path, nil)
+				} else {
+					reply(paths, nil)
+				}
+			})
+			var filters *lcl.TStringList
+			if len(options.Filters) > 0 {
+				filters = lcl.NewStringList()
+				for _, filter := range options.Filters {
+					filters.Add(filter)
+				}
+				defer filters.Free()
+			}
+			if filters != nil {
+				browser.RunFileDialog(mode, options.Title, options.DefaultPath, filters, callback)
+			} else {
+				browser.RunFileDialog(mode, options.Title, options.DefaultPath, nil, callback)
+			}
+		})
+	}
+}
+
+func runtimeShellOpenURL(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	var rawURL string
+	if err := args.decode(0, &rawURL); err != nil {
+		reply(nil, err)
+		return
+	}
+	if err := runtimeCheckOpenURL(rawURL); err != nil {
+		reply(nil, err)
+		return
+	}
+	reply(nil, runtimeOpenURL(rawURL))
+}
+
+// runtimeCheckOpenURL 只允许 http、https、mailto 协议
+func runtimeCheckOpenURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if !runtimeOpenURLSchemes[strings.ToLower(u.Scheme)] {
+		return fmt.Errorf("energy.runtime: url scheme %q is not allowed", u.Scheme)
+	}
+	return nil
+}
+
+func runtimeShellShowItemInFolder(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	var path string
+	if err := args.decode(0, &path); err != nil {
+		reply(nil, err)
+		return
+	}
+	if !filepath.IsAbs(path) {
+		reply(nil, fmt.Errorf("energy.runtime: path %q is not absolute", path))
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		reply(nil, err)
+		return
+	}
+	reply(nil, runtimeShowItemInFolder(filepath.Clean(path)))
+}
+
+// runtimeWindow 当前窗口控制
+func runtimeWindow(fn func(window IBrowserWindow)) func(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	return func(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+		RunOnMainThread(func() {
+			fn(window)
+			reply(nil, nil)
+		})
+	}
+}
+
+func runtimeWindowSetTitle(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	var title string
+	if err := args.decode(0, &title); err != nil {
+		reply(nil, err)
+		return
+	}
+	RunOnMainThread(func() {
+		window.SetTitle(title)
+		reply(nil, nil)
+	})
+}
+
+// runtimeWindowSetBounds 设置窗口坐标或宽高
+func runtimeWindowSetBounds(point bool) func(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	return func(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+		var a, b int32
+		if err := args.decode(0, &a); err != nil {
+			reply(nil, err)
+			return
+		}
+		if err := args.decode(1, &b); err != nil {
+			reply(nil, err)
+			return
+		}
+		RunOnMainThread(func() {
+			if point {
+				window.SetPoint(a, b)
+			} else {
+				window.SetSize(a, b)
+			}
+			reply(nil, nil)
+		})
+	}
+}
+
+func runtimeWindowBounds(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	RunOnMainThread(func() {
+		var rect runtimeRect
+		if bounds := window.Bounds(); bounds != nil {
+			rect = runtimeRect{X: bounds.X, Y: bounds.Y, Width: bounds.Width, Height: bounds.Height}
+		}
+		reply(rect, nil)
+	})
+}
+
+func runtimeAppInfo(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	info := RuntimeAppInfo{
+		Name:     runtimeAppName,
+		Version:  runtimeAppVersion,
+		Platform: runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Pid:      os.Getpid(),
+	}
+	if info.Version == "" {
+		info.Version = version.Version()
+	}
+	if info.Name == "" {
+		exe := filepath.Base(os.Args[0])
+		info.Name = strings.TrimSuffix(exe, filepath.Ext(exe))
+	}
+	if application != nil {
+		info.CEFVersion = application.LibCefVersion()
+		info.ChromeVersion = application.ChromeVersion()
+	}
+	reply(info, nil)
+}
+
+func runtimeNotificationShow(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	var title, body string
+	if err := args.decode(0, &title); err != nil {
+		reply(nil, err)
+		return
+	}
+	if err := args.decode(1, &body); err != nil {
+		reply(nil, err)
+		return
+	}
+	notice.SendNotification(notice.NewNotification(title, body))
+	reply(nil, nil)
+}
+
+// runtimeScreen 所有屏幕或主屏幕信息
+func runtimeScreen(primary bool) func(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	return func(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+		RunOnMainThread(func() {
+			screen := window.Screen()
+			if screen == nil {
+				reply(nil, errors.New("energy.runtime: screen is not available"))
+				return
+			}
+			if primary {
+				reply(newRuntimeDisplay(screen.Primary()), nil)
+				return
+			}
+			var displays = make([]runtimeDisplay, 0, screen.Count())
+			for i := 0; i < screen.Count(); i++ {
+				displays = append(displays, newRuntimeDisplay(screen.Get(i)))
+			}
+			reply(displays, nil)
+		})
+	}
+}
+
+func newRuntimeDisplay(display Display) runtimeDisplay {
+	return runtimeDisplay{
+		ID:          display.ID,
+		Bounds:      runtimeRect{X: display.Bounds.X, Y: display.Bounds.Y, Width: display.Bounds.Width, Height: display.Bounds.Height},
+		WorkArea:    runtimeRect{X: display.WorkArea.X, Y: display.WorkArea.Y, Width: display.WorkArea.Width, Height: display.WorkArea.Height},
+		ScaleFactor: display.DeviceScaleFactor,
+		Rotation:    display.Rotation,
+	}
+}
+
+// runtimeUpdaterOf 返回设置的应用更新
+func runtimeUpdaterOf() (*update.Updater, error) {
+	if runtimeUpdater == nil {
+		return nil, errors.New("energy.runtime: updater is not set, cef.SetRuntimeUpdater")
+	}
+	return runtimeUpdater, nil
+}
+
+// runtimeUpdateCheck 检查更新, 返回新版本, 没有时为 null
+func runtimeUpdateCheck(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	updater, err := runtimeUpdaterOf()
+	if err != nil {
+		reply(nil, err)
+		return
+	}
+	go func() {
+		reply(updater.Check())
+	}()
+}
+
+// runtimeUpdateInstall
+//	检查、下载并安装新版本, 返回安装的版本, 没有时为 null
+//	进度发送到调用的窗口 energy.runtime.update.onProgress
+func runtimeUpdateInstall(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	updater, err := runtimeUpdaterOf()
+	if err != nil {
+		reply(nil, err)
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&runtimeUpdating, 0, 1) {
+		reply(nil, errors.New("energy.runtime: update is in progress"))
+		return
+	}
+	var (
+		installer  = *updater
+		onProgress = updater.OnProgress
+		progressTo = window.Target()
+		lastStage  string
+		lastValue  = int64(-1)
+	)
+	installer.OnProgress = func(progress update.Progress) {
+		if onProgress != nil {
+			onProgress(progress)
+		}
+		// 下载进度按百分比发送
+		value := progress.Current
+		if progress.Total > 0 {
+			value = progress.Current * 100 / progress.Total
+		}
+		if progressTo == nil || progress.Stage == lastStage && value == lastValue {
+			return
+		}
+		lastStage, lastValue = progress.Stage, value
+		ipc.EmitTarget(internalRuntimeUpdateProgress, progressTo, progress.Stage, progress.Current, progress.Total)
+	}
+	go func() {
+		defer atomic.StoreInt32(&runtimeUpdating, 0)
+		reply(installer.Update())
+	}()
+}
+
+// runtimeUpdateRestart 启动新版本并退出应用
+func runtimeUpdateRestart(window IBrowserWindow, args runtimeArgs, reply runtimeReply) {
+	if err := update.Restart(); err != nil {
+		reply(nil, err)
+		return
+	}
+	reply(nil, nil)
+	RunOnMainThread(WindowManager.Quit)
+}
+
+// runtimeExtensionHandler 渲染进程, 注册 JS energy.runtime 扩展
+//	energy 对象由 energyI18n 扩展声明, 需要在它之后注册
+//	所有方法返回 Promise, 未启用的功能 reject
+//	energy.runtime.enabled(name)                     功能是否启用
+//	energy.runtime.clipboard.readText/writeText(text)
+//	energy.runtime.dialog.openFile(options)/saveFile(options)
+//	energy.runtime.shell.openURL(url)/showItemInFolder(path)
+//	energy.runtime.window.minimize/maximize/restore/show/hide/close/fullScreen/exitFullScreen()
+//	energy.runtime.window.setTitle(title)/setSize(width, height)/setPosition(x, y)/bounds()
+//	energy.runtime.app.info()
+//	energy.runtime.notification.show(title, body)
+//	energy.runtime.screen.displays()/primary()
+//	energy.runtime.update.check()/install()/restart()/onProgress(function (progress) {})
+func runtimeExtensionHandler() {
+	if RuntimePermissions() == 0 {
+		return
+	}
+	handler := V8HandlerRef.New()
+	handler.Execute(func(name string, object *ICefV8Value, arguments *TCefV8ValueArray, retVal *ResultV8Value, exception *ResultString) bool {
+		if name == runtimeEnabled {
+			var enabled bool
+			if arguments.Size() > 0 {
+				arg := arguments.Get(0)
+				if permission, ok := runtimePermissionNames[arg.GetStringValue()]; ok {
+					enabled = RuntimePermissions()&permission != 0
+				}
+				arg.Free()
+			}
+			retVal.SetResult(V8ValueRef.NewBool(enabled))
+			return true
+		}
+		return false
+	})
+	var code = `
+		(function () {
+			let seq = 0;
+			let pending = {};
+			let listening = false;
+			let call = function (method, args) {
+				return new Promise(function (resolve, reject) {
+					if (typeof ipc === "undefined") {
+						reject(new Error("energy.runtime: ipc is not available"));
+						return;
+					}
+					if (!listening) {
+						listening = true;
+						ipc.on("` + internalRuntimeReply + `", function (id, result, error) {
+							let p = pending[id];
+							if (!p) {
+								return;
+							}
+							delete pending[id];
+							if (error) {
+								p.reject(new Error(error));
+							} else {
+								p.resolve(result ? JSON.parse(result) : null);
+							}
+						});
+					}
+					let id = ++seq;
+					pending[id] = {resolve: resolve, reject: reject};
+					ipc.emit("` + internalRuntimeCall + `", [id, method, JSON.stringify(args || [])]);
+				});
+			};
+			let method = function (name) {
+				return function () {
+					return call(name, Array.prototype.slice.call(arguments));
+				};
+			};
+			let namespace = function (ns, names) {
+				let result = {};
+				for (let i = 0; i < names.length; i++) {
+					result[names[i]] = method(ns + "." + names[i]);
+				}
+				return result;
+			};
+			energy.runtime = {
+				enabled: function (name) {
+					native function enabled();
+					return enabled(name);
+				},
+				clipboard: namespace("clipboard", ["readText", "writeText"]),
+				dialog: namespace("dialog", ["openFile", "saveFile"]),
+				shell: namespace("shell", ["openURL", "showItemInFolder"]),
+				window: namespace("window", ["minimize", "maximize", "restore", "show", "hide", "close", "fullScreen", "exitFullScreen", "setTitle", "setSize", "setPosition", "bounds"]),
+				app: namespace("app", ["info"]),
+				notification: namespace("notification", ["show"]),
+				screen: namespace("screen", ["displays", "primary"]),
+				update: namespace("update", ["check", "install", "restart"])
+			};
+			energy.runtime.update.onProgress = function (callback) {
+				if (typeof ipc !== "undefined") {
+					ipc.on("` + internalRuntimeUpdateProgress + `", function (stage, current, total) {
+						callback({stage: stage, current: current, total: total});
+					});
+				}
+			};
+		})();
+`
+	RegisterExtension("energyRuntime", code, handler)
+}
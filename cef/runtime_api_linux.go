@@ -0,0 +1,40 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+//go:build linux
+// +build linux
+
+// energy.runtime - linux
+
+package cef
+
+import (
+	"net/url"
+	"os/exec"
+	"path/filepath"
+)
+
+// runtimeOpenURL 系统默认浏览器打开URL
+func runtimeOpenURL(rawURL string) error {
+	return exec.Command("xdg-open", rawURL).Start()
+}
+
+// runtimeShowItemInFolder
+//	文件管理器中显示并选中文件, 使用 org.freedesktop.FileManager1
+//	文件管理器不支持时打开文件所在目录
+func runtimeShowItemInFolder(path string) error {
+	uri := (&url.URL{Scheme: "file", Path: path}).String()
+	err := exec.Command("dbus-send", "--session", "--print-reply", "--dest=org.freedesktop.FileManager1", "--type=method_call",
+		"/org/freedesktop/FileManager1", "org.freedesktop.FileManager1.ShowItems", "array:string:"+uri, "string:").Run()
+	if err != nil {
+		return exec.Command("xdg-open", filepath.Dir(path)).Start()
+	}
+	return nil
+}
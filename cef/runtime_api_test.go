@@ -0,0 +1,89 @@
+package cef
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRuntimeCall(t *testing.T) {
+	var (
+		result interface{}
+		err    error
+	)
+	reply := func(r interface{}, e error) {
+		result, err = r, e
+	}
+	runtimeCall(nil, RuntimePermissionAll, "clipboard.unknown", nil, reply)
+	if err == nil {
+		t.Fatal("expected unknown method error")
+	}
+	runtimeCall(nil, RuntimePermissionClipboard, "app.info", nil, reply)
+	if err == nil || err.Error() != "energy.runtime: app permission not enabled" {
+		t.Fatal("expected permission error", err)
+	}
+	SetRuntimeAppInfo("demo", "1.0.0")
+	runtimeCall(nil, RuntimePermissionApp, "app.info", nil, reply)
+	if info, ok := result.(RuntimeAppInfo); err != nil || !ok || info.Name != "demo" || info.Version != "1.0.0" || info.Platform == "" {
+		t.Fatal("unexpected app info", result, err)
+	}
+	// 参数错误和不允许的URL协议
+	var args runtimeArgs
+	if err = json.Unmarshal([]byte(`[1]`), &args); err != nil {
+		t.Fatal(err)
+	}
+	runtimeCall(nil, RuntimePermissionShell, "shell.openURL", args, reply)
+	if err == nil {
+		t.Fatal("expected argument error")
+	}
+	if err = json.Unmarshal([]byte(`["file:///etc/passwd"]`), &args); err != nil {
+		t.Fatal(err)
+	}
+	runtimeCall(nil, RuntimePermissionShell, "shell.openURL", args, reply)
+	if err == nil {
+		t.Fatal("expected scheme error")
+	}
+	runtimeCall(nil, RuntimePermissionShell, "shell.showItemInFolder", runtimeArgs{json.RawMessage(`"relative/file.txt"`)}, reply)
+	if err == nil {
+		t.Fatal("expected path error")
+	}
+	if runtimeCheckOpenURL("HTTPS://energy.yanghy.cn") != nil || runtimeCheckOpenURL("mailto:a@example.com") != nil {
+		t.Fatal("url should be allowed")
+	}
+}
+
+func TestRuntimeArgs(t *testing.T) {
+	var args runtimeArgs
+	if err := json.Unmarshal([]byte(`["title", null, {"multiple": true, "filters": [".png"]}]`), &args); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		title   string
+		body    = "default"
+		options runtimeFileDialogOptions
+		missing int32 = 5
+	)
+	if args.decode(0, &title) != nil || args.decode(1, &body) != nil || args.decode(2, &options) != nil || args.decode(3, &missing) != nil {
+		t.Fatal("decode failed")
+	}
+	if title != "title" || body != "default" || !options.Multiple || len(options.Filters) != 1 || missing != 5 {
+		t.Fatal("unexpected args", title, body, options, missing)
+	}
+}
+
+func TestRuntimeOriginAllowed(t *testing.T) {
+	t.Cleanup(func() { runtimeAllowedOrigins = nil })
+	for _, u := range []string{"file:///C:/app/index.html", "http://localhost:8080/", "https://127.0.0.1/index.html"} {
+		if !runtimeOriginAllowed(u) {
+			t.Fatal("origin should be allowed", u)
+		}
+	}
+	for _, u := range []string{"https://example.com/", "http://localhost.example.com/", "about:blank", ""} {
+		if runtimeOriginAllowed(u) {
+			t.Fatal("origin should not be allowed", u)
+		}
+	}
+	SetRuntimeAllowedOrigins("HTTPS://App.Example.com/")
+	if !runtimeOriginAllowed("https://app.example.com/index.html") || runtimeOriginAllowed("http://app.example.com/") {
+		t.Fatal("unexpected allowed origins")
+	}
+}
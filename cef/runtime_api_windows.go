@@ -0,0 +1,30 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+//go:build windows
+// +build windows
+
+// energy.runtime - windows
+
+package cef
+
+import (
+	"os/exec"
+)
+
+// runtimeOpenURL 系统默认浏览器打开URL
+func runtimeOpenURL(url string) error {
+	return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+}
+
+// runtimeShowItemInFolder 资源管理器中显示并选中文件
+func runtimeShowItemInFolder(path string) error {
+	return exec.Command("explorer", "/select,", path).Start()
+}
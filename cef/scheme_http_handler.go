@@ -0,0 +1,434 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package cef
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	. "github.com/energye/energy/v2/consts"
+	"github.com/energye/energy/v2/logger"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// 自定义协议 http.Handler
+type schemeHTTPHandler struct {
+	scheme  string
+	domain  string
+	handler http.Handler
+}
+
+// 已注册的自定义协议 http.Handler
+var schemeHTTPHandlers []*schemeHTTPHandler
+
+// RegisterSchemeHTTPHandler
+//
+//	使用 Go http.Handler 处理自定义协议请求, 例: gin, chi, net/http ServeMux, 不需要监听 TCP 端口
+//	scheme: 自定义协议, 例: app, domain: 域名, 例: energy, 空时处理协议的所有域名
+//	在 cef.Run 之前调用, 所有进程都需要调用以注册自定义协议
+//	支持流式响应, 实现 http.Flusher, Handler 在协程中执行, 不阻塞 CEF IO 线程
+func RegisterSchemeHTTPHandler(scheme, domain string, h http.Handler) {
+	schemeHTTPHandlers = append(schemeHTTPHandlers, &schemeHTTPHandler{scheme: strings.ToLower(scheme), domain: domain, handler: h})
+}
+
+// regSchemeHTTPHandlerSchemes 注册 http.Handler 自定义协议
+func regSchemeHTTPHandlerSchemes(registrar *TCefSchemeRegistrarRef) {
+	registered := make(map[string]bool)
+	if localLoadRes.enable() {
+		registered[strings.ToLower(localLoadRes.Scheme)] = true
+	}
+	for _, h := range schemeHTTPHandlers {
+		// 以下几种默认的协议不去注册
+		switch strings.ToUpper(h.scheme) {
+		case "HTTP", "HTTPS", "FILE", "FTP", "ABOUT", "DATA":
+			continue
+		}
+		if registered[h.scheme] {
+			continue
+		}
+		registered[h.scheme] = true
+		if application.IsSpecVer49() {
+			registrar.AddCustomScheme(h.scheme, CEF_SCHEME_OPTION_STANDARD|CEF_SCHEME_OPTION_LOCAL)
+		} else {
+			registrar.AddCustomScheme(h.scheme,
+				CEF_SCHEME_OPTION_STANDARD|CEF_SCHEME_OPTION_CORS_ENABLED|CEF_SCHEME_OPTION_SECURE|CEF_SCHEME_OPTION_FETCH_ENABLED)
+		}
+	}
+}
+
+// registerSchemeHTTPHandlerFactory 在浏览器请求上下文注册 http.Handler 资源处理器
+func registerSchemeHTTPHandlerFactory(browser *ICefBrowser) {
+	if len(schemeHTTPHandlers) == 0 {
+		return
+	}
+	requestContext := browser.GetRequestContext()
+	for _, h := range schemeHTTPHandlers {
+		h := h
+		factory := SchemeHandlerFactoryRef.New()
+		factory.SetNew(func(browser *ICefBrowser, frame *ICefFrame, schemeName string, request *ICefRequest) *ICefResourceHandler {
+			return h.resourceHandler(browser, frame, schemeName, request)
+		})
+		requestContext.RegisterSchemeHandlerFactory(h.scheme, h.domain, factory)
+	}
+}
+
+// resourceHandler 创建资源处理器
+func (m *schemeHTTPHandler) resourceHandler(browser *ICefBrowser, frame *ICefFrame, schemeName string, request *ICefRequest) *ICefResourceHandler {
+	handler := ResourceHandlerRef.New(browser, frame, schemeName, request)
+	if handler == nil {
+		return nil
+	}
+	exchange := &schemeHTTPExchange{handler: m.handler}
+	handler.ProcessRequest(exchange.processRequest)
+	handler.GetResponseHeaders(exchange.response)
+	handler.ReadResponse(exchange.readResponse)
+	handler.Cancel(exchange.cancel)
+	return handler
+}
+
+// cefRequestBody 读取请求数据
+func cefRequestBody(request *ICefRequest) []byte {
+	requestData := new(bytes.Buffer)
+	data := request.GetPostData()
+	if data.IsValid() {
+		dataCount := int(data.GetElementCount())
+		elements := data.GetElements()
+		for i := 0; i < dataCount; i++ {
+			element := elements.Get(uint32(i))
+			switch element.GetType() {
+			case PDE_TYPE_EMPTY:
+			case PDE_TYPE_BYTES:
+				if byt, c := element.GetBytes(); c > 0 {
+					requestData.Write(byt)
+				}
+			case PDE_TYPE_FILE:
+				if f := element.GetFile(); f != "" {
+					if byt, err := ioutil.ReadFile(f); err == nil {
+						requestData.Write(byt)
+					}
+				}
+			}
+			element.Free()
+		}
+		data.Free()
+	}
+	return requestData.Bytes()
+}
+
+// cefRequestHeader 读取请求头
+func cefRequestHeader(request *ICefRequest) http.Header {
+	result := make(http.Header)
+	header := request.GetHeaderMap()
+	if header.IsValid() {
+		size := header.GetSize()
+		for i := 0; i < int(size); i++ {
+			key := header.GetKey(uint32(i))
+			if _, ok := result[http.CanonicalHeaderKey(key)]; ok {
+				continue
+			}
+			c := header.FindCount(key)
+			for j := 0; j < int(c); j++ {
+				result.Add(key, header.GetEnumerate(key, uint32(j)))
+			}
+		}
+		header.Free()
+	}
+	return result
+}
+
+// newHTTPRequest ICefRequest 转换为 *http.Request
+func newHTTPRequest(ctx context.Context, request *ICefRequest) (*http.Request, error) {
+	reqUrl, err := url.Parse(request.URL())
+	if err != nil {
+		return nil, err
+	}
+	method := request.Method()
+	if method == "" {
+		method = http.MethodGet
+	}
+	body := cefRequestBody(request)
+	httpRequest, err := http.NewRequestWithContext(ctx, method, reqUrl.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header = cefRequestHeader(request)
+	httpRequest.RequestURI = reqUrl.RequestURI()
+	httpRequest.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		httpRequest.Body = http.NoBody
+	}
+	return httpRequest, nil
+}
+
+// schemeHTTPExchange 一次请求, 在协程中执行 http.Handler, 资源处理器读取响应
+type schemeHTTPExchange struct {
+	handler    http.Handler
+	request    *http.Request
+	writer     *schemeResponseWriter
+	cancelFunc context.CancelFunc
+}
+
+// processRequest
+//
+//	创建 http.Request 并在协程中执行 http.Handler, 立即返回
+//	响应头提交后由 Handler 协程调用 callback.Cont
+func (m *schemeHTTPExchange) processRequest(request *ICefRequest, callback *ICefCallback) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	httpRequest, err := newHTTPRequest(ctx, request)
+	if err != nil {
+		cancel()
+		logger.Error("SchemeHTTPHandler request:", err.Error())
+		return false
+	}
+	m.cancelFunc = cancel
+	m.request = httpRequest
+	m.writer = newSchemeResponseWriter()
+	m.writer.onHeader(callback.Cont)
+	go m.serve()
+	return true
+}
+
+// serve 执行 http.Handler, panic 时响应 500
+func (m *schemeHTTPExchange) serve() {
+	defer func() {
+		if err := recover(); err != nil && err != http.ErrAbortHandler {
+			logger.Error("SchemeHTTPHandler panic:", err, "URL:", m.request.URL.String())
+			m.writer.abort(http.StatusInternalServerError)
+		}
+		m.writer.finish()
+	}()
+	m.handler.ServeHTTP(m.writer, m.request)
+}
+
+// response 设置响应状态和响应头
+func (m *schemeHTTPExchange) response(response *ICefResponse) (responseLength int64, redirectUrl string) {
+	status, header, length := m.writer.committed()
+	response.SetStatus(int32(status))
+	response.SetStatusText(http.StatusText(status))
+	if mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil {
+		response.SetMimeType(mediaType)
+		if charset, ok := params["charset"]; ok {
+			response.SetCharset(charset)
+		}
+	}
+	headerMap := response.GetHeaderMap()
+	if headerMap.IsValid() {
+		for key, value := range header {
+			for _, vs := range value {
+				headerMap.Append(key, vs)
+			}
+		}
+		response.SetHeaderMap(headerMap)
+		headerMap.Free()
+	}
+	if status >= 300 && status < 400 {
+		if location := header.Get("Location"); location != "" {
+			if loc, err := m.request.URL.Parse(location); err == nil {
+				redirectUrl = loc.String()
+			}
+		}
+	}
+	return length, redirectUrl
+}
+
+// readResponse
+//
+//	读取响应数据, 没有数据时返回 (0, true), 数据写入或响应结束时调用 callback.Cont
+//	返回 false 时响应结束
+func (m *schemeHTTPExchange) readResponse(dataOut uintptr, bytesToRead int32, callback *ICefCallback) (bytesRead int32, result bool) {
+	if dataOut == 0 || bytesToRead <= 0 {
+		return 0, false
+	}
+	data := *(*unsafe.Pointer)(unsafe.Pointer(&dataOut))
+	dst := (*[1 << 30]byte)(data)[:bytesToRead:bytesToRead]
+	n, ok := m.writer.read(dst, callback.Cont)
+	return int32(n), ok
+}
+
+// cancel 请求取消, 取消 http.Request Context
+func (m *schemeHTTPExchange) cancel() {
+	if m.writer != nil {
+		m.writer.close()
+	}
+	if m.cancelFunc != nil {
+		m.cancelFunc()
+	}
+}
+
+// schemeResponseWriter
+//
+//	http.ResponseWriter http.Flusher 实现
+//	Handler 写入的数据缓存在 buf, 由资源处理器按块读取
+//	资源处理器不等待, 响应头提交和数据写入时调用已登记的 CEF 回调
+type schemeResponseWriter struct {
+	lock        sync.Mutex
+	header      http.Header
+	sentHeader  http.Header // 提交时的响应头副本
+	status      int
+	wroteHeader bool
+	buf         bytes.Buffer
+	done        bool   // Handler 执行结束
+	closed      bool   // 请求已取消
+	headerReady func() // 响应头提交回调, ProcessRequest callback.Cont
+	dataReady   func() // 等待数据回调, ReadResponse callback.Cont
+}
+
+func newSchemeResponseWriter() *schemeResponseWriter {
+	return &schemeResponseWriter{header: make(http.Header)}
+}
+
+// unlock 解锁并调用满足条件的回调, 回调在锁外执行
+func (m *schemeResponseWriter) unlock() {
+	var ready []func()
+	if m.closed {
+		m.headerReady, m.dataReady = nil, nil
+	}
+	if m.headerReady != nil && m.wroteHeader {
+		ready = append(ready, m.headerReady)
+		m.headerReady = nil
+	}
+	if m.dataReady != nil && (m.buf.Len() > 0 || m.done) {
+		ready = append(ready, m.dataReady)
+		m.dataReady = nil
+	}
+	m.lock.Unlock()
+	for _, fn := range ready {
+		fn()
+	}
+}
+
+func (m *schemeResponseWriter) Header() http.Header {
+	return m.header
+}
+
+func (m *schemeResponseWriter) WriteHeader(statusCode int) {
+	m.lock.Lock()
+	defer m.unlock()
+	m.writeHeader(statusCode)
+}
+
+func (m *schemeResponseWriter) writeHeader(statusCode int) {
+	if m.wroteHeader {
+		return
+	}
+	if statusCode < 100 || statusCode > 999 {
+		panic(fmt.Sprintf("invalid WriteHeader code %v", statusCode))
+	}
+	m.wroteHeader = true
+	m.status = statusCode
+	m.sentHeader = m.header.Clone()
+}
+
+func (m *schemeResponseWriter) Write(data []byte) (int, error) {
+	m.lock.Lock()
+	defer m.unlock()
+	if m.closed {
+		return 0, io.ErrClosedPipe
+	}
+	if !m.wroteHeader {
+		if m.header.Get("Content-Type") == "" {
+			m.header.Set("Content-Type", http.DetectContentType(data))
+		}
+		m.writeHeader(http.StatusOK)
+	}
+	return m.buf.Write(data)
+}
+
+// Flush 提交响应头, 已写入的数据可立即被读取
+func (m *schemeResponseWriter) Flush() {
+	m.lock.Lock()
+	defer m.unlock()
+	m.writeHeader(http.StatusOK)
+}
+
+// finish Handler 执行结束
+func (m *schemeResponseWriter) finish() {
+	m.lock.Lock()
+	defer m.unlock()
+	m.writeHeader(http.StatusOK)
+	m.done = true
+}
+
+// abort Handler panic, 未提交响应头时响应 statusCode
+func (m *schemeResponseWriter) abort(statusCode int) {
+	m.lock.Lock()
+	defer m.unlock()
+	if !m.wroteHeader {
+		m.header = http.Header{"Content-Type": {"text/plain; charset=utf-8"}}
+		m.writeHeader(statusCode)
+		m.buf.WriteString(http.StatusText(statusCode))
+	}
+}
+
+// close 请求取消, 之后的写入返回错误, 已登记的回调不再调用
+func (m *schemeResponseWriter) close() {
+	m.lock.Lock()
+	defer m.unlock()
+	m.closed = true
+}
+
+// onHeader 登记响应头提交回调, 已提交时立即调用
+func (m *schemeResponseWriter) onHeader(fn func()) {
+	m.lock.Lock()
+	defer m.unlock()
+	m.headerReady = fn
+}
+
+// committed
+//
+//	返回已提交的状态码, 响应头和响应长度
+//	Handler 已结束时为缓存数据长度, 否则为 Content-Length, 未知时 -1
+func (m *schemeResponseWriter) committed() (status int, header http.Header, length int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	status, header, length = m.status, m.sentHeader, -1
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if header == nil {
+		header = make(http.Header)
+	}
+	if m.done {
+		length = int64(m.buf.Len())
+	} else if cl, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil && cl >= 0 {
+		length = cl
+	}
+	return
+}
+
+// read
+//
+//	读取响应数据, 不等待
+//	有数据时返回 (n, true), 没有数据时登记 dataReady 并返回 (0, true), 响应结束或取消时返回 (0, false)
+func (m *schemeResponseWriter) read(dst []byte, dataReady func()) (int, bool) {
+	m.lock.Lock()
+	defer m.unlock()
+	if m.closed {
+		return 0, false
+	}
+	if m.buf.Len() > 0 {
+		n, _ := m.buf.Read(dst)
+		return n, true
+	}
+	if m.done {
+		return 0, false
+	}
+	m.dataReady = dataReady
+	return 0, true
+}
@@ -0,0 +1,138 @@
+package cef
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestExchange(t *testing.T, h http.HandlerFunc) *schemeHTTPExchange {
+	ctx, cancel := context.WithCancel(context.Background())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "app://energy/api/stream?n=3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchange := &schemeHTTPExchange{handler: h, request: request, writer: newSchemeResponseWriter(), cancelFunc: cancel}
+	header := make(chan bool, 1)
+	exchange.writer.onHeader(func() { header <- true })
+	go exchange.serve()
+	select {
+	case <-header:
+	case <-time.After(5 * time.Second):
+		t.Fatal("response header not committed")
+	}
+	return exchange
+}
+
+// readWait 读取响应数据, 没有数据时等待 dataReady 回调, 同 CEF ReadResponse
+func readWait(t *testing.T, m *schemeResponseWriter, buf []byte) int {
+	for {
+		ready := make(chan bool, 1)
+		n, ok := m.read(buf, func() { ready <- true })
+		if n > 0 || !ok {
+			return n
+		}
+		select {
+		case <-ready:
+		case <-time.After(5 * time.Second):
+			t.Fatal("data ready callback not called")
+		}
+	}
+}
+
+func readAll(t *testing.T, m *schemeResponseWriter, chunk int) string {
+	var result strings.Builder
+	buf := make([]byte, chunk)
+	for {
+		n := readWait(t, m, buf)
+		if n == 0 {
+			return result.String()
+		}
+		result.Write(buf[:n])
+	}
+}
+
+func TestSchemeHTTPHandlerResponse(t *testing.T) {
+	exchange := newTestExchange(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Energy", "1")
+		fmt.Fprint(w, "<html><body>hello</body></html>")
+	})
+	// 等待 Handler 结束, 响应长度已知
+	for {
+		exchange.writer.lock.Lock()
+		done := exchange.writer.done
+		exchange.writer.lock.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	status, header, length := exchange.writer.committed()
+	if status != http.StatusOK || header.Get("X-Energy") != "1" || length != 31 {
+		t.Fatal("unexpected response", status, header, length)
+	}
+	if !strings.HasPrefix(header.Get("Content-Type"), "text/html") {
+		t.Fatal("content type not detected", header.Get("Content-Type"))
+	}
+	if body := readAll(t, exchange.writer, 4); body != "<html><body>hello</body></html>" {
+		t.Fatal("unexpected body", body)
+	}
+}
+
+func TestSchemeHTTPHandlerStream(t *testing.T) {
+	next := make(chan bool)
+	exchange := newTestExchange(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusAccepted)
+		for i := 0; i < 3; i++ {
+			<-next
+			fmt.Fprintf(w, "data: %d\n\n", i)
+			w.(http.Flusher).Flush()
+		}
+	})
+	// 响应头在数据写入前提交, 长度未知
+	status, header, length := exchange.writer.committed()
+	if status != http.StatusAccepted || header.Get("Content-Type") != "text/event-stream" || length != -1 {
+		t.Fatal("unexpected response", status, header, length)
+	}
+	buf := make([]byte, 64)
+	// 没有数据时不阻塞, 返回 (0, true) 并在写入后回调
+	if n, ok := exchange.writer.read(buf, func() {}); n != 0 || !ok {
+		t.Fatal("expected pending read", n, ok)
+	}
+	for i := 0; i < 3; i++ {
+		next <- true
+		if n := readWait(t, exchange.writer, buf); string(buf[:n]) != fmt.Sprintf("data: %d\n\n", i) {
+			t.Fatal("unexpected chunk", string(buf[:n]))
+		}
+	}
+	if n := readWait(t, exchange.writer, buf); n != 0 {
+		t.Fatal("stream not finished")
+	}
+}
+
+func TestSchemeHTTPHandlerPanicAndCancel(t *testing.T) {
+	exchange := newTestExchange(t, func(w http.ResponseWriter, r *http.Request) {
+		panic("handler error")
+	})
+	if status, _, _ := exchange.writer.committed(); status != http.StatusInternalServerError {
+		t.Fatal("expected 500", status)
+	}
+	canceled := make(chan bool)
+	exchange = newTestExchange(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		<-r.Context().Done()
+		_, err := w.Write([]byte("late"))
+		canceled <- err != nil
+	})
+	exchange.cancel()
+	if !<-canceled {
+		t.Fatal("write after cancel should fail")
+	}
+	if n, ok := exchange.writer.read(make([]byte, 8), func() {}); n != 0 || ok {
+		t.Fatal("read after cancel")
+	}
+}
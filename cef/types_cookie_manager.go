@@ -42,11 +42,12 @@ func (m *ICefCookieManager) IsValid() bool {
 	return m.instance != nil
 }
 
-func (m *ICefCookieManager) VisitAllCookies(visitor *ICefCookieVisitor) {
+func (m *ICefCookieManager) VisitAllCookies(visitor *ICefCookieVisitor) bool {
 	if !m.IsValid() {
-		return
+		return false
 	}
-	imports.Proc(def.CefCookieManager_VisitAllCookies).Call(m.Instance(), visitor.Instance())
+	r1, _, _ := imports.Proc(def.CefCookieManager_VisitAllCookies).Call(m.Instance(), visitor.Instance())
+	return api.GoBool(r1)
 }
 
 func (m *ICefCookieManager) VisitUrlCookies(url string, includeHttpOnly bool, visitor *ICefCookieVisitor) bool {
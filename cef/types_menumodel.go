@@ -51,18 +51,26 @@ type MenuItem struct {
 	Callback    FuncCallback //点击 或 快捷键触发的回调
 }
 
+// parseAccelerator 解析快捷键 shift+ctrl+alt+n, 返回按键和修饰键
+func parseAccelerator(accelerator string) (keyCode rune, shift, ctrl, alt, ok bool) {
+	as := strings.Split(strings.Replace(strings.ToUpper(accelerator), " ", "", -1), "+")
+	if len(as) == 0 || len(as) > 4 || as[len(as)-1] == "" {
+		return
+	}
+	shift = ArrayIndexOf(as, MA_Shift) != -1
+	ctrl = ArrayIndexOf(as, MA_Ctrl) != -1
+	alt = ArrayIndexOf(as, MA_Alt) != -1
+	keyCode = rune(as[len(as)-1][0])
+	return keyCode, shift, ctrl, alt, true
+}
+
 // AddAcceleratorCustom 添加自定义快捷键
 func (m *keyEventAccelerator) AddAcceleratorCustom(accelerator *AcceleratorCustom) {
 	if accelerator == nil {
 		return
 	}
 	accelerator.Accelerator = strings.Replace(strings.ToUpper(accelerator.Accelerator), " ", "", -1)
-	as := strings.Split(accelerator.Accelerator, "+")
-	if len(as) > 0 && len(as) <= 4 {
-		var shift = ArrayIndexOf(as, MA_Shift) != -1
-		var ctrl = ArrayIndexOf(as, MA_Ctrl) != -1
-		var alt = ArrayIndexOf(as, MA_Alt) != -1
-		var keyCode = rune(strings.ToUpper(as[len(as)-1])[0])
+	if keyCode, shift, ctrl, alt, ok := parseAccelerator(accelerator.Accelerator); ok {
 		accelerator.Accelerator = acceleratorCode(shift, ctrl, alt, keyCode)
 		m.acceleratorCustom[accelerator.Accelerator] = accelerator
 	}
@@ -108,7 +116,9 @@ func (m *keyEventAccelerator) accelerator(browse *ICefBrowser, event *TCefKeyEve
 			//m.ctrl = false
 			//m.alt = false
 			m.keyCode = -1
-			if m.acceleratorEventCallback(browse, accelerator, result) {
+			if contextMenus.accelerator(browse, accelerator, result) {
+				return true
+			} else if m.acceleratorEventCallback(browse, accelerator, result) {
 				return true
 			} else if m.acceleratorCustomCallback(accelerator, browse, event, result) {
 				return true
@@ -162,12 +172,7 @@ func (m *ICefMenuModel) AddMenuItem(item *MenuItem) bool {
 	}
 	if item.Accelerator != "" {
 		item.Accelerator = strings.Replace(strings.ToUpper(item.Accelerator), " ", "", -1)
-		as := strings.Split(item.Accelerator, "+")
-		if len(as) > 0 && len(as) <= 4 {
-			var shift = ArrayIndexOf(as, MA_Shift) != -1
-			var ctrl = ArrayIndexOf(as, MA_Ctrl) != -1
-			var alt = ArrayIndexOf(as, MA_Alt) != -1
-			var keyCode = rune(strings.ToUpper(as[len(as)-1])[0])
+		if keyCode, shift, ctrl, alt, ok := parseAccelerator(item.Accelerator); ok {
 			item.Accelerator = acceleratorCode(shift, ctrl, alt, keyCode)
 			m.SetAccelerator(item.CommandId, keyCode, shift, ctrl, alt)
 			KeyAccelerator.acceleratorItems[item.Accelerator] = item
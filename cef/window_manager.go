@@ -0,0 +1,525 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 多窗口管理, 按名称打开和查找窗口
+
+package cef
+
+import (
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/cef/internal/ipc"
+	"github.com/energye/energy/v2/consts"
+	"github.com/energye/golcl/lcl"
+	"github.com/energye/golcl/lcl/api"
+	"github.com/energye/golcl/lcl/types"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// WindowClosePolicy 窗口关闭后应用退出策略
+type WindowClosePolicy int32
+
+const (
+	WindowClosePolicyDefault          WindowClosePolicy = iota // 默认, 启用主窗口时关闭主窗口退出, 禁用主窗口时最后一个窗口关闭退出
+	WindowClosePolicyQuitOnLastWindow                          // 最后一个窗口关闭时退出, 还有其它窗口时关闭主窗口只隐藏
+	WindowClosePolicyKeepRunning                               // 窗口全部关闭后继续运行(例如托盘), 关闭主窗口只隐藏, 调用 Quit 退出
+)
+
+// WindowManagerEvent 窗口管理事件, name: 窗口名称, 未命名的窗口为空
+type WindowManagerEvent func(name string, window IBrowserWindow)
+
+// ErrWindowNotFound 窗口名称不存在
+var ErrWindowNotFound = errors.New("window not found")
+
+// windowRelation 子窗口和父窗口关系
+type windowRelation struct {
+	parent string
+	modal  bool
+}
+
+// windowManager 多窗口管理
+type windowManager struct {
+	lock       sync.Mutex
+	windows    map[string]IBrowserWindow
+	relations  map[string]*windowRelation // 子窗口名称 > 父窗口
+	browsers   map[int32]bool             // 已触发打开事件的浏览器
+	policy     WindowClosePolicy
+	quitting   bool
+	mainHidden bool
+	onOpened   WindowManagerEvent
+	onClosed   WindowManagerEvent
+	onFocused  WindowManagerEvent
+}
+
+// WindowManager
+//	多窗口管理, 按名称打开、查找、聚焦和关闭窗口
+//	维护父子窗口和模态关系, 窗口打开、关闭、获得焦点事件和应用退出策略
+var WindowManager = &windowManager{
+	windows:   make(map[string]IBrowserWindow),
+	relations: make(map[string]*windowRelation),
+	browsers:  make(map[int32]bool),
+}
+
+// Open
+//	按名称打开窗口, 窗口已存在时聚焦并返回已存在的窗口
+//	url 为空时使用 wp.Url
+//	在主线程创建窗口, 非主线程调用时等待创建完成
+func (m *windowManager) Open(name, url string, wp WindowProperty) IBrowserWindow {
+	window, _ := m.open(name, "", false, url, wp)
+	return window
+}
+
+// OpenChild
+//	按名称打开子窗口, 父窗口关闭时同时关闭子窗口
+//	modal: 模态窗口, 子窗口关闭之前禁用父窗口
+func (m *windowManager) OpenChild(name, parent string, modal bool, url string, wp WindowProperty) (IBrowserWindow, error) {
+	return m.open(name, parent, modal, url, wp)
+}
+
+func (m *windowManager) open(name, parent string, modal bool, url string, wp WindowProperty) (window IBrowserWindow, err error) {
+	if name == "" {
+		return nil, errors.New("window name is empty")
+	}
+	if url != "" {
+		wp.Url = url
+	}
+	runOnMainThreadSync(func() {
+		if window = m.Get(name); window != nil {
+			m.focus(window)
+			return
+		}
+		if parent != "" && m.Get(parent) == nil {
+			err = fmt.Errorf("parent %q: %w", parent, ErrWindowNotFound)
+			return
+		}
+		window = NewBrowserWindow(nil, wp, nil)
+		if window == nil {
+			err = errors.New("create window failed")
+			return
+		}
+		m.Register(name, window)
+		if parent != "" {
+			err = m.SetParent(name, parent, modal)
+		}
+		window.EnableAllDefaultEvent()
+		window.Show()
+	})
+	return
+}
+
+// Register 按名称管理已存在的窗口, 例如主窗口
+func (m *windowManager) Register(name string, window IBrowserWindow) {
+	if name == "" || window == nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.windows[name] = window
+}
+
+// Get 返回名称对应的窗口, 不存在或已关闭返回nil
+func (m *windowManager) Get(name string) IBrowserWindow {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if window, ok := m.windows[name]; ok && !window.IsClosing() {
+		return window
+	}
+	return nil
+}
+
+// Name 返回窗口名称, 未命名的窗口返回空
+func (m *windowManager) Name(window IBrowserWindow) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.nameOf(window)
+}
+
+func (m *windowManager) nameOf(window IBrowserWindow) string {
+	for name, w := range m.windows {
+		if w == window {
+			return name
+		}
+	}
+	return ""
+}
+
+// Names 返回所有窗口名称
+func (m *windowManager) Names() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var names = make([]string, 0, len(m.windows))
+	for name := range m.windows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Focus 显示并聚焦窗口, 窗口不存在返回false
+func (m *windowManager) Focus(name string) bool {
+	window := m.Get(name)
+	if window == nil {
+		return false
+	}
+	m.focus(window)
+	return true
+}
+
+// Close 关闭窗口, 同时关闭它的子窗口
+func (m *windowManager) Close(name string) bool {
+	window := m.Get(name)
+	if window == nil {
+		return false
+	}
+	window.CloseBrowserWindow()
+	return true
+}
+
+// SetParent
+//	设置父子窗口关系, 父窗口关闭时同时关闭子窗口
+//	modal: 模态窗口, 子窗口关闭之前禁用父窗口
+func (m *windowManager) SetParent(child, parent string, modal bool) error {
+	m.lock.Lock()
+	parentWindow, ok := m.windows[parent]
+	if _, exists := m.windows[child]; !exists || !ok {
+		m.lock.Unlock()
+		return ErrWindowNotFound
+	}
+	for name := parent; name != ""; {
+		if name == child {
+			m.lock.Unlock()
+			return fmt.Errorf("window %q: parent %q creates a cycle", child, parent)
+		}
+		if relation, ok := m.relations[name]; ok {
+			name = relation.parent
+		} else {
+			name = ""
+		}
+	}
+	m.relations[child] = &windowRelation{parent: parent, modal: modal}
+	m.lock.Unlock()
+	if modal {
+		setWindowEnabled(parentWindow, false)
+	}
+	return nil
+}
+
+// Parent 返回子窗口的父窗口名称
+func (m *windowManager) Parent(child string) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if relation, ok := m.relations[child]; ok {
+		return relation.parent
+	}
+	return ""
+}
+
+// Children 返回父窗口的子窗口名称
+func (m *windowManager) Children(parent string) []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.children(parent)
+}
+
+func (m *windowManager) children(parent string) (names []string) {
+	for name, relation := range m.relations {
+		if relation.parent == parent {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// modalChild 返回父窗口打开的模态子窗口
+func (m *windowManager) modalChild(parent string) string {
+	for _, name := range m.children(parent) {
+		if m.relations[name].modal {
+			return name
+		}
+	}
+	return ""
+}
+
+// Broadcast 向所有窗口发送IPC事件
+func (m *windowManager) Broadcast(name string, argument ...interface{}) {
+	for _, window := range BrowserWindow.GetWindowInfos() {
+		if window.IsClosing() {
+			continue
+		}
+		if target := window.Target(); target != nil {
+			ipc.EmitTarget(name, target, argument...)
+		}
+	}
+}
+
+// SetClosePolicy 设置窗口关闭后应用退出策略
+func (m *windowManager) SetClosePolicy(policy WindowClosePolicy) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.policy = policy
+}
+
+// ClosePolicy 返回窗口关闭后应用退出策略
+func (m *windowManager) ClosePolicy() WindowClosePolicy {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.policy
+}
+
+// Quit 关闭所有窗口并退出应用
+func (m *windowManager) Quit() {
+	m.lock.Lock()
+	m.quitting = true
+	m.lock.Unlock()
+	var windows []IBrowserWindow
+	for _, window := range BrowserWindow.GetWindowInfos() {
+		windows = append(windows, window)
+	}
+	if len(windows) == 0 {
+		RunOnMainThread(func() {
+			if application.IsMessageLoop() {
+				application.QuitMessageLoop()
+			} else {
+				lcl.Application.Terminate()
+			}
+		})
+		return
+	}
+	for _, window := range windows {
+		window.CloseBrowserWindow()
+	}
+}
+
+// SetOnOpened 窗口打开事件, 浏览器创建之后触发
+func (m *windowManager) SetOnOpened(fn WindowManagerEvent) {
+	m.onOpened = fn
+}
+
+// SetOnClosed 窗口关闭事件
+func (m *windowManager) SetOnClosed(fn WindowManagerEvent) {
+	m.onClosed = fn
+}
+
+// SetOnFocused 窗口获得焦点事件
+func (m *windowManager) SetOnFocused(fn WindowManagerEvent) {
+	m.onFocused = fn
+}
+
+// opened 浏览器创建之后, 每个浏览器只触发一次
+func (m *windowManager) opened(window IBrowserWindow, browser *ICefBrowser) {
+	id := browser.BrowserId()
+	m.lock.Lock()
+	if m.browsers[id] {
+		m.lock.Unlock()
+		return
+	}
+	m.browsers[id] = true
+	name := m.nameOf(window)
+	m.lock.Unlock()
+	if m.onOpened != nil {
+		m.onOpened(name, window)
+	}
+}
+
+// closed 浏览器关闭之前, 移除窗口名称, 关闭子窗口, 恢复模态父窗口
+func (m *windowManager) closed(window IBrowserWindow, browserId int32) {
+	m.lock.Lock()
+	delete(m.browsers, browserId)
+	name := m.nameOf(window)
+	var (
+		children    []IBrowserWindow
+		enableModal IBrowserWindow
+	)
+	if name != "" {
+		delete(m.windows, name)
+		for _, child := range m.children(name) {
+			if w, ok := m.windows[child]; ok {
+				children = append(children, w)
+			}
+			delete(m.relations, child)
+		}
+		if relation, ok := m.relations[name]; ok {
+			delete(m.relations, name)
+			if relation.modal && m.modalChild(relation.parent) == "" {
+				enableModal = m.windows[relation.parent]
+			}
+		}
+	}
+	m.lock.Unlock()
+	if enableModal != nil {
+		setWindowEnabled(enableModal, true)
+		m.focus(enableModal)
+	}
+	for _, child := range children {
+		child.CloseBrowserWindow()
+	}
+	if m.onClosed != nil {
+		m.onClosed(name, window)
+	}
+}
+
+// focused 窗口获得焦点, 有模态子窗口时聚焦到子窗口
+func (m *windowManager) focused(window IBrowserWindow) {
+	m.lock.Lock()
+	name := m.nameOf(window)
+	var modal IBrowserWindow
+	if name != "" {
+		if child := m.modalChild(name); child != "" {
+			modal = m.windows[child]
+		}
+	}
+	m.lock.Unlock()
+	if modal != nil && !modal.IsClosing() {
+		m.focus(modal)
+		return
+	}
+	if m.onFocused != nil {
+		m.onFocused(name, window)
+	}
+}
+
+// focus 显示并聚焦窗口
+func (m *windowManager) focus(window IBrowserWindow) {
+	if window.WindowType() == consts.WT_MAIN_BROWSER {
+		m.lock.Lock()
+		m.mainHidden = false
+		m.lock.Unlock()
+	}
+	RunOnMainThread(func() {
+		if window.IsClosing() {
+			return
+		}
+		if window.IsLCL() {
+			bw := window.AsLCLBrowserWindow().BrowserWindow()
+			bw.Show()
+			bw.SetFocus()
+		} else if window.IsViewsFramework() {
+			bw := window.AsViewsFrameworkBrowserWindow().BrowserWindow()
+			if bw.WindowState() == types.WsMinimized {
+				bw.Restore()
+			}
+			bw.Show()
+			bw.WindowComponent().Activate()
+		}
+	})
+}
+
+// hideMainWindow
+//	关闭主窗口时根据退出策略隐藏主窗口, 返回true表示已隐藏, 不关闭窗口
+func (m *windowManager) hideMainWindow(window IBrowserWindow) bool {
+	if window.WindowType() != consts.WT_MAIN_BROWSER || window.IsClosing() || BrowserWindow.Config == nil || !BrowserWindow.Config.EnableMainWindow {
+		return false
+	}
+	var others int
+	for _, w := range BrowserWindow.GetWindowInfos() {
+		if w != window {
+			others++
+		}
+	}
+	m.lock.Lock()
+	hide := hideMainOnClose(m.policy, m.quitting, others)
+	if hide {
+		m.mainHidden = true
+	}
+	m.lock.Unlock()
+	if hide {
+		RunOnMainThread(func() {
+			window.Hide()
+		})
+	}
+	return hide
+}
+
+// closeHiddenMainWindow
+//	WindowClosePolicyQuitOnLastWindow 策略, 最后一个子窗口关闭时关闭隐藏的主窗口
+func (m *windowManager) closeHiddenMainWindow() bool {
+	infos := BrowserWindow.GetWindowInfos()
+	main := BrowserWindow.MainWindow()
+	m.lock.Lock()
+	if !m.mainHidden || m.quitting || m.policy != WindowClosePolicyQuitOnLastWindow || main == nil || len(infos) != 1 || infos[main.Id()] == nil {
+		m.lock.Unlock()
+		return false
+	}
+	m.quitting = true
+	m.lock.Unlock()
+	main.CloseBrowserWindow()
+	return true
+}
+
+// terminateOnClose 窗口关闭后是否退出应用
+func (m *windowManager) terminateOnClose(window IBrowserWindow) bool {
+	enableMainWindow := BrowserWindow.Config != nil && BrowserWindow.Config.EnableMainWindow
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return terminateOnClose(m.policy, m.quitting, enableMainWindow, window.WindowType() == consts.WT_MAIN_BROWSER, len(BrowserWindow.GetWindowInfos()))
+}
+
+// terminateOnClose
+//	isMain: 关闭的是主窗口, count: 剩余窗口数量
+func terminateOnClose(policy WindowClosePolicy, quitting, enableMainWindow, isMain bool, count int) bool {
+	if quitting {
+		return count < 1 || (enableMainWindow && isMain)
+	}
+	switch policy {
+	case WindowClosePolicyQuitOnLastWindow:
+		return count < 1
+	case WindowClosePolicyKeepRunning:
+		return false
+	default:
+		if enableMainWindow {
+			return isMain
+		}
+		return count < 1
+	}
+}
+
+// hideMainOnClose 关闭主窗口时是否隐藏, others: 其它窗口数量
+func hideMainOnClose(policy WindowClosePolicy, quitting bool, others int) bool {
+	if quitting {
+		return false
+	}
+	switch policy {
+	case WindowClosePolicyQuitOnLastWindow:
+		return others > 0
+	case WindowClosePolicyKeepRunning:
+		return true
+	}
+	return false
+}
+
+// setWindowEnabled 启用或禁用窗口, 模态子窗口打开时禁用父窗口
+func setWindowEnabled(window IBrowserWindow, enabled bool) {
+	RunOnMainThread(func() {
+		if window.IsClosing() {
+			return
+		}
+		if window.IsLCL() {
+			window.AsLCLBrowserWindow().BrowserWindow().SetEnabled(enabled)
+		} else if window.IsViewsFramework() {
+			window.AsViewsFrameworkBrowserWindow().BrowserWindow().BrowserViewComponent().SetEnabled(enabled)
+		}
+	})
+}
+
+// runOnMainThreadSync 在主线程执行并等待完成
+func runOnMainThreadSync(fn func()) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if api.DMainThreadId() == api.DCurrentThreadId() {
+		fn()
+	} else {
+		QueueSyncCall(func(id int) {
+			fn()
+		})
+	}
+}
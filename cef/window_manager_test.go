@@ -0,0 +1,87 @@
+package cef
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testManagerWindow struct {
+	IBrowserWindow
+	closing bool
+}
+
+func (m *testManagerWindow) IsClosing() bool {
+	return m.closing
+}
+
+func (m *testManagerWindow) CloseBrowserWindow() {
+	m.closing = true
+}
+
+func TestWindowManagerRelations(t *testing.T) {
+	manager := &windowManager{windows: make(map[string]IBrowserWindow), relations: make(map[string]*windowRelation), browsers: make(map[int32]bool)}
+	main, settings, about := &testManagerWindow{}, &testManagerWindow{}, &testManagerWindow{}
+	manager.Register("main", main)
+	manager.Register("settings", settings)
+	manager.Register("about", about)
+	if manager.Get("settings") != settings || manager.Name(about) != "about" || manager.Get("none") != nil {
+		t.Fatal("unexpected window")
+	}
+	if err := manager.SetParent("settings", "main", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetParent("about", "settings", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetParent("main", "about", false); err == nil {
+		t.Fatal("expected cycle error")
+	}
+	if err := manager.SetParent("none", "main", false); err != ErrWindowNotFound {
+		t.Fatal("expected not found", err)
+	}
+	if children := manager.Children("main"); !reflect.DeepEqual(children, []string{"settings"}) || manager.Parent("about") != "settings" {
+		t.Fatal("unexpected children", children)
+	}
+	var closed []string
+	manager.SetOnClosed(func(name string, window IBrowserWindow) {
+		closed = append(closed, name)
+	})
+	// 关闭父窗口同时关闭子窗口
+	manager.closed(settings, 2)
+	if !about.closing || manager.Get("about") != nil || manager.Get("settings") != nil || manager.Parent("about") != "" {
+		t.Fatal("child not closed")
+	}
+	if !reflect.DeepEqual(closed, []string{"settings"}) || !reflect.DeepEqual(manager.Names(), []string{"about", "main"}) {
+		t.Fatal("unexpected closed", closed, manager.Names())
+	}
+}
+
+func TestWindowClosePolicy(t *testing.T) {
+	cases := []struct {
+		policy           WindowClosePolicy
+		quitting         bool
+		enableMainWindow bool
+		isMain           bool
+		count            int
+		terminate        bool
+	}{
+		{WindowClosePolicyDefault, false, true, true, 2, true},
+		{WindowClosePolicyDefault, false, true, false, 1, false},
+		{WindowClosePolicyDefault, false, false, false, 0, true},
+		{WindowClosePolicyQuitOnLastWindow, false, true, true, 1, false},
+		{WindowClosePolicyQuitOnLastWindow, false, true, false, 0, true},
+		{WindowClosePolicyKeepRunning, false, false, false, 0, false},
+		{WindowClosePolicyKeepRunning, true, false, false, 0, true},
+		{WindowClosePolicyKeepRunning, true, true, true, 1, true},
+	}
+	for _, c := range cases {
+		if terminateOnClose(c.policy, c.quitting, c.enableMainWindow, c.isMain, c.count) != c.terminate {
+			t.Fatal("unexpected terminate", c)
+		}
+	}
+	if hideMainOnClose(WindowClosePolicyDefault, false, 1) || !hideMainOnClose(WindowClosePolicyQuitOnLastWindow, false, 1) ||
+		hideMainOnClose(WindowClosePolicyQuitOnLastWindow, false, 0) || !hideMainOnClose(WindowClosePolicyKeepRunning, false, 0) ||
+		hideMainOnClose(WindowClosePolicyKeepRunning, true, 0) {
+		t.Fatal("unexpected hide")
+	}
+}
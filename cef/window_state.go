@@ -0,0 +1,299 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 窗口状态保存和恢复
+
+package cef
+
+import (
+	"encoding/json"
+	"github.com/energye/energy/v2/logger"
+	"github.com/energye/golcl/lcl/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 窗口状态写入文件的延迟, 移动和调整大小时合并写入
+const windowStateSaveDelay = 500 * time.Millisecond
+
+// 恢复窗口时, 窗口在屏幕工作区内至少可见的宽高
+const (
+	windowStateMinVisibleWidth  = 100
+	windowStateMinVisibleHeight = 50
+)
+
+// windowState 窗口保存的状态
+type windowState struct {
+	X       int32              `json:"x"`       // 正常状态的坐标和宽高
+	Y       int32              `json:"y"`       //
+	Width   int32              `json:"width"`   //
+	Height  int32              `json:"height"`  //
+	State   types.TWindowState `json:"state"`   // 正常、最大化、全屏
+	Display int                `json:"display"` // 所在屏幕ID
+}
+
+// windowStateStore 按窗口名称保存的窗口状态
+type windowStateStore struct {
+	lock   sync.Mutex
+	file   string
+	loaded bool
+	states map[string]*windowState
+	timer  *time.Timer
+}
+
+var windowStates = &windowStateStore{}
+
+// SetWindowStateFile
+//	设置窗口状态保存文件
+//	默认保存到用户数据目录(RootCache 或 Cache)下的 window-state.json, 未设置时使用系统用户配置目录
+func SetWindowStateFile(file string) {
+	windowStates.lock.Lock()
+	defer windowStates.lock.Unlock()
+	windowStates.file = file
+	windowStates.loaded = false
+}
+
+// 窗口状态保存文件
+func (m *windowStateStore) path() string {
+	if m.file != "" {
+		return m.file
+	}
+	var dir string
+	if application != nil {
+		if dir = application.RootCache(); dir == "" {
+			dir = application.Cache()
+		}
+	}
+	if dir == "" {
+		if configDir, err := os.UserConfigDir(); err == nil {
+			exe := filepath.Base(os.Args[0])
+			dir = filepath.Join(configDir, strings.TrimSuffix(exe, filepath.Ext(exe)))
+		}
+	}
+	m.file = filepath.Join(dir, "window-state.json")
+	return m.file
+}
+
+// load 第一次使用时加载状态文件
+func (m *windowStateStore) load() {
+	if m.loaded {
+		return
+	}
+	m.loaded = true
+	m.states = make(map[string]*windowState)
+	data, err := ioutil.ReadFile(m.path())
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(data, &m.states); err != nil {
+		logger.Error("window state:", err)
+		m.states = make(map[string]*windowState)
+	}
+}
+
+// get 返回保存的窗口状态
+func (m *windowStateStore) get(name string) *windowState {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.load()
+	if state, ok := m.states[name]; ok {
+		result := *state
+		return &result
+	}
+	return nil
+}
+
+// update 更新窗口状态, flush 立即写入文件, 否则延迟写入
+func (m *windowStateStore) update(name string, fn func(state *windowState), flush bool) {
+	m.lock.Lock()
+	m.load()
+	state, ok := m.states[name]
+	if !ok {
+		state = &windowState{}
+		m.states[name] = state
+	}
+	fn(state)
+	if flush {
+		if m.timer != nil {
+			m.timer.Stop()
+			m.timer = nil
+		}
+		m.lock.Unlock()
+		m.save()
+		return
+	}
+	if m.timer == nil {
+		m.timer = time.AfterFunc(windowStateSaveDelay, func() {
+			m.lock.Lock()
+			m.timer = nil
+			m.lock.Unlock()
+			m.save()
+		})
+	}
+	m.lock.Unlock()
+}
+
+// save 写入状态文件
+func (m *windowStateStore) save() {
+	m.lock.Lock()
+	if !m.loaded {
+		m.lock.Unlock()
+		return
+	}
+	data, err := json.MarshalIndent(m.states, "", "  ")
+	file := m.path()
+	m.lock.Unlock()
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(file), 0755); err == nil {
+			err = ioutil.WriteFile(file, data, 0644)
+		}
+	}
+	if err != nil {
+		logger.Error("window state:", err)
+	}
+}
+
+// restoreWindowState
+//	创建窗口时恢复保存的窗口状态到窗口属性
+//	保存的位置不在任何屏幕内时, 移动到原屏幕或主屏幕
+func restoreWindowState(window IBrowserWindow) {
+	wp := window.WindowProperty()
+	if wp.WindowStateName == "" {
+		return
+	}
+	// 恢复之后才开始记录窗口状态
+	wp.current.stateRestored = true
+	state := windowStates.get(wp.WindowStateName)
+	if state == nil || state.Width <= 0 || state.Height <= 0 {
+		return
+	}
+	var displays []Display
+	var primary Display
+	if screen := window.Screen(); screen != nil {
+		for i := 0; i < screen.Count(); i++ {
+			displays = append(displays, screen.Get(i))
+		}
+		primary = screen.Primary()
+	}
+	bounds := fitWindowBounds(TCefRect{X: state.X, Y: state.Y, Width: state.Width, Height: state.Height}, state.Display, displays, primary)
+	wp.X, wp.Y, wp.Width, wp.Height = bounds.X, bounds.Y, bounds.Width, bounds.Height
+	wp.EnableCenterWindow = false
+	switch state.State {
+	case types.WsMaximized, types.WsFullScreen:
+		wp.WindowInitState = state.State
+	default:
+		wp.WindowInitState = types.WsNormal
+	}
+}
+
+// fitWindowBounds
+//	窗口在屏幕工作区内可见时返回原位置
+//	不可见时移动到 displayId 屏幕居中, 屏幕不存在时使用主屏幕, 宽高不超过屏幕工作区
+func fitWindowBounds(bounds TCefRect, displayId int, displays []Display, primary Display) TCefRect {
+	if len(displays) == 0 {
+		return bounds
+	}
+	for _, display := range displays {
+		if windowVisibleIn(bounds, display.WorkArea) {
+			return bounds
+		}
+	}
+	target := primary
+	for _, display := range displays {
+		if display.ID == displayId {
+			target = display
+			break
+		}
+	}
+	area := target.WorkArea
+	if area.Width <= 0 || area.Height <= 0 {
+		area = displays[0].WorkArea
+	}
+	if bounds.Width > area.Width {
+		bounds.Width = area.Width
+	}
+	if bounds.Height > area.Height {
+		bounds.Height = area.Height
+	}
+	bounds.X = area.X + (area.Width-bounds.Width)/2
+	bounds.Y = area.Y + (area.Height-bounds.Height)/2
+	return bounds
+}
+
+// windowVisibleIn 窗口顶部在工作区内可见的部分足够拖动
+func windowVisibleIn(bounds, area TCefRect) bool {
+	left, right := maxInt32(bounds.X, area.X), minInt32(bounds.X+bounds.Width, area.X+area.Width)
+	top, bottom := maxInt32(bounds.Y, area.Y), minInt32(bounds.Y+bounds.Height, area.Y+area.Height)
+	// 标题栏必须在工作区内
+	if bounds.Y < area.Y || bounds.Y >= area.Y+area.Height {
+		return false
+	}
+	return right-left >= minInt32(windowStateMinVisibleWidth, bounds.Width) && bottom-top >= minInt32(windowStateMinVisibleHeight, bounds.Height)
+}
+
+// saveWindowState
+//	记录窗口当前状态, 最小化时不记录
+//	最大化和全屏时只记录状态, 保留正常状态的坐标和宽高
+func saveWindowState(window IBrowserWindow, flush bool) {
+	wp := window.WindowProperty()
+	if wp == nil || wp.WindowStateName == "" || !wp.current.stateRestored {
+		return
+	}
+	ws := window.WindowState()
+	if window.IsFullScreen() {
+		ws = types.WsFullScreen
+	}
+	if ws == types.WsMinimized || ws < 0 {
+		if flush {
+			windowStates.save()
+		}
+		return
+	}
+	var bounds *TCefRect
+	if ws == types.WsNormal {
+		bounds = window.Bounds()
+	}
+	var displayId = -1
+	if screen := window.Screen(); screen != nil && bounds != nil {
+		for i := 0; i < screen.Count(); i++ {
+			if display := screen.Get(i); windowVisibleIn(*bounds, display.WorkArea) {
+				displayId = display.ID
+				break
+			}
+		}
+	}
+	windowStates.update(wp.WindowStateName, func(state *windowState) {
+		state.State = ws
+		if bounds != nil && bounds.Width > 0 && bounds.Height > 0 {
+			state.X, state.Y, state.Width, state.Height = bounds.X, bounds.Y, bounds.Width, bounds.Height
+			if displayId >= 0 {
+				state.Display = displayId
+			}
+		}
+	}, flush)
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,63 @@
+package cef
+
+import (
+	"github.com/energye/golcl/lcl/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFitWindowBounds(t *testing.T) {
+	primary := Display{ID: 1, WorkArea: TCefRect{X: 0, Y: 0, Width: 1920, Height: 1040}}
+	second := Display{ID: 2, WorkArea: TCefRect{X: 1920, Y: 0, Width: 1280, Height: 984}}
+	displays := []Display{primary, second}
+	// 在第二个屏幕内, 保持原位置
+	bounds := TCefRect{X: 2000, Y: 100, Width: 800, Height: 600}
+	if result := fitWindowBounds(bounds, 2, displays, primary); result != bounds {
+		t.Fatal("unexpected bounds", result)
+	}
+	// 第二个屏幕已移除, 移动到主屏幕居中
+	if result := fitWindowBounds(bounds, 2, []Display{primary}, primary); result != (TCefRect{X: 560, Y: 220, Width: 800, Height: 600}) {
+		t.Fatal("unexpected bounds", result)
+	}
+	// 标题栏在屏幕外, 移动到原屏幕, 宽高不超过工作区
+	bounds = TCefRect{X: 1900, Y: -500, Width: 1600, Height: 1200}
+	if result := fitWindowBounds(bounds, 2, displays, primary); result != (TCefRect{X: 1920, Y: 0, Width: 1280, Height: 984}) {
+		t.Fatal("unexpected bounds", result)
+	}
+	// 只有少量在屏幕内
+	bounds = TCefRect{X: 1880, Y: 100, Width: 800, Height: 600}
+	if !windowVisibleIn(bounds, second.WorkArea) || windowVisibleIn(bounds, primary.WorkArea) {
+		t.Fatal("unexpected visible")
+	}
+	if result := fitWindowBounds(TCefRect{X: 10, Y: 10, Width: 50, Height: 50}, 0, nil, Display{}); result.X != 10 {
+		t.Fatal("no displays should keep bounds", result)
+	}
+}
+
+func TestWindowStateStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "energy-window-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "state", "window-state.json")
+	store := &windowStateStore{file: file}
+	if store.get("main") != nil {
+		t.Fatal("unexpected state")
+	}
+	store.update("main", func(state *windowState) {
+		state.X, state.Y, state.Width, state.Height = 10, 20, 800, 600
+	}, false)
+	store.update("main", func(state *windowState) {
+		state.State = types.WsMaximized
+	}, true)
+	if store.timer != nil {
+		t.Fatal("flush should stop timer")
+	}
+	store = &windowStateStore{file: file}
+	if state := store.get("main"); state == nil || state.Width != 800 || state.Y != 20 || state.State != types.WsMaximized {
+		t.Fatal("unexpected state", state)
+	}
+}
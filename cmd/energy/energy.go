@@ -13,6 +13,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/energye/energy/v2/cmd/internal"
 	"github.com/energye/energy/v2/cmd/internal/checkversion"
 	"github.com/energye/energy/v2/cmd/internal/command"
@@ -36,13 +37,30 @@ var commands = []*command.Command{
 	internal.CmdInit,
 	internal.CmdBuild,
 	internal.CmdBindata,
+	internal.CmdDev,
+	internal.CmdDoctor,
+	internal.CmdList,
+	internal.CmdUse,
+	internal.CmdRemove,
 }
 
 func main() {
-	term.GoENERGY()
+	// --json 输出时只输出 JSON
+	if !isJSONOutput() {
+		term.GoENERGY()
+	}
 	termRun()
 }
 
+func isJSONOutput() bool {
+	for _, arg := range os.Args[1:] {
+		if arg == "--json" {
+			return true
+		}
+	}
+	return false
+}
+
 func termRun() {
 	wd := tools.CurrentExecuteDir()
 	cc := &command.Config{Wd: wd}
@@ -76,6 +94,16 @@ func termRun() {
 			cc.Index = 7
 		case "bindata":
 			cc.Index = 8
+		case "dev":
+			cc.Index = 9
+		case "doctor":
+			cc.Index = 10
+		case "list":
+			cc.Index = 11
+		case "use":
+			cc.Index = 12
+		case "remove":
+			cc.Index = 13
 		case "v":
 			checkversion.Check()
 			return
@@ -90,9 +118,16 @@ func termRun() {
 			term.Section.Println(cmd.UsageLine, "\n", cmd.Long)
 			os.Exit(0)
 		}
-		term.Section.Println(cmd.Short)
+		cc.Args = extraArgs
+		if !isJSONOutput() {
+			term.Section.Println(cmd.Short)
+		}
 		readConfig(cc)
 		if err := cmd.Run(cc); err != nil {
+			var exit *command.ExitError
+			if errors.As(err, &exit) {
+				os.Exit(exit.Code)
+			}
 			term.Section.Println(err.Error())
 			os.Exit(1)
 		}
@@ -41,6 +41,11 @@ func BuildOutPath(proj *project.Project) string {
 	return filepath.Join(proj.ProjectPath, "build")
 }
 
+// Templates 返回内置项目模板目录, energy init --template
+func Templates() (fs.FS, error) {
+	return fs.Sub(assets, "assets/templates")
+}
+
 // ReadFile
 //  读取文件，根据项目配置先在本地目录读取，如果读取失败，则在内置资源目录读取
 func ReadFile(proj *project.Project, assetsFSPath, file string) ([]byte, error) {
@@ -0,0 +1,24 @@
+// Typed energy IPC bridge, the global ipc object injected by energy.
+// Keep the event maps in sync with the ipc.On / ipc.Emit calls in main.go.
+
+// Go -> JavaScript: ipc.Emit(name, args...) in Go, ipc.on(name, callback) in JavaScript
+export interface GoEvents {
+  osInfo: [os: string]
+}
+
+// JavaScript -> Go: ipc.emit(name, [args], callback) in JavaScript, ipc.On(name, fn) in Go
+// args: the Go function parameters, result: the Go function return values
+export interface JSEvents {
+  count: { args: [value: number]; result: [] }
+  greet: { args: [name: string]; result: [message: string] }
+}
+
+export interface EnergyIPC {
+  on<K extends keyof GoEvents>(name: K, callback: (...args: GoEvents[K]) => void): void
+  emit<K extends keyof JSEvents>(name: K, args?: JSEvents[K]['args'], callback?: (...result: JSEvents[K]['result']) => void): void
+  emitSync<K extends keyof JSEvents>(name: K, args?: JSEvents[K]['args']): JSEvents[K]['result'][0]
+}
+
+declare global {
+  const ipc: EnergyIPC
+}
@@ -0,0 +1,30 @@
+import { useEffect, useState } from 'react'
+
+export default function App() {
+  const [os, setOS] = useState('--')
+  const [name, setName] = useState('energy')
+  const [message, setMessage] = useState('')
+
+  useEffect(() => {
+    // Go -> JavaScript
+    ipc.on('osInfo', (value) => setOS(value))
+    // JavaScript -> Go
+    let count = 0
+    const timer = setInterval(() => ipc.emit('count', [++count]), 1000)
+    return () => clearInterval(timer)
+  }, [])
+
+  // JavaScript -> Go, with result
+  const greet = () => ipc.emit('greet', [name], (result) => setMessage(result))
+
+  return (
+    <>
+      <h1>Welcome to your new energy project!</h1>
+      <p>OS Info: {os}</p>
+      <p>
+        <input value={name} onChange={(e) => setName(e.target.value)} /> <button onClick={greet}>Greet</button>
+      </p>
+      <p>{message}</p>
+    </>
+  )
+}
@@ -0,0 +1,12 @@
+import { defineConfig } from 'vite'
+import react from '@vitejs/plugin-react'
+
+// Build into ../resources/dist, embedded by main.go
+export default defineConfig({
+  plugins: [react()],
+  base: './',
+  build: {
+    outDir: '../resources/dist',
+    emptyOutDir: true,
+  },
+})
@@ -0,0 +1,26 @@
+import './style.css'
+
+const app = document.querySelector<HTMLDivElement>('#app')!
+app.innerHTML = `
+  <h1>Welcome to your new energy project!</h1>
+  <p>OS Info: <span id="os">--</span></p>
+  <p><input id="name" value="energy"> <button id="greet">Greet</button></p>
+  <p id="message"></p>
+`
+
+// Go -> JavaScript
+ipc.on('osInfo', (os) => {
+  document.querySelector('#os')!.textContent = os
+})
+
+// JavaScript -> Go, with result
+document.querySelector('#greet')!.addEventListener('click', () => {
+  const name = document.querySelector<HTMLInputElement>('#name')!.value
+  ipc.emit('greet', [name], (message) => {
+    document.querySelector('#message')!.textContent = message
+  })
+})
+
+// JavaScript -> Go
+let count = 0
+setInterval(() => ipc.emit('count', [++count]), 1000)
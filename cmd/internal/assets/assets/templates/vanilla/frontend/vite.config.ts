@@ -0,0 +1,10 @@
+import { defineConfig } from 'vite'
+
+// Build into ../resources/dist, embedded by main.go
+export default defineConfig({
+  base: './',
+  build: {
+    outDir: '../resources/dist',
+    emptyOutDir: true,
+  },
+})
@@ -0,0 +1,12 @@
+import { defineConfig } from 'vite'
+import vue from '@vitejs/plugin-vue'
+
+// Build into ../resources/dist, embedded by main.go
+export default defineConfig({
+  plugins: [vue()],
+  base: './',
+  build: {
+    outDir: '../resources/dist',
+    emptyOutDir: true,
+  },
+})
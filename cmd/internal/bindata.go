@@ -21,7 +21,11 @@ var CmdBindata = &command.Command{
 	UsageLine: "bindata",
 	Short:     "Use bindata to embed static resources",
 	Long: `
-	If the go version is less than 1.16, you can use bindata to embed static resources
+	Generate a Go file embedding static resources, also for go versions less than 1.16
+	The generated FS implements emfs.IEmbedFS, on go1.16 and later [output]_fs.go implements fs.FS
+	Text, scripts, styles, json, svg and wasm are GZIP compressed by MIME type, --compress=[auto, all, none]
+	The content hashes are used by LocalLoadResource and assetserve for ETags and immutable caching,
+	--manifest writes them as JSON, for go:embed resources with assetserve.LoadManifest
 Example golang code:
 	package main  
 
@@ -30,9 +34,10 @@ Example golang code:
 		// other imports...  
 	)  
 	  
-	//go:generate energy bindata --fs --o=assets/assets.go --pkg=assets --paths=./assets
+	//go:generate energy bindata --o=assets/assets.go --pkg=assets --paths=./resources/...
 	  
 	func main() {  
+		// cef.GlobalInit(nil, assets.FS)
 		// your code here...  
 	}
 Bash: Run the following command in the same directory
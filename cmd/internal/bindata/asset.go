@@ -4,5 +4,12 @@ package bindata
 type Asset struct {
 	Path string // Full file path.
 	Name string // Key used in TOC -- name by which asset is referenced.
-	Func string // Function name for the procedure returning the asset contents.
+
+	MimeType   string // MIME type by file extension, chooses the compression.
+	Hash       string // Hex encoded sha256 of the file content.
+	Compressed bool   // Data is GZIP compressed.
+	Data       []byte // Embedded data, compressed when Compressed is set.
+	Size       int64
+	Mode       uint
+	ModTime    int64
 }
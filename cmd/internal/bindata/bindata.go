@@ -35,10 +35,12 @@ func argsConfig(bind command.Bindata) *Config {
 	c.Tags = bind.Tags
 	c.Prefix = bind.Prefix
 	c.Package = bind.Package
-	c.NoMemCopy = bind.NoMemCopy
-	c.NoCompress = bind.NoCompress
+	c.Compress = bind.Compress
+	if bind.NoCompress {
+		c.Compress = CompressNone
+	}
+	c.Manifest = bind.Manifest
 	c.NoMetadata = bind.NoMetadata
-	c.FSSystem = bind.FSSystem
 	c.Mode = bind.Mode
 	c.ModTime = bind.ModTime
 	c.Output = bind.Output
@@ -0,0 +1,100 @@
+package bindata
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const generatedTest = `package demo
+
+import (
+	"demo/assets"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAssets(t *testing.T) {
+	if err := fstest.TestFS(assets.FS, "resources/index.html", "resources/js/app.js", "resources/logo.png"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := assets.FS.ReadFile("resources/index.html")
+	if err != nil || string(data) != "<html></html>" {
+		t.Fatalf("ReadFile: %q %v", data, err)
+	}
+	var manifest map[string]string
+	data, _ = ioutil.ReadFile("manifest.json")
+	if err = json.Unmarshal(data, &manifest); err != nil || len(manifest) != 3 {
+		t.Fatalf("manifest: %v %v", manifest, err)
+	}
+	for name, hash := range assets.FS.Manifest() {
+		if manifest[name] != hash {
+			t.Fatalf("hash %s: %s != %s", name, hash, manifest[name])
+		}
+	}
+	if _, err = assets.FS.ReadFile("resources/../resources/index.html"); err == nil {
+		t.Fatal("invalid path")
+	}
+}
+`
+
+func TestTranslate(t *testing.T) {
+	dir := t.TempDir()
+	png := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(png)
+	files := map[string][]byte{
+		"resources/index.html":  []byte("<html></html>"),
+		"resources/js/app.js":   []byte(strings.Repeat("console.log('energy');\n", 100)),
+		"resources/logo.png":    png,
+		"go.mod":                []byte("module demo\n\ngo 1.16\n"),
+		"assets_test.go":        []byte(generatedTest),
+		"resources/.gitignore":  []byte("*"),
+		"resources/ignore.tmp~": []byte("tmp"),
+	}
+	for name, data := range files {
+		file := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(file), 0755)
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bind := NewConfig()
+	bind.Package = "assets"
+	bind.Prefix = dir
+	bind.Input = []InputConfig{parseInput(filepath.Join(dir, "resources") + "/...")}
+	bind.Output = filepath.Join(dir, "assets", "assets.go")
+	bind.Manifest = filepath.Join(dir, "manifest.json")
+	bind.Tags = "!nobindata"
+	bind.Ignore = []*regexp.Regexp{regexp.MustCompile(`\.gitignore$`), regexp.MustCompile(`~$`)}
+	if err := Translate(bind); err != nil {
+		t.Fatal(err)
+	}
+	code, err := os.ReadFile(bind.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(code, []byte("//go:build !nobindata")) || !bytes.Contains(code, []byte(`{name: "resources/js/app.js", data: _bindata1, compressed: true`)) ||
+		!bytes.Contains(code, []byte(`{name: "resources/logo.png", data: _bindata2, compressed: false`)) {
+		t.Fatalf("generated code:\n%s", code[:bytes.Index(code, []byte("\nvar _bindata0"))])
+	}
+	if code, _ = os.ReadFile(fsOutput(bind.Output)); !bytes.Contains(code, []byte("//go:build go1.16 && !nobindata")) {
+		t.Fatalf("generated fs code:\n%s", code)
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GO111MODULE=on")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+}
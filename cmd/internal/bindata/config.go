@@ -51,60 +51,18 @@ type Config struct {
 	// 	go_bindata["templates/foo.html"] = templates_foo_html
 	Prefix string
 
-	// NoMemCopy will alter the way the output file is generated.
-	//
-	// It will employ a hack that allows us to read the file data directly from
-	// the compiled program's `.rodata` section. This ensures that when we call
-	// call our generated function, we omit unnecessary mem copies.
-	//
-	// The downside of this, is that it requires dependencies on the `reflect` and
-	// `unsafe` packages. These may be restricted on platforms like AppEngine and
-	// thus prevent you from using this mode.
-	//
-	// Another disadvantage is that the byte slice we create, is strictly read-only.
-	// For most use-cases this is not a problem, but if you ever try to alter the
-	// returned byte slice, a runtime panic is thrown. Use this mode only on target
-	// platforms where memory constraints are an issue.
-	//
-	// The default behaviour is to use the old code generation method. This
-	// prevents the two previously mentioned issues, but will employ at least one
-	// extra memcopy and thus increase memory requirements.
-	//
-	// For instance, consider the following two examples:
-	//
-	// This would be the default mode, using an extra memcopy but gives a safe
-	// implementation without dependencies on `reflect` and `unsafe`:
-	//
-	// 	func myfile() []byte {
-	// 		return []byte{0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a}
-	// 	}
-	//
-	// Here is the same functionality, but uses the `.rodata` hack.
-	// The byte slice returned from this example can not be written to without
-	// generating a runtime error.
-	//
-	// 	var _myfile = "\x89\x50\x4e\x47\x0d\x0a\x1a"
-	//
-	// 	func myfile() []byte {
-	// 		var empty [0]byte
-	// 		sx := (*reflect.StringHeader)(unsafe.Pointer(&_myfile))
-	// 		b := empty[:]
-	// 		bx := (*reflect.SliceHeader)(unsafe.Pointer(&b))
-	// 		bx.Data = sx.Data
-	// 		bx.Len = len(_myfile)
-	// 		bx.Cap = bx.Len
-	// 		return b
-	// 	}
-	NoMemCopy bool
-
-	// NoCompress means the assets are /not/ GZIP compressed before being turned
-	// into Go code. The generated function will automatically unzip
-	// the file data when called. Defaults to false.
-	NoCompress bool
-
-	// FSSystem means whether generate return fs.FileSystem interface
-	// instance's function.When true,will generate relate code.
-	FSSystem bool
+	// Compress chooses which assets are GZIP compressed before being
+	// turned into Go code, one of CompressAuto, CompressAll or CompressNone.
+	// Defaults to CompressAuto, which compresses text, scripts, styles, json,
+	// svg and wasm by MIME type, images, fonts, media and archives are
+	// already compressed and stored as is. An asset is only stored
+	// compressed when it becomes smaller.
+	Compress string
+
+	// Manifest is an optional JSON file to write the content hashes of all
+	// assets to, name: sha256. Assets embedded with go:embed can use it
+	// through assetserve.LoadManifest for ETags and immutable caching.
+	Manifest string
 
 	// Perform a debug build. This generates an asset file, which
 	// loads the asset contents directly from disk at their original
@@ -127,7 +85,7 @@ type Config struct {
 	// repository.
 	Dev bool
 
-	// When true, size, mode and modtime are not preserved from files
+	// When true, mode and modtime are not preserved from files
 	NoMetadata bool
 	// When nonzero, use this as mode for all files.
 	Mode uint
@@ -146,13 +104,18 @@ type Config struct {
 	Paths string
 }
 
+// Asset compression modes.
+const (
+	CompressAuto = "auto"
+	CompressAll  = "all"
+	CompressNone = "none"
+)
+
 // NewConfig returns a default configuration struct.
 func NewConfig() *Config {
 	c := new(Config)
 	c.Package = "main"
-	c.NoMemCopy = false
-	c.NoCompress = false
-	c.FSSystem = false
+	c.Compress = CompressAuto
 	c.Debug = false
 	c.Output = "./bindata.go"
 	c.Ignore = make([]*regexp.Regexp, 0)
@@ -166,6 +129,14 @@ func (c *Config) validate() error {
 		return fmt.Errorf("missing package name")
 	}
 
+	switch c.Compress {
+	case "":
+		c.Compress = CompressAuto
+	case CompressAuto, CompressAll, CompressNone:
+	default:
+		return fmt.Errorf("invalid compress '%s', should be: auto, all, none", c.Compress)
+	}
+
 	for _, input := range c.Input {
 		_, err := os.Lstat(input.Path)
 		if err != nil {
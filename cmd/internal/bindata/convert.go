@@ -2,18 +2,23 @@ package bindata
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
-	"unicode"
 )
 
 // Translate reads assets from an input directory, converts them
 // to Go code and writes new files to the output specified
 // in the given configuration.
+//
+// The generated FS implements emfs.IEmbedFS, on Go 1.16 and later
+// the [output]_fs.go file implements fs.FS with a go1.16 build tag.
 func Translate(c *Config) error {
 	var toc []Asset
 
@@ -23,86 +28,83 @@ func Translate(c *Config) error {
 		return err
 	}
 
-	var knownFuncs = make(map[string]int)
 	var visitedPaths = make(map[string]bool)
 	// Locate all the assets.
 	for _, input := range c.Input {
-		err = findFiles(input.Path, c.Prefix, input.Recursive, &toc, c.Ignore, knownFuncs, visitedPaths)
+		err = findFiles(input.Path, c.Prefix, input.Recursive, &toc, c.Ignore, visitedPaths)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Create output file.
-	fd, err := os.Create(c.Output)
-	if err != nil {
-		return err
-	}
-
-	defer fd.Close()
-
-	// Create a buffered writer for better performance.
-	bfd := bufio.NewWriter(fd)
-	defer bfd.Flush()
-
-	// Write the header. This makes e.g. Github ignore diffs in generated files.
-	if _, err = fmt.Fprintf(bfd, "// Code generated by go-bindata. (@generated) DO NOT EDIT.\n\n// Package %s generated by go-bindata.\n", c.Package); err != nil {
-		return err
-	}
-	if _, err = fmt.Fprint(bfd, "// sources:\n"); err != nil {
-		return err
+	// Read, hash and compress the assets.
+	for i := range toc {
+		if err = loadAsset(c, &toc[i]); err != nil {
+			return err
+		}
 	}
 
 	wd, err := os.Getwd()
 	if err != nil {
 		return err
 	}
-
+	var sources []string
 	for _, asset := range toc {
 		relative, _ := filepath.Rel(wd, asset.Path)
-		if _, err = fmt.Fprintf(bfd, "// %s\n", filepath.ToSlash(relative)); err != nil {
-			return err
-		}
+		sources = append(sources, filepath.ToSlash(relative))
 	}
-	//if _, err = fmt.Fprint(bfd, "// DO NOT EDIT!\n\n"); err != nil {
-	//	return err
-	//}
 
-	// Write build tags, if applicable.
-	if len(c.Tags) > 0 {
-		if _, err = fmt.Fprintf(bfd, "// +build %s\n\n", c.Tags); err != nil {
-			return err
-		}
+	if err = writeFile(c.Output, func(w io.Writer) error {
+		return writeAssets(w, c, toc, sources)
+	}); err != nil {
+		return err
 	}
-
-	// Write package declaration.
-	_, err = fmt.Fprintf(bfd, "package %s\n\n", c.Package)
-	if err != nil {
+	if err = writeFile(fsOutput(c.Output), func(w io.Writer) error {
+		return writeFS(w, c)
+	}); err != nil {
 		return err
 	}
-
-	// Write assets.
-	if c.Debug || c.Dev {
-		err = writeDebug(bfd, c, toc)
-	} else {
-		err = writeRelease(bfd, c, toc)
+	if c.Manifest != "" {
+		return writeManifest(c.Manifest, toc)
 	}
+	return nil
+}
+
+// fsOutput returns the file name of the fs.FS implementation, [output]_fs.go.
+func fsOutput(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_fs.go"
+}
 
+// writeFile creates file and writes the generated code through a buffered writer.
+func writeFile(file string, write func(w io.Writer) error) error {
+	fd, err := os.Create(file)
 	if err != nil {
 		return err
 	}
-
-	// Write table of contents
-	if err := writeTOC(bfd, toc); err != nil {
+	defer fd.Close()
+	bfd := bufio.NewWriter(fd)
+	if err = write(bfd); err != nil {
 		return err
 	}
-	// Write hierarchical tree of assets
-	if err := writeTOCTree(bfd, toc); err != nil {
+	return bfd.Flush()
+}
+
+// writeManifest writes the content hashes of all assets as JSON, name: sha256.
+func writeManifest(file string, toc []Asset) error {
+	manifest := make(map[string]string, len(toc))
+	for _, asset := range toc {
+		manifest[asset.Name] = asset.Hash
+	}
+	data, err := json.MarshalIndent(manifest, "", "\t")
+	if err != nil {
 		return err
 	}
-
-	// Write restore procedure
-	return writeRestore(bfd)
+	if dir := filepath.Dir(file); dir != "" {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(file, data, 0644)
 }
 
 // ByName implements sort.Interface for []os.FileInfo based on Name()
@@ -115,7 +117,7 @@ func (v ByName) Less(i, j int) bool { return v[i].Name() < v[j].Name() }
 // findFiles recursively finds all the file paths in the given directory tree.
 // They are added to the given map as keys. Values will be safe function names
 // for each file, which will be used when generating the output code.
-func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regexp.Regexp, knownFuncs map[string]int, visitedPaths map[string]bool) error {
+func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regexp.Regexp, visitedPaths map[string]bool) error {
 	dirpath := dir
 	if len(prefix) > 0 {
 		dirpath, _ = filepath.Abs(dirpath)
@@ -171,7 +173,7 @@ func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regex
 			if recursive {
 				recursivePath := filepath.Join(dir, file.Name())
 				visitedPaths[asset.Path] = true
-				findFiles(recursivePath, prefix, recursive, toc, ignore, knownFuncs, visitedPaths)
+				findFiles(recursivePath, prefix, recursive, toc, ignore, visitedPaths)
 			}
 			continue
 		} else if file.Mode()&os.ModeSymlink == os.ModeSymlink {
@@ -186,7 +188,7 @@ func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regex
 			}
 			if _, ok := visitedPaths[linkPath]; !ok {
 				visitedPaths[linkPath] = true
-				findFiles(asset.Path, prefix, recursive, toc, ignore, knownFuncs, visitedPaths)
+				findFiles(asset.Path, prefix, recursive, toc, ignore, visitedPaths)
 			}
 			continue
 		}
@@ -207,51 +209,9 @@ func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regex
 			return fmt.Errorf("invalid file: %v", asset.Path)
 		}
 
-		asset.Func = safeFunctionName(asset.Name, knownFuncs)
 		asset.Path, _ = filepath.Abs(asset.Path)
 		*toc = append(*toc, asset)
 	}
 
 	return nil
 }
-
-var regFuncName = regexp.MustCompile(`[^a-zA-Z0-9_]`)
-
-// safeFunctionName converts the given name into a name
-// which qualifies as a valid function identifier. It
-// also compares against a known list of functions to
-// prevent conflict based on name translation.
-func safeFunctionName(name string, knownFuncs map[string]int) string {
-	var inBytes, outBytes []byte
-	var toUpper bool
-
-	name = strings.ToLower(name)
-	inBytes = []byte(name)
-
-	for i := 0; i < len(inBytes); i++ {
-		if regFuncName.Match([]byte{inBytes[i]}) {
-			toUpper = true
-		} else if toUpper {
-			outBytes = append(outBytes, []byte(strings.ToUpper(string(inBytes[i])))...)
-			toUpper = false
-		} else {
-			outBytes = append(outBytes, inBytes[i])
-		}
-	}
-
-	name = string(outBytes)
-
-	// Identifier can't start with a digit.
-	if unicode.IsDigit(rune(name[0])) {
-		name = "_" + name
-	}
-
-	if num, ok := knownFuncs[name]; ok {
-		knownFuncs[name] = num + 1
-		name = fmt.Sprintf("%s%d", name, num)
-	} else {
-		knownFuncs[name] = 2
-	}
-
-	return name
-}
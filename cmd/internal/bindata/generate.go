@@ -0,0 +1,454 @@
+package bindata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/energye/energy/v2/pkgs/assetserve"
+	"go/build/constraint"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// compressibleTypes MIME types of text based assets, matched by substring.
+var compressibleTypes = []string{"json", "javascript", "ecmascript", "xml", "svg", "wasm", "x-font-ttf", "x-font-otf"}
+
+// loadAsset reads the asset content, computes its hash and compresses it
+// when the configuration and MIME type ask for it.
+func loadAsset(c *Config, asset *Asset) error {
+	data, err := ioutil.ReadFile(asset.Path)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(asset.Path)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	asset.Hash = hex.EncodeToString(sum[:])
+	asset.MimeType = assetserve.ContentType(filepath.Ext(asset.Name))
+	asset.Size = int64(len(data))
+	asset.Mode = uint(fi.Mode().Perm())
+	asset.ModTime = fi.ModTime().Unix()
+	if c.NoMetadata {
+		asset.Mode = 0
+		asset.ModTime = 0
+	}
+	if c.Mode > 0 {
+		asset.Mode = uint(os.ModePerm) & c.Mode
+	}
+	if c.ModTime > 0 {
+		asset.ModTime = c.ModTime
+	}
+	if c.Debug || c.Dev {
+		// Contents are loaded from disk.
+		return nil
+	}
+	asset.Data = data
+	if c.Compress == CompressAll || c.Compress == CompressAuto && compressible(asset.MimeType) {
+		var buf bytes.Buffer
+		gz, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if _, err = gz.Write(data); err != nil {
+			return err
+		}
+		if err = gz.Close(); err != nil {
+			return err
+		}
+		// Only keep the compressed data when it saves at least 1/8.
+		if c.Compress == CompressAll || buf.Len() < len(data)-len(data)/8 {
+			asset.Data = buf.Bytes()
+			asset.Compressed = true
+		}
+	}
+	return nil
+}
+
+// compressible reports whether assets of the MIME type are worth compressing.
+// Unknown types are tried and kept only when they become smaller.
+func compressible(mimeType string) bool {
+	if mimeType == "" || strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	for _, t := range compressibleTypes {
+		if strings.Contains(mimeType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildConstraint returns the //go:build and // +build lines of the
+// configured tags combined with goVersion, empty when there is none.
+func buildConstraint(tags, goVersion string) (string, error) {
+	var expr constraint.Expr
+	if tags != "" {
+		var err error
+		if expr, err = constraint.Parse("// +build " + tags); err != nil {
+			return "", fmt.Errorf("invalid tags '%s': %v", tags, err)
+		}
+	}
+	if goVersion != "" {
+		if expr == nil {
+			expr = &constraint.TagExpr{Tag: goVersion}
+		} else {
+			expr = &constraint.AndExpr{X: &constraint.TagExpr{Tag: goVersion}, Y: expr}
+		}
+	}
+	if expr == nil {
+		return "", nil
+	}
+	lines, err := constraint.PlusBuildLines(expr)
+	if err != nil {
+		return "", err
+	}
+	return "//go:build " + expr.String() + "\n" + strings.Join(lines, "\n") + "\n\n", nil
+}
+
+// assetDirs returns the sorted children of every directory, the root is "".
+func assetDirs(toc []Asset) map[string][]string {
+	var (
+		dirs = map[string][]string{"": nil}
+		seen = make(map[string]bool)
+	)
+	for _, asset := range toc {
+		for name := asset.Name; name != "."; name = path.Dir(name) {
+			dir := path.Dir(name)
+			if dir == "." {
+				dir = ""
+			}
+			if !seen[name] {
+				seen[name] = true
+				dirs[dir] = append(dirs[dir], path.Base(name))
+			}
+		}
+	}
+	for _, children := range dirs {
+		sort.Strings(children)
+	}
+	return dirs
+}
+
+type templateData struct {
+	*Config
+	Constraint string
+	Sources    []string
+	Assets     []Asset
+	Dirs       map[string][]string
+}
+
+// writeAssets writes the assets file, the formatted code is followed by
+// the asset data, which is streamed without formatting.
+func writeAssets(w io.Writer, c *Config, toc []Asset, sources []string) error {
+	build, err := buildConstraint(c.Tags, "")
+	if err != nil {
+		return err
+	}
+	err = executeTemplate(w, assetsTemplate, &templateData{Config: c, Constraint: build, Sources: sources, Assets: toc, Dirs: assetDirs(toc)})
+	if err != nil || c.Debug || c.Dev {
+		return err
+	}
+	for i := range toc {
+		if _, err = fmt.Fprintf(w, "\nvar _bindata%d = \"", i); err != nil {
+			return err
+		}
+		if _, err = (&StringWriter{Writer: w}).Write(toc[i].Data); err != nil {
+			return err
+		}
+		if _, err = fmt.Fprint(w, "\"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFS writes the fs.FS implementation, which is only built by Go 1.16 and later.
+func writeFS(w io.Writer, c *Config) error {
+	build, err := buildConstraint(c.Tags, "go1.16")
+	if err != nil {
+		return err
+	}
+	return executeTemplate(w, fsTemplate, &templateData{Config: c, Constraint: build})
+}
+
+func executeTemplate(w io.Writer, tmpl *template.Template, data *templateData) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated code: %v", err)
+	}
+	_, err = w.Write(code)
+	return err
+}
+
+var assetsTemplate = template.Must(template.New("assets").Parse(`// Code generated by energy bindata. DO NOT EDIT.
+// sources:
+{{- range .Sources}}
+// {{.}}
+{{- end}}
+
+{{.Constraint}}package {{.Package}}
+
+import (
+	{{- if or .Debug .Dev}}
+	"crypto/sha256"
+	"encoding/hex"
+	{{- else}}
+	"compress/gzip"
+	{{- end}}
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// FS holds the embedded assets. It implements emfs.IEmbedFS, on Go 1.16
+// and later also fs.FS, fs.ReadFileFS, fs.ReadDirFS and fs.StatFS.
+var FS = &bindataFS{}
+
+// AssetFile returns FS, compatible with code generated by earlier versions.
+func AssetFile() *bindataFS {
+	return FS
+}
+
+type bindataFS struct{}
+
+type bindataFile struct {
+	name       string
+	{{- if or .Debug .Dev}}
+	path       string // The file is read from disk.
+	{{- else}}
+	data       string // The GZIP compressed data when compressed is set.
+	compressed bool
+	{{- end}}
+	size       int64
+	mode       os.FileMode
+	modTime    int64
+	hash       string // Hex encoded sha256 of the content.
+}
+
+// bindataDirInfo is the FileInfo and DirEntry of a directory.
+type bindataDirInfo string
+
+// ReadFile reads the named file.
+func (*bindataFS) ReadFile(name string) ([]byte, error) {
+	file, ok := bindataLookup(name)
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return file.bytes()
+}
+
+// Stat returns the FileInfo of the named file or directory.
+func (*bindataFS) Stat(name string) (os.FileInfo, error) {
+	if key, ok := bindataName(name); ok {
+		if file, ok := bindataFiles[key]; ok {
+			return file, nil
+		}
+		if _, ok := bindataDirs[key]; ok {
+			return bindataDirInfo(key), nil
+		}
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// Hash returns the hex encoded sha256 of the named file content,
+// used for ETags and cache busting.
+func (*bindataFS) Hash(name string) (string, bool) {
+	file, ok := bindataLookup(name)
+	if !ok {
+		return "", false
+	}
+	{{- if or .Debug .Dev}}
+	data, err := file.bytes()
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+	{{- else}}
+	return file.hash, true
+	{{- end}}
+}
+
+// Manifest returns the content hashes of all files, name: sha256.
+func (m *bindataFS) Manifest() map[string]string {
+	manifest := make(map[string]string, len(bindataFiles))
+	for name := range bindataFiles {
+		manifest[name], _ = m.Hash(name)
+	}
+	return manifest
+}
+
+// bindataName returns the key of name, ok is false when name is not
+// a valid path as of fs.ValidPath, the same as embed.FS.
+func bindataName(name string) (string, bool) {
+	if name == "." {
+		return "", true
+	}
+	if name == "" || name[0] == '/' || name == ".." || strings.HasPrefix(name, "../") || path.Clean(name) != name {
+		return "", false
+	}
+	return name, true
+}
+
+func bindataLookup(name string) (*bindataFile, bool) {
+	key, ok := bindataName(name)
+	if !ok {
+		return nil, false
+	}
+	file, ok := bindataFiles[key]
+	return file, ok
+}
+
+func (f *bindataFile) bytes() ([]byte, error) {
+	{{- if or .Debug .Dev}}
+	return ioutil.ReadFile(f.path)
+	{{- else}}
+	if !f.compressed {
+		return []byte(f.data), nil
+	}
+	gz, err := gzip.NewReader(strings.NewReader(f.data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return ioutil.ReadAll(gz)
+	{{- end}}
+}
+
+func (f *bindataFile) Name() string               { return path.Base(f.name) }
+func (f *bindataFile) Size() int64                { return f.size }
+func (f *bindataFile) Mode() os.FileMode          { return f.mode }
+func (f *bindataFile) ModTime() time.Time         { return time.Unix(f.modTime, 0) }
+func (f *bindataFile) IsDir() bool                { return false }
+func (f *bindataFile) Sys() interface{}           { return nil }
+func (f *bindataFile) Type() os.FileMode          { return f.mode & os.ModeType }
+func (f *bindataFile) Info() (os.FileInfo, error) { return f, nil }
+
+func (d bindataDirInfo) Name() string               { return path.Base(string(d)) }
+func (d bindataDirInfo) Size() int64                { return 0 }
+func (d bindataDirInfo) Mode() os.FileMode          { return os.ModeDir | 0555 }
+func (d bindataDirInfo) ModTime() time.Time         { return time.Time{} }
+func (d bindataDirInfo) IsDir() bool                { return true }
+func (d bindataDirInfo) Sys() interface{}           { return nil }
+func (d bindataDirInfo) Type() os.FileMode          { return os.ModeDir }
+func (d bindataDirInfo) Info() (os.FileInfo, error) { return d, nil }
+
+var bindataFiles = map[string]*bindataFile{
+	{{- range $i, $asset := .Assets}}
+	{{printf "%q" .Name}}: {name: {{printf "%q" .Name}},
+		{{- if $.Dev}} path: rootDir + {{printf "%q" (print "/" .Name)}},
+		{{- else if $.Debug}} path: {{printf "%q" .Path}},
+		{{- else}} data: _bindata{{$i}}, compressed: {{.Compressed}},
+		{{- end}} size: {{.Size}}, mode: {{printf "%#o" .Mode}}, modTime: {{.ModTime}}, hash: {{printf "%q" .Hash}}},
+	{{- end}}
+}
+
+// bindataDirs holds the sorted entries of every directory, the root is "".
+var bindataDirs = map[string][]string{
+	{{- range $dir, $children := .Dirs}}
+	{{printf "%q" $dir}}: { {{- range $children}}{{printf "%q" .}}, {{end -}} },
+	{{- end}}
+}
+`))
+
+var fsTemplate = template.Must(template.New("fs").Parse(`// Code generated by energy bindata. DO NOT EDIT.
+
+{{.Constraint}}package {{.Package}}
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+)
+
+// Open opens the named file or directory, implements fs.FS.
+func (m *bindataFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	key, _ := bindataName(name)
+	if file, ok := bindataFiles[key]; ok {
+		data, err := file.bytes()
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &bindataOpenFile{Reader: bytes.NewReader(data), file: file}, nil
+	}
+	if _, ok := bindataDirs[key]; ok {
+		entries, _ := m.ReadDir(key)
+		return &bindataOpenDir{info: bindataDirInfo(key), entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir reads the named directory, implements fs.ReadDirFS.
+func (*bindataFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	dir, _ := bindataName(name)
+	children, ok := bindataDirs[dir]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, len(children))
+	for i, child := range children {
+		child = path.Join(dir, child)
+		if file, ok := bindataFiles[child]; ok {
+			entries[i] = file
+		} else {
+			entries[i] = bindataDirInfo(child)
+		}
+	}
+	return entries, nil
+}
+
+type bindataOpenFile struct {
+	*bytes.Reader
+	file *bindataFile
+}
+
+func (f *bindataOpenFile) Stat() (fs.FileInfo, error) { return f.file, nil }
+func (f *bindataOpenFile) Close() error               { return nil }
+
+type bindataOpenDir struct {
+	info    bindataDirInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *bindataOpenDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *bindataOpenDir) Close() error               { return nil }
+
+func (d *bindataOpenDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: string(d.info), Err: errors.New("is a directory")}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *bindataOpenDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	n := len(d.entries) - d.offset
+	if n == 0 && count > 0 {
+		return nil, io.EOF
+	}
+	if count > 0 && n > count {
+		n = count
+	}
+	entries := make([]fs.DirEntry, n)
+	copy(entries, d.entries[d.offset:d.offset+n])
+	d.offset += n
+	return entries, nil
+}
+`))
@@ -18,7 +18,7 @@ import (
 )
 
 var CmdBuild = &command.Command{
-	UsageLine: "build -p [path] -u [upx] --upxFlag --args --libemfs",
+	UsageLine: "build -p [path] -u [upx] --upxFlag --args --libemfs --targets",
 	Short:     "build energy project",
 	Long: `
 	Building energy project
@@ -27,6 +27,12 @@ var CmdBuild = &command.Command{
 	   --upxFlag: Upx command line parameters
 	--args Set go build [args]
 	--libemfs Built in dynamic libraries to executable files, Copy liblcl to the built-in directory every compilation
+	--targets Build matrix, OS/ARCH separated by commas: linux/amd64,windows/amd64. Output to build/[os]-[arch]
+	energy.json "build" configuration:
+	  preBuild: Commands executed before building
+	  postBuild: Commands executed after each target is built successfully
+	  frontend: Frontend build {command, dir, output}, output directory must not be empty after building
+	Version, git commit and build time are written to the github.com/energye/energy/v2/energy/version package
 `,
 }
 
@@ -11,53 +11,303 @@
 package build
 
 import (
+	"errors"
+	"fmt"
 	"github.com/energye/energy/v2/cmd/internal/command"
-	"github.com/energye/energy/v2/cmd/internal/consts"
 	"github.com/energye/energy/v2/cmd/internal/project"
+	"github.com/energye/energy/v2/cmd/internal/term"
 	"github.com/energye/energy/v2/cmd/internal/tools"
 	"io"
+	"io/ioutil"
 	"os"
-	"path"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 )
 
 const (
 	assetsFSPath = "assets/build/"
 )
 
+// 编译时写入版本信息的包
+const versionPackage = "github.com/energye/energy/v2/energy/version"
+
+// target 编译目标系统架构
+type target struct {
+	OS   command.OS
+	Arch command.Arch
+}
+
+func (m target) String() string {
+	return string(m.OS) + "/" + string(m.Arch)
+}
+
 func Build(c *command.Config) error {
 	// 读取项目配置文件 energy.json 在main函数目录
-	if proj, err := project.NewProject(c.Build.Path); err != nil {
+	proj, err := project.NewProject(c.Build.Path)
+	if err != nil {
 		return err
-	} else {
-		// libemfs 标志开启后,如果使用了内置libs到执行文件, 项目energy.json配置将ENERGY_HOME环境变量下的liblcl库复制到内置目录
-		// 这里仅仅是在编译时把liblcl复制到内置资源目录中
-		if !consts.IsDarwin && c.Build.Libemfs {
-			// 复制liblcl到内置目录
-			emfsPath := path.Join(proj.ProjectPath, proj.LibEMFS)
-			dllPath := path.Join(emfsPath, tools.GetDLLName())
-			if tools.IsExist(dllPath) {
-				os.Remove(dllPath)
-			} else {
-				os.MkdirAll(emfsPath, os.ModePerm)
-			}
-			// copy
-			libsrc := path.Join(os.Getenv("ENERGY_HOME"), tools.GetDLLName())
-			src, err := os.Open(libsrc)
-			if err != nil {
-				return err
-			}
-			defer src.Close()
-			st, _ := src.Stat()
-			dst, err := os.OpenFile(dllPath, os.O_RDWR|os.O_CREATE, st.Mode())
-			if err != nil {
-				return err
-			}
-			defer dst.Close()
-			_, err = io.Copy(dst, src)
-			if err != nil {
-				return err
+	}
+	targets, err := parseTargets(c.Build.Targets)
+	if err != nil {
+		return err
+	}
+	for _, cmd := range proj.Build.PreBuild {
+		term.Section.Println("Pre build", cmd)
+		if err = runShell(proj.ProjectPath, hookEnv(proj, ""), cmd); err != nil {
+			return fmt.Errorf("pre build failed: %w", err)
+		}
+	}
+	if !c.Build.Dev {
+		if err = buildFrontend(proj); err != nil {
+			return err
+		}
+	}
+	if len(targets) == 0 {
+		return buildTarget(c, proj, proj.OutputFilename)
+	}
+	for _, t := range targets {
+		targetProj := proj.ForTarget(t.OS, t.Arch)
+		// 多个目标时输出到 build/[os]-[arch]
+		output := filepath.Join("build", fmt.Sprintf("%s-%s", t.OS, t.Arch), targetProj.OutputFilename)
+		if err = buildTarget(c, targetProj, output); err != nil {
+			return fmt.Errorf("build %s failed: %w", t, err)
+		}
+	}
+	return nil
+}
+
+// parseTargets 解析编译目标 linux/amd64,windows/amd64
+func parseTargets(value string) ([]target, error) {
+	var result []target
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid target %q, format: os/arch", item)
+		}
+		t := target{OS: command.OS(strings.ToLower(parts[0])), Arch: command.Arch(strings.ToLower(parts[1]))}
+		if !t.OS.IsWindows() && !t.OS.IsLinux() && !t.OS.IsDarwin() {
+			return nil, fmt.Errorf("invalid target %q, unsupported os: %s", item, t.OS)
+		}
+		if t.Arch != "386" && !t.Arch.IsAMD64() && !t.Arch.IsARM64() {
+			return nil, fmt.Errorf("invalid target %q, unsupported arch: %s", item, t.Arch)
+		}
+		for _, exist := range result {
+			if exist == t {
+				return nil, fmt.Errorf("duplicate target %q", item)
 			}
 		}
-		return build(c, proj)
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// buildFrontend 执行前端编译命令, 检查输出目录
+func buildFrontend(proj *project.Project) error {
+	frontend := proj.Build.Frontend
+	if frontend.Command == "" {
+		return nil
+	}
+	dir := proj.ProjectPath
+	if frontend.Dir != "" {
+		dir = projectFile(proj, frontend.Dir)
+	}
+	term.Section.Println("Building frontend", frontend.Command)
+	if err := runShell(dir, hookEnv(proj, ""), frontend.Command); err != nil {
+		return fmt.Errorf("frontend build failed: %w", err)
+	}
+	if frontend.Output != "" {
+		output := projectFile(proj, frontend.Output)
+		if files, err := ioutil.ReadDir(output); err != nil || len(files) == 0 {
+			return errors.New("frontend build output is empty: " + output)
+		}
+	}
+	return nil
+}
+
+// buildTarget 编译一个目标系统架构, output 相对项目目录
+func buildTarget(c *command.Config, proj *project.Project, output string) (err error) {
+	// libemfs 标志开启后,如果使用了内置libs到执行文件, 项目energy.json配置将ENERGY_HOME环境变量下的liblcl库复制到内置目录
+	// 这里仅仅是在编译时把liblcl复制到内置资源目录中
+	if !proj.OS.IsDarwin() && c.Build.Libemfs {
+		if proj.IsCross() {
+			return errors.New("--libemfs only supports the current system architecture")
+		}
+		if err = copyLibEMFS(proj); err != nil {
+			return err
+		}
+	}
+	// windows 图标和版本信息
+	if proj.OS.IsWindows() {
+		var syso string
+		if syso, err = windowsResource(proj); syso != "" {
+			defer os.Remove(syso)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if proj.IsCross() && !proj.OS.IsWindows() && os.Getenv("CC") == "" {
+		term.Logger.Warn("Cross compiling "+string(proj.OS)+" requires cgo, set CC to a C cross compiler", term.Logger.Args("target", string(proj.OS)+"/"+string(proj.Arch)))
+	}
+	// go build
+	term.Section.Println("Building", output)
+	var args = []string{"build"}
+	if c.Build.Args != "" {
+		args = append(args, strings.Fields(c.Build.Args)...)
+	}
+	args = append(args, "-ldflags", ldflags(proj, gitCommit(proj.ProjectPath), time.Now()))
+	args = append(args, "-o", output)
+	env := []string{"GOOS=" + string(proj.OS), "GOARCH=" + string(proj.Arch)}
+	if !proj.OS.IsWindows() {
+		env = append(env, "CGO_ENABLED=1")
+	}
+	if err = run(proj.ProjectPath, env, "go", args...); err != nil {
+		return err
+	}
+	// strip 只能处理当前系统的执行文件
+	if !proj.OS.IsWindows() && !proj.IsCross() && tools.CommandExists("strip") {
+		if err = run(proj.ProjectPath, nil, "strip", output); err != nil {
+			return err
+		}
+	}
+	// upx
+	if c.Build.Upx && tools.CommandExists("upx") {
+		term.Section.Println("Upx compression")
+		args = []string{"--best", "--no-color", "--no-progress", output}
+		if c.Build.UpxFlag != "" {
+			args = strings.Split(c.Build.UpxFlag, " ")
+			args = append(args, output)
+		}
+		if err = run(proj.ProjectPath, nil, "upx", args...); err != nil {
+			return err
+		}
+	} else if c.Build.Upx {
+		if runtime.GOOS == "darwin" {
+			term.Logger.Error("upx command not found", term.Logger.Args("install-upx", "brew install upx"))
+		} else {
+			term.Logger.Error("upx command not found")
+		}
+	}
+	for _, cmd := range proj.Build.PostBuild {
+		term.Section.Println("Post build", cmd)
+		if err = runShell(proj.ProjectPath, hookEnv(proj, output), cmd); err != nil {
+			return fmt.Errorf("post build failed: %w", err)
+		}
+	}
+	term.Section.Println("Build Successfully")
+	return nil
+}
+
+// copyLibEMFS 复制项目框架目录下的 liblcl 到内置目录
+func copyLibEMFS(proj *project.Project) error {
+	emfsPath := filepath.Join(proj.ProjectPath, proj.LibEMFS)
+	dllPath := filepath.Join(emfsPath, tools.GetDLLName())
+	if tools.IsExist(dllPath) {
+		os.Remove(dllPath)
+	} else {
+		os.MkdirAll(emfsPath, os.ModePerm)
+	}
+	// copy
+	libsrc := filepath.Join(proj.FrameworkPath, tools.GetDLLName())
+	src, err := os.Open(libsrc)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	st, _ := src.Stat()
+	dst, err := os.OpenFile(dllPath, os.O_RDWR|os.O_CREATE, st.Mode())
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// ldflags 编译参数, 写入版本、git commit 和编译时间
+func ldflags(proj *project.Project, commit string, buildTime time.Time) string {
+	flags := []string{"-s", "-w"}
+	if proj.OS.IsWindows() {
+		flags = append(flags, "-H", "windowsgui")
+	}
+	var set = func(name, value string) {
+		if value == "" {
+			return
+		}
+		flag := versionPackage + "." + name + "=" + strings.Replace(value, "'", "", -1)
+		if strings.ContainsAny(flag, " \t\"") {
+			flag = "'" + flag + "'"
+		}
+		flags = append(flags, "-X", flag)
+	}
+	set("version", proj.Info.ProductVersion)
+	set("commit", commit)
+	set("buildTime", buildTime.UTC().Format(time.RFC3339))
+	return strings.Join(flags, " ")
+}
+
+// gitCommit 项目的 git commit, 不是 git 仓库或没有 git 时为空
+func gitCommit(dir string) string {
+	if !tools.CommandExists("git") {
+		return ""
+	}
+	cmd := exec.Command("git", "rev-parse", "--short", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// hookEnv 编译命令的环境变量
+func hookEnv(proj *project.Project, output string) []string {
+	env := []string{
+		"ENERGY_PROJECT_PATH=" + proj.ProjectPath,
+		"ENERGY_OS=" + string(proj.OS),
+		"ENERGY_ARCH=" + string(proj.Arch),
+		"ENERGY_VERSION=" + proj.Info.ProductVersion,
+	}
+	if output != "" {
+		env = append(env, "ENERGY_OUTPUT="+projectFile(proj, output))
+	}
+	return env
+}
+
+// projectFile 相对项目目录的文件路径
+func projectFile(proj *project.Project, path string) string {
+	path = filepath.FromSlash(path)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(proj.ProjectPath, path)
+}
+
+// run 执行命令并输出到终端, 命令失败时返回错误
+func run(dir string, env []string, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// runShell 使用系统 shell 执行命令
+func runShell(dir string, env []string, command string) error {
+	if runtime.GOOS == "windows" {
+		return run(dir, env, "cmd", "/C", command)
 	}
+	return run(dir, env, "sh", "-c", command)
 }
@@ -0,0 +1,46 @@
+package build
+
+import (
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTargets(t *testing.T) {
+	targets, err := parseTargets(" linux/amd64, Windows/386 ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 || targets[0].String() != "linux/amd64" || targets[1].String() != "windows/386" {
+		t.Fatal("unexpected targets", targets)
+	}
+	for _, value := range []string{"linux", "android/arm64", "linux/mips", "linux/amd64,linux/amd64"} {
+		if _, err = parseTargets(value); err == nil {
+			t.Fatal("expected error", value)
+		}
+	}
+	if targets, err = parseTargets(""); err != nil || len(targets) != 0 {
+		t.Fatal("unexpected targets", targets, err)
+	}
+}
+
+func TestLdflags(t *testing.T) {
+	proj := (&project.Project{OS: "linux", Arch: "amd64", OutputFilename: "app"}).ForTarget("windows", "amd64")
+	if proj.OutputFilename != "app.exe" {
+		t.Fatal("unexpected output", proj.OutputFilename)
+	}
+	proj.Info.ProductVersion = "1.2.0 beta"
+	flags := ldflags(proj, "abc1234", time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC))
+	if !strings.HasPrefix(flags, "-s -w -H windowsgui -X '"+versionPackage+".version=1.2.0 beta'") {
+		t.Fatal("unexpected version flag", flags)
+	}
+	if !strings.Contains(flags, "-X "+versionPackage+".commit=abc1234") || !strings.HasSuffix(flags, "-X "+versionPackage+".buildTime=2024-05-06T07:08:09Z") {
+		t.Fatal("unexpected flags", flags)
+	}
+	proj = proj.ForTarget("linux", "arm64")
+	proj.Info.ProductVersion = ""
+	if flags = ldflags(proj, "", time.Time{}); strings.Contains(flags, "windowsgui") || strings.Contains(flags, ".version=") || proj.OutputFilename != "app" {
+		t.Fatal("unexpected flags", flags, proj.OutputFilename)
+	}
+}
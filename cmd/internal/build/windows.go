@@ -8,9 +8,6 @@
 //
 //----------------------------------------
 
-//go:build windows
-// +build windows
-
 package build
 
 import (
@@ -18,19 +15,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/energye/energy/v2/cmd/internal/assets"
-	"github.com/energye/energy/v2/cmd/internal/command"
 	"github.com/energye/energy/v2/cmd/internal/project"
-	"github.com/energye/energy/v2/cmd/internal/term"
 	"github.com/energye/energy/v2/cmd/internal/tools"
 	"github.com/energye/energy/v2/pkgs/winicon"
-	toolsCommand "github.com/energye/golcl/tools/command"
 	"github.com/tc-hib/winres"
 	"github.com/tc-hib/winres/version"
 	"io/fs"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 )
 
@@ -39,62 +32,15 @@ const (
 	windowVersionInfo = "windows/version.info.json"
 )
 
-// 构建windows执行程序
-//
-//	exe生成图标
-//	编译go
-//	upx
-func build(c *command.Config, proj *project.Project) (err error) {
-	var (
-		iconPath string
-		syso     string
-	)
+// windowsResource
+//	生成 windows 执行程序的图标、manifest 和版本信息 syso 文件
+//	返回的 syso 文件在编译后删除
+func windowsResource(proj *project.Project) (syso string, err error) {
+	var iconPath string
 	if iconPath, err = generaICON(proj); err != nil {
-		return err
-	}
-	var delSyso = func() {
-		if syso != "" {
-			os.Remove(syso)
-			syso = ""
-		}
-	}
-	defer delSyso()
-	if syso, err = generaSYSO(iconPath, proj); err != nil {
-		return err
-	}
-	// go build
-	cmd := toolsCommand.NewCMD()
-	cmd.Dir = proj.ProjectPath
-	cmd.IsPrint = false
-	term.Section.Println("Building", proj.OutputFilename)
-	var args = []string{"build"}
-	if c.Build.Args != "" {
-		// go build args
-		gbargs := strings.Split(c.Build.Args, " ")
-		for i := range gbargs {
-			args = append(args, gbargs[i])
-		}
-	}
-	args = append(args, "-ldflags", "-s -w -H windowsgui")
-	args = append(args, "-o", proj.OutputFilename)
-	cmd.Command("go", args...)
-	delSyso()
-	// upx
-	if c.Build.Upx && tools.CommandExists("upx") {
-		term.Section.Println("Upx compression")
-		args = []string{"--best", "--no-color", "--no-progress", proj.OutputFilename}
-		if c.Build.UpxFlag != "" {
-			args = strings.Split(c.Build.UpxFlag, " ")
-			args = append(args, proj.OutputFilename)
-		}
-		cmd.Command("upx", args...)
-	}
-	cmd.Close()
-	if err == nil {
-		term.Section.Println("Build Successfully")
+		return "", err
 	}
-
-	return nil
+	return generaSYSO(iconPath, proj)
 }
 
 // 生成syso图标
@@ -157,7 +103,8 @@ func generaSYSO(iconPath string, proj *project.Project) (string, error) {
 		}
 		rs.SetVersionInfo(v)
 	}
-	targetFile := filepath.Join(proj.ProjectPath, fmt.Sprintf("%s-%s.syso", proj.Name, runtime.GOOS))
+	// 文件名带有目标系统架构, 只在编译到该目标时链接
+	targetFile := filepath.Join(proj.ProjectPath, fmt.Sprintf("%s_%s_%s.syso", proj.Name, proj.OS, proj.Arch))
 	fout, err := os.Create(targetFile)
 	if err != nil {
 		return "", err
@@ -168,9 +115,9 @@ func generaSYSO(iconPath string, proj *project.Project) (string, error) {
 		"arm64": winres.ArchARM64,
 		"386":   winres.ArchI386,
 	}
-	targetArch, supported := archs[runtime.GOARCH]
+	targetArch, supported := archs[string(proj.Arch)]
 	if !supported {
-		return targetFile, fmt.Errorf("arch '%s' not supported", runtime.GOARCH)
+		return targetFile, fmt.Errorf("arch '%s' not supported", proj.Arch)
 	}
 	err = rs.WriteObject(fout, targetArch)
 	if err != nil {
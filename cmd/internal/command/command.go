@@ -10,6 +10,8 @@
 
 package command
 
+import "strconv"
+
 type OS string
 type Arch string
 
@@ -17,6 +19,7 @@ type Config struct {
 	Index     int
 	Wd        string
 	EnergyCfg EnergyConfig
+	Args      []string
 	Install   Install `command:"install" description:"install energy development dependency environment"`
 	Package   Package `command:"package" description:"energy application production and installation package"`
 	Version   Version `command:"version" description:"list all release version numbers of energy"`
@@ -24,7 +27,12 @@ type Config struct {
 	Env       Env     `command:"env" description:"display ENERGY_ HOME framework environment directory"`
 	Init      Init    `command:"init" description:"initialize the energy application project"`
 	Build     Build   `command:"build" description:"building an energy project"`
-	Bindata   Bindata `command:"bindata" description:"if the go version is less than 1.16, you can use bindata to embed static resources"`
+	Bindata   Bindata `command:"bindata" description:"embed static resources as an emfs.IEmbedFS and fs.FS compatible Go file, also for go version less than 1.16"`
+	Dev       Dev     `command:"dev" description:"development mode, watch the project, rebuild and relaunch the energy application"`
+	Doctor    Doctor  `command:"doctor" description:"diagnose the energy development environment"`
+	List      List    `command:"list" description:"list the installed energy frameworks"`
+	Use       Use     `command:"use" description:"switch the energy framework used globally or by a project"`
+	Remove    Remove  `command:"remove" description:"remove an installed energy framework"`
 	Help      Help    `command:"help" description:"energy [cmd] help"`
 	V         string  `command:"v" description:"energy cli version"`
 }
@@ -34,6 +42,17 @@ type Command struct {
 	UsageLine, Short, Long string
 }
 
+// ExitError
+//	命令只设置退出码, 不输出错误信息, 由 energy 决定退出
+//	例: doctor --json 已输出报告
+type ExitError struct {
+	Code int
+}
+
+func (m *ExitError) Error() string {
+	return "exit status " + strconv.Itoa(m.Code)
+}
+
 type Install struct {
 	Path     string `short:"p" long:"path" description:"Installation directory Default current directory"`
 	Version  string `short:"v" long:"version" description:"Specifying a version number"`
@@ -42,6 +61,8 @@ type Install struct {
 	OS       OS     `long:"os" description:"Specify install OS: [windows, linux, darwin], default current system: os"`
 	Arch     Arch   `long:"arch" description:"Specify install ARCH: [386, amd64, arm64], Default current system: architecture"`
 	CEF      string `long:"cef" description:"Install system supports CEF version, provide 4 options, default empty. options: 109(support windows7), 106(support linux gtk2), 87(support flash)" default:""`
+	Offline  string `long:"offline" description:"Install from a pre-downloaded cache directory, contains SHA256SUMS, SHA256SUMS.sig, edv.json, extract.json and installation packages" default:""`
+	NoVerify bool   `long:"no-verify" description:"Skip the ed25519 signature verification of the SHA-256 checksum manifest, SHA-256 checksums are still verified, not recommended"`
 	IGolang  bool   // 是否安装Golang
 	ICEF     bool   // 是否安装CEF
 	INSIS    bool   // 是否安装nsis
@@ -54,6 +75,8 @@ type Package struct {
 	Path     string `short:"p" long:"path" description:"Project path, default current path. Can be configured in energy.json" default:""`
 	Clean    bool   `short:"c" long:"clean" description:"Clear configuration and regenerate the default configuration"`
 	Pkgbuild bool   `long:"pkg" description:"Using pkgbuild to create pkg development installation packages"`
+	OS       OS     `long:"os" description:"Specify package OS: [windows, linux, darwin], default current system: os. Framework from ENERGY_HOME_[OS]_[ARCH]"`
+	Arch     Arch   `long:"arch" description:"Specify package ARCH: [386, amd64, arm64], Default current system: architecture"`
 }
 
 type Env struct {
@@ -71,13 +94,14 @@ type Version struct {
 }
 
 type Init struct {
-	Name    string `short:"n" long:"name" description:"Initialized project name"`
-	ResLoad string `short:"r" long:"resload" description:"Resource loading method, 1: HTTP, 2: Local Load, default 1 HTTP"`
-	IGo     bool
-	INSIS   bool
-	IUPX    bool
-	IEnv    bool
-	INpm    bool
+	Name     string `short:"n" long:"name" description:"Initialized project name"`
+	ResLoad  string `short:"r" long:"resload" description:"Resource loading method, 1: HTTP, 2: Local Load, default 1 HTTP"`
+	Template string `short:"t" long:"template" description:"Project template: default, vanilla, vue, react, svelte, a local template directory or a git repository URL"`
+	IGo      bool
+	INSIS    bool
+	IUPX     bool
+	IEnv     bool
+	INpm     bool
 }
 
 type Build struct {
@@ -86,6 +110,35 @@ type Build struct {
 	UpxFlag string `long:"upxFlag" description:"Upx command line parameters" default:""`
 	Args    string `long:"args" description:"go build [args]" default:""`
 	Libemfs bool   `long:"libemfs" description:"Built in dynamic libraries to executable files, Copy liblcl to the built-in directory every compilation"`
+	Targets string `long:"targets" description:"Build matrix, OS/ARCH separated by commas: linux/amd64,windows/amd64. Output to build/[os]-[arch]" default:""`
+	Dev     bool   // energy dev 编译, 不执行前端编译
+}
+
+type Dev struct {
+	Path       string `short:"p" long:"path" description:"Project path, default current path. Can be configured in energy.json" default:""`
+	Args       string `long:"args" description:"go build [args]" default:""`
+	NoFrontend bool   `long:"nofrontend" description:"Do not start the frontend dev command configured in energy.json"`
+}
+
+type Doctor struct {
+	JSON bool `long:"json" description:"Output the report as JSON, for CI"`
+}
+
+type List struct {
+	All bool `long:"all" description:"Also list frameworks whose directory no longer exists"`
+}
+
+type Use struct {
+	Project bool   `long:"project" description:"Write the version to the cef field of the project energy.json, instead of setting ENERGY_HOME"`
+	Path    string `short:"p" long:"path" description:"Project path, default current path" default:""`
+	OS      OS     `long:"os" description:"Framework OS: [windows, linux, darwin], default current system: os"`
+	Arch    Arch   `long:"arch" description:"Framework ARCH: [386, amd64, arm64], default current system: architecture"`
+}
+
+type Remove struct {
+	OS    OS   `long:"os" description:"Framework OS: [windows, linux, darwin], default current system: os"`
+	Arch  Arch `long:"arch" description:"Framework ARCH: [386, amd64, arm64], default current system: architecture"`
+	Force bool `long:"force" description:"Remove the framework even if it is in use"`
 }
 
 type Bindata struct {
@@ -94,10 +147,12 @@ type Bindata struct {
 	Tags       string `long:"tags" description:"Optional set of build tags to include." default:""`
 	Prefix     string `long:"prefix" description:"Optional path prefix to strip off asset names." default:""`
 	Package    string `long:"pkg" description:"Package name to use in the generated code." default:"main"`
-	NoMemCopy  bool   `long:"nomemcopy" description:"Use a .rodata hack to get rid of unnecessary memcopies. Refer to the documentation to see what implications this carries."`
-	NoCompress bool   `long:"nocompress" description:"Assets will *not* be GZIP compressed when this flag is specified."`
-	NoMetadata bool   `long:"nometadata" description:"Assets will not preserve size, mode, and modtime info."`
-	FSSystem   bool   `long:"fs" description:"Whether generate instance http.FileSystem interface code."`
+	NoMemCopy  bool   `long:"nomemcopy" description:"Deprecated, assets are always stored as read-only string data."`
+	NoCompress bool   `long:"nocompress" description:"Assets will *not* be GZIP compressed when this flag is specified, same as --compress=none."`
+	Compress   string `long:"compress" description:"GZIP compression: [auto, all, none], auto compresses text, scripts, styles, json, svg and wasm by MIME type." default:"auto"`
+	Manifest   string `long:"manifest" description:"Optional JSON file to write the content hashes of all assets to, for ETags and immutable caching." default:""`
+	NoMetadata bool   `long:"nometadata" description:"Assets will not preserve mode and modtime info."`
+	FSSystem   bool   `long:"fs" description:"Deprecated, the generated FS always implements emfs.IEmbedFS and fs.FS."`
 	Mode       uint   `long:"mode" description:"Optional file mode override for all files."`
 	ModTime    int64  `long:"modtime" description:"Optional modification unix timestamp override for all files."`
 	Output     string `long:"o" description:"Optional name of the output file to be generated." default:"./bindata.go"`
@@ -109,6 +164,7 @@ type EnergyConfig struct {
 	Framework string         `json:"framework"`
 	Version   string         `json:"version"`
 	Source    DownloadSource `json:"source"`
+	PublicKey string         `json:"publicKey"` // SHA-256 清单签名 ed25519 公钥, base64
 }
 
 type DownloadSource struct {
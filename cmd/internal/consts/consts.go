@@ -66,6 +66,17 @@ const (
 	UPXHomeKey = "UPX_HOME"
 )
 
+const (
+	DownloadChecksumURL    = domain + "/api/cmd/checksum"     // 安装文件 SHA-256 清单, 格式同 sha256sum, 包含 edv.json extract.json
+	DownloadChecksumSigURL = domain + "/api/cmd/checksum/sig" // SHA-256 清单 ed25519 签名, base64
+)
+
+// ChecksumPublicKey
+//	energy 发布的 SHA-256 清单签名 ed25519 公钥, base64, 随源码发布, go install 编译的 CLI 同样校验签名
+//	~/.energy/energy.json publicKey 配置时优先使用
+//	为空时只校验 SHA-256 并警告清单未校验签名
+const ChecksumPublicKey = ""
+
 const (
 	CefKey              = "cef"
 	LiblclKey           = "liblcl"
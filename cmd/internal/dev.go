@@ -0,0 +1,45 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 开发模式 监听项目, 编译并重启 energy 应用
+
+package internal
+
+import (
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/dev"
+)
+
+var CmdDev = &command.Command{
+	UsageLine: "dev -p [path] --args --nofrontend",
+	Short:     "energy project development mode",
+	Long: `
+	Development mode, watch the energy project, rebuild and relaunch the application
+	-p Project path, default current path. Can be configured in energy.json
+	--args Set go build [args]
+	--nofrontend Do not start the frontend dev command
+	energy.json "dev" configuration:
+	  frontend: Frontend resource directory, on change notify the application to reload
+	  embed: Frontend resources are embedded into the executable, rebuild on change
+	  command: Frontend dev command started and stopped with the application, e.g. npm run dev
+	  commandDir: Frontend dev command directory, default frontend directory
+	  watch: Extra Go source directories
+	  exclude: Directories or files not watched
+	  delay: Wait time (ms) after change, default 300
+`,
+}
+
+func init() {
+	CmdDev.Run = runDev
+}
+
+func runDev(c *command.Config) error {
+	return dev.Dev(c)
+}
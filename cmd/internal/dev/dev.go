@@ -0,0 +1,200 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// energy dev 开发模式: 监听、编译、重启应用
+
+package dev
+
+import (
+	"errors"
+	"github.com/energye/energy/v2/cmd/internal/build"
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/consts"
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// 轮询文件改变的间隔
+const pollInterval = 500 * time.Millisecond
+
+// developer 开发模式运行状态
+type developer struct {
+	c        *command.Config
+	proj     *project.Project
+	watcher  *watcher
+	server   *reloadServer
+	app      *process
+	frontend *process
+}
+
+func Dev(c *command.Config) error {
+	proj, err := project.NewProject(c.Dev.Path)
+	if err != nil {
+		return err
+	}
+	server, err := newReloadServer()
+	if err != nil {
+		return err
+	}
+	defer server.close()
+	m := &developer{
+		c:       c,
+		proj:    proj,
+		watcher: newWatcher(proj.ProjectPath, proj.Dev.Frontend, proj.Dev.Watch, proj.Dev.Exclude),
+		server:  server,
+	}
+	defer m.stop()
+	if !c.Dev.NoFrontend && proj.Dev.Command != "" {
+		if err = m.startFrontend(); err != nil {
+			return err
+		}
+	}
+	if m.build() {
+		m.restart()
+	}
+	return m.watch()
+}
+
+// watch 监听文件改变, Ctrl+C 退出
+func (m *developer) watch() error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	delay := time.Duration(m.proj.Dev.Delay) * time.Millisecond
+	term.Section.Println("Watching", m.proj.ProjectPath, "press Ctrl+C to exit")
+	current := m.watcher.scan()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-signals:
+			term.Section.Println("Stopping")
+			return nil
+		case <-ticker.C:
+			next := m.watcher.scan()
+			changed := changes(current, next)
+			if len(changed) == 0 {
+				continue
+			}
+			// 等待连续的改变完成后一起处理
+			for {
+				time.Sleep(delay)
+				latest := m.watcher.scan()
+				more := changes(next, latest)
+				next = latest
+				if len(more) == 0 {
+					break
+				}
+				changed = append(changed, more...)
+			}
+			current = next
+			m.changed(changed)
+		}
+	}
+}
+
+// changed 处理改变的文件, Go 源码改变时重新编译和启动, 只有前端资源改变时通知应用重新加载
+func (m *developer) changed(paths []string) {
+	goChanged, frontendChanged := m.watcher.classify(paths)
+	for _, path := range paths {
+		if rel, err := filepath.Rel(m.proj.ProjectPath, path); err == nil {
+			path = rel
+		}
+		term.Logger.Info("Changed", term.Logger.Args("file", path))
+	}
+	if goChanged || (frontendChanged && m.proj.Dev.Embed) {
+		if m.build() {
+			m.restart()
+		}
+	} else if frontendChanged {
+		if !m.app.running() {
+			term.Logger.Warn("Application is not running, skip reload")
+		} else if m.server.reload() == 0 {
+			term.Logger.Warn("Application is not connected, restart it to enable reload")
+		} else {
+			term.Section.Println("Reloading")
+		}
+	}
+}
+
+// outputPath 编译输出的执行文件
+func (m *developer) outputPath() string {
+	if filepath.IsAbs(m.proj.OutputFilename) {
+		return m.proj.OutputFilename
+	}
+	return filepath.Join(m.proj.ProjectPath, m.proj.OutputFilename)
+}
+
+// build 使用 energy build 相同的方式编译, 前端由开发命令负责, 失败时保留正在运行的应用
+func (m *developer) build() bool {
+	m.c.Build = command.Build{Path: m.proj.ProjectPath, Args: m.c.Dev.Args, Dev: true}
+	if err := build.Build(m.c); err != nil {
+		term.Logger.Error("Build failed, keep the running application", term.Logger.Args("error", err.Error()))
+		return false
+	}
+	return true
+}
+
+// restart 结束正在运行的应用后重新启动
+func (m *developer) restart() {
+	if m.app.running() {
+		term.Section.Println("Restarting", m.proj.OutputFilename)
+		m.app.stop()
+	} else {
+		term.Section.Println("Starting", m.proj.OutputFilename)
+	}
+	cmd := exec.Command(m.outputPath())
+	cmd.Dir = m.proj.ProjectPath
+	// 应用使用项目配置的框架目录
+	cmd.Env = append(os.Environ(), devServerEnvKey+"="+m.server.addr(), consts.EnergyHomeKey+"="+m.proj.FrameworkPath)
+	app, err := startProcess("app", cmd)
+	if err != nil {
+		term.Logger.Error("Start application failed", term.Logger.Args("error", err.Error()))
+		return
+	}
+	m.app = app
+}
+
+// startFrontend 启动前端开发命令
+func (m *developer) startFrontend() error {
+	args := strings.Fields(m.proj.Dev.Command)
+	if len(args) == 0 {
+		return errors.New("frontend dev command is empty")
+	}
+	dir := m.proj.Dev.CommandDir
+	if dir == "" {
+		dir = m.proj.Dev.Frontend
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(m.proj.ProjectPath, dir)
+	}
+	term.Section.Println("Starting frontend", m.proj.Dev.Command)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	frontend, err := startProcess("frontend", cmd)
+	if err != nil {
+		return err
+	}
+	m.frontend = frontend
+	return nil
+}
+
+// stop 结束应用和前端开发命令
+func (m *developer) stop() {
+	m.app.stop()
+	m.frontend.stop()
+}
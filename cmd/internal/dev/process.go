@@ -0,0 +1,126 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package dev
+
+import (
+	"bytes"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// 多个进程同时输出时按行写入
+var outputLock sync.Mutex
+
+// prefixWriter 每行输出前添加前缀
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	buf    bytes.Buffer
+}
+
+func newPrefixWriter(prefix string, out io.Writer) *prefixWriter {
+	return &prefixWriter{prefix: prefix, out: out}
+}
+
+func (m *prefixWriter) Write(p []byte) (int, error) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	m.buf.Write(p)
+	for {
+		line, err := m.buf.ReadBytes('\n')
+		if err != nil {
+			// 不完整的行留到下次写入
+			m.buf.Reset()
+			m.buf.Write(line)
+			break
+		}
+		m.out.Write([]byte(m.prefix))
+		m.out.Write(line)
+	}
+	return len(p), nil
+}
+
+// Flush 输出剩余的不完整行
+func (m *prefixWriter) Flush() {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	if m.buf.Len() > 0 {
+		m.out.Write([]byte(m.prefix))
+		m.out.Write(m.buf.Bytes())
+		m.out.Write([]byte("\n"))
+		m.buf.Reset()
+	}
+}
+
+// process 开发模式启动的子进程, 应用或前端开发命令
+type process struct {
+	name   string
+	cmd    *exec.Cmd
+	stdout *prefixWriter
+	stderr *prefixWriter
+	done   chan struct{}
+}
+
+// startProcess 启动子进程, 输出添加 [name] 前缀
+func startProcess(name string, cmd *exec.Cmd) (*process, error) {
+	m := &process{name: name, cmd: cmd, done: make(chan struct{})}
+	m.stdout = newPrefixWriter("["+name+"] ", os.Stdout)
+	m.stderr = newPrefixWriter("["+name+"] ", os.Stderr)
+	cmd.Stdout, cmd.Stderr = m.stdout, m.stderr
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		err := cmd.Wait()
+		m.stdout.Flush()
+		m.stderr.Flush()
+		if err != nil {
+			term.Logger.Info("["+m.name+"] exited", term.Logger.Args("error", err.Error()))
+		} else {
+			term.Logger.Info("[" + m.name + "] exited")
+		}
+		close(m.done)
+	}()
+	return m, nil
+}
+
+// running 进程是否在运行
+func (m *process) running() bool {
+	if m == nil {
+		return false
+	}
+	select {
+	case <-m.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// stop 结束进程和进程启动的子进程
+func (m *process) stop() {
+	if !m.running() {
+		return
+	}
+	if err := killProcessTree(m.cmd.Process); err != nil {
+		m.cmd.Process.Kill()
+	}
+	select {
+	case <-m.done:
+	case <-time.After(5 * time.Second):
+		term.Logger.Error("[" + m.name + "] stop timeout")
+	}
+}
@@ -0,0 +1,29 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+//go:build !windows
+// +build !windows
+
+package dev
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup 子进程使用新的进程组, 结束时包括它启动的进程
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessTree(process *os.Process) error {
+	return syscall.Kill(-process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,110 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package dev
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// 通知应用重新加载的环境变量名, 应用启动后连接该地址, 和 cef 包保持一致
+const devServerEnvKey = "ENERGY_DEV_SERVER"
+
+// reloadServer
+//	本地重新加载服务, 应用连接后按行接收命令
+//	reload: 重新加载所有窗口
+type reloadServer struct {
+	listener net.Listener
+	lock     sync.Mutex
+	conns    map[net.Conn]bool
+}
+
+func newReloadServer() (*reloadServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	m := &reloadServer{listener: listener, conns: make(map[net.Conn]bool)}
+	go m.accept()
+	return m, nil
+}
+
+func (m *reloadServer) accept() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		m.lock.Lock()
+		m.conns[conn] = true
+		m.lock.Unlock()
+		// 连接关闭时移除
+		go func() {
+			var buf [64]byte
+			for {
+				if _, err := conn.Read(buf[:]); err != nil {
+					break
+				}
+			}
+			m.remove(conn)
+		}()
+	}
+}
+
+func (m *reloadServer) remove(conn net.Conn) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.conns[conn] {
+		delete(m.conns, conn)
+		conn.Close()
+	}
+}
+
+// addr 服务地址
+func (m *reloadServer) addr() string {
+	return m.listener.Addr().String()
+}
+
+// send 发送命令到所有连接的应用, 返回发送成功的数量
+func (m *reloadServer) send(command string) int {
+	m.lock.Lock()
+	var conns []net.Conn
+	for conn := range m.conns {
+		conns = append(conns, conn)
+	}
+	m.lock.Unlock()
+	var count int
+	for _, conn := range conns {
+		conn.SetWriteDeadline(time.Now().Add(time.Second))
+		if _, err := conn.Write([]byte(command + "\n")); err != nil {
+			m.remove(conn)
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// reload 通知应用重新加载所有窗口
+func (m *reloadServer) reload() int {
+	return m.send("reload")
+}
+
+func (m *reloadServer) close() {
+	m.listener.Close()
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for conn := range m.conns {
+		conn.Close()
+	}
+	m.conns = make(map[net.Conn]bool)
+}
@@ -0,0 +1,179 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package dev
+
+import (
+	"github.com/energye/energy/v2/cmd/internal/consts"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 文件改变类型
+type changeKind int8
+
+const (
+	changeNone     changeKind = iota
+	changeGo                  // Go 源码, 需要重新编译
+	changeFrontend            // 前端资源, 通知应用重新加载
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// 文件快照 绝对路径 => 状态
+type snapshot map[string]fileState
+
+// watcher 轮询方式监听项目目录, 不依赖系统文件通知
+type watcher struct {
+	root     string   // 项目目录
+	frontend string   // 前端资源目录, 空时不监听
+	extra    []string // 额外的 Go 源码目录
+	exclude  []string // 排除的目录或文件, 相对项目目录
+}
+
+func newWatcher(root, frontend string, extra, exclude []string) *watcher {
+	m := &watcher{root: filepath.Clean(root)}
+	if frontend != "" {
+		m.frontend = m.abs(frontend)
+		if m.frontend == m.root {
+			// 前端目录不能是项目目录
+			m.frontend = ""
+		}
+	}
+	for _, dir := range extra {
+		m.extra = append(m.extra, m.abs(dir))
+	}
+	for _, pattern := range exclude {
+		pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+		if pattern != "" {
+			m.exclude = append(m.exclude, pattern)
+		}
+	}
+	return m
+}
+
+func (m *watcher) abs(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(m.root, path)
+}
+
+// excluded 排除的目录或文件: 隐藏目录, node_modules 和配置的排除项
+func (m *watcher) excluded(path string, isDir bool) bool {
+	name := filepath.Base(path)
+	if isDir && path != m.root && (strings.HasPrefix(name, ".") || name == "node_modules") {
+		return true
+	}
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range m.exclude {
+		if rel == pattern || strings.HasPrefix(rel, pattern+"/") {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isGoFile 改变后需要重新编译的文件
+func isGoFile(path string) bool {
+	switch filepath.Base(path) {
+	case "go.mod", "go.sum", consts.EnergyProjectConfig:
+		return true
+	}
+	return filepath.Ext(path) == ".go"
+}
+
+// scan 返回当前所有监听文件的快照
+func (m *watcher) scan() snapshot {
+	result := make(snapshot)
+	walk := func(root string, frontend bool) {
+		filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				if m.excluded(path, true) || (!frontend && m.frontend != "" && path == m.frontend) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if m.excluded(path, false) || (!frontend && !isGoFile(path)) {
+				return nil
+			}
+			result[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+	}
+	walk(m.root, false)
+	for _, dir := range m.extra {
+		walk(dir, false)
+	}
+	if m.frontend != "" {
+		walk(m.frontend, true)
+	}
+	return result
+}
+
+// kind 返回文件的改变类型
+func (m *watcher) kind(path string) changeKind {
+	if m.frontend != "" && (path == m.frontend || strings.HasPrefix(path, m.frontend+string(filepath.Separator))) {
+		return changeFrontend
+	}
+	if isGoFile(path) {
+		return changeGo
+	}
+	return changeNone
+}
+
+// changes 返回两个快照之间新增、修改和删除的文件
+func changes(old, new snapshot) []string {
+	var result []string
+	for path, state := range new {
+		if oldState, ok := old[path]; !ok || !oldState.modTime.Equal(state.modTime) || oldState.size != state.size {
+			result = append(result, path)
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// classify 返回改变的文件中是否有 Go 源码和前端资源
+func (m *watcher) classify(paths []string) (goChanged, frontendChanged bool) {
+	for _, path := range paths {
+		switch m.kind(path) {
+		case changeGo:
+			goChanged = true
+		case changeFrontend:
+			frontendChanged = true
+		}
+	}
+	return
+}
@@ -0,0 +1,69 @@
+package dev
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherChanges(t *testing.T) {
+	root, err := ioutil.TempDir("", "energy-dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	write := func(name, data string) string {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	mainGo := write("main.go", "package main")
+	write("README.md", "readme")
+	write("tmp/gen.go", "package tmp")
+	write(".git/x.go", "package x")
+	index := write("resources/index.html", "<html>")
+	write("resources/node_modules/a.js", "a")
+	w := newWatcher(root, "resources", nil, []string{"tmp"})
+	current := w.scan()
+	if len(current) != 2 {
+		t.Fatal("unexpected snapshot", current)
+	}
+	if _, ok := current[mainGo]; !ok {
+		t.Fatal("main.go not watched")
+	}
+	write("resources/index.html", "<html></html>")
+	next := w.scan()
+	changed := changes(current, next)
+	if len(changed) != 1 || changed[0] != index {
+		t.Fatal("unexpected changes", changed)
+	}
+	if goChanged, frontendChanged := w.classify(changed); goChanged || !frontendChanged {
+		t.Fatal("unexpected classify", goChanged, frontendChanged)
+	}
+	os.Chtimes(mainGo, time.Now(), time.Now().Add(time.Second))
+	os.Remove(index)
+	changed = changes(next, w.scan())
+	if len(changed) != 2 {
+		t.Fatal("unexpected changes", changed)
+	}
+	if goChanged, frontendChanged := w.classify(changed); !goChanged || !frontendChanged {
+		t.Fatal("unexpected classify", goChanged, frontendChanged)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := newPrefixWriter("[app] ", &out)
+	w.Write([]byte("hello\nwor"))
+	w.Write([]byte("ld\npart"))
+	w.Flush()
+	if out.String() != "[app] hello\n[app] world\n[app] part\n" {
+		t.Fatal("unexpected output", out.String())
+	}
+}
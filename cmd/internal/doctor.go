@@ -0,0 +1,37 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 检查 energy 开发环境
+
+package internal
+
+import (
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/doctor"
+)
+
+var CmdDoctor = &command.Command{
+	UsageLine: "doctor --json",
+	Short:     "diagnose the energy development environment",
+	Long: `
+	Check the energy development environment and print a pass/fail report with fix hints
+	  Golang, GOARCH, ENERGY_HOME, CEF framework files, liblcl, library architecture and version,
+	  Linux shared libraries (GTK, NSS...), package tools: makensis, 7za, upx, appimagetool, pkgbuild
+	--json Output the report as JSON, for CI. Exit code is 1 when any check fails
+`,
+}
+
+func init() {
+	CmdDoctor.Run = runDoctor
+}
+
+func runDoctor(c *command.Config) error {
+	return doctor.Doctor(c)
+}
@@ -0,0 +1,207 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// energy doctor 开发环境检查
+
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/consts"
+	"github.com/energye/energy/v2/cmd/internal/install"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"github.com/pterm/pterm"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Status 检查结果状态
+type Status string
+
+const (
+	StatusPass Status = "pass" // 通过
+	StatusWarn Status = "warn" // 警告, 可能影响部分功能
+	StatusFail Status = "fail" // 失败, 无法编译、运行或打包
+)
+
+// Result 一项检查结果
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"` // 修复提示
+}
+
+// Report 检查报告
+type Report struct {
+	OS       string   `json:"os"`
+	Arch     string   `json:"arch"`
+	GOARCH   string   `json:"goarch"`
+	Home     string   `json:"energyHome"`
+	Results  []Result `json:"results"`
+	Passed   int      `json:"passed"`
+	Warnings int      `json:"warnings"`
+	Failures int      `json:"failures"`
+}
+
+func (m *Report) add(name string, status Status, message, hint string) {
+	m.Results = append(m.Results, Result{Name: name, Status: status, Message: message, Hint: hint})
+	switch status {
+	case StatusPass:
+		m.Passed++
+	case StatusWarn:
+		m.Warnings++
+	case StatusFail:
+		m.Failures++
+	}
+}
+
+func (m *Report) pass(name, message string) {
+	m.add(name, StatusPass, message, "")
+}
+
+func (m *Report) warn(name, message, hint string) {
+	m.add(name, StatusWarn, message, hint)
+}
+
+func (m *Report) fail(name, message, hint string) {
+	m.add(name, StatusFail, message, hint)
+}
+
+func Doctor(c *command.Config) error {
+	report := &Report{OS: runtime.GOOS, Arch: runtime.GOARCH, Home: os.Getenv(consts.EnergyHomeKey)}
+	checkGo(report)
+	checkInstallEnv(c, report)
+	checkFramework(report, report.Home, runtime.GOOS, report.GOARCH)
+	if consts.IsLinux {
+		checkLinuxLibraries(report, report.Home)
+	}
+	checkPackageTools(report, runtime.GOOS, tools.CommandExists)
+	if c.Doctor.JSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		// JSON 只输出报告, 失败时退出码 1, 不输出错误信息
+		if report.Failures > 0 {
+			return &command.ExitError{Code: 1}
+		}
+		return nil
+	} else {
+		printReport(report)
+	}
+	if report.Failures > 0 {
+		return fmt.Errorf("energy doctor: %d checks failed", report.Failures)
+	}
+	return nil
+}
+
+// checkGo 检查 Golang 和 GOARCH
+func checkGo(report *Report) {
+	report.GOARCH = os.Getenv("GOARCH")
+	if !tools.CommandExists("go") {
+		report.fail("Golang", "go command not found", "energy install, or install Golang and add it to PATH")
+		if report.GOARCH == "" {
+			report.GOARCH = runtime.GOARCH
+		}
+		return
+	}
+	out, err := exec.Command("go", "env", "GOVERSION", "GOOS", "GOARCH").Output()
+	if err != nil {
+		report.fail("Golang", "go env: "+err.Error(), "check the Golang installation")
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) < 3 {
+		report.fail("Golang", "go env: unexpected output", "check the Golang installation")
+		return
+	}
+	goVersion, goos, goarch := strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1]), strings.TrimSpace(lines[2])
+	report.GOARCH = goarch
+	if goos != runtime.GOOS {
+		report.warn("Golang", fmt.Sprintf("%s, GOOS=%s differs from the current system %s", goVersion, goos, runtime.GOOS), "unset GOOS, energy build --targets is used for cross compiling")
+	} else {
+		report.pass("Golang", fmt.Sprintf("%s %s/%s", goVersion, goos, goarch))
+	}
+}
+
+// checkInstallEnv 和 energy install 相同的开发环境检查
+func checkInstallEnv(c *command.Config, report *Report) {
+	for _, env := range install.CheckEnv(c) {
+		// Golang CEF 框架和打包工具单独检查
+		if env.Name == "Golang" || strings.HasPrefix(env.Name, "CEF Framework") || env.Name == "NSIS" || env.Name == "UPX" || env.Name == "7za" {
+			continue
+		}
+		if env.Installed {
+			report.pass(env.Name, "installed")
+		} else {
+			report.warn(env.Name, "not installed, supported platform: "+env.Desc, "energy install")
+		}
+	}
+}
+
+// packageTool 打包和编译使用的外部命令
+type packageTool struct {
+	name    string
+	missing string // 不存在时的影响
+	hint    string
+}
+
+// 各系统需要检查的外部命令, deb rpm tar.gz 为纯Go实现, 不需要 dpkg rpmbuild
+var packageTools = map[string][]packageTool{
+	"windows": {
+		{"makensis", "makensis not found, energy package can not create the NSIS installer", "energy install"},
+		{"7za", "7za not found, the NSIS installer can not compress the CEF framework", "energy install"},
+		{"upx", "upx not found, energy build --upx can not compress the executable", "energy install"},
+	},
+	"linux": {
+		{"appimagetool", "appimagetool not found, energy package only creates the AppImage AppDir", "https://github.com/AppImage/appimagetool/releases"},
+	},
+	"darwin": {
+		{"pkgbuild", "pkgbuild not found, energy package --pkg can not create pkg", "xcode-select --install"},
+	},
+}
+
+// checkPackageTools 检查安装包制作工具
+func checkPackageTools(report *Report, goos string, exists func(name string) bool) {
+	for _, tool := range packageTools[goos] {
+		if exists(tool.name) {
+			report.pass(tool.name, tool.name+" installed")
+		} else {
+			report.warn(tool.name, tool.missing, tool.hint)
+		}
+	}
+}
+
+func printReport(report *Report) {
+	term.Section.Println("energy doctor", report.OS+"/"+report.Arch, "GOARCH="+report.GOARCH)
+	for _, result := range report.Results {
+		var status string
+		switch result.Status {
+		case StatusPass:
+			status = pterm.Green("[PASS]")
+		case StatusWarn:
+			status = pterm.Yellow("[WARN]")
+		default:
+			status = pterm.Red("[FAIL]")
+		}
+		pterm.Println(status, result.Name+":", result.Message)
+		if result.Hint != "" {
+			pterm.Println("      ", pterm.Gray("hint: "+result.Hint))
+		}
+	}
+	term.Section.Println(fmt.Sprintf("%d passed, %d warnings, %d failed", report.Passed, report.Warnings, report.Failures))
+}
@@ -0,0 +1,80 @@
+package doctor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestScanBinary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "energy-doctor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	hash := "0123456789abcdef0123456789abcdef01234567"
+	file := filepath.Join(dir, "libcef.so")
+	data := []byte("\x00" + hash + "\x00" + hash + "ff\x00Mozilla/5.0 Chrome/109.0.5414.120 Safari\x00")
+	if err = ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	hashes, version, err := scanBinary(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 1 || !hashes[hash] || version != "109.0.5414.120" {
+		t.Fatal("unexpected result", hashes, version)
+	}
+}
+
+func TestLibraries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "energy-doctor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	libDir := filepath.Join(dir, "lib")
+	os.MkdirAll(filepath.Join(dir, "conf.d"), 0755)
+	os.MkdirAll(libDir, 0755)
+	ioutil.WriteFile(filepath.Join(dir, "ld.so.conf"), []byte("# comment\ninclude conf.d/*.conf\n/opt/lib\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "conf.d", "a.conf"), []byte(libDir+"\n"), 0644)
+	ioutil.WriteFile(filepath.Join(libDir, "libnss3.so"), nil, 0644)
+	dirs := parseLdSoConf(filepath.Join(dir, "ld.so.conf"), 0)
+	if len(dirs) != 2 || dirs[0] != libDir || dirs[1] != "/opt/lib" {
+		t.Fatal("unexpected dirs", dirs)
+	}
+	missing := missingLibraries([]string{"libnss3.so", "libgtk-3.so.0"}, dirs)
+	if len(missing) != 1 || missing[0] != "libgtk-3.so.0" {
+		t.Fatal("unexpected missing", missing)
+	}
+}
+
+func TestCheckFramework(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archs, err := binaryArch(exe); err != nil || !containsString(archs, runtime.GOARCH) {
+		t.Fatal("unexpected arch", archs, err)
+	}
+	report := &Report{}
+	checkFramework(report, "", runtime.GOOS, runtime.GOARCH)
+	if report.Failures != 1 || report.Results[0].Hint == "" {
+		t.Fatal("unexpected report", report.Results)
+	}
+}
+
+func TestCheckPackageTools(t *testing.T) {
+	report := &Report{}
+	checkPackageTools(report, "linux", func(name string) bool { return false })
+	if len(report.Results) != 1 || report.Results[0].Name != "appimagetool" || report.Warnings != 1 {
+		t.Fatal("unexpected report", report.Results)
+	}
+	report = &Report{}
+	checkPackageTools(report, "windows", func(name string) bool { return name != "upx" })
+	if report.Passed != 2 || report.Warnings != 1 || report.Results[2].Name != "upx" {
+		t.Fatal("unexpected report", report.Results)
+	}
+}
@@ -0,0 +1,325 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package doctor
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/consts"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CEF 框架文件, 参考 cef.CheckDLLs, cef.CheckResources 和 cef.CheckLocales
+//	required: 缺少时无法运行
+//	optional: 缺少时 GPU 等部分功能不可用, 旧版本 CEF 没有部分文件
+var (
+	cefRequiredFiles = map[string][]string{
+		"windows": {"libcef.dll", "chrome_elf.dll", "icudtl.dat", "snapshot_blob.bin", "v8_context_snapshot.bin", "resources.pak", "chrome_100_percent.pak", "chrome_200_percent.pak"},
+		"linux":   {"libcef.so", "icudtl.dat", "snapshot_blob.bin", "v8_context_snapshot.bin", "resources.pak", "chrome_100_percent.pak", "chrome_200_percent.pak"},
+		"darwin":  {"cef_sandbox.a", filepath.Join("Chromium Embedded Framework.framework", "Chromium Embedded Framework")},
+	}
+	cefOptionalFiles = map[string][]string{
+		"windows": {"d3dcompiler_47.dll", "libEGL.dll", "libGLESv2.dll", "vk_swiftshader.dll", "vk_swiftshader_icd.json", "vulkan-1.dll"},
+		"linux":   {"libEGL.so", "libGLESv2.so", "libvk_swiftshader.so", "vk_swiftshader_icd.json", "libvulkan.so.1"},
+	}
+)
+
+// CEF 动态库和 liblcl 文件名
+var (
+	cefLibraryNames = map[string]string{
+		"windows": "libcef.dll",
+		"linux":   "libcef.so",
+		"darwin":  filepath.Join("Chromium Embedded Framework.framework", "Chromium Embedded Framework"),
+	}
+	liblclNames = map[string]string{
+		"windows": "liblcl.dll",
+		"linux":   "liblcl.so",
+		"darwin":  "liblcl.dylib",
+	}
+)
+
+// checkFramework 检查 ENERGY_HOME 框架目录
+func checkFramework(report *Report, home, goos, goarch string) {
+	const name = "ENERGY_HOME"
+	if home == "" {
+		report.fail(name, consts.EnergyHomeKey+" environment variable is not set", "energy install, or energy setenv -p [framework dir]")
+		return
+	}
+	if info, err := os.Stat(home); err != nil || !info.IsDir() {
+		report.fail(name, "directory does not exist: "+home, "energy install, or energy setenv -p [framework dir]")
+		return
+	}
+	report.pass(name, home)
+	// 和 energy install 相同的检查
+	if !tools.CheckCEFDir() {
+		report.fail("CEF Framework", "CEF is not installed in "+home, "energy install")
+		return
+	}
+	// CEF 文件
+	if missing := missingFiles(home, cefRequiredFiles[goos]); len(missing) > 0 {
+		report.fail("CEF files", "missing: "+strings.Join(missing, ", "), "the CEF framework is incomplete, reinstall: energy install")
+	} else {
+		report.pass("CEF files", "complete")
+	}
+	if missing := missingFiles(home, cefOptionalFiles[goos]); len(missing) > 0 {
+		report.warn("CEF optional files", "missing: "+strings.Join(missing, ", "), "GPU acceleration may be unavailable, older CEF versions do not ship some of these files")
+	}
+	if goos != "darwin" {
+		checkLocales(report, filepath.Join(home, "locales"))
+	}
+	// liblcl
+	liblcl := filepath.Join(home, liblclNames[goos])
+	if !tools.IsExist(liblcl) {
+		report.fail("liblcl", "missing: "+liblcl, "reinstall: energy install")
+		return
+	}
+	report.pass("liblcl", liblcl)
+	// 架构
+	libcef := filepath.Join(home, cefLibraryNames[goos])
+	for _, lib := range []string{libcef, liblcl} {
+		if !tools.IsExist(lib) {
+			continue
+		}
+		archs, err := binaryArch(lib)
+		if err != nil {
+			report.warn("Architecture", filepath.Base(lib)+": "+err.Error(), "")
+			continue
+		}
+		if !containsString(archs, goarch) {
+			report.fail("Architecture", fmt.Sprintf("%s is %s, GOARCH is %s", filepath.Base(lib), strings.Join(archs, ","), goarch),
+				fmt.Sprintf("install the %s framework: energy install --arch %s, or set GOARCH=%s", goarch, goarch, archs[0]))
+		} else {
+			report.pass("Architecture", fmt.Sprintf("%s %s", filepath.Base(lib), strings.Join(archs, ",")))
+		}
+	}
+	// 版本
+	if tools.IsExist(libcef) {
+		checkVersion(report, libcef, liblcl)
+	}
+}
+
+// checkLocales 检查语言包, en-US.pak 必须存在
+func checkLocales(report *Report, dir string) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		report.fail("CEF locales", "missing locales directory: "+dir, "the CEF framework is incomplete, reinstall: energy install")
+		return
+	}
+	var count int
+	for _, file := range files {
+		if filepath.Ext(file.Name()) == ".pak" {
+			count++
+		}
+	}
+	if !tools.IsExist(filepath.Join(dir, "en-US.pak")) {
+		report.fail("CEF locales", "missing locales/en-US.pak", "the CEF framework is incomplete, reinstall: energy install")
+	} else {
+		report.pass("CEF locales", fmt.Sprintf("%d locales", count))
+	}
+}
+
+// checkVersion
+//	liblcl 编译时的 CEF API hash 需要和 libcef 相同, 不同时运行时崩溃
+//	liblcl 中没有找到 API hash 时不检查, 按字符串查找可能不准确, 不同时只警告
+func checkVersion(report *Report, libcef, liblcl string) {
+	const name = "CEF version"
+	lclHashes, _, err := scanBinary(liblcl)
+	if err != nil {
+		report.warn(name, err.Error(), "")
+		return
+	}
+	cefHashes, chromeVersion, err := scanBinary(libcef)
+	if err != nil {
+		report.warn(name, err.Error(), "")
+		return
+	}
+	message := "CEF " + chromeVersion
+	if chromeVersion == "" {
+		message = "unknown CEF version"
+	}
+	if len(lclHashes) == 0 {
+		report.pass(name, message)
+		return
+	}
+	for hash := range lclHashes {
+		if cefHashes[hash] {
+			report.pass(name, message+", liblcl API hash matches")
+			return
+		}
+	}
+	report.warn(name, message+", no liblcl API hash found in libcef, liblcl may be built for a different CEF version", "install matching versions: energy install -v [version], or energy install --cef [version]")
+}
+
+// 查找的 Chrome 版本前缀
+var chromeVersionPrefix = []byte("Chrome/")
+
+// scanBinary
+//	返回文件中独立的 40 位十六进制字符串(CEF API hash)和 Chrome 版本号
+//	按块读取, 支持较大的 libcef
+func scanBinary(path string) (hashes map[string]bool, chromeVersion string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+	hashes = make(map[string]bool)
+	var (
+		buf = make([]byte, 4<<20)
+		run []byte // 当前连续的十六进制字符
+		// 上一块结尾, 查找跨块的 Chrome 版本
+		tail []byte
+	)
+	for {
+		n, err := f.Read(buf)
+		chunk := buf[:n]
+		for _, b := range chunk {
+			if (b >= '0' && b <= '9') || (b >= 'a' && b <= 'f') {
+				if len(run) <= 40 {
+					run = append(run, b)
+				}
+				continue
+			}
+			if len(run) == 40 {
+				hashes[string(run)] = true
+			}
+			run = run[:0]
+		}
+		if chromeVersion == "" && n > 0 {
+			// 跨块部分和当前块
+			if head := chunk; len(tail) > 0 {
+				if len(head) > 64 {
+					head = head[:64]
+				}
+				chromeVersion = findChromeVersion(append(tail, head...))
+			}
+			if chromeVersion == "" {
+				chromeVersion = findChromeVersion(chunk)
+			}
+			if len(chunk) > 64 {
+				tail = append(tail[:0], chunk[len(chunk)-64:]...)
+			} else {
+				tail = append(tail[:0], chunk...)
+			}
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, "", err
+		}
+	}
+	if len(run) == 40 {
+		hashes[string(run)] = true
+	}
+	return hashes, chromeVersion, nil
+}
+
+// findChromeVersion 查找 Chrome/109.0.5414.120 格式的版本号
+func findChromeVersion(data []byte) string {
+	for {
+		index := bytes.Index(data, chromeVersionPrefix)
+		if index < 0 {
+			return ""
+		}
+		data = data[index+len(chromeVersionPrefix):]
+		var end, dots int
+		for end < len(data) && ((data[end] >= '0' && data[end] <= '9') || data[end] == '.') {
+			if data[end] == '.' {
+				dots++
+			}
+			end++
+		}
+		if dots == 3 && end < len(data) {
+			return string(data[:end])
+		}
+	}
+}
+
+// binaryArch 返回动态库的 CPU 架构, GOARCH 名称, macOS 通用二进制返回多个
+func binaryArch(path string) ([]string, error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		switch f.Machine {
+		case elf.EM_X86_64:
+			return []string{"amd64"}, nil
+		case elf.EM_386:
+			return []string{"386"}, nil
+		case elf.EM_AARCH64:
+			return []string{"arm64"}, nil
+		case elf.EM_ARM:
+			return []string{"arm"}, nil
+		}
+		return nil, errors.New("unknown ELF machine " + f.Machine.String())
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		switch f.FileHeader.Machine {
+		case pe.IMAGE_FILE_MACHINE_AMD64:
+			return []string{"amd64"}, nil
+		case pe.IMAGE_FILE_MACHINE_I386:
+			return []string{"386"}, nil
+		case pe.IMAGE_FILE_MACHINE_ARM64:
+			return []string{"arm64"}, nil
+		}
+		return nil, fmt.Errorf("unknown PE machine %#x", f.FileHeader.Machine)
+	}
+	if f, err := macho.OpenFat(path); err == nil {
+		defer f.Close()
+		var result []string
+		for _, arch := range f.Arches {
+			result = append(result, machoArch(arch.Cpu))
+		}
+		return result, nil
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		return []string{machoArch(f.Cpu)}, nil
+	}
+	return nil, errors.New("unknown binary format")
+}
+
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuAmd64:
+		return "amd64"
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.Cpu386:
+		return "386"
+	}
+	return cpu.String()
+}
+
+// missingFiles 返回目录中不存在的文件
+func missingFiles(dir string, files []string) (missing []string) {
+	for _, file := range files {
+		if !tools.IsExist(filepath.Join(dir, file)) {
+			missing = append(missing, file)
+		}
+	}
+	return
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package doctor
+
+import (
+	"bufio"
+	"debug/elf"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Linux 依赖库所在的 Debian/Ubuntu 安装包, 用于修复提示
+var libraryPackages = map[string]string{
+	"libgtk-3.so.0":          "libgtk-3-0",
+	"libgdk-3.so.0":          "libgtk-3-0",
+	"libgtk-x11-2.0.so.0":    "libgtk2.0-0",
+	"libgdk-x11-2.0.so.0":    "libgtk2.0-0",
+	"libglib-2.0.so.0":       "libglib2.0-0",
+	"libgobject-2.0.so.0":    "libglib2.0-0",
+	"libgio-2.0.so.0":        "libglib2.0-0",
+	"libpango-1.0.so.0":      "libpango-1.0-0",
+	"libcairo.so.2":          "libcairo2",
+	"libgdk_pixbuf-2.0.so.0": "libgdk-pixbuf2.0-0",
+	"libatk-1.0.so.0":        "libatk1.0-0",
+	"libatk-bridge-2.0.so.0": "libatk-bridge2.0-0",
+	"libatspi.so.0":          "libatspi2.0-0",
+	"libnss3.so":             "libnss3",
+	"libnssutil3.so":         "libnss3",
+	"libsmime3.so":           "libnss3",
+	"libnspr4.so":            "libnspr4",
+	"libasound.so.2":         "libasound2",
+	"libcups.so.2":           "libcups2",
+	"libdbus-1.so.3":         "libdbus-1-3",
+	"libdrm.so.2":            "libdrm2",
+	"libgbm.so.1":            "libgbm1",
+	"libexpat.so.1":          "libexpat1",
+	"libxkbcommon.so.0":      "libxkbcommon0",
+	"libX11.so.6":            "libx11-6",
+	"libxcb.so.1":            "libxcb1",
+	"libXext.so.6":           "libxext6",
+	"libXcomposite.so.1":     "libxcomposite1",
+	"libXdamage.so.1":        "libxdamage1",
+	"libXfixes.so.3":         "libxfixes3",
+	"libXrandr.so.2":         "libxrandr2",
+}
+
+// checkLinuxLibraries 检查 libcef 和 liblcl 依赖的系统库, 类似 ldd
+func checkLinuxLibraries(report *Report, home string) {
+	const name = "Shared libraries"
+	if home == "" {
+		return
+	}
+	var libs []string
+	for _, lib := range []string{"libcef.so", "liblcl.so"} {
+		if path := filepath.Join(home, lib); fileExists(path) {
+			libs = append(libs, path)
+		}
+	}
+	if len(libs) == 0 {
+		return
+	}
+	dirs := librarySearchDirs(home)
+	var missing []string
+	for _, lib := range libs {
+		needed, err := elfNeeded(lib)
+		if err != nil {
+			report.warn(name, filepath.Base(lib)+": "+err.Error(), "")
+			continue
+		}
+		for _, item := range missingLibraries(needed, dirs) {
+			if !containsString(missing, item) {
+				missing = append(missing, item)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		report.pass(name, "all dependencies of libcef and liblcl found")
+		return
+	}
+	sort.Strings(missing)
+	var packages []string
+	for _, lib := range missing {
+		if pkg, ok := libraryPackages[lib]; ok && !containsString(packages, pkg) {
+			packages = append(packages, pkg)
+		}
+	}
+	hint := "install the missing libraries with the system package manager"
+	if len(packages) > 0 {
+		hint = "sudo apt install " + strings.Join(packages, " ")
+	}
+	report.fail(name, "missing: "+strings.Join(missing, ", "), hint)
+}
+
+// elfNeeded 返回 ELF 文件依赖的动态库 DT_NEEDED
+func elfNeeded(path string) ([]string, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.ImportedLibraries()
+}
+
+// missingLibraries 返回在搜索目录中找不到的动态库
+func missingLibraries(needed, dirs []string) (missing []string) {
+	for _, lib := range needed {
+		if filepath.IsAbs(lib) {
+			if !fileExists(lib) {
+				missing = append(missing, lib)
+			}
+			continue
+		}
+		var found bool
+		for _, dir := range dirs {
+			if fileExists(filepath.Join(dir, lib)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, lib)
+		}
+	}
+	return
+}
+
+// librarySearchDirs 动态库搜索目录: 框架目录, LD_LIBRARY_PATH, /etc/ld.so.conf 和系统默认目录
+func librarySearchDirs(home string) []string {
+	var dirs []string
+	var add = func(dir string) {
+		if dir != "" && !containsString(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	add(home)
+	for _, dir := range filepath.SplitList(os.Getenv("LD_LIBRARY_PATH")) {
+		add(dir)
+	}
+	for _, dir := range parseLdSoConf("/etc/ld.so.conf", 0) {
+		add(dir)
+	}
+	for _, dir := range []string{"/lib", "/usr/lib", "/lib64", "/usr/lib64", "/usr/local/lib"} {
+		add(dir)
+	}
+	return dirs
+}
+
+// parseLdSoConf 解析 ld.so.conf, 支持 include
+func parseLdSoConf(path string, depth int) (dirs []string) {
+	if depth > 8 {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "include") {
+			pattern := strings.TrimSpace(strings.TrimPrefix(line, "include"))
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(filepath.Dir(path), pattern)
+			}
+			files, _ := filepath.Glob(pattern)
+			sort.Strings(files)
+			for _, file := range files {
+				dirs = append(dirs, parseLdSoConf(file, depth+1)...)
+			}
+			continue
+		}
+		dirs = append(dirs, line)
+	}
+	return
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,58 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 已安装框架的管理: list, use, remove
+
+package internal
+
+import (
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/framework"
+)
+
+var CmdList = &command.Command{
+	UsageLine: "list --all",
+	Short:     "List the installed energy frameworks",
+	Long: `
+	List the frameworks installed by energy install, by CEF, liblcl version and OS/ARCH
+	  * marks the framework in use: ENERGY_HOME for the current system, energy use for other systems
+	  Installing into the same directory replaces the previous record, use energy install -n [name] to keep several versions
+	--all Also list frameworks whose directory no longer exists
+`,
+}
+
+var CmdUse = &command.Command{
+	UsageLine: "use [version] --project -p [path] --os [os] --arch [arch]",
+	Short:     "Switch the energy framework",
+	Long: `
+	Switch to an installed framework, version: CEF version (109 or 109.1.18), liblcl or energy version
+	  The newest installed framework matching the version is used
+	  Global: set ENERGY_HOME for the current system, other OS/ARCH are used by cross compiling and packaging
+	--project Write the version to the cef field of the project energy.json, energy build, dev and package use it
+	-p Project path, default current path
+	--os --arch Framework OS/ARCH, default current system
+`,
+}
+
+var CmdRemove = &command.Command{
+	UsageLine: "remove [version] --os [os] --arch [arch] --force",
+	Short:     "Remove an installed energy framework",
+	Long: `
+	Delete the framework directory and its record, version must match a single framework
+	--os --arch Framework OS/ARCH, default current system
+	--force Remove the framework even if it is in use
+`,
+}
+
+func init() {
+	CmdList.Run = framework.List
+	CmdUse.Run = framework.Use
+	CmdRemove.Run = framework.Remove
+}
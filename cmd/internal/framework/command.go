@@ -0,0 +1,185 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/consts"
+	"github.com/energye/energy/v2/cmd/internal/env"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"github.com/pterm/pterm"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+)
+
+// List energy list 显示已安装的框架
+func List(c *command.Config) error {
+	store, err := OpenDefault()
+	if err != nil {
+		return err
+	}
+	list := store.List()
+	if len(list) == 0 {
+		term.Logger.Info("No installed frameworks, install: energy install")
+		return nil
+	}
+	home := os.Getenv(consts.EnergyHomeKey)
+	var tableData = pterm.TableData{{"", "CEF", "liblcl", "energy", "OS/ARCH", "Path"}}
+	for _, fw := range list {
+		if !c.List.All && !fw.Exists() {
+			continue
+		}
+		var flag string
+		if string(fw.OS) == runtime.GOOS && string(fw.Arch) == runtime.GOARCH {
+			// 当前系统架构以 ENERGY_HOME 为准
+			if samePath(home, fw.Path) {
+				flag = "*"
+			}
+		} else if store.IsCurrent(fw) {
+			flag = "*"
+		}
+		path := fw.Path
+		if !fw.Exists() {
+			path += " (missing)"
+		}
+		tableData = append(tableData, []string{flag, fw.CEF, fw.Liblcl, fw.Energy, fw.Target(), path})
+	}
+	if err = pterm.DefaultTable.WithHasHeader().WithHeaderRowSeparator("-").WithBoxed().WithData(tableData).Render(); err != nil {
+		return err
+	}
+	term.Section.Println("* in use, energy use [version] to switch")
+	return nil
+}
+
+// Use
+//	energy use [version] 切换使用的框架
+//	全局: 当前系统架构设置 ENERGY_HOME, 其它系统架构记录为交叉编译和打包的默认框架
+//	项目: 写入项目 energy.json cef 配置
+func Use(c *command.Config) error {
+	if len(c.Args) == 0 {
+		return errNoVersion
+	}
+	version := c.Args[0]
+	targetOS, targetArch := targetOf(c.Use.OS, c.Use.Arch)
+	store, err := OpenDefault()
+	if err != nil {
+		return err
+	}
+	fw, err := store.Find(version, targetOS, targetArch)
+	if err != nil {
+		return err
+	}
+	if c.Use.Project {
+		projectPath := c.Use.Path
+		if projectPath == "" {
+			projectPath = c.Wd
+		}
+		config := filepath.Join(projectPath, consts.EnergyProjectConfig)
+		if err = setProjectCEF(config, version); err != nil {
+			return err
+		}
+		term.Logger.Info("Project uses framework", term.Logger.Args("cef", version, "path", fw.Path, "config", config))
+		return nil
+	}
+	store.SetCurrent(fw)
+	if err = store.Save(); err != nil {
+		return err
+	}
+	if string(targetOS) == runtime.GOOS && string(targetArch) == runtime.GOARCH {
+		env.SetEnergyHomeEnv(fw.Path)
+	}
+	term.Logger.Info("Use framework", term.Logger.Args("cef", fw.CEF, "liblcl", fw.Liblcl, "target", fw.Target(), "path", fw.Path))
+	return nil
+}
+
+// Remove energy remove [version] 删除已安装的框架目录和记录
+func Remove(c *command.Config) error {
+	if len(c.Args) == 0 {
+		return errNoVersion
+	}
+	version := c.Args[0]
+	targetOS, targetArch := targetOf(c.Remove.OS, c.Remove.Arch)
+	store, err := OpenDefault()
+	if err != nil {
+		return err
+	}
+	list := store.Match(version, targetOS, targetArch)
+	if len(list) == 0 {
+		return fmt.Errorf("framework %s for %s is not installed", version, target(targetOS, targetArch))
+	} else if len(list) > 1 {
+		msg := fmt.Sprintf("%s matches %d frameworks, specify the full CEF version:", version, len(list))
+		for _, fw := range list {
+			msg += "\n\t" + fw.CEF + " " + fw.Path
+		}
+		return errors.New(msg)
+	}
+	fw := list[0]
+	if !c.Remove.Force && (samePath(os.Getenv(consts.EnergyHomeKey), fw.Path) || store.IsCurrent(fw)) {
+		return fmt.Errorf("framework %s is in use, switch with energy use [version] first, or remove with --force", fw.Path)
+	}
+	if fw.Exists() {
+		term.Logger.Info("Remove framework", term.Logger.Args("path", fw.Path))
+		if err = os.RemoveAll(fw.Path); err != nil {
+			return err
+		}
+	}
+	store.Remove(fw)
+	return store.Save()
+}
+
+func targetOf(targetOS command.OS, targetArch command.Arch) (command.OS, command.Arch) {
+	if targetOS == "" {
+		targetOS = command.OS(runtime.GOOS)
+	}
+	if targetArch == "" {
+		targetArch = command.Arch(runtime.GOARCH)
+	}
+	return targetOS, targetArch
+}
+
+var cefFieldRegexp = regexp.MustCompile(`"cef"\s*:\s*"[^"]*"`)
+
+// setProjectCEF
+//	设置项目 energy.json cef 配置, 直接修改文本保留原有格式和顺序
+func setProjectCEF(config, version string) error {
+	if !tools.IsExist(config) {
+		return errors.New("project configuration does not exist: " + config + ", energy init creates it")
+	}
+	data, err := ioutil.ReadFile(config)
+	if err != nil {
+		return err
+	}
+	value := `"cef": ` + strconv.Quote(version)
+	if cefFieldRegexp.Match(data) {
+		data = cefFieldRegexp.ReplaceAllLiteral(data, []byte(value))
+	} else {
+		index := regexp.MustCompile(`^\s*\{`).FindIndex(data)
+		if index == nil {
+			return errors.New("invalid project configuration: " + config)
+		}
+		rest := data[index[1]:]
+		if !regexp.MustCompile(`^\s*\}`).Match(rest) {
+			value += ","
+		}
+		var result []byte
+		result = append(result, data[:index[1]]...)
+		result = append(result, "\n\t"+value...)
+		data = append(result, rest...)
+	}
+	return ioutil.WriteFile(config, data, 0644)
+}
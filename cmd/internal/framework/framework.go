@@ -0,0 +1,304 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 已安装的框架管理, 按 CEF/liblcl 版本和系统架构区分
+//	记录在 ~/.energy/frameworks.json
+
+package framework
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"github.com/energye/golcl/energy/homedir"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const storeFileName = "frameworks.json"
+
+// Framework 已安装的框架
+type Framework struct {
+	Energy    string       `json:"energy"` // energy 版本
+	CEF       string       `json:"cef"`    // CEF 版本
+	Liblcl    string       `json:"liblcl"` // liblcl 版本
+	OS        command.OS   `json:"os"`
+	Arch      command.Arch `json:"arch"`
+	Path      string       `json:"path"` // 框架目录
+	Installed time.Time    `json:"installed"`
+}
+
+// Target 系统架构 windows/amd64
+func (m *Framework) Target() string {
+	return target(m.OS, m.Arch)
+}
+
+// Exists 框架目录是否存在
+func (m *Framework) Exists() bool {
+	return tools.IsExist(m.Path)
+}
+
+// Match
+//	版本是否匹配, 匹配 CEF 版本, CEF 主版本(109), liblcl 或 energy 版本
+func (m *Framework) Match(version string) bool {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return false
+	}
+	if m.CEF == version || strings.HasPrefix(m.CEF, version+".") {
+		return true
+	}
+	version = strings.TrimPrefix(version, "v")
+	return version == strings.TrimPrefix(m.Liblcl, "v") || version == strings.TrimPrefix(m.Energy, "v")
+}
+
+// Store 已安装框架的记录
+type Store struct {
+	file       string
+	Frameworks []*Framework      `json:"frameworks"`
+	Current    map[string]string `json:"current"` // 系统架构 => 全局使用的框架目录
+}
+
+// StoreFile 返回记录文件 ~/.energy/frameworks.json
+func StoreFile() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".energy", storeFileName), nil
+}
+
+// Open 读取记录文件, 文件不存在时返回空记录
+func Open(file string) (*Store, error) {
+	store := &Store{file: file}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			store.Current = make(map[string]string)
+			return store, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+	if store.Current == nil {
+		store.Current = make(map[string]string)
+	}
+	return store, nil
+}
+
+// OpenDefault 读取默认记录文件
+func OpenDefault() (*Store, error) {
+	file, err := StoreFile()
+	if err != nil {
+		return nil, err
+	}
+	return Open(file)
+}
+
+// Save 保存记录文件
+func (m *Store) Save() error {
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(m.file), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(m.file, data, 0644)
+}
+
+// Add 添加框架, 相同目录的记录被替换
+func (m *Store) Add(fw *Framework) {
+	for i, item := range m.Frameworks {
+		if samePath(item.Path, fw.Path) {
+			m.Frameworks[i] = fw
+			return
+		}
+	}
+	m.Frameworks = append(m.Frameworks, fw)
+}
+
+// Remove 删除框架记录, 不删除框架目录
+func (m *Store) Remove(fw *Framework) {
+	for i, item := range m.Frameworks {
+		if item == fw {
+			m.Frameworks = append(m.Frameworks[:i], m.Frameworks[i+1:]...)
+			break
+		}
+	}
+	for key, path := range m.Current {
+		if samePath(path, fw.Path) {
+			delete(m.Current, key)
+		}
+	}
+}
+
+// List 返回按系统架构和版本排序的框架, 新版本在前
+func (m *Store) List() []*Framework {
+	result := make([]*Framework, len(m.Frameworks))
+	copy(result, m.Frameworks)
+	sortFrameworks(result)
+	return result
+}
+
+// Match 返回匹配版本和系统架构的框架, 新版本在前
+func (m *Store) Match(version string, targetOS command.OS, targetArch command.Arch) []*Framework {
+	var result []*Framework
+	for _, fw := range m.Frameworks {
+		if fw.OS == targetOS && fw.Arch == targetArch && fw.Match(version) {
+			result = append(result, fw)
+		}
+	}
+	sortFrameworks(result)
+	return result
+}
+
+// Find 返回匹配版本和系统架构的最新框架, 框架目录必须存在
+func (m *Store) Find(version string, targetOS command.OS, targetArch command.Arch) (*Framework, error) {
+	for _, fw := range m.Match(version, targetOS, targetArch) {
+		if fw.Exists() {
+			return fw, nil
+		}
+	}
+	return nil, fmt.Errorf("framework %s for %s is not installed, install it: energy install --cef %s --os %s --arch %s, list installed: energy list",
+		version, target(targetOS, targetArch), version, targetOS, targetArch)
+}
+
+// SetCurrent 设置系统架构全局使用的框架
+func (m *Store) SetCurrent(fw *Framework) {
+	m.Current[fw.Target()] = fw.Path
+}
+
+// CurrentPath 返回系统架构全局使用的框架目录, 未设置或不存在时为空
+func (m *Store) CurrentPath(targetOS command.OS, targetArch command.Arch) string {
+	path := m.Current[target(targetOS, targetArch)]
+	if path != "" && !tools.IsExist(path) {
+		return ""
+	}
+	return path
+}
+
+// IsCurrent 是否为全局使用的框架
+func (m *Store) IsCurrent(fw *Framework) bool {
+	return samePath(m.Current[fw.Target()], fw.Path)
+}
+
+// Register
+//	安装成功后记录框架, current 为 true 时设置为全局使用的框架
+func Register(fw *Framework, current bool) error {
+	store, err := OpenDefault()
+	if err != nil {
+		return err
+	}
+	if path, err := filepath.Abs(fw.Path); err == nil {
+		fw.Path = path
+	}
+	if fw.Installed.IsZero() {
+		fw.Installed = time.Now()
+	}
+	store.Add(fw)
+	if current {
+		store.SetCurrent(fw)
+	}
+	return store.Save()
+}
+
+// Resolve
+//	返回版本和系统架构对应的框架目录
+//	项目 energy.json cef 配置和 energy use 使用
+func Resolve(version string, targetOS command.OS, targetArch command.Arch) (string, error) {
+	store, err := OpenDefault()
+	if err != nil {
+		return "", err
+	}
+	fw, err := store.Find(version, targetOS, targetArch)
+	if err != nil {
+		return "", err
+	}
+	return fw.Path, nil
+}
+
+// Current 返回系统架构全局使用的框架目录, 没有时为空
+func Current(targetOS command.OS, targetArch command.Arch) string {
+	store, err := OpenDefault()
+	if err != nil {
+		return ""
+	}
+	return store.CurrentPath(targetOS, targetArch)
+}
+
+func target(targetOS command.OS, targetArch command.Arch) string {
+	return string(targetOS) + "/" + string(targetArch)
+}
+
+func samePath(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+func sortFrameworks(list []*Framework) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Target() != b.Target() {
+			return a.Target() < b.Target()
+		}
+		if c := compareVersion(a.CEF, b.CEF); c != 0 {
+			return c > 0
+		}
+		return compareVersion(a.Liblcl, b.Liblcl) > 0
+	})
+}
+
+// compareVersion 按数字比较版本 109.1.18 > 109.1.2, 返回 1, 0, -1
+func compareVersion(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		if xerr == nil && yerr == nil {
+			if xn != yn {
+				if xn > yn {
+					return 1
+				}
+				return -1
+			}
+			continue
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// errNoVersion 未指定版本
+var errNoVersion = errors.New("missing version, energy list shows the installed frameworks")
@@ -0,0 +1,125 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package framework
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, storeFileName)
+	store, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var newFramework = func(name, cef, liblcl string) *Framework {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(path, os.ModePerm)
+		return &Framework{CEF: cef, Liblcl: liblcl, OS: "linux", Arch: "amd64", Path: path}
+	}
+	store.Add(newFramework("cef106", "106.1.1", "2.4.0"))
+	store.Add(newFramework("cef109", "109.1.2", "2.4.1"))
+	store.Add(newFramework("cef109b", "109.1.18", "2.4.1"))
+	store.Add(newFramework("cef119", "119.4.7", "2.4.2"))
+	// 相同目录替换
+	store.Add(newFramework("cef119", "119.4.7", "2.4.3"))
+	if len(store.Frameworks) != 4 {
+		t.Fatalf("frameworks: %d", len(store.Frameworks))
+	}
+	fw, err := store.Find("109", "linux", "amd64")
+	if err != nil || fw.CEF != "109.1.18" {
+		t.Fatalf("find 109: %v %v", fw, err)
+	}
+	if fw, err = store.Find("v2.4.3", "linux", "amd64"); err != nil || fw.CEF != "119.4.7" {
+		t.Fatalf("find liblcl: %v %v", fw, err)
+	}
+	if _, err = store.Find("10", "linux", "amd64"); err == nil {
+		t.Fatal("10 must not match 106 or 109")
+	}
+	if _, err = store.Find("109", "windows", "amd64"); err == nil {
+		t.Fatal("109 windows is not installed")
+	}
+	// 目录不存在时使用下一个匹配的框架
+	os.RemoveAll(filepath.Join(dir, "cef109b"))
+	if fw, err = store.Find("109", "linux", "amd64"); err != nil || fw.CEF != "109.1.2" {
+		t.Fatalf("find 109 after removing: %v %v", fw, err)
+	}
+
+	store.SetCurrent(fw)
+	if err = store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	store, err = Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.CurrentPath("linux", "amd64") != fw.Path {
+		t.Fatalf("current: %s", store.CurrentPath("linux", "amd64"))
+	}
+	list := store.Match("109", "linux", "amd64")
+	if len(list) != 2 {
+		t.Fatalf("match 109: %d", len(list))
+	}
+	store.Remove(list[1])
+	if len(store.Frameworks) != 3 || store.CurrentPath("linux", "amd64") != "" {
+		t.Fatalf("remove: %d %s", len(store.Frameworks), store.CurrentPath("linux", "amd64"))
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	var tests = []struct {
+		a, b   string
+		result int
+	}{
+		{"109.1.18", "109.1.2", 1},
+		{"106.1.1", "109.1.2", -1},
+		{"v2.4.1", "2.4.1", 0},
+		{"2.4", "2.4.1", -1},
+	}
+	for _, test := range tests {
+		if result := compareVersion(test.a, test.b); result != test.result {
+			t.Errorf("compareVersion(%s, %s) = %d, want %d", test.a, test.b, result, test.result)
+		}
+	}
+}
+
+func TestSetProjectCEF(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "energy.json")
+	var tests = []struct {
+		data string
+		want string
+	}{
+		{"{\n\t\"name\": \"demo\"\n}", "{\n\t\"cef\": \"109\",\n\t\"name\": \"demo\"\n}"},
+		{"{\n\t\"name\": \"demo\",\n\t\"cef\": \"106\"\n}", "{\n\t\"name\": \"demo\",\n\t\"cef\": \"109\"\n}"},
+		{"{}", "{\n\t\"cef\": \"109\"}"},
+	}
+	for _, test := range tests {
+		if err := ioutil.WriteFile(config, []byte(test.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := setProjectCEF(config, "109"); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadFile(config)
+		if string(data) != test.want {
+			t.Errorf("got %q, want %q", data, test.want)
+		}
+		var value map[string]interface{}
+		if err := json.Unmarshal(data, &value); err != nil || value["cef"] != "109" {
+			t.Errorf("invalid json %q: %v", data, err)
+		}
+	}
+}
@@ -23,11 +23,16 @@ import (
 )
 
 var CmdInit = &command.Command{
-	UsageLine: "init -n [name]",
+	UsageLine: "init -n [name] -t [template]",
 	Short:     "Initialized energy project",
 	Long: `
 	Initialize energy golang project
 	-n Initialized project name
+	-r Resource loading method, 1: HTTP, 2: Local Load
+	-t Project template
+	  default: main.go and resources/index.html
+	  vanilla, vue, react, svelte: Vite frontend in frontend/, built into resources/dist, typed IPC in frontend/src/energy.d.ts
+	  [dir] or [git url]: third-party template, template.json describes prompts, [file].tmpl is rendered with the prompt values
 `,
 }
 
@@ -64,5 +69,18 @@ func runInit(c *command.Config) error {
 		m.ResLoad = "2"
 	}
 
+	if m.Template == "" {
+		printer = term.DefaultInteractiveSelect.WithOnInterruptFunc(func() {
+			os.Exit(1)
+		}).WithOptions(initialize.BuiltinTemplates)
+		printer.CheckmarkANSI()
+		printer.DefaultText = "Project Template. Default default"
+		printer.Filter = false
+		if m.Template, err = printer.Show(); err != nil {
+			return err
+		}
+		pterm.Info.Printfln("Selected: %s", pterm.Green(m.Template))
+	}
+
 	return initialize.InitEnergyProject(c)
 }
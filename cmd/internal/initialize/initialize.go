@@ -30,20 +30,43 @@ import (
 )
 
 func InitEnergyProject(c *command.Config) error {
+	// 项目模板
+	tpl, err := loadTemplate(c.Init.Template)
+	if err != nil {
+		return err
+	}
+	if tpl != nil {
+		defer tpl.Close()
+		term.Logger.Info("Project template", term.Logger.Args("name", tpl.Name, "description", tpl.Description))
+		if err = tpl.ask(map[string]interface{}{"Name": c.Init.Name}, promptInput); err != nil {
+			return err
+		}
+	}
 	// 检查环境
 	checkEnv(&c.Init)
 	// 生成项目
-	if err := generaProject(c); err != nil {
+	if err = generaProject(c, tpl); err != nil {
 		return err
 	}
 	pterm.Println()
 	term.Section.Println("Successfully initialized the energy application project:", c.Init.Name)
 	term.Logger.Info("Website", term.Logger.Args("Github", "https://github.com/energye/energy", "ENERGY", "https://energy.yanghy.cn"))
 	term.Section.Println("Run Application")
+	if tpl != nil && len(tpl.Next) > 0 {
+		term.Section.Println("Build Frontend")
+		tableData := pterm.TableData{{"command"}}
+		for _, next := range tpl.Next {
+			tableData = append(tableData, []string{next})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithHeaderRowSeparator("-").WithBoxed().WithData(tableData).Render()
+		if err != nil {
+			return err
+		}
+	}
 	tableData := pterm.TableData{
 		{"command"}, {"go run main.go"},
 	}
-	err := pterm.DefaultTable.WithHasHeader().WithHeaderRowSeparator("-").WithBoxed().WithData(tableData).Render()
+	err = pterm.DefaultTable.WithHasHeader().WithHeaderRowSeparator("-").WithBoxed().WithData(tableData).Render()
 	if err != nil {
 		return err
 	}
@@ -68,7 +91,7 @@ func InitEnergyProject(c *command.Config) error {
 	return nil
 }
 
-func generaProject(c *command.Config) error {
+func generaProject(c *command.Config, tpl *Template) error {
 	pterm.Println()
 	projectPath := filepath.Join(c.Wd, c.Init.Name)
 	term.Logger.Info("Create Project", term.Logger.Args("Name", c.Init.Name))
@@ -188,6 +211,26 @@ func generaProject(c *command.Config) error {
 		return err
 	}
 
+	// 项目模板, 覆盖默认生成的文件
+	if tpl != nil {
+		term.Logger.Info("Generate template", term.Logger.Args("name", tpl.Name))
+		data = make(map[string]interface{})
+		data["Name"] = c.Init.Name
+		data["ProjectPath"] = filepath.ToSlash(projectPath)
+		data["FrameworkPath"] = filepath.ToSlash(os.Getenv(consts.EnergyHomeKey))
+		data["GoVersion"] = "1.18"
+		data["EnergyVersion"] = latest
+		data["ResLoad"] = c.Init.ResLoad
+		data["HTTP"] = c.Init.ResLoad != "2"
+		data["ResourcesDir"] = "resources/dist"
+		if err := tpl.generate(projectPath, data); err != nil {
+			return err
+		}
+		if err := tpl.energyConfig(filepath.Join(projectPath, consts.EnergyProjectConfig)); err != nil {
+			return err
+		}
+	}
+
 	// cmd
 	term.Section.Println("Config Go Environment")
 	cmd := toolsCommand.NewCMD()
@@ -275,3 +318,29 @@ func checkEnv(init *command.Init) {
 		init.INpm = true
 	}
 }
+
+// promptInput 提示输入模板参数
+func promptInput(prompt Prompt, def string) string {
+	message := prompt.Message
+	if message == "" {
+		message = prompt.Name
+	}
+	if len(prompt.Options) > 0 {
+		printer := term.DefaultInteractiveSelect.WithOnInterruptFunc(func() {
+			os.Exit(1)
+		}).WithOptions(prompt.Options)
+		printer.CheckmarkANSI()
+		printer.DefaultText = message
+		printer.DefaultOption = def
+		printer.Filter = false
+		value, err := printer.Show()
+		if err != nil {
+			return ""
+		}
+		return value
+	}
+	if def != "" {
+		message += " (" + def + ")"
+	}
+	return term.TextInputWith(message, ": ")
+}
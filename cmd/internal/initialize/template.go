@@ -0,0 +1,308 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package initialize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/assets"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"go/format"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	templateManifest = "template.json" // 模板清单文件
+	templateSuffix   = ".tmpl"         // 使用 tools.RenderTemplate 渲染的文件, 生成时去掉后缀
+	defaultTemplate  = "default"       // 默认模板, main.go 和 resources/index.html
+	commonTemplate   = "common"        // 内置前端模板共用的文件
+)
+
+// BuiltinTemplates 内置项目模板
+var BuiltinTemplates = []string{defaultTemplate, "vanilla", "vue", "react", "svelte"}
+
+// Prompt 模板参数, 初始化项目时提示输入
+type Prompt struct {
+	Name    string   `json:"name"`    // 参数名, 模板中使用 {{.[name]}}
+	Message string   `json:"message"` // 提示信息
+	Default string   `json:"default"` // 默认值, 支持模板 {{.Name}}
+	Options []string `json:"options"` // 可选值, 空时输入
+}
+
+// Template 项目模板
+//	模板目录内的文件按目录结构生成到项目目录, 覆盖默认生成的文件
+//	[file].tmpl 使用 tools.RenderTemplate 渲染, gitignore 生成为 .gitignore
+type Template struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Prompts     []Prompt        `json:"prompts"`
+	Build       json.RawMessage `json:"build"` // 写入 energy.json build 配置
+	Dev         json.RawMessage `json:"dev"`   // 写入 energy.json dev 配置
+	Next        []string        `json:"next"`  // 生成后提示执行的命令
+	layers      []fs.FS         // 模板文件, 按顺序生成
+	values      map[string]string
+	tempDir     string // git 仓库克隆目录
+}
+
+// loadTemplate
+//	加载项目模板, 内置模板名, 本地目录或 git 仓库地址
+//	default 模板返回 nil
+func loadTemplate(source string) (*Template, error) {
+	source = strings.TrimSpace(source)
+	if source == "" || source == defaultTemplate {
+		return nil, nil
+	}
+	for _, name := range BuiltinTemplates {
+		if source == name {
+			return builtinTemplate(name)
+		}
+	}
+	var (
+		dir     = source
+		tempDir string
+	)
+	if isGitURL(source) {
+		if !tools.CommandExists("git") {
+			return nil, errors.New("git command not found, required by the template " + source)
+		}
+		var err error
+		if tempDir, err = ioutil.TempDir("", "energy-template"); err != nil {
+			return nil, err
+		}
+		term.Logger.Info("Clone template", term.Logger.Args("url", source))
+		cmd := exec.Command("git", "clone", "--depth", "1", source, tempDir)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err = cmd.Run(); err != nil {
+			os.RemoveAll(tempDir)
+			return nil, fmt.Errorf("clone template %s: %w", source, err)
+		}
+		dir = tempDir
+	} else if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("template %s does not exist, built-in templates: %s, or a local directory, or a git repository URL", source, strings.Join(BuiltinTemplates, ", "))
+	}
+	fsys := os.DirFS(dir)
+	tpl, err := readManifest(fsys)
+	if err != nil {
+		if tempDir != "" {
+			os.RemoveAll(tempDir)
+		}
+		return nil, err
+	}
+	if tpl.Name == "" {
+		tpl.Name = strings.TrimSuffix(path.Base(filepath.ToSlash(source)), ".git")
+	}
+	tpl.layers = []fs.FS{fsys}
+	tpl.tempDir = tempDir
+	return tpl, nil
+}
+
+// builtinTemplate 内置模板, 先生成共用文件
+func builtinTemplate(name string) (*Template, error) {
+	templates, err := assets.Templates()
+	if err != nil {
+		return nil, err
+	}
+	common, err := fs.Sub(templates, commonTemplate)
+	if err != nil {
+		return nil, err
+	}
+	fsys, err := fs.Sub(templates, name)
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := readManifest(fsys)
+	if err != nil {
+		return nil, err
+	}
+	tpl.layers = []fs.FS{common, fsys}
+	return tpl, nil
+}
+
+// readManifest 读取模板清单, 第三方模板可以没有清单
+func readManifest(fsys fs.FS) (*Template, error) {
+	tpl := &Template{}
+	data, err := fs.ReadFile(fsys, templateManifest)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tpl, nil
+	} else if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if err = json.Unmarshal(data, tpl); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", templateManifest, err)
+	}
+	for _, prompt := range tpl.Prompts {
+		if prompt.Name == "" {
+			return nil, fmt.Errorf("invalid %s: prompt name is empty", templateManifest)
+		}
+	}
+	return tpl, nil
+}
+
+// isGitURL 是否为 git 仓库地址
+func isGitURL(source string) bool {
+	for _, prefix := range []string{"https://", "http://", "git@", "ssh://", "git://"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(source, ".git") && !tools.IsExist(source)
+}
+
+// Close 删除克隆的模板目录
+func (m *Template) Close() {
+	if m.tempDir != "" {
+		os.RemoveAll(m.tempDir)
+		m.tempDir = ""
+	}
+}
+
+// ask
+//	提示输入模板参数, input 返回输入值, 空时使用默认值
+func (m *Template) ask(data map[string]interface{}, input func(prompt Prompt, def string) string) error {
+	m.values = make(map[string]string)
+	for _, prompt := range m.Prompts {
+		def, err := tools.RenderTemplate(prompt.Default, data)
+		if err != nil {
+			return fmt.Errorf("template prompt %s: %w", prompt.Name, err)
+		}
+		value := strings.TrimSpace(input(prompt, string(def)))
+		if value == "" {
+			value = string(def)
+		}
+		if len(prompt.Options) > 0 && !containsString(prompt.Options, value) {
+			return fmt.Errorf("template prompt %s: %q is not one of %s", prompt.Name, value, strings.Join(prompt.Options, ", "))
+		}
+		m.values[prompt.Name] = value
+	}
+	return nil
+}
+
+// generate 生成模板文件到项目目录
+func (m *Template) generate(projectPath string, data map[string]interface{}) error {
+	for key, value := range m.values {
+		data[key] = value
+	}
+	for i, layer := range m.layers {
+		err := fs.WalkDir(layer, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			// 最后一层是模板自身, 清单不生成
+			if name == templateManifest && i == len(m.layers)-1 {
+				return nil
+			}
+			content, err := fs.ReadFile(layer, name)
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(name, templateSuffix) {
+				name = strings.TrimSuffix(name, templateSuffix)
+				if content, err = tools.RenderTemplate(string(content), data); err != nil {
+					return fmt.Errorf("render %s: %w", name, err)
+				}
+				if path.Ext(name) == ".go" {
+					if formatted, err := format.Source(content); err == nil {
+						content = formatted
+					}
+				}
+			}
+			if path.Base(name) == "gitignore" {
+				name = path.Join(path.Dir(name), ".gitignore")
+			}
+			target := filepath.Join(projectPath, filepath.FromSlash(name))
+			if err = os.MkdirAll(filepath.Dir(target), fs.ModePerm); err != nil {
+				return err
+			}
+			var perm fs.FileMode = 0666
+			if path.Ext(name) == ".sh" {
+				perm = 0755
+			}
+			return ioutil.WriteFile(target, content, perm)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// energyConfig
+//	把模板的 build 和 dev 配置写入项目 energy.json
+//	插入到最后的 } 之前, 保留原有格式, 已配置时不覆盖
+func (m *Template) energyConfig(config string) error {
+	if len(m.Build) == 0 && len(m.Dev) == 0 {
+		return nil
+	}
+	data, err := ioutil.ReadFile(config)
+	if err != nil {
+		return err
+	}
+	var exists map[string]json.RawMessage
+	if err = json.Unmarshal(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), &exists); err != nil {
+		return fmt.Errorf("invalid %s: %w", config, err)
+	}
+	var fields bytes.Buffer
+	for _, field := range []struct {
+		name  string
+		value json.RawMessage
+	}{{"build", m.Build}, {"dev", m.Dev}} {
+		if len(field.value) == 0 {
+			continue
+		}
+		if _, ok := exists[field.name]; ok {
+			continue
+		}
+		var value bytes.Buffer
+		if err = json.Indent(&value, field.value, "  ", "  "); err != nil {
+			return fmt.Errorf("template %s: %w", field.name, err)
+		}
+		fmt.Fprintf(&fields, ",\n  %q: %s", field.name, value.String())
+	}
+	if fields.Len() == 0 {
+		return nil
+	}
+	end := bytes.LastIndexByte(data, '}')
+	if end < 0 {
+		return errors.New("invalid " + config)
+	}
+	content := bytes.TrimRight(data[:end], " \t\r\n")
+	var result []byte
+	result = append(result, content...)
+	result = append(result, fields.Bytes()...)
+	result = append(result, '\n')
+	result = append(result, data[end:]...)
+	return ioutil.WriteFile(config, result, 0666)
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package initialize
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func templateData(dir string, http bool) map[string]interface{} {
+	return map[string]interface{}{
+		"Name":          "demo",
+		"ProjectPath":   filepath.ToSlash(dir),
+		"FrameworkPath": "",
+		"GoVersion":     "1.18",
+		"EnergyVersion": "latest",
+		"HTTP":          http,
+		"ResourcesDir":  "resources/dist",
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	for _, name := range BuiltinTemplates {
+		tpl, err := loadTemplate(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if name == defaultTemplate {
+			if tpl != nil {
+				t.Fatal("default template must be nil")
+			}
+			continue
+		}
+		for _, http := range []bool{true, false} {
+			dir := t.TempDir()
+			if err = tpl.generate(dir, templateData(dir, http)); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			for _, file := range []string{"main.go", ".gitignore", "resources/dist/index.html", "frontend/package.json", "frontend/index.html", "frontend/src/energy.d.ts"} {
+				if _, err = os.Stat(filepath.Join(dir, file)); err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+			}
+			if _, err = os.Stat(filepath.Join(dir, templateManifest)); err == nil {
+				t.Fatalf("%s: %s must not be generated", name, templateManifest)
+			}
+			src, _ := ioutil.ReadFile(filepath.Join(dir, "main.go"))
+			if _, err = parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+				t.Fatalf("%s main.go: %v", name, err)
+			}
+			if http != strings.Contains(string(src), "assetserve") {
+				t.Fatalf("%s main.go http=%v:\n%s", name, http, src)
+			}
+			var pkg map[string]interface{}
+			data, _ := ioutil.ReadFile(filepath.Join(dir, "frontend", "package.json"))
+			if err = json.Unmarshal(data, &pkg); err != nil || pkg["name"] != "demo-frontend" {
+				t.Fatalf("%s package.json: %v %v", name, pkg["name"], err)
+			}
+		}
+	}
+}
+
+func TestLocalTemplate(t *testing.T) {
+	src := t.TempDir()
+	manifest := `{
+  "name": "custom",
+  "prompts": [
+    {"name": "Title", "message": "Window title", "default": "{{.Name}} app"},
+    {"name": "UI", "options": ["none", "element"], "default": "none"}
+  ],
+  "dev": {"frontend": "web"}
+}`
+	ioutil.WriteFile(filepath.Join(src, templateManifest), []byte(manifest), 0644)
+	os.MkdirAll(filepath.Join(src, "web"), 0755)
+	ioutil.WriteFile(filepath.Join(src, "web", "index.html.tmpl"), []byte("<title>{{.Title}}</title>{{.UI}}"), 0644)
+	ioutil.WriteFile(filepath.Join(src, "web", "app.js"), []byte("const a = `{{.Title}}`"), 0644)
+
+	tpl, err := loadTemplate(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tpl.Close()
+	if tpl.Name != "custom" || len(tpl.Prompts) != 2 {
+		t.Fatalf("manifest: %+v", tpl)
+	}
+	answers := map[string]string{"Title": "", "UI": "element"}
+	err = tpl.ask(map[string]interface{}{"Name": "demo"}, func(prompt Prompt, def string) string {
+		return answers[prompt.Name]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err = tpl.generate(dir, templateData(dir, true)); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadFile(filepath.Join(dir, "web", "index.html"))
+	if string(data) != "<title>demo app</title>element" {
+		t.Fatalf("index.html: %s", data)
+	}
+	// 没有 .tmpl 后缀的文件不渲染
+	data, _ = ioutil.ReadFile(filepath.Join(dir, "web", "app.js"))
+	if string(data) != "const a = `{{.Title}}`" {
+		t.Fatalf("app.js: %s", data)
+	}
+
+	// 不在可选值中
+	err = tpl.ask(map[string]interface{}{"Name": "demo"}, func(prompt Prompt, def string) string {
+		return "vue"
+	})
+	if err == nil {
+		t.Fatal("expected option error")
+	}
+
+	if _, err = loadTemplate(filepath.Join(src, "not-exist")); err == nil {
+		t.Fatal("expected error for a missing template")
+	}
+}
+
+func TestTemplateEnergyConfig(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "energy.json")
+	ioutil.WriteFile(config, []byte("{\n  \"name\": \"demo\",\n  \"dev\": {\"delay\": 500}\n}\n"), 0644)
+	tpl := &Template{
+		Build: json.RawMessage(`{"frontend": {"command": "npm run build"}}`),
+		Dev:   json.RawMessage(`{"frontend": "resources/dist"}`),
+	}
+	if err := tpl.energyConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadFile(config)
+	var value struct {
+		Name  string `json:"name"`
+		Build struct {
+			Frontend struct {
+				Command string `json:"command"`
+			} `json:"frontend"`
+		} `json:"build"`
+		Dev struct {
+			Delay    int    `json:"delay"`
+			Frontend string `json:"frontend"`
+		} `json:"dev"`
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("%v\n%s", err, data)
+	}
+	// dev 已配置时不覆盖
+	if value.Name != "demo" || value.Build.Frontend.Command != "npm run build" || value.Dev.Delay != 500 || value.Dev.Frontend != "" {
+		t.Fatalf("energy.json:\n%s", data)
+	}
+}
@@ -16,7 +16,7 @@ import (
 )
 
 var CmdInstall = &command.Command{
-	UsageLine: "install -p [path] -v [version] -n [name] -d [download] --os --arch --cef",
+	UsageLine: "install -p [path] -v [version] -n [name] -d [download] --os --arch --cef --offline [dir]",
 	Short:     "Automatic installation and configuration of the energy framework complete development environment",
 	Long: `
 	-p Installation directory Default current directory
@@ -31,9 +31,16 @@ var CmdInstall = &command.Command{
 		    106 : CEF 106.1.1 is the last default support for GTK2 in Linux.
 		    87  : CEF 87.1.14 is the last one to support Flash.
 		    49  : CEF 49.0.2623 is the last on to support Windows XP.
+	--offline Install from a pre-downloaded cache directory, for air-gapped machines
+		The directory [path]/EnergyFrameworkDownloadCache of an online installation can be used directly
+		Contains: SHA256SUMS, SHA256SUMS.sig(optional), edv.json, extract.json, installation packages
+	--no-verify Skip the ed25519 signature verification of the SHA-256 checksum manifest, not recommended
+		SHA-256 checksums of installation packages, edv.json and extract.json are still verified
 
 Auto installation and configuration of the energy framework complete development environment.
 Installation package is downloaded over the network during the installation process.
+All installation packages, edv.json and extract.json are verified by the SHA-256 checksum manifest before use,
+the manifest is verified by its ed25519 signature.
 `,
 }
 
@@ -0,0 +1,273 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package install
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/consts"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 下载缓存目录内的安装配置文件, 用于离线安装
+const (
+	checksumFileName    = "SHA256SUMS"     // SHA-256 清单, 格式同 sha256sum: [hex]  [file name]
+	checksumSigFileName = "SHA256SUMS.sig" // 清单 ed25519 签名, base64
+	versionFileName     = "edv.json"       // 版本列表
+	extractFileName     = "extract.json"   // 提取文件配置
+)
+
+// checksumManifest SHA-256 清单, 文件名 => sha256
+type checksumManifest map[string]string
+
+// 当前安装使用的清单, 首次下载时加载
+var checksums checksumManifest
+
+// parseChecksums
+//	解析 sha256sum 格式清单
+//	[hex]  [file name] 或 [hex] *[file name], # 开头为注释
+func parseChecksums(data []byte) (checksumManifest, error) {
+	result := make(checksumManifest)
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid checksum manifest line %d: %s", n, line)
+		}
+		sum := strings.ToLower(fields[0])
+		if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("invalid checksum manifest line %d: %s", n, line)
+		}
+		result[strings.TrimPrefix(fields[1], "*")] = sum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, errors.New("checksum manifest is empty")
+	}
+	return result, nil
+}
+
+// verifySignature
+//	使用 ed25519 公钥校验清单签名
+//	publicKey, signature: base64
+func verifySignature(manifest, signature []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("invalid checksum public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid checksum signature: %w", err)
+	}
+	if !ed25519.Verify(key, manifest, sig) {
+		return errors.New("checksum manifest signature verification failed")
+	}
+	return nil
+}
+
+// fileSHA256 计算文件 SHA-256
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verify 校验文件 SHA-256, 文件名在清单中必须存在
+func (m checksumManifest) verify(path string) error {
+	_, name := filepath.Split(path)
+	if _, ok := m[name]; !ok {
+		return fmt.Errorf("no checksum for %s in %s", name, checksumFileName)
+	}
+	actual, err := fileSHA256(path)
+	if err != nil {
+		return err
+	}
+	return m.verifySum(name, actual)
+}
+
+// verifyData 校验内存数据 SHA-256, 用于安装配置文件
+func (m checksumManifest) verifyData(name string, data []byte) error {
+	sum := sha256.Sum256(data)
+	return m.verifySum(name, hex.EncodeToString(sum[:]))
+}
+
+func (m checksumManifest) verifySum(name, actual string) error {
+	expected, ok := m[name]
+	if !ok {
+		return fmt.Errorf("no checksum for %s in %s", name, checksumFileName)
+	}
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch for %s\n\texpected: %s\n\tactual:   %s", name, expected, actual)
+	}
+	return nil
+}
+
+// cacheDir 下载缓存目录
+func cacheDir(c *command.Config) string {
+	return filepath.Join(c.Install.Path, consts.FrameworkCache)
+}
+
+// fetchInstallConfig
+//	获取安装配置文件
+//	在线: 请求 url 并保存到下载缓存目录, 离线: 读取离线目录
+func fetchInstallConfig(c *command.Config, url, fileName string) ([]byte, error) {
+	if c.Install.Offline != "" {
+		data, err := os.ReadFile(filepath.Join(c.Install.Offline, fileName))
+		if err != nil {
+			return nil, fmt.Errorf("offline install: %w", err)
+		}
+		return data, nil
+	}
+	data, err := tools.HttpRequestGET(url)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(cacheDir(c), 0755); err == nil {
+		os.WriteFile(filepath.Join(cacheDir(c), fileName), data, 0644)
+	}
+	return data, nil
+}
+
+// fetchVerifiedConfig
+//	获取安装配置文件 edv.json extract.json, 使用清单校验 SHA-256
+func fetchVerifiedConfig(c *command.Config, url, fileName string) ([]byte, error) {
+	data, err := fetchInstallConfig(c, url, fileName)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := loadChecksums(c)
+	if err != nil {
+		return nil, err
+	}
+	if err = manifest.verifyData(fileName, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// loadChecksums
+//	加载 SHA-256 清单并校验签名, 签名无效时返回错误
+//	--no-verify 或没有公钥时跳过签名校验并警告, 仍然使用清单校验 SHA-256
+func loadChecksums(c *command.Config) (checksumManifest, error) {
+	if checksums != nil {
+		return checksums, nil
+	}
+	manifest, err := fetchInstallConfig(c, consts.DownloadChecksumURL, checksumFileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checksum manifest: %w", err)
+	}
+	publicKey := c.EnergyCfg.PublicKey
+	if publicKey == "" {
+		publicKey = consts.ChecksumPublicKey
+	}
+	if c.Install.NoVerify {
+		term.Logger.Warn("Skip checksum manifest signature verification")
+	} else if publicKey == "" {
+		term.Logger.Warn("Checksum public key not configured, checksum manifest signature is not verified, set publicKey in energy.json")
+	} else {
+		signature, err := fetchInstallConfig(c, consts.DownloadChecksumSigURL, checksumSigFileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get checksum signature: %w", err)
+		}
+		if err = verifySignature(manifest, signature, publicKey); err != nil {
+			return nil, err
+		}
+		term.Logger.Info("Checksum manifest signature verified")
+	}
+	if checksums, err = parseChecksums(manifest); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+// downloadVerify
+//	获取安装文件并在解压前校验 SHA-256
+//	已存在且校验通过的文件不再下载
+//	在线: 下载 url, 离线: 从离线目录复制同名文件
+func downloadVerify(c *command.Config, url, savePath string) error {
+	manifest, err := loadChecksums(c)
+	if err != nil {
+		return err
+	}
+	if tools.IsExist(savePath) {
+		if err = manifest.verify(savePath); err == nil {
+			term.Logger.Info("File already exists, checksum verified: " + savePath)
+			return nil
+		}
+		term.Logger.Warn(err.Error())
+		os.Remove(savePath)
+	}
+	if c.Install.Offline != "" {
+		err = copyOfflineFile(c, savePath)
+	} else {
+		err = DownloadFile(url, savePath, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if err = manifest.verify(savePath); err != nil {
+		os.Remove(savePath)
+		return err
+	}
+	term.Logger.Info("Checksum verified: " + savePath)
+	return nil
+}
+
+// copyOfflineFile 离线安装, 从离线目录复制文件到下载缓存目录
+func copyOfflineFile(c *command.Config, savePath string) error {
+	_, name := filepath.Split(savePath)
+	src := filepath.Join(c.Install.Offline, name)
+	if filepath.Clean(src) == filepath.Clean(savePath) {
+		return nil
+	}
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("offline install: %w", err)
+	}
+	defer srcFile.Close()
+	if err = os.MkdirAll(filepath.Dir(savePath), 0755); err != nil {
+		return err
+	}
+	dstFile, err := os.Create(savePath)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+	if _, err = io.Copy(dstFile, srcFile); err != nil {
+		return err
+	}
+	return dstFile.Close()
+}
@@ -0,0 +1,169 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package install
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/command"
+	"github.com/energye/energy/v2/cmd/internal/consts"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := sha256Hex([]byte("cef"))
+	manifest := fmt.Sprintf("# energy\n%s  cef.tar.bz2\n%s *liblcl.zip\n\n", sum, sum)
+	m, err := parseChecksums([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["cef.tar.bz2"] != sum || m["liblcl.zip"] != sum {
+		t.Fatal("unexpected manifest", m)
+	}
+	if _, err = parseChecksums([]byte("1234  cef.tar.bz2")); err == nil {
+		t.Fatal("expected invalid checksum error")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := []byte(sha256Hex([]byte("cef")) + "  cef.tar.bz2\n")
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, manifest))
+	key := base64.StdEncoding.EncodeToString(pub)
+	if err = verifySignature(manifest, []byte(sig), key); err != nil {
+		t.Fatal(err)
+	}
+	manifest[0] = 'x'
+	if err = verifySignature(manifest, []byte(sig), key); err == nil {
+		t.Fatal("expected signature verification error")
+	}
+}
+
+// writeSignedChecksums 写入离线目录签名清单, 返回公钥
+func writeSignedChecksums(t *testing.T, dir string, manifest []byte) string {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, checksumFileName), manifest, 0644)
+	os.WriteFile(filepath.Join(dir, checksumSigFileName), []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, manifest))), 0644)
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+func TestDownloadVerifyOffline(t *testing.T) {
+	defer func() { checksums = nil }()
+	offline := t.TempDir()
+	installPath := t.TempDir()
+	data := []byte("liblcl data")
+	os.WriteFile(filepath.Join(offline, "liblcl.zip"), data, 0644)
+	c := &command.Config{}
+	c.Install.Path = installPath
+	c.Install.Offline = offline
+	c.EnergyCfg.PublicKey = writeSignedChecksums(t, offline, []byte(sha256Hex(data)+"  liblcl.zip\n"))
+	savePath := filepath.Join(cacheDir(c), "liblcl.zip")
+	if err := downloadVerify(c, "", savePath); err != nil {
+		t.Fatal(err)
+	}
+	// 篡改后重新复制离线文件
+	os.WriteFile(savePath, []byte("tampered"), 0644)
+	if err := downloadVerify(c, "", savePath); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(savePath); string(content) != string(data) {
+		t.Fatal("tampered file was not replaced")
+	}
+	// 离线文件不在清单中
+	os.WriteFile(filepath.Join(offline, "cef.tar.bz2"), data, 0644)
+	if err := downloadVerify(c, "", filepath.Join(cacheDir(c), "cef.tar.bz2")); err == nil {
+		t.Fatal("expected missing checksum error")
+	}
+}
+
+func TestDownloadVerifyMismatch(t *testing.T) {
+	defer func() { checksums = nil }()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "8")
+		w.Write([]byte("tampered"))
+	}))
+	defer server.Close()
+	checksums = checksumManifest{"go.tar.gz": sha256Hex([]byte("original"))}
+	c := &command.Config{}
+	c.Install.Path = t.TempDir()
+	savePath := filepath.Join(c.Install.Path, "go.tar.gz")
+	if err := downloadVerify(c, server.URL+"/go.tar.gz", savePath); err == nil {
+		t.Fatal("expected checksum mismatch error")
+	}
+	if _, err := os.Stat(savePath); !os.IsNotExist(err) {
+		t.Fatal("mismatched file was not removed")
+	}
+}
+
+func TestLoadChecksumsFailClosed(t *testing.T) {
+	defer func() { checksums = nil }()
+	offline := t.TempDir()
+	extract := []byte(`{"windows":{}}`)
+	os.WriteFile(filepath.Join(offline, extractFileName), extract, 0644)
+	os.WriteFile(filepath.Join(offline, versionFileName), []byte(`{}`), 0644)
+	publicKey := writeSignedChecksums(t, offline, []byte(sha256Hex(extract)+"  "+extractFileName+"\n"))
+	c := &command.Config{}
+	c.Install.Path = t.TempDir()
+	c.Install.Offline = offline
+	// 未配置公钥, 只校验 SHA-256
+	if consts.ChecksumPublicKey == "" {
+		if data, err := fetchVerifiedConfig(c, "", extractFileName); err != nil || string(data) != string(extract) {
+			t.Fatal(string(data), err)
+		}
+		if _, err := fetchVerifiedConfig(c, "", versionFileName); err == nil {
+			t.Fatal("expected missing checksum error")
+		}
+		checksums = nil
+	}
+	// 公钥不匹配
+	c.EnergyCfg.PublicKey = base64.StdEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize))
+	if _, err := fetchVerifiedConfig(c, "", extractFileName); err == nil {
+		t.Fatal("expected signature verification error")
+	}
+	c.EnergyCfg.PublicKey = publicKey
+	if data, err := fetchVerifiedConfig(c, "", extractFileName); err != nil || string(data) != string(extract) {
+		t.Fatal(string(data), err)
+	}
+	// edv.json 不在清单中
+	if _, err := fetchVerifiedConfig(c, "", versionFileName); err == nil {
+		t.Fatal("expected missing checksum error")
+	}
+	// --no-verify 跳过签名, 仍然校验 SHA-256
+	checksums = nil
+	c.EnergyCfg.PublicKey = base64.StdEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize))
+	c.Install.NoVerify = true
+	if _, err := fetchVerifiedConfig(c, "", extractFileName); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(offline, extractFileName), []byte(`{"linux":{}}`), 0644)
+	if _, err := fetchVerifiedConfig(c, "", extractFileName); err == nil {
+		t.Fatal("expected checksum mismatch error")
+	}
+}
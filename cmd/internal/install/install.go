@@ -358,6 +358,25 @@ func checkInstallEnv(c *command.Config) (result []*softEnf) {
 	return
 }
 
+// EnvStatus 开发环境软件检查结果
+type EnvStatus struct {
+	Name      string
+	Desc      string
+	Installed bool
+}
+
+// CheckEnv 检查当前系统架构的开发环境, 和 energy install 相同: golang, cef, nsis, upx, 7za
+func CheckEnv(c *command.Config) []EnvStatus {
+	cc := *c
+	cc.Install = command.Install{}
+	defaultInstallConfig(&cc)
+	var result []EnvStatus
+	for _, se := range checkInstallEnv(&cc) {
+		result = append(result, EnvStatus{Name: se.name, Desc: se.desc, Installed: se.installed})
+	}
+	return result
+}
+
 func defaultInstallConfig(c *command.Config) {
 	if c.Install.Path == "" {
 		// current dir
@@ -695,7 +714,14 @@ func UnBz2ToTar(name string, callback func(totalLength, processLength int64)) (s
 	defer fileBz2.Close()
 	dirName := fileBz2.Name()
 	dirName = dirName[:strings.LastIndex(dirName, ".")]
-	if !tools.IsExist(dirName) {
+	// tar 早于 bz2 时重新释放, bz2 已重新下载
+	var tarIsNewer bool
+	if bz2Info, err := fileBz2.Stat(); err == nil {
+		if tarInfo, err := os.Stat(dirName); err == nil {
+			tarIsNewer = !tarInfo.ModTime().Before(bz2Info.ModTime())
+		}
+	}
+	if !tarIsNewer {
 		r := bzip2.NewReader(fileBz2)
 		w, err := os.Create(dirName)
 		if err != nil {
@@ -760,7 +786,11 @@ func DownloadFile(url string, localPath string, callback func(totalLength, proce
 	if err != nil {
 		return err
 	}
-	fsize, err = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 32)
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+	fsize, err = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
 	if err != nil {
 		return err
 	}
@@ -15,7 +15,6 @@ import (
 	"github.com/energye/energy/v2/cmd/internal/command"
 	"github.com/energye/energy/v2/cmd/internal/consts"
 	"github.com/energye/energy/v2/cmd/internal/term"
-	"github.com/energye/energy/v2/cmd/internal/tools"
 	"github.com/pterm/pterm"
 	"path/filepath"
 )
@@ -30,16 +29,13 @@ func install7z(c *command.Config) (string, func()) {
 	fileName := fmt.Sprintf("7za.windows.all-%s.zip", version)
 	downloadUrl := fmt.Sprintf(consts.Z7ZDownloadURL, fileName)
 	savePath := filepath.Join(c.Install.Path, consts.FrameworkCache, fileName) // 下载保存目录
-	var err error
 	term.Logger.Info("7za Download URL: " + downloadUrl)
 	term.Logger.Info("7za Save Path: " + savePath)
-	if !tools.IsExist(savePath) {
-		err = DownloadFile(downloadUrl, savePath, nil)
-		if err != nil {
-			term.Logger.Error("Download [" + fileName + "] failed: " + err.Error())
-		} else {
-			term.Logger.Info("Download ["+fileName+"]", term.Logger.Args(fileName, "success"))
-		}
+	err := downloadVerify(c, downloadUrl, savePath)
+	if err != nil {
+		term.Logger.Error("Download [" + fileName + "] failed: " + err.Error())
+	} else {
+		term.Logger.Info("Download ["+fileName+"]", term.Logger.Args(fileName, "success"))
 	}
 	if err == nil {
 		// 安装目录
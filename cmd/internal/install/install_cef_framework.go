@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"github.com/energye/energy/v2/cmd/internal/command"
 	"github.com/energye/energy/v2/cmd/internal/consts"
+	"github.com/energye/energy/v2/cmd/internal/framework"
 	"github.com/energye/energy/v2/cmd/internal/term"
 	"github.com/energye/energy/v2/cmd/internal/tools"
 	"github.com/pterm/pterm"
@@ -42,7 +43,7 @@ func installCEFFramework(c *command.Config) (string, func()) {
 	pterm.Println()
 	term.Section.Println("Install CEF")
 	// 获取提取文件配置
-	extractData, err := tools.HttpRequestGET(consts.DownloadExtractURL)
+	extractData, err := fetchVerifiedConfig(c, consts.DownloadExtractURL, extractFileName)
 	if err != nil {
 		term.Logger.Error(err.Error())
 		return "", nil
@@ -56,7 +57,7 @@ func installCEFFramework(c *command.Config) (string, func()) {
 	extractOSConfig := extractConfig[string(c.Install.OS)].(map[string]interface{})
 
 	// 获取安装版本配置
-	downloadJSON, err := tools.HttpRequestGET(consts.DownloadVersionURL)
+	downloadJSON, err := fetchVerifiedConfig(c, consts.DownloadVersionURL, versionFileName)
 	if err != nil {
 		term.Logger.Error(err.Error())
 		return "", nil
@@ -209,14 +210,14 @@ func installCEFFramework(c *command.Config) (string, func()) {
 		downloads[consts.LiblclKey] = &downloadInfo{isSupport: isSupport, fileName: urlName(downloadEnergyURL), downloadPath: filepath.Join(c.Install.Path, consts.FrameworkCache, urlName(downloadEnergyURL)), frameworkPath: installPathName, url: downloadEnergyURL, module: liblclModuleName}
 	}
 
-	// 在线下载框架二进制包
+	// 在线下载框架二进制包, 并校验 SHA-256
 	for key, dl := range downloads {
 		term.Section.Println("Download", key, ":", dl.url)
 		if !dl.isSupport {
 			term.Logger.Warn("Warn module is not built or configured [" + dl.module + "]")
 			continue
 		}
-		err = DownloadFile(dl.url, dl.downloadPath, nil)
+		err = downloadVerify(c, dl.url, dl.downloadPath)
 		if err != nil {
 			term.Logger.Error("Download [" + dl.fileName + "] " + err.Error())
 			return "", nil
@@ -260,6 +261,11 @@ func installCEFFramework(c *command.Config) (string, func()) {
 	}
 	return installPathName, func() {
 		term.Logger.Info("CEF Installed Successfully", term.Logger.Args("Version", c.Install.Version, "liblcl", liblclVersion))
+		// 记录已安装的框架, energy list, energy use
+		fw := &framework.Framework{Energy: c.Install.Version, CEF: cefVersion, Liblcl: liblclVersion, OS: c.Install.OS, Arch: c.Install.Arch, Path: installPathName}
+		if err := framework.Register(fw, c.Install.IsSame); err != nil {
+			term.Logger.Warn("Record installed framework failed", term.Logger.Args("error", err.Error()))
+		}
 		if liblclModule == nil {
 			term.Section.Println("hint: liblcl module", liblclModuleName, `is not configured in the current version`)
 		}
@@ -51,22 +51,19 @@ func installGolang(c *command.Config) (string, func()) {
 	}
 	fileName := fmt.Sprintf("go%s.%s-%s.%s", version, gos, arch, ext)
 	savePath := filepath.Join(c.Install.Path, consts.FrameworkCache, fileName) // 下载保存目录
-	var err error
-	if !tools.IsExist(savePath) {
-		// Go下载源, 格式只能是 [https://xxx.xxx.xx]/dl/go1.18.10.windows-arm64.zip
-		downloadSource := strings.TrimSpace(c.EnergyCfg.Source.Golang)
-		if downloadSource == "" {
-			downloadSource = consts.GolangDownloadSource
-		}
-		downloadUrl := fmt.Sprintf(consts.GolangDownloadURL, downloadSource, fileName)
-		term.Logger.Info("Golang Download URL: " + downloadUrl)
-		term.Logger.Info("Golang Save Path: " + savePath)
-		err = downloadGolang(downloadUrl, savePath, fileName, 0)
-		if err != nil {
-			term.Logger.Error("Download [" + fileName + "] failed: " + err.Error())
-		} else {
-			term.Logger.Info("Download [" + fileName + "] success")
-		}
+	// Go下载源, 格式只能是 [https://xxx.xxx.xx]/dl/go1.18.10.windows-arm64.zip
+	downloadSource := strings.TrimSpace(c.EnergyCfg.Source.Golang)
+	if downloadSource == "" {
+		downloadSource = consts.GolangDownloadSource
+	}
+	downloadUrl := fmt.Sprintf(consts.GolangDownloadURL, downloadSource, fileName)
+	term.Logger.Info("Golang Download URL: " + downloadUrl)
+	term.Logger.Info("Golang Save Path: " + savePath)
+	err := downloadGolang(c, downloadUrl, savePath, fileName, 0)
+	if err != nil {
+		term.Logger.Error("Download [" + fileName + "] failed: " + err.Error())
+	} else {
+		term.Logger.Info("Download [" + fileName + "] success")
 	}
 	if err == nil {
 		// 安装目录
@@ -92,15 +89,15 @@ func installGolang(c *command.Config) (string, func()) {
 	return "", nil
 }
 
-func downloadGolang(downloadUrl, savePath, fileName string, count int) error {
-	err := DownloadFile(downloadUrl, savePath, nil)
-	if err != nil && count < 5 {
+func downloadGolang(c *command.Config, downloadUrl, savePath, fileName string, count int) error {
+	err := downloadVerify(c, downloadUrl, savePath)
+	if err != nil && count < 5 && c.Install.Offline == "" {
 		// 失败尝试5次，每次递增一秒等待
 		n := count + 1
 		term.Logger.Error(err.Error())
 		term.Logger.Error(fmt.Sprintf("Download failed. %d second retry", n), term.Logger.Args("count", fmt.Sprintf("%d/5", n)))
 		time.Sleep(time.Second * time.Duration(n))
-		return downloadGolang(downloadUrl, savePath, fileName, n)
+		return downloadGolang(c, downloadUrl, savePath, fileName, n)
 	}
 	return err
 }
@@ -14,7 +14,6 @@ import (
 	"github.com/energye/energy/v2/cmd/internal/command"
 	"github.com/energye/energy/v2/cmd/internal/consts"
 	"github.com/energye/energy/v2/cmd/internal/term"
-	"github.com/energye/energy/v2/cmd/internal/tools"
 	"github.com/pterm/pterm"
 	"path/filepath"
 )
@@ -28,16 +27,13 @@ func installNSIS7z(c *command.Config) (string, func()) {
 	fileName := fmt.Sprintf("nsis7z.windows.386-%s.zip", version)
 	downloadUrl := fmt.Sprintf(consts.NSIS7zDownloadURL, fileName)
 	savePath := filepath.Join(c.Install.Path, consts.FrameworkCache, fileName) // 下载保存目录
-	var err error
-	if !tools.IsExist(savePath) {
-		term.Logger.Info("NSIS7z Download URL: " + downloadUrl)
-		term.Logger.Info("NSIS7z Save Path: " + savePath)
-		err = DownloadFile(downloadUrl, savePath, nil)
-		if err != nil {
-			term.Logger.Error("Download [" + fileName + "] failed: " + err.Error())
-		} else {
-			term.Logger.Info("Download ["+fileName+"]", term.Logger.Args(fileName, "success"))
-		}
+	term.Logger.Info("NSIS7z Download URL: " + downloadUrl)
+	term.Logger.Info("NSIS7z Save Path: " + savePath)
+	err := downloadVerify(c, downloadUrl, savePath)
+	if err != nil {
+		term.Logger.Error("Download [" + fileName + "] failed: " + err.Error())
+	} else {
+		term.Logger.Info("Download ["+fileName+"]", term.Logger.Args(fileName, "success"))
 	}
 	if err == nil {
 		// 安装目录
@@ -17,20 +17,30 @@ import (
 )
 
 var CmdPackage = &command.Command{
-	UsageLine: "package -p [path] -c [clean]",
+	UsageLine: "package -p [path] -c [clean] --os [os] --arch [arch]",
 	Short:     "Making an Installation Package",
 	Long: `
 	-p Project path, default current path. Can be configured in energy.json
 	-c Clear configuration and regenerate the default configuration
+	--os Target OS: windows, linux, darwin, default current system
+	--arch Target ARCH: 386, amd64, arm64, default current system
+		Cross target framework from environment variable ENERGY_HOME_[OS]_[ARCH]
+		For example: ENERGY_HOME_WINDOWS_AMD64=/to/path/EnergyFramework
 
 Making an Installation Package
 	Windows: 
 		Creating an installation program using NSIS for Windows
 	Linux: 
-		Creating deb installation packages using dpkg
+		Creating deb, rpm, AppImage or tar.gz packages, energy.json dpkg.targets: ["deb", "rpm", "appimage", "tar.gz"]
+		Does not depend on dpkg or rpmbuild, AppImage requires appimagetool, otherwise only generate AppDir
 	MacOS:
 		Generate app package for energy
 		--pkg Switch, generate pkg package
+
+Application update
+	energy.json update.feed generates the update package, binary patch and feed.json in build/update
+	Signed with update.privateKey, generated when missing, or environment variable ENERGY_UPDATE_PRIVATE_KEY
+	Upload build/update to the feed directory, the application checks it with pkgs/update
 `,
 }
 
@@ -39,7 +49,7 @@ func init() {
 }
 
 func runPackage(c *command.Config) error {
-	if proj, err := project.NewProject(c.Package.Path); err != nil {
+	if proj, err := project.NewTargetProject(c.Package.Path, c.Package.OS, c.Package.Arch); err != nil {
 		return err
 	} else {
 		proj.Clean = c.Package.Clean
@@ -0,0 +1,355 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// deb 控制文件目录
+const deb = "DEBIAN"
+
+// stageFile 构建目录内的文件信息
+type stageFile struct {
+	Path string      // 相对构建目录, 使用 / 分隔
+	Info os.FileInfo //
+	Link string      // 软链接目标
+}
+
+// walkStage
+//	遍历构建目录, 按路径排序返回所有文件和目录
+//	skip: 返回 true 时跳过该文件或目录
+func walkStage(root string, skip func(rel string) bool) ([]*stageFile, error) {
+	var files []*stageFile
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if skip != nil && skip(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		file := &stageFile{Path: rel, Info: info}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if file.Link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+// tarGzWriter tar.gz 写入
+type tarGzWriter struct {
+	gz     *gzip.Writer
+	tw     *tar.Writer
+	size   int64    // 文件总大小
+	md5sum []string // md5sums, 格式: [md5]  [path]
+}
+
+func newTarGzWriter(w io.Writer) *tarGzWriter {
+	gz, _ := gzip.NewWriterLevel(w, gzip.BestCompression)
+	return &tarGzWriter{gz: gz, tw: tar.NewWriter(gz)}
+}
+
+// addDir 添加目录
+func (m *tarGzWriter) addDir(name string, mode os.FileMode, modTime time.Time) error {
+	return m.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     strings.TrimSuffix(name, "/") + "/",
+		Mode:     int64(mode.Perm()),
+		ModTime:  modTime,
+		Uname:    "root",
+		Gname:    "root",
+		Format:   tar.FormatGNU,
+	})
+}
+
+// addBytes 添加内存文件
+func (m *tarGzWriter) addBytes(name string, data []byte, mode os.FileMode, modTime time.Time) error {
+	err := m.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     int64(mode.Perm()),
+		ModTime:  modTime,
+		Uname:    "root",
+		Gname:    "root",
+		Format:   tar.FormatGNU,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = m.tw.Write(data)
+	return err
+}
+
+// addStage
+//	添加构建目录文件
+//	prefix: 包内路径前缀, ./ 或 [name]/
+func (m *tarGzWriter) addStage(root, prefix string, files []*stageFile, md5sums bool) error {
+	for _, file := range files {
+		name := prefix + file.Path
+		hdr, err := tar.FileInfoHeader(file.Info, file.Link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if file.Info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Uid, hdr.Gid = 0, 0
+		hdr.Uname, hdr.Gname = "root", "root"
+		hdr.Format = tar.FormatGNU
+		if err = m.tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !file.Info.Mode().IsRegular() {
+			continue
+		}
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(file.Path)))
+		if err != nil {
+			return err
+		}
+		h := md5.New()
+		_, err = io.Copy(io.MultiWriter(m.tw, h), f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		m.size += file.Info.Size()
+		if md5sums {
+			m.md5sum = append(m.md5sum, hex.EncodeToString(h.Sum(nil))+"  "+file.Path)
+		}
+	}
+	return nil
+}
+
+func (m *tarGzWriter) Close() error {
+	if err := m.tw.Close(); err != nil {
+		return err
+	}
+	return m.gz.Close()
+}
+
+// writeTarGz
+//	构建目录打包为 tar.gz, 包内所有文件位于 prefix 目录下
+func writeTarGz(root, prefix, outFile string) error {
+	files, err := walkStage(root, nil)
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	tw := newTarGzWriter(out)
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	if err = tw.addDir(prefix, 0755, time.Now()); err != nil {
+		return err
+	}
+	if err = tw.addStage(root, prefix, files, false); err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// arWriter ar 归档写入, deb 包格式
+type arWriter struct {
+	w io.Writer
+}
+
+func newArWriter(w io.Writer) (*arWriter, error) {
+	if _, err := io.WriteString(w, "!<arch>\n"); err != nil {
+		return nil, err
+	}
+	return &arWriter{w: w}, nil
+}
+
+// add 添加 ar 成员, 成员数据按2字节对齐
+func (m *arWriter) add(name string, size int64, modTime time.Time, r io.Reader) error {
+	hdr := fmt.Sprintf("%-16s%-12d%-6d%-6d%-8s%-10d`\n", name, modTime.Unix(), 0, 0, "100644", size)
+	if _, err := io.WriteString(m.w, hdr); err != nil {
+		return err
+	}
+	if n, err := io.Copy(m.w, r); err != nil {
+		return err
+	} else if n != size {
+		return fmt.Errorf("ar member %s: expected %d bytes, wrote %d", name, size, n)
+	}
+	if size%2 != 0 {
+		if _, err := m.w.Write([]byte{'\n'}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDeb
+//	纯Go实现 dpkg -b, 不依赖 dpkg 命令
+//	stageDir: 包目录, DEBIAN 目录内为控制文件, 其它为安装文件
+func writeDeb(stageDir, outFile string) error {
+	controlDir := filepath.Join(stageDir, deb)
+	control, err := os.ReadFile(filepath.Join(controlDir, "control"))
+	if err != nil {
+		return fmt.Errorf("deb control file not found: %w", err)
+	}
+	now := time.Now()
+	// data.tar.gz
+	dataFiles, err := walkStage(stageDir, func(rel string) bool {
+		return rel == deb
+	})
+	if err != nil {
+		return err
+	}
+	dataTmp, err := os.CreateTemp("", "energy-deb-data-*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		dataTmp.Close()
+		os.Remove(dataTmp.Name())
+	}()
+	data := newTarGzWriter(dataTmp)
+	if err = data.addDir("./", 0755, now); err != nil {
+		return err
+	}
+	if err = data.addStage(stageDir, "./", dataFiles, true); err != nil {
+		return err
+	}
+	if err = data.Close(); err != nil {
+		return err
+	}
+	// control.tar.gz
+	controlFiles, err := walkStage(controlDir, func(rel string) bool {
+		return rel == "control" || rel == "md5sums"
+	})
+	if err != nil {
+		return err
+	}
+	control = debInstalledSize(control, data.size)
+	controlTmp, err := os.CreateTemp("", "energy-deb-control-*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		controlTmp.Close()
+		os.Remove(controlTmp.Name())
+	}()
+	ctrl := newTarGzWriter(controlTmp)
+	if err = ctrl.addDir("./", 0755, now); err != nil {
+		return err
+	}
+	if err = ctrl.addBytes("./control", control, 0644, now); err != nil {
+		return err
+	}
+	if len(data.md5sum) > 0 {
+		if err = ctrl.addBytes("./md5sums", []byte(strings.Join(data.md5sum, "\n")+"\n"), 0644, now); err != nil {
+			return err
+		}
+	}
+	// preinst postinst prerm postrm ...
+	if err = ctrl.addStage(controlDir, "./", controlFiles, false); err != nil {
+		return err
+	}
+	if err = ctrl.Close(); err != nil {
+		return err
+	}
+	// debian-binary + control.tar.gz + data.tar.gz
+	out, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	ar, err := newArWriter(out)
+	if err != nil {
+		return err
+	}
+	if err = ar.add("debian-binary", 4, now, strings.NewReader("2.0\n")); err != nil {
+		return err
+	}
+	for _, member := range []struct {
+		name string
+		file *os.File
+	}{{"control.tar.gz", controlTmp}, {"data.tar.gz", dataTmp}} {
+		st, err := member.file.Stat()
+		if err != nil {
+			return err
+		}
+		if _, err = member.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err = ar.add(member.name, st.Size(), now, member.file); err != nil {
+			return err
+		}
+	}
+	return out.Close()
+}
+
+// debInstalledSize control 文件未配置 Installed-Size 时添加, 单位 KiB
+func debInstalledSize(control []byte, size int64) []byte {
+	text := strings.TrimRight(strings.ReplaceAll(string(control), "\r\n", "\n"), "\n")
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.ToLower(line), "installed-size:") {
+			return []byte(text + "\n")
+		}
+	}
+	return []byte(text + "\nInstalled-Size: " + strconv.FormatInt((size+1023)/1024, 10) + "\n")
+}
+
+// debArch GOARCH 转换 deb Architecture
+func debArch(goarch string) string {
+	switch goarch {
+	case "386":
+		return "i386"
+	case "arm":
+		return "armhf"
+	}
+	return goarch
+}
@@ -0,0 +1,534 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rpm header 数据类型
+const (
+	rpmTypeInt16       = 3
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeBin         = 7
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+// rpm 签名 tag
+const (
+	rpmSigHeaderSignatures = 62
+	rpmSigSHA1             = 269
+	rpmSigSHA256           = 273
+	rpmSigSize             = 1000
+	rpmSigMD5              = 1004
+	rpmSigPayloadSize      = 1007
+)
+
+// rpm header tag
+const (
+	rpmTagHeaderImmutable   = 63
+	rpmTagHeaderI18NTable   = 100
+	rpmTagName              = 1000
+	rpmTagVersion           = 1001
+	rpmTagRelease           = 1002
+	rpmTagSummary           = 1004
+	rpmTagDescription       = 1005
+	rpmTagBuildTime         = 1006
+	rpmTagBuildHost         = 1007
+	rpmTagSize              = 1009
+	rpmTagVendor            = 1011
+	rpmTagLicense           = 1014
+	rpmTagPackager          = 1015
+	rpmTagGroup             = 1016
+	rpmTagURL               = 1020
+	rpmTagOS                = 1021
+	rpmTagArch              = 1022
+	rpmTagFileSizes         = 1028
+	rpmTagFileModes         = 1030
+	rpmTagFileRDevs         = 1033
+	rpmTagFileMTimes        = 1034
+	rpmTagFileDigests       = 1035
+	rpmTagFileLinkTos       = 1036
+	rpmTagFileFlags         = 1037
+	rpmTagFileUserName      = 1039
+	rpmTagFileGroupName     = 1040
+	rpmTagProvideName       = 1047
+	rpmTagRequireFlags      = 1048
+	rpmTagRequireName       = 1049
+	rpmTagRequireVersion    = 1050
+	rpmTagRPMVersion        = 1064
+	rpmTagFileDevices       = 1095
+	rpmTagFileINodes        = 1096
+	rpmTagFileLangs         = 1097
+	rpmTagProvideFlags      = 1112
+	rpmTagProvideVersion    = 1113
+	rpmTagDirIndexes        = 1116
+	rpmTagBaseNames         = 1117
+	rpmTagDirNames          = 1118
+	rpmTagPayloadFormat     = 1124
+	rpmTagPayloadCompressor = 1125
+	rpmTagPayloadFlags      = 1126
+)
+
+// rpm 依赖标记
+const (
+	rpmSenseLess   = 1 << 1
+	rpmSenseEqual  = 1 << 3
+	rpmSenseRPMLib = 1 << 24
+)
+
+// rpmSystemDirs 系统目录, 不属于应用包
+var rpmSystemDirs = map[string]bool{
+	"/opt":                     true,
+	"/usr":                     true,
+	"/usr/bin":                 true,
+	"/usr/lib":                 true,
+	"/usr/share":               true,
+	"/usr/share/applications":  true,
+	"/usr/share/icons":         true,
+	"/usr/share/icons/hicolor": true,
+	"/usr/share/pixmaps":       true,
+	"/usr/share/doc":           true,
+	"/usr/share/metainfo":      true,
+	"/usr/share/mime":          true,
+	"/usr/share/mime/packages": true,
+}
+
+// rpmInfo rpm 包信息
+type rpmInfo struct {
+	Name        string
+	Version     string
+	Release     string
+	Arch        string // rpm 架构, x86_64 aarch64 i386
+	Summary     string
+	Description string
+	License     string
+	Vendor      string
+	Packager    string
+	URL         string
+	Group       string
+}
+
+// rpmHeader rpm header 结构, 索引 + 数据区
+type rpmHeader struct {
+	entries []rpmEntry
+}
+
+type rpmEntry struct {
+	tag, typ, count int32
+	data            []byte
+}
+
+func (m *rpmHeader) add(tag, typ, count int32, data []byte) {
+	m.entries = append(m.entries, rpmEntry{tag: tag, typ: typ, count: count, data: data})
+}
+
+func (m *rpmHeader) string(tag int32, value string) {
+	m.add(tag, rpmTypeString, 1, append([]byte(value), 0))
+}
+
+func (m *rpmHeader) i18nString(tag int32, value string) {
+	m.add(tag, rpmTypeI18NString, 1, append([]byte(value), 0))
+}
+
+func (m *rpmHeader) stringArray(tag int32, values []string) {
+	var buf bytes.Buffer
+	for _, v := range values {
+		buf.WriteString(v)
+		buf.WriteByte(0)
+	}
+	m.add(tag, rpmTypeStringArray, int32(len(values)), buf.Bytes())
+}
+
+func (m *rpmHeader) int32(tag int32, values ...int32) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, values)
+	m.add(tag, rpmTypeInt32, int32(len(values)), buf.Bytes())
+}
+
+func (m *rpmHeader) int16(tag int32, values ...int16) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, values)
+	m.add(tag, rpmTypeInt16, int32(len(values)), buf.Bytes())
+}
+
+func (m *rpmHeader) bin(tag int32, data []byte) {
+	m.add(tag, rpmTypeBin, int32(len(data)), data)
+}
+
+// bytes
+//	序列化 header, region: 62 签名 或 63 header
+//	pad: 签名 header 需要按8字节对齐
+func (m *rpmHeader) bytes(region int32, pad bool) []byte {
+	sort.SliceStable(m.entries, func(i, j int) bool {
+		return m.entries[i].tag < m.entries[j].tag
+	})
+	var (
+		store bytes.Buffer
+		index bytes.Buffer
+	)
+	nindex := int32(len(m.entries) + 1)
+	offsets := make([]int32, len(m.entries))
+	for i, e := range m.entries {
+		// 数据类型对齐
+		var align int
+		switch e.typ {
+		case rpmTypeInt16:
+			align = 2
+		case rpmTypeInt32:
+			align = 4
+		}
+		if align > 0 {
+			for store.Len()%align != 0 {
+				store.WriteByte(0)
+			}
+		}
+		offsets[i] = int32(store.Len())
+		store.Write(e.data)
+	}
+	// region trailer
+	trailerOffset := int32(store.Len())
+	binary.Write(&store, binary.BigEndian, []int32{region, rpmTypeBin, -nindex * 16, 16})
+	binary.Write(&index, binary.BigEndian, []int32{region, rpmTypeBin, trailerOffset, 16})
+	for i, e := range m.entries {
+		binary.Write(&index, binary.BigEndian, []int32{e.tag, e.typ, offsets[i], e.count})
+	}
+	var out bytes.Buffer
+	out.Write([]byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0})
+	binary.Write(&out, binary.BigEndian, []int32{nindex, int32(store.Len())})
+	out.Write(index.Bytes())
+	out.Write(store.Bytes())
+	if pad {
+		for out.Len()%8 != 0 {
+			out.WriteByte(0)
+		}
+	}
+	return out.Bytes()
+}
+
+// cpioWriter cpio newc 格式写入, rpm payload
+type cpioWriter struct {
+	w     io.Writer
+	n     int64
+	inode int32
+}
+
+func (m *cpioWriter) write(data []byte) error {
+	n, err := m.w.Write(data)
+	m.n += int64(n)
+	return err
+}
+
+func (m *cpioWriter) pad() error {
+	if r := m.n % 4; r != 0 {
+		return m.write(make([]byte, 4-r))
+	}
+	return nil
+}
+
+// header 写入文件头, 返回文件 inode
+func (m *cpioWriter) header(name string, mode uint32, mtime int64, size int64) (int32, error) {
+	m.inode++
+	hdr := fmt.Sprintf("070701%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X",
+		m.inode, mode, 0, 0, 1, mtime, size, 0, 0, 0, 0, len(name)+1, 0)
+	if err := m.write([]byte(hdr)); err != nil {
+		return 0, err
+	}
+	if err := m.write(append([]byte(name), 0)); err != nil {
+		return 0, err
+	}
+	return m.inode, m.pad()
+}
+
+// close 写入结束标记
+func (m *cpioWriter) close() error {
+	m.inode = -1
+	_, err := m.header("TRAILER!!!", 0, 0, 0)
+	return err
+}
+
+// unixMode os.FileMode 转换 unix st_mode
+func unixMode(mode os.FileMode) uint32 {
+	m := uint32(mode.Perm())
+	switch {
+	case mode.IsDir():
+		m |= 0040000
+	case mode&os.ModeSymlink != 0:
+		m |= 0120000
+	default:
+		m |= 0100000
+	}
+	if mode&os.ModeSetuid != 0 {
+		m |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		m |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		m |= 01000
+	}
+	return m
+}
+
+// rpmArch GOARCH 转换 rpm 架构
+func rpmArch(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "x86_64"
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "i386"
+	case "arm":
+		return "armv7hl"
+	case "loong64":
+		return "loongarch64"
+	}
+	return goarch
+}
+
+// writeRPM
+//	纯Go生成 rpm 包, 不依赖 rpmbuild 命令
+//	stageDir: 安装根目录, DEBIAN 目录被忽略
+func writeRPM(info *rpmInfo, stageDir, outFile string) error {
+	files, err := walkStage(stageDir, func(rel string) bool {
+		return rel == deb
+	})
+	if err != nil {
+		return err
+	}
+	if info.Release == "" {
+		info.Release = "1"
+	}
+	if info.License == "" {
+		info.License = "Proprietary"
+	}
+	if info.Group == "" {
+		info.Group = "Applications/Internet"
+	}
+	// payload: cpio.gz
+	payloadTmp, err := os.CreateTemp("", "energy-rpm-payload-*.cpio.gz")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		payloadTmp.Close()
+		os.Remove(payloadTmp.Name())
+	}()
+	gz, _ := gzip.NewWriterLevel(payloadTmp, gzip.BestCompression)
+	cpio := &cpioWriter{w: gz}
+	var (
+		totalSize                 int64
+		sizes, mtimes, flags      []int32
+		inodes, devices, dirIndex []int32
+		modes, rdevs              []int16
+		digests, links, langs     []string
+		users, groups, baseNames  []string
+		dirNames                  []string
+		dirs                      = make(map[string]int32)
+	)
+	for _, file := range files {
+		name := "/" + file.Path
+		if file.Info.IsDir() && (rpmSystemDirs[name] || strings.HasPrefix(name, "/usr/share/icons/")) {
+			continue
+		}
+		mode := unixMode(file.Info.Mode())
+		mtime := file.Info.ModTime().Unix()
+		var (
+			size   int64
+			digest string
+			data   io.Reader
+		)
+		switch {
+		case file.Info.Mode().IsRegular():
+			size = file.Info.Size()
+			f, err := os.Open(filepath.Join(stageDir, filepath.FromSlash(file.Path)))
+			if err != nil {
+				return err
+			}
+			h := md5.New()
+			_, err = io.Copy(h, f)
+			if err == nil {
+				_, err = f.Seek(0, io.SeekStart)
+			}
+			if err != nil {
+				f.Close()
+				return err
+			}
+			digest = hex.EncodeToString(h.Sum(nil))
+			data = f
+		case file.Link != "":
+			size = int64(len(file.Link))
+			data = strings.NewReader(file.Link)
+		}
+		inode, err := cpio.header("."+name, mode, mtime, size)
+		if err == nil && data != nil {
+			var n int64
+			n, err = io.Copy(gz, data)
+			cpio.n += n
+			if err == nil {
+				err = cpio.pad()
+			}
+		}
+		if c, ok := data.(io.Closer); ok {
+			c.Close()
+		}
+		if err != nil {
+			return err
+		}
+		dir, base := path.Split(name)
+		idx, ok := dirs[dir]
+		if !ok {
+			idx = int32(len(dirNames))
+			dirs[dir] = idx
+			dirNames = append(dirNames, dir)
+		}
+		totalSize += size
+		sizes = append(sizes, int32(size))
+		modes = append(modes, int16(mode))
+		rdevs = append(rdevs, 0)
+		mtimes = append(mtimes, int32(mtime))
+		digests = append(digests, digest)
+		links = append(links, file.Link)
+		flags = append(flags, 0)
+		users = append(users, "root")
+		groups = append(groups, "root")
+		devices = append(devices, 1)
+		inodes = append(inodes, inode)
+		langs = append(langs, "")
+		dirIndex = append(dirIndex, idx)
+		baseNames = append(baseNames, base)
+	}
+	if err = cpio.close(); err != nil {
+		return err
+	}
+	payloadSize := cpio.n
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	// header
+	host, _ := os.Hostname()
+	hdr := &rpmHeader{}
+	hdr.stringArray(rpmTagHeaderI18NTable, []string{"C"})
+	hdr.string(rpmTagName, info.Name)
+	hdr.string(rpmTagVersion, info.Version)
+	hdr.string(rpmTagRelease, info.Release)
+	hdr.i18nString(rpmTagSummary, info.Summary)
+	hdr.i18nString(rpmTagDescription, info.Description)
+	hdr.int32(rpmTagBuildTime, int32(time.Now().Unix()))
+	hdr.string(rpmTagBuildHost, host)
+	hdr.int32(rpmTagSize, int32(totalSize))
+	if info.Vendor != "" {
+		hdr.string(rpmTagVendor, info.Vendor)
+	}
+	hdr.string(rpmTagLicense, info.License)
+	if info.Packager != "" {
+		hdr.string(rpmTagPackager, info.Packager)
+	}
+	hdr.i18nString(rpmTagGroup, info.Group)
+	if info.URL != "" {
+		hdr.string(rpmTagURL, info.URL)
+	}
+	hdr.string(rpmTagOS, "linux")
+	hdr.string(rpmTagArch, info.Arch)
+	hdr.string(rpmTagRPMVersion, "4.11.3")
+	hdr.stringArray(rpmTagProvideName, []string{info.Name})
+	hdr.int32(rpmTagProvideFlags, rpmSenseEqual)
+	hdr.stringArray(rpmTagProvideVersion, []string{info.Version + "-" + info.Release})
+	hdr.stringArray(rpmTagRequireName, []string{"rpmlib(CompressedFileNames)", "rpmlib(PayloadFilesHavePrefix)"})
+	hdr.int32(rpmTagRequireFlags, rpmSenseRPMLib|rpmSenseLess|rpmSenseEqual, rpmSenseRPMLib|rpmSenseLess|rpmSenseEqual)
+	hdr.stringArray(rpmTagRequireVersion, []string{"3.0.4-1", "4.0-1"})
+	if len(baseNames) > 0 {
+		hdr.int32(rpmTagFileSizes, sizes...)
+		hdr.int16(rpmTagFileModes, modes...)
+		hdr.int16(rpmTagFileRDevs, rdevs...)
+		hdr.int32(rpmTagFileMTimes, mtimes...)
+		hdr.stringArray(rpmTagFileDigests, digests)
+		hdr.stringArray(rpmTagFileLinkTos, links)
+		hdr.int32(rpmTagFileFlags, flags...)
+		hdr.stringArray(rpmTagFileUserName, users)
+		hdr.stringArray(rpmTagFileGroupName, groups)
+		hdr.int32(rpmTagFileDevices, devices...)
+		hdr.int32(rpmTagFileINodes, inodes...)
+		hdr.stringArray(rpmTagFileLangs, langs)
+		hdr.int32(rpmTagDirIndexes, dirIndex...)
+		hdr.stringArray(rpmTagBaseNames, baseNames)
+		hdr.stringArray(rpmTagDirNames, dirNames)
+	}
+	hdr.string(rpmTagPayloadFormat, "cpio")
+	hdr.string(rpmTagPayloadCompressor, "gzip")
+	hdr.string(rpmTagPayloadFlags, "9")
+	header := hdr.bytes(rpmTagHeaderImmutable, false)
+	// signature: header + payload
+	if _, err = payloadTmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	md5Hash := md5.New()
+	md5Hash.Write(header)
+	payloadLen, err := io.Copy(md5Hash, payloadTmp)
+	if err != nil {
+		return err
+	}
+	sha1Sum := sha1.Sum(header)
+	sha256Sum := sha256.Sum256(header)
+	sig := &rpmHeader{}
+	sig.string(rpmSigSHA1, hex.EncodeToString(sha1Sum[:]))
+	sig.string(rpmSigSHA256, hex.EncodeToString(sha256Sum[:]))
+	sig.int32(rpmSigSize, int32(int64(len(header))+payloadLen))
+	sig.bin(rpmSigMD5, md5Hash.Sum(nil))
+	sig.int32(rpmSigPayloadSize, int32(payloadSize))
+	signature := sig.bytes(rpmSigHeaderSignatures, true)
+	// lead
+	lead := make([]byte, 96)
+	copy(lead, []byte{0xed, 0xab, 0xee, 0xdb, 3, 0})
+	binary.BigEndian.PutUint16(lead[6:], 0) // binary
+	binary.BigEndian.PutUint16(lead[8:], 1) // archnum
+	name := fmt.Sprintf("%s-%s-%s", info.Name, info.Version, info.Release)
+	if len(name) > 65 {
+		name = name[:65]
+	}
+	copy(lead[10:76], name)
+	binary.BigEndian.PutUint16(lead[76:], 1) // linux
+	binary.BigEndian.PutUint16(lead[78:], 5) // header style signature
+	out, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	for _, data := range [][]byte{lead, signature, header} {
+		if _, err = out.Write(data); err != nil {
+			return err
+		}
+	}
+	if _, err = payloadTmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, payloadTmp); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,207 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testStage(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"DEBIAN/control":                      "Package: demo\nVersion: 1.0.0\nArchitecture: amd64\nMaintainer: a <a@b.c>\nDescription: demo\n",
+		"DEBIAN/postinst":                     "#!/bin/sh\n",
+		"opt/demo/demo/demo":                  "binary",
+		"opt/demo/demo/locales/en.pak":        "pak",
+		"usr/share/applications/demo.desktop": "[Desktop Entry]\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		mode := os.FileMode(0644)
+		if name == "DEBIAN/postinst" || name == "opt/demo/demo/demo" {
+			mode = 0755
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// readAr 读取 ar 成员
+func readAr(t *testing.T, data []byte) (names []string, members map[string][]byte) {
+	if !bytes.HasPrefix(data, []byte("!<arch>\n")) {
+		t.Fatal("invalid ar magic")
+	}
+	members = make(map[string][]byte)
+	data = data[8:]
+	for len(data) >= 60 {
+		name := strings.TrimSpace(string(data[:16]))
+		size, err := strconv.Atoi(strings.TrimSpace(string(data[48:58])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data[58:60]) != "`\n" {
+			t.Fatal("invalid ar member header", name)
+		}
+		names = append(names, name)
+		members[name] = data[60 : 60+size]
+		data = data[60+size+size%2:]
+	}
+	return
+}
+
+// readTarGz 读取 tar.gz 文件名和内容
+func readTarGz(t *testing.T, data []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(content)
+	}
+	return files
+}
+
+func TestWriteDeb(t *testing.T) {
+	stage := testStage(t)
+	out := filepath.Join(t.TempDir(), "demo.deb")
+	if err := writeDeb(stage, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, members := readAr(t, data)
+	if strings.Join(names, ",") != "debian-binary,control.tar.gz,data.tar.gz" {
+		t.Fatal("unexpected ar members", names)
+	}
+	if string(members["debian-binary"]) != "2.0\n" {
+		t.Fatal("unexpected debian-binary", string(members["debian-binary"]))
+	}
+	control := readTarGz(t, members["control.tar.gz"])
+	if !strings.Contains(control["./control"], "Installed-Size: 1\n") {
+		t.Fatal("Installed-Size not generated", control["./control"])
+	}
+	if _, ok := control["./postinst"]; !ok {
+		t.Fatal("postinst not found")
+	}
+	if !strings.Contains(control["./md5sums"], "  opt/demo/demo/demo\n") {
+		t.Fatal("md5sums not generated", control["./md5sums"])
+	}
+	files := readTarGz(t, members["data.tar.gz"])
+	if files["./opt/demo/demo/demo"] != "binary" {
+		t.Fatal("data file not found")
+	}
+	for name := range files {
+		if strings.HasPrefix(name, "./DEBIAN") {
+			t.Fatal("DEBIAN in data.tar.gz", name)
+		}
+	}
+}
+
+func TestWriteRPM(t *testing.T) {
+	stage := testStage(t)
+	out := filepath.Join(t.TempDir(), "demo.rpm")
+	info := &rpmInfo{Name: "demo", Version: "1.0.0", Arch: rpmArch("amd64"), Summary: "demo"}
+	if err := writeRPM(info, stage, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte{0xed, 0xab, 0xee, 0xdb, 3, 0}) {
+		t.Fatal("invalid rpm lead")
+	}
+	// signature + header
+	readHeader := func(offset int, pad bool) (int, map[int32]bool) {
+		if !bytes.Equal(data[offset:offset+3], []byte{0x8e, 0xad, 0xe8}) {
+			t.Fatal("invalid rpm header magic", offset)
+		}
+		nindex := int(binary.BigEndian.Uint32(data[offset+8:]))
+		hsize := int(binary.BigEndian.Uint32(data[offset+12:]))
+		tags := make(map[int32]bool)
+		for i := 0; i < nindex; i++ {
+			tags[int32(binary.BigEndian.Uint32(data[offset+16+i*16:]))] = true
+		}
+		end := offset + 16 + nindex*16 + hsize
+		if pad {
+			end += (8 - end%8) % 8
+		}
+		return end, tags
+	}
+	offset, sigTags := readHeader(96, true)
+	if !sigTags[rpmSigHeaderSignatures] || !sigTags[rpmSigMD5] || !sigTags[rpmSigSize] {
+		t.Fatal("rpm signature tags missing")
+	}
+	offset, tags := readHeader(offset, false)
+	for _, tag := range []int32{rpmTagHeaderImmutable, rpmTagName, rpmTagArch, rpmTagBaseNames, rpmTagDirNames} {
+		if !tags[tag] {
+			t.Fatal("rpm header tag missing", tag)
+		}
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data[offset:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(payload, []byte("070701")) || !bytes.Contains(payload, []byte("./opt/demo/demo/demo\x00")) {
+		t.Fatal("invalid cpio payload")
+	}
+	if bytes.Contains(payload, []byte("DEBIAN")) || bytes.Contains(payload, []byte("./usr/share/applications\x00")) {
+		t.Fatal("unexpected file in rpm payload")
+	}
+	if !bytes.Contains(payload, []byte("TRAILER!!!\x00")) {
+		t.Fatal("cpio trailer missing")
+	}
+}
+
+func TestWriteTarGz(t *testing.T) {
+	stage := testStage(t)
+	out := filepath.Join(t.TempDir(), "demo.tar.gz")
+	if err := writeTarGz(filepath.Join(stage, "opt", "demo", "demo"), "demo-1.0.0", out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := readTarGz(t, data)
+	if files["demo-1.0.0/demo"] != "binary" || files["demo-1.0.0/locales/en.pak"] != "pak" {
+		t.Fatal("unexpected tar.gz content", files)
+	}
+}
@@ -0,0 +1,94 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"github.com/energye/energy/v2/cmd/internal/assets"
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/pkgs/winicon"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// linux 图标主题目录
+const usrShareHicolor = "usr/share/icons/hicolor"
+
+// windows ico 图标尺寸
+var icoSizes = []int{256, 128, 64, 48, 32, 16}
+
+// convertIcon 读取 src 图标, 使用 generate 转换后写入 dst
+func convertIcon(src, dst string, generate func(r io.Reader, w io.Writer) error) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err = os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err = generate(in, out); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// generateICO png => ico
+func generateICO(r io.Reader, w io.Writer) error {
+	return winicon.GenerateIcon(r, w, icoSizes)
+}
+
+// isPNG 图标是 png 格式
+func isPNG(icon string) bool {
+	return strings.ToLower(filepath.Ext(icon)) == ".png"
+}
+
+// windowsICO
+//	NSIS 安装包图标, png 图标转换为 ico
+//	返回 ico 文件, 不是 png 时返回原文件
+func windowsICO(proj *project.Project, icon, name string) (string, error) {
+	if !isPNG(icon) {
+		return icon, nil
+	}
+	ico := filepath.Join(assets.BuildOutPath(proj), "windows", name+".ico")
+	if err := convertIcon(icon, ico, generateICO); err != nil {
+		return "", err
+	}
+	return ico, nil
+}
+
+// linuxHicolor
+//	生成 hicolor 图标主题目录 usr/share/icons/hicolor/[size]/apps/[name].png
+//	只支持 png 图标, 生成后返回 true, .desktop 使用图标名称
+func linuxHicolor(proj *project.Project, appRoot string) (bool, error) {
+	if !isPNG(proj.Info.Icon) {
+		term.Logger.Warn("Linux icon theme requires a png icon, skip hicolor", term.Logger.Args("icon", proj.Info.Icon))
+		return false, nil
+	}
+	term.Logger.Info("Generate dpkg hicolor icons")
+	in, err := os.Open(proj.Info.Icon)
+	if err != nil {
+		return false, err
+	}
+	defer in.Close()
+	dir := filepath.Join(assets.BuildOutPath(proj), appRoot, usrShareHicolor)
+	if _, err = winicon.GenerateHicolor(in, dir, proj.Name, winicon.HicolorSizes); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -10,6 +10,90 @@
 
 package packager
 
+import (
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"strings"
+)
+
 const (
 	assetsFSPath = "assets/packager/"
 )
+
+// Tool 打包依赖的外部命令
+type Tool struct {
+	Name     string // 命令名
+	Purpose  string // 用途
+	Required bool   // true: 必须, false: 可选, 不存在时跳过对应功能
+	Found    bool   // 当前系统是否存在
+}
+
+// RequiredTools
+//	返回目标系统打包所需的外部命令
+//	deb rpm tar.gz 为纯Go实现, 不需要外部命令
+func RequiredTools(proj *project.Project) []*Tool {
+	var result []*Tool
+	var add = func(name, purpose string, required bool) {
+		result = append(result, &Tool{Name: name, Purpose: purpose, Required: required, Found: tools.CommandExists(name)})
+	}
+	switch proj.OS {
+	case "windows":
+		add("makensis", "NSIS installer compiler", true)
+		switch proj.NSIS.Compress {
+		case "7z", "7za":
+			add(proj.NSIS.Compress, "compress CEF framework", false)
+		}
+	case "linux":
+		for _, target := range proj.Dpkg.Targets {
+			if target == linuxTargetAppImage {
+				add("appimagetool", "AppImage from AppDir", false)
+			}
+		}
+	case "darwin":
+		if proj.PList.Pkgbuild {
+			add("pkgbuild", "pkg installer, macOS only", true)
+		}
+	}
+	return result
+}
+
+// GeneraInstaller
+//	根据目标系统生成安装包
+//	windows: nsis, linux: deb rpm AppImage tar.gz, darwin: app pkg
+//	配置 update.feed 时生成应用更新包和更新源
+func GeneraInstaller(proj *project.Project) error {
+	term.Logger.Info("Generate installation package", term.Logger.Args("os", proj.OS, "arch", proj.Arch, "framework", proj.FrameworkPath))
+	var missing []string
+	for _, tool := range RequiredTools(proj) {
+		if tool.Found {
+			term.Logger.Info("Tool found", term.Logger.Args("name", tool.Name, "purpose", tool.Purpose))
+			continue
+		}
+		if tool.Required {
+			missing = append(missing, fmt.Sprintf("%s (%s)", tool.Name, tool.Purpose))
+		} else {
+			term.Logger.Warn("Tool not found, skip", term.Logger.Args("name", tool.Name, "purpose", tool.Purpose))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("failed to create %s/%s installation package. Could not find the required commands:\n\t%s",
+			proj.OS, proj.Arch, strings.Join(missing, "\n\t"))
+	}
+	var err error
+	switch proj.OS {
+	case "windows":
+		err = windowsInstaller(proj)
+	case "linux":
+		err = linuxInstaller(proj)
+	case "darwin":
+		err = darwinInstaller(proj)
+	default:
+		return fmt.Errorf("unsupported package os: %s, options: windows, linux, darwin", proj.OS)
+	}
+	if err != nil {
+		return err
+	}
+	return updateArtifacts(proj)
+}
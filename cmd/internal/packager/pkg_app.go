@@ -8,19 +8,16 @@
 //
 //----------------------------------------
 
-//go:build darwin
-// +build darwin
-
 package packager
 
 import (
 	"errors"
 	"fmt"
 	"github.com/energye/energy/v2/cmd/internal/assets"
-	"github.com/energye/energy/v2/cmd/internal/consts"
 	"github.com/energye/energy/v2/cmd/internal/project"
 	"github.com/energye/energy/v2/cmd/internal/term"
 	"github.com/energye/energy/v2/cmd/internal/tools"
+	"github.com/energye/energy/v2/pkgs/winicon"
 	"github.com/energye/golcl/tools/command"
 	"io"
 	"io/fs"
@@ -48,19 +45,7 @@ const (
 )
 
 var (
-	pkgInfo  = []byte{0x41, 0x50, 0x50, 0x4C, 0x3F, 0x3F, 0x3F, 0x3F, 0x0D, 0x0A}
-	sipsCmds = []string{
-		"-z 16 16 %s -o icons.iconset/%s_16x16.png",
-		"-z 32 32 %s -o icons.iconset/%s_16x16@2x.png",
-		"-z 32 32 %s -o icons.iconset/%s_32x32.png",
-		"-z 64 64 %s -o icons.iconset/%s_32x32@2x.png",
-		"-z 128 128 %s -o icons.iconset/%s_128x128.png",
-		"-z 256 256 %s -o icons.iconset/%s_128x128@2x.png",
-		"-z 256 256 %s -o icons.iconset/%s_256x256.png",
-		"-z 512 512 %s -o icons.iconset/%s_256x256@2x.png",
-		"-z 512 512 %s -o icons.iconset/%s_512x512.png",
-		"-z 1024 1024 %s -o icons.iconset/%s_512x512@2x.png",
-	}
+	pkgInfo = []byte{0x41, 0x50, 0x50, 0x4C, 0x3F, 0x3F, 0x3F, 0x3F, 0x0D, 0x0A}
 	helpers = []string{
 		macCefHelper,
 		macCefHelperGpu,
@@ -70,7 +55,7 @@ var (
 	projectPath string
 )
 
-func GeneraInstaller(proj *project.Project) error {
+func darwinInstaller(proj *project.Project) error {
 	appRoot := fmt.Sprintf("darwin/%s.app", proj.Name)
 	buildOutDir := assets.BuildOutPath(proj)
 	buildOutDir = filepath.Join(buildOutDir, appRoot)
@@ -183,9 +168,9 @@ func copyFrameworkFile(proj *project.Project, appRoot string) error {
 	if !tools.IsExist(exeDir) {
 		return fmt.Errorf("execution file not found: %s", exeDir)
 	}
-	cefDir := os.Getenv(consts.EnergyHomeKey)
+	cefDir := proj.FrameworkPath
 	if !tools.IsExist(cefDir) {
-		return fmt.Errorf("%s not found: %s", consts.EnergyHomeKey, cefDir)
+		return fmt.Errorf("energy framework not found: %s", cefDir)
 	}
 	term.Logger.Info("Generate app copy:", term.Logger.Args("execution", exeDir))
 	// Contents/MacOS/exe
@@ -249,13 +234,6 @@ func copyHelperFile(proj *project.Project, appRoot string) error {
 	// 设置为helper选项
 	proj.PList.LSUIElement = true
 	proj.AppType = project.AtHelper
-	cmd := command.NewCMD()
-	cmd.IsPrint = false
-	cmd.MessageCallback = func(bytes []byte, e error) {
-		if e != nil {
-			err = e
-		}
-	}
 	for _, app := range helpers {
 		proj.ProjectPath = frameworksDir
 		helperAppRoot := fmt.Sprintf("%s %s.app", proj.Name, app)
@@ -272,9 +250,9 @@ func copyHelperFile(proj *project.Project, appRoot string) error {
 			return err
 		}
 		// helper ln liblcl.dylib
-		cmd.Dir = filepath.Join(proj.ProjectPath, helperAppRoot, appContents, appContentsFrameworks)
-		cmd.Command("ln", "-shf", "../../../liblcl.dylib", "liblcl.dylib")
-		if err != nil {
+		helperLiblcl := filepath.Join(proj.ProjectPath, helperAppRoot, appContents, appContentsFrameworks, "liblcl.dylib")
+		os.Remove(helperLiblcl)
+		if err = os.Symlink("../../../liblcl.dylib", helperLiblcl); err != nil {
 			return err
 		}
 		// helper exe
@@ -287,7 +265,6 @@ func copyHelperFile(proj *project.Project, appRoot string) error {
 		helperMacOSExeFile.Close()
 		exeFile.Seek(0, 0)
 	}
-	cmd.Close()
 	return nil
 }
 
@@ -327,50 +304,15 @@ func generateICNS(proj *project.Project, appRoot string) error {
 	tmpWorkDir := filepath.Join(buildOutDir, "tmp")
 	os.Remove(tmpWorkDir)
 	if iconExt == ".png" {
-		term.Logger.Info("\tcreate icnns")
-		src, err := os.Open(proj.PList.Icon)
-		if err != nil {
-			return err
-		}
-		var closeSrc = func() {
-			if src != nil {
-				src.Close()
-				src = nil
-			}
-		}
-		defer closeSrc()
-		_, icnsName := filepath.Split(proj.PList.Icon)
+		// png => icns, 不依赖 sips 和 iconutil
+		term.Logger.Info("\tcreate icns")
 		os.MkdirAll(tmpWorkDir, fs.ModePerm)
-
-		outIconsetPath := filepath.Join(tmpWorkDir, "icons.iconset")
-		os.MkdirAll(outIconsetPath, fs.ModePerm)
-		tmpIconFilePath := filepath.Join(tmpWorkDir, icnsName)
-		dst, err := os.OpenFile(tmpIconFilePath, os.O_CREATE|os.O_WRONLY, 0755)
-		if err != nil {
-			return err
-		}
-		io.Copy(dst, src)
-		dst.Close()
-		closeSrc()
-		name := icnsName[:len(icnsName)-4]
-		// 生成图标
-		cmd := command.NewCMD()
-		cmd.Dir = tmpWorkDir
-		cmd.IsPrint = false
-		cmd.MessageCallback = func(bytes []byte, e error) {
-			if e != nil {
-				err = e
-			}
-		}
-		for _, arg := range sipsCmds {
-			cmd.Command("sips", strings.Split(fmt.Sprintf(arg, icnsName, name), " ")...)
-		}
-		cmd.Command("iconutil", strings.Split(fmt.Sprintf("-c icns icons.iconset -o %s.icns", name), " ")...)
-		cmd.Close()
-		if err != nil {
+		_, pngName := filepath.Split(proj.PList.Icon)
+		icnsFile := filepath.Join(tmpWorkDir, strings.TrimSuffix(pngName, filepath.Ext(pngName))+".icns")
+		if err := convertIcon(proj.PList.Icon, icnsFile, winicon.GenerateICNS); err != nil {
 			return err
 		}
-		proj.PList.Icon = filepath.Join(tmpWorkDir, name+".icns")
+		proj.PList.Icon = icnsFile
 	}
 	iconExt = strings.ToLower(filepath.Ext(proj.PList.Icon))
 	if iconExt == ".icns" {
@@ -0,0 +1,534 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/assets"
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	debControl        = deb + "/control"
+	debPreinit        = deb + "/preinit"
+	debPostinit       = deb + "/postinit"
+	debPrerm          = deb + "/prerm"
+	debPostrm         = deb + "/postrm"
+	usrSharApps       = "usr/share/applications"
+	optCompanyProduct = "opt/%s/%s"
+)
+
+const (
+	linuxDebControl = "linux/control"
+	linuxAppDesktop = "linux/app.desktop"
+	linuxARMStartup = "linux/startup.sh"
+	linuxAppRun     = "linux/AppRun"
+)
+
+// linux 安装包格式
+const (
+	linuxTargetDeb      = "deb"
+	linuxTargetRPM      = "rpm"
+	linuxTargetAppImage = "appimage"
+	linuxTargetTarGz    = "tar.gz"
+)
+
+func linuxInstaller(proj *project.Project) error {
+	for _, target := range proj.Dpkg.Targets {
+		switch target {
+		case linuxTargetDeb, linuxTargetRPM, linuxTargetAppImage, linuxTargetTarGz:
+		default:
+			return fmt.Errorf("unsupported linux package target: %s, options: deb, rpm, appimage, tar.gz", target)
+		}
+	}
+	// 创建构建输出目录
+	appRoot := fmt.Sprintf("linux/%s-%s", proj.Name, proj.Info.ProductVersion)
+	buildOutDir := assets.BuildOutPath(proj)
+	buildOutDir = filepath.Join(buildOutDir, appRoot)
+	if !tools.IsExist(buildOutDir) {
+		if err := os.MkdirAll(buildOutDir, 0755); err != nil {
+			return fmt.Errorf("unable to create directory: %w", err)
+		}
+	}
+	var err error
+	// create debian/control
+	if err = linuxControl(proj, appRoot); err != nil {
+		return err
+	}
+	// create debian/copyright
+	if err = linuxCopyright(proj, appRoot); err != nil {
+		return err
+	}
+	// create app.desktop
+	if err = linuxDesktop(proj, appRoot); err != nil {
+		return err
+	}
+	// copy source
+	if err = linuxOptCopy(proj, appRoot); err != nil {
+		return err
+	}
+	// copy linux arm startup.sh
+	if err = linuxARMStartupSH(proj, appRoot); err != nil {
+		return err
+	}
+	// 7zz 压缩 CEF
+	comper := proj.NSIS.Compress
+	switch comper {
+	case "7z", "7za":
+		proj.NSIS.UseCompress = tools.CommandExists(comper)
+	}
+	for _, target := range proj.Dpkg.Targets {
+		var outFile string
+		switch target {
+		case linuxTargetDeb:
+			outFile, err = linuxDeb(proj, appRoot)
+		case linuxTargetRPM:
+			outFile, err = linuxRPM(proj, appRoot)
+		case linuxTargetAppImage:
+			outFile, err = linuxAppImage(proj, appRoot)
+		case linuxTargetTarGz:
+			outFile, err = linuxTarGz(proj, appRoot)
+		}
+		if err != nil {
+			return err
+		}
+		// out log
+		switch target {
+		case linuxTargetDeb:
+			successLog := "Success \n\tInstall Package: %s\n\tInstall: sudo dpkg -i %s\n\tRemove:  sudo dpkg -r %s"
+			term.Section.Println(fmt.Sprintf(successLog, outFile, filepath.Base(outFile), proj.Dpkg.Package))
+		case linuxTargetRPM:
+			successLog := "Success \n\tInstall Package: %s\n\tInstall: sudo rpm -i %s\n\tRemove:  sudo rpm -e %s"
+			term.Section.Println(fmt.Sprintf(successLog, outFile, filepath.Base(outFile), proj.Dpkg.Package))
+		case linuxTargetAppImage:
+			term.Section.Println(fmt.Sprintf("Success \n\tAppImage: %s", outFile))
+		case linuxTargetTarGz:
+			term.Section.Println(fmt.Sprintf("Success \n\tPortable Package: %s", outFile))
+		}
+	}
+	return nil
+}
+
+// linuxDeb 生成 deb 安装包, 不依赖 dpkg 命令
+func linuxDeb(proj *project.Project, appRoot string) (string, error) {
+	dir := filepath.Join(assets.BuildOutPath(proj), "linux")
+	debName := fmt.Sprintf("%s-%s-%s.deb", proj.Name, proj.OS, proj.Arch)
+	outFile := filepath.Join(dir, debName)
+	term.Logger.Info("Generate dpkg package. Almost complete", term.Logger.Args("deb", debName))
+	os.Remove(outFile)
+	if err := writeDeb(filepath.Join(assets.BuildOutPath(proj), appRoot), outFile); err != nil {
+		return "", fmt.Errorf("failed to create deb package: %w", err)
+	}
+	return outFile, nil
+}
+
+// linuxRPM 生成 rpm 安装包, 不依赖 rpmbuild 命令
+func linuxRPM(proj *project.Project, appRoot string) (string, error) {
+	info := &rpmInfo{
+		Name:     proj.Dpkg.Package,
+		Version:  strings.ReplaceAll(proj.Info.ProductVersion, "-", "_"),
+		Release:  proj.Dpkg.Release,
+		Arch:     rpmArch(string(proj.Arch)),
+		Summary:  proj.Info.ProductName,
+		License:  proj.Dpkg.License,
+		Vendor:   proj.Info.CompanyName,
+		Packager: fmt.Sprintf("%s <%s>", proj.Author.Name, proj.Author.Email),
+		URL:      proj.Dpkg.Homepage,
+	}
+	if proj.Info.Comments != nil {
+		info.Description = *proj.Info.Comments
+	}
+	if info.Description == "" {
+		info.Description = info.Summary
+	}
+	if info.Release == "" {
+		info.Release = "1"
+	}
+	rpmName := fmt.Sprintf("%s-%s-%s.%s.rpm", proj.Name, info.Version, info.Release, info.Arch)
+	outFile := filepath.Join(assets.BuildOutPath(proj), "linux", rpmName)
+	term.Logger.Info("Generate rpm package. Almost complete", term.Logger.Args("rpm", rpmName))
+	os.Remove(outFile)
+	if err := writeRPM(info, filepath.Join(assets.BuildOutPath(proj), appRoot), outFile); err != nil {
+		return "", fmt.Errorf("failed to create rpm package: %w", err)
+	}
+	return outFile, nil
+}
+
+// linuxTarGz 生成免安装 tar.gz 包, 解压后直接运行
+func linuxTarGz(proj *project.Project, appRoot string) (string, error) {
+	prefix := fmt.Sprintf("%s-%s", proj.Name, proj.Info.ProductVersion)
+	tarName := fmt.Sprintf("%s-%s-%s.tar.gz", prefix, proj.OS, proj.Arch)
+	outFile := filepath.Join(assets.BuildOutPath(proj), "linux", tarName)
+	term.Logger.Info("Generate tar.gz package. Almost complete", term.Logger.Args("tar.gz", tarName))
+	optDir := filepath.Join(assets.BuildOutPath(proj), appRoot, fmt.Sprintf(optCompanyProduct, proj.Info.CompanyName, proj.Info.ProductName))
+	os.Remove(outFile)
+	if err := writeTarGz(optDir, prefix, outFile); err != nil {
+		return "", fmt.Errorf("failed to create tar.gz package: %w", err)
+	}
+	return outFile, nil
+}
+
+// linuxAppImage
+//	生成 AppImage 目录 [name].AppDir
+//	存在 appimagetool 命令时生成 [name]-[arch].AppImage
+func linuxAppImage(proj *project.Project, appRoot string) (string, error) {
+	buildOutDir := filepath.Join(assets.BuildOutPath(proj), "linux")
+	appDirName := fmt.Sprintf("%s.AppDir", proj.Name)
+	appDir := filepath.Join(buildOutDir, appDirName)
+	term.Logger.Info("Generate AppImage AppDir", term.Logger.Args("AppDir", appDirName))
+	os.RemoveAll(appDir)
+	// AppDir/usr/lib/[name]
+	libDir := filepath.Join(appDir, "usr", "lib", proj.Name)
+	if err := os.MkdirAll(libDir, 0755); err != nil {
+		return "", fmt.Errorf("unable to create directory: %w", err)
+	}
+	optDir := filepath.Join(assets.BuildOutPath(proj), appRoot, fmt.Sprintf(optCompanyProduct, proj.Info.CompanyName, proj.Info.ProductName))
+	if err := linkTree(optDir, libDir); err != nil {
+		return "", err
+	}
+	// AppDir/AppRun
+	appRunData, err := assets.ReadFile(proj, assetsFSPath, linuxAppRun)
+	if err != nil {
+		return "", err
+	}
+	data := make(map[string]interface{})
+	data["EXECUTE"] = proj.Name
+	sh := strings.NewReplacer("\r", "")
+	content, err := tools.RenderTemplate(sh.Replace(string(appRunData)), data)
+	if err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(filepath.Join(appDir, "AppRun"), content, 0755); err != nil {
+		return "", err
+	}
+	// AppDir/[name].desktop AppDir/[icon] AppDir/.DirIcon
+	_, icon := filepath.Split(proj.Info.Icon)
+	iconName := strings.TrimSuffix(icon, filepath.Ext(icon))
+	if content, err = linuxDesktopContent(proj, proj.Name, iconName); err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(filepath.Join(appDir, fmt.Sprintf("%s.desktop", proj.Name)), content, 0644); err != nil {
+		return "", err
+	}
+	if err = linkTree(filepath.Join(libDir, icon), filepath.Join(appDir, icon)); err != nil {
+		return "", err
+	}
+	if err = os.Symlink(icon, filepath.Join(appDir, ".DirIcon")); err != nil {
+		return "", err
+	}
+	if !tools.CommandExists("appimagetool") {
+		term.Logger.Info("appimagetool not found, skip AppImage, Only generate AppDir")
+		return appDir, nil
+	}
+	appImageName := fmt.Sprintf("%s-%s.AppImage", proj.Name, rpmArch(string(proj.Arch)))
+	outFile := filepath.Join(buildOutDir, appImageName)
+	term.Logger.Info("Generate AppImage. Almost complete", term.Logger.Args("AppImage", appImageName))
+	os.Remove(outFile)
+	// ARCH 只设置在 appimagetool 子进程, 不修改 energy 进程环境变量
+	cmd := exec.Command("appimagetool", appDirName, appImageName)
+	cmd.Dir = buildOutDir
+	cmd.Env = append(os.Environ(), "ARCH="+rpmArch(string(proj.Arch)))
+	cmd.Stderr = os.Stderr
+	if err = cmd.Run(); err != nil {
+		return "", fmt.Errorf("appimagetool: %w", err)
+	}
+	return outFile, nil
+}
+
+// linkTree 硬链接目录或文件, 失败时复制
+func linkTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		if os.Link(path, target) == nil {
+			return nil
+		}
+		srcFile, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer srcFile.Close()
+		dstFile, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+		if err != nil {
+			return err
+		}
+		defer dstFile.Close()
+		_, err = io.Copy(dstFile, srcFile)
+		return err
+	})
+}
+
+func opt(proj *project.Project) string {
+	return filepath.Join("/opt", proj.Info.CompanyName, proj.Info.ProductName)
+}
+
+func linuxOptCopy(proj *project.Project, appRoot string) error {
+	term.Logger.Info("Generate dpkg copy:",
+		term.Logger.Args("company", proj.Info.CompanyName, "product", proj.Info.ProductName, "opt",
+			fmt.Sprintf("/opt/%s/%s", proj.Info.CompanyName, proj.Info.ProductName)))
+	buildOutDir := assets.BuildOutPath(proj)
+	appDir := filepath.Join(buildOutDir, appRoot)
+	// app/opt/[company]/[product]
+	optDir := filepath.Join(appDir, fmt.Sprintf(optCompanyProduct, proj.Info.CompanyName, proj.Info.ProductName))
+	if err := os.MkdirAll(optDir, 0755); err != nil {
+		return fmt.Errorf("unable to create directory: %w", err)
+	}
+	exeDir := filepath.Join(proj.ProjectPath, proj.OutputFilename)
+	if !tools.IsExist(exeDir) {
+		return fmt.Errorf("execution file not found: %s", exeDir)
+	}
+	exeIconDir := proj.Info.Icon
+	if !tools.IsExist(exeIconDir) {
+		return fmt.Errorf("exeIcon file not found: %s", exeIconDir)
+	}
+
+	term.Logger.Info("Generate dpkg execution " + exeDir)
+	cefDir := proj.FrameworkPath
+	if !tools.IsExist(cefDir) {
+		return fmt.Errorf("energy framework not found: %s", cefDir)
+	}
+	term.Logger.Info("Generate dpkg framework " + cefDir)
+	var copyFiles = func(src, dst string) error {
+		if srcFile, err := os.Open(src); err != nil {
+			return err
+		} else {
+			st, err := srcFile.Stat()
+			if err != nil {
+				return err
+			}
+			if st.IsDir() {
+				srcFile.Close() //close
+				var pathLen = len(src)
+				err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+					if path == src { // current root
+						return nil
+					}
+					outPath := path[pathLen:]
+					// exclude file or dir
+					for _, p := range proj.Dpkg.Exclude {
+						if strings.Contains(outPath, p) {
+							return nil
+						}
+					}
+					targetPath := filepath.Join(dst, outPath)
+					info, _ := d.Info()
+					if d.IsDir() {
+						return os.MkdirAll(targetPath, info.Mode())
+					} else {
+						if tools.IsExistAndSize(targetPath, info.Size()) {
+							//term.Logger.Info("\tcopy skip: " + outPath)
+							return nil
+						}
+						srcFile, err := os.Open(path)
+						if err != nil {
+							return err
+						}
+						defer srcFile.Close()
+						dstFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY, info.Mode())
+						if err != nil {
+							return err
+						}
+						defer dstFile.Close()
+						//term.Logger.Info("\tcopy: " + outPath)
+						_, err = io.Copy(dstFile, srcFile)
+						return err
+					}
+				})
+				if err != nil {
+					return err
+				}
+			} else {
+				defer srcFile.Close()
+				dstFilePath := filepath.Join(dst, st.Name())
+				dstFile, err := os.OpenFile(dstFilePath, os.O_CREATE|os.O_WRONLY, st.Mode())
+				if err != nil {
+					return err
+				}
+				defer dstFile.Close()
+				_, err = io.Copy(dstFile, srcFile)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	term.Logger.Info("Generate dpkg copy:", term.Logger.Args("execution", exeDir))
+	if err := copyFiles(exeDir, optDir); err != nil {
+		return err
+	}
+	term.Logger.Info("Generate dpkg copy:", term.Logger.Args("icon", exeIconDir))
+	if err := copyFiles(exeIconDir, optDir); err != nil {
+		return err
+	}
+	term.Logger.Info("Generate dpkg copy:", term.Logger.Args("framework", cefDir))
+	if err := copyFiles(cefDir, optDir); err != nil {
+		return err
+	}
+	for _, include := range proj.Dpkg.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(proj.ProjectPath, include)
+		}
+		// 支持通配符 /to/dir/*.*
+		matches, err := filepath.Glob(include)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", include, err)
+		} else if len(matches) == 0 {
+			return fmt.Errorf("include %s: %w", include, fs.ErrNotExist)
+		}
+		for _, match := range matches {
+			term.Logger.Info("Generate dpkg copy:", term.Logger.Args("include", match))
+			if err = copyFiles(match, optDir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func linuxARMStartupSH(proj *project.Project, appRoot string) error {
+	if proj.Arch.IsARM64() {
+		term.Logger.Info("Generate dpkg startup.sh")
+		buildOutDir := assets.BuildOutPath(proj)
+		appDir := filepath.Join(buildOutDir, appRoot)
+		if startupshData, err := assets.ReadFile(proj, assetsFSPath, linuxARMStartup); err != nil {
+			return err
+		} else {
+			data := make(map[string]interface{})
+			data["INSTALLPATH"] = opt(proj)
+			data["EXECUTE"] = proj.Name
+			sh := strings.NewReplacer("\r", "")
+			if content, err := tools.RenderTemplate(sh.Replace(string(startupshData)), data); err != nil {
+				return err
+			} else {
+				optDir := opt(proj)
+				outFilePath := filepath.Join(appDir, optDir, fmt.Sprintf("%s.sh", proj.Name))
+				outFile, err := os.OpenFile(outFilePath, os.O_CREATE|os.O_WRONLY, 0755)
+				if err != nil {
+					return err
+				}
+				defer outFile.Close()
+				outFile.Write(content)
+			}
+
+		}
+	}
+	return nil
+}
+
+func linuxDesktop(proj *project.Project, appRoot string) error {
+	term.Logger.Info("Generate dpkg desktop")
+	buildOutDir := assets.BuildOutPath(proj)
+	appDir := filepath.Join(buildOutDir, appRoot)
+	// app/usr/share/applications
+	apps := filepath.Join(appDir, usrSharApps)
+	if err := os.MkdirAll(apps, 0755); err != nil {
+		return fmt.Errorf("unable to create directory: %w", err)
+	}
+	optDir := opt(proj)
+	_, icon := filepath.Split(proj.Info.Icon)
+	icon = filepath.Join(optDir, icon)
+	// 图标主题 usr/share/icons/hicolor, .desktop 使用图标名称
+	if hicolor, err := linuxHicolor(proj, appRoot); err != nil {
+		return err
+	} else if hicolor {
+		icon = proj.Name
+	}
+	startup := proj.Name
+	if proj.Arch.IsARM64() {
+		startup += ".sh"
+	}
+	if content, err := linuxDesktopContent(proj, filepath.Join(optDir, startup), icon); err != nil {
+		return err
+	} else {
+		debControlFile := filepath.Join(appRoot, usrSharApps, fmt.Sprintf("%s.desktop", proj.Name))
+		if err = assets.WriteFile(proj, debControlFile, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linuxDesktopContent 生成 .desktop 文件内容
+func linuxDesktopContent(proj *project.Project, exec, icon string) ([]byte, error) {
+	desktopData, err := assets.ReadFile(proj, assetsFSPath, linuxAppDesktop)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]interface{})
+	data["Name"] = proj.Name
+	data["Exec"] = exec
+	data["Icon"] = icon
+	data["Comments"] = proj.Info.Comments
+	return tools.RenderTemplate(string(desktopData), data)
+}
+
+func linuxCopyright(proj *project.Project, appRoot string) error {
+	term.Logger.Info("Generate dpkg copyright")
+	return nil
+}
+
+func linuxControl(proj *project.Project, appRoot string) error {
+	term.Logger.Info("Generate dpkg control")
+	buildOutDir := assets.BuildOutPath(proj)
+	appDir := filepath.Join(buildOutDir, appRoot)
+	// DEBIAN app/DEBIAN
+	debDir := filepath.Join(appDir, deb)
+	if err := os.MkdirAll(debDir, 0755); err != nil {
+		return fmt.Errorf("unable to create directory: %w", err)
+	}
+	if controlData, err := assets.ReadFile(proj, assetsFSPath, linuxDebControl); err != nil {
+		return err
+	} else {
+		data := make(map[string]interface{})
+		data["Arch"] = debArch(string(proj.Arch))
+		data["Info"] = proj.Info
+		data["Author"] = proj.Author
+		data["Dpkg"] = proj.Dpkg
+		if content, err := tools.RenderTemplate(string(controlData), data); err != nil {
+			return err
+		} else {
+			debControlFile := filepath.Join(appRoot, debControl)
+			if err = assets.WriteFile(proj, debControlFile, content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
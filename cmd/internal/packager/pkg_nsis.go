@@ -0,0 +1,349 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/assets"
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"github.com/energye/golcl/tools/command"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	windowsNsis      = "windows/installer-nsis.nsi"
+	windowsNsisTools = "windows/installer-tools.nsh"
+)
+
+func windowsInstaller(proj *project.Project) error {
+	var err error
+	// 创建构建输出目录
+	buildOutDir := assets.BuildOutPath(proj)
+	buildOutDir = filepath.Join(buildOutDir, "windows")
+	if !tools.IsExist(buildOutDir) {
+		if err := os.MkdirAll(buildOutDir, 0755); err != nil {
+			return fmt.Errorf("unable to create directory: %w", err)
+		}
+	}
+	// 7za 压缩 CEF
+	comper := proj.NSIS.Compress
+	switch comper {
+	case "7z", "7za":
+		proj.NSIS.UseCompress = tools.CommandExists(comper)
+	}
+	if proj.NSIS.UseCompress {
+		if cef7zFile, err := compressCEF7za(proj); err != nil {
+			return err
+		} else {
+			proj.NSIS.CompressFile = cef7zFile
+		}
+	}
+
+	// 生成 nsis 脚本
+	if err = windows(proj); err != nil {
+		return err
+	}
+
+	// make
+	var outInstall string
+	if outInstall, err = makeNSIS(proj); err != nil {
+		return err
+	}
+	term.Section.Println("Success \n\tInstall package:", outInstall)
+	return nil
+}
+
+func compressCEF7za(proj *project.Project) (string, error) {
+	term.Logger.Info("7za compress " + proj.NSIS.CompressName + ", This may take some time")
+	buildWindowsPath := filepath.Join(assets.BuildOutPath(proj), "windows")
+	outFilePath := filepath.FromSlash(filepath.Join(buildWindowsPath, proj.NSIS.CompressName))
+	if proj.Clean {
+		os.Remove(outFilePath)
+	} else if tools.IsExist(outFilePath) {
+		term.Logger.Info(proj.NSIS.CompressName + " file exist")
+		return outFilePath, nil
+	}
+
+	wd := tools.CurrentExecuteDir()
+	defer func() {
+		os.Chdir(wd)
+	}()
+	err := os.Chdir(proj.FrameworkPath)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := command.NewCMD()
+	cmd.IsPrint = false
+	var args = []string{"a", outFilePath, filepath.FromSlash(fmt.Sprintf("%s/*", proj.FrameworkPath))}
+	for _, exc := range proj.NSIS.Exclude {
+		args = append(args, "-xr!"+exc)
+	}
+	cmd.Command(proj.NSIS.Compress, args...)
+	cmd.Close()
+	return outFilePath, nil
+}
+
+func windows(proj *project.Project) error {
+	term.Logger.Info("Generate NSIS script")
+	// 生成安装生成配置文件 nsis.nsi
+	if nsisData, err := assets.ReadFile(proj, assetsFSPath, windowsNsis); err != nil {
+		return err
+	} else {
+		if err = assets.WriteFile(proj, windowsNsis, nsisData); err != nil {
+			return err
+		}
+	}
+	// tools.nsh
+	if toolsData, err := assets.ReadFile(proj, assetsFSPath, windowsNsisTools); err != nil {
+		return err
+	} else {
+		data := make(map[string]interface{})
+		data["Name"] = proj.Name
+		data["ProjectPath"] = filepath.FromSlash(proj.ProjectPath)
+		data["FrameworkPath"] = filepath.FromSlash(proj.FrameworkPath)
+		// NSIS 只支持 ico 图标, png 图标转换为 ico
+		if proj.NSIS.Icon, err = windowsICO(proj, proj.NSIS.Icon, "installer"); err != nil {
+			return err
+		}
+		if proj.NSIS.UnIcon, err = windowsICO(proj, proj.NSIS.UnIcon, "uninstaller"); err != nil {
+			return err
+		}
+		proj.Info.FromSlash()
+		proj.NSIS.FromSlash()
+		if err = nsisOptions(proj, data); err != nil {
+			return err
+		}
+		data["Info"] = proj.Info
+		data["NSIS"] = proj.NSIS
+		if content, err := tools.RenderTemplate(string(toolsData), data); err != nil {
+			return err
+		} else if err = assets.WriteFile(proj, windowsNsisTools, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nsisSection 安装包组件, optional 时在组件页选择
+type nsisSection struct {
+	Title  string
+	Option string
+	Lines  []string
+}
+
+// nsisPrerequisite 安装前检查, If 条件成立时提示并退出安装
+type nsisPrerequisite struct {
+	Comment string
+	Before  []string
+	If      string
+	Message string
+	URL     string
+}
+
+// nsisAssociation 文件类型关联
+type nsisAssociation struct {
+	Ext         string
+	ProgID      string
+	Description string
+	Icon        string
+}
+
+// nsisWindowsVersions 最低 Windows 版本, WinVer.nsh
+var nsisWindowsVersions = map[string]string{
+	"7":   "AtLeastWin7",
+	"8":   "AtLeastWin8",
+	"8.1": "AtLeastWin8.1",
+	"10":  "AtLeastWin10",
+	"11":  "AtLeastWin11",
+}
+
+// nsisRegistryRoots 注册表根键
+var nsisRegistryRoots = map[string]string{
+	"HKLM": "HKLM", "HKEY_LOCAL_MACHINE": "HKLM",
+	"HKCU": "HKCU", "HKEY_CURRENT_USER": "HKCU",
+	"HKCR": "HKCR", "HKEY_CLASSES_ROOT": "HKCR",
+	"HKU": "HKU", "HKEY_USERS": "HKU",
+}
+
+// nsisString 转义 NSIS 字符串
+func nsisString(s string) string {
+	return strings.NewReplacer("$", "$$", `"`, `$\"`, "\r", "", "\n", `$\r$\n`).Replace(s)
+}
+
+// nsisPathConstants 文件路径中可以使用的 NSIS 目录常量
+var nsisPathConstants = []string{
+	"PROGRAMFILES64", "PROGRAMFILES32", "PROGRAMFILES", "COMMONFILES64", "COMMONFILES32", "COMMONFILES",
+	"LOCALAPPDATA", "APPDATA", "SYSDIR", "WINDIR", "TEMP", "INSTDIR", "EXEDIR", "PROFILE", "DOCUMENTS",
+}
+
+// nsisPath 转义 NSIS 文件路径, 保留开头的目录常量 $SYSDIR\xxx.dll, 其它 $ 和 " 按字符串转义
+func nsisPath(s string) string {
+	for _, name := range nsisPathConstants {
+		prefix := "$" + name
+		if s == prefix || strings.HasPrefix(s, prefix+`\`) || strings.HasPrefix(s, prefix+"/") {
+			return prefix + nsisString(s[len(prefix):])
+		}
+	}
+	return nsisString(s)
+}
+
+func checkNSISOption(name, value string) error {
+	switch value {
+	case project.NSISYes, project.NSISOptional, project.NSISNo:
+		return nil
+	}
+	return fmt.Errorf("invalid nsis %s '%s', should be: yes, optional, no", name, value)
+}
+
+// nsisOptions
+//	安装方式、快捷方式、开机启动、文件类型关联、安装前检查和卸载清理的模板数据
+func nsisOptions(proj *project.Project, data map[string]interface{}) error {
+	nsis := &proj.NSIS
+	switch nsis.InstallMode {
+	case "machine", "user", "both":
+	default:
+		return fmt.Errorf("invalid nsis installMode '%s', should be: machine, user, both", nsis.InstallMode)
+	}
+	for _, option := range [][2]string{{"shortcuts.startMenu", nsis.Shortcuts.StartMenu}, {"shortcuts.desktop", nsis.Shortcuts.Desktop},
+		{"autoStart", nsis.AutoStart}, {"uninstall.removeUserData", nsis.Uninstall.RemoveUserData}} {
+		if err := checkNSISOption(option[0], option[1]); err != nil {
+			return err
+		}
+	}
+	// 快捷方式和开机启动
+	autoStart := `$\"$INSTDIR\${PRODUCT_EXECUTABLE}$\"`
+	if nsis.AutoStartArgs != "" {
+		autoStart += " " + nsisString(nsis.AutoStartArgs)
+	}
+	var (
+		sections = []nsisSection{
+			{Title: "Start Menu Shortcut", Option: nsis.Shortcuts.StartMenu,
+				Lines: []string{`CreateShortcut "$SMPROGRAMS\${INFO_ProductName}.lnk" "$INSTDIR\${PRODUCT_EXECUTABLE}"`}},
+			{Title: "Desktop Shortcut", Option: nsis.Shortcuts.Desktop,
+				Lines: []string{`CreateShortcut "$DESKTOP\${INFO_ProductName}.lnk" "$INSTDIR\${PRODUCT_EXECUTABLE}"`}},
+			{Title: "Start on Login", Option: nsis.AutoStart,
+				Lines: []string{`WriteRegStr SHCTX "${AUTOSTART_KEY}" "${INFO_ProductName}" "` + autoStart + `"`}},
+		}
+		enabled    []nsisSection
+		components bool
+	)
+	for _, section := range sections {
+		if section.Option != project.NSISNo {
+			enabled = append(enabled, section)
+			components = components || section.Option == project.NSISOptional
+		}
+	}
+	// 文件类型关联
+	var associations []nsisAssociation
+	for _, as := range nsis.Associations {
+		ext := strings.TrimPrefix(as.Ext, ".")
+		if ext == "" || strings.ContainsAny(ext, `\/"$ `) {
+			return fmt.Errorf("invalid nsis association ext '%s'", as.Ext)
+		}
+		association := nsisAssociation{Ext: "." + ext, ProgID: as.Name, Description: nsisString(as.Description)}
+		if association.ProgID == "" {
+			association.ProgID = proj.Name + "." + ext
+		}
+		if association.Description == "" {
+			association.Description = strings.ToUpper(ext) + " File"
+		}
+		if as.Icon != "" {
+			icon, err := windowsICO(proj, as.Icon, "file-"+ext)
+			if err != nil {
+				return err
+			}
+			association.Icon = filepath.FromSlash(icon)
+		}
+		associations = append(associations, association)
+	}
+	// 安装前检查
+	var prerequisites []nsisPrerequisite
+	for _, pre := range nsis.Prerequisites {
+		prerequisite := nsisPrerequisite{Comment: strings.NewReplacer("\r", " ", "\n", " ").Replace(pre.Type + " " + pre.Value), Message: nsisString(pre.Message), URL: nsisString(pre.URL)}
+		name := pre.Name
+		switch pre.Type {
+		case "windows":
+			macro, ok := nsisWindowsVersions[pre.Value]
+			if !ok {
+				return fmt.Errorf("invalid nsis prerequisite windows version '%s', should be: 7, 8, 8.1, 10, 11", pre.Value)
+			}
+			prerequisite.If = "${IfNot} ${" + macro + "}"
+			if name == "" {
+				name = "Windows " + pre.Value + " or later"
+			}
+		case "x64":
+			prerequisite.If = "${IfNot} ${RunningX64}"
+			if name == "" {
+				name = "64-bit Windows"
+			}
+		case "registry":
+			key := strings.Replace(pre.Value, "/", `\`, -1)
+			index := strings.Index(key, `\`)
+			if index == -1 || nsisRegistryRoots[strings.ToUpper(key[:index])] == "" {
+				return fmt.Errorf("invalid nsis prerequisite registry key '%s', example: HKLM\\SOFTWARE\\...", pre.Value)
+			}
+			// 64 位注册表项存在
+			prerequisite.Before = []string{
+				`StrCpy $1 "1"`,
+				"SetRegView 64",
+				"ClearErrors",
+				fmt.Sprintf(`EnumRegKey $0 %s "%s" 0`, nsisRegistryRoots[strings.ToUpper(key[:index])], nsisString(key[index+1:])),
+				"${If} ${Errors}",
+				`    StrCpy $1 ""`,
+				"${EndIf}",
+				"SetRegView default",
+			}
+			prerequisite.If = `${If} $1 == ""`
+		case "file":
+			if pre.Value == "" {
+				return fmt.Errorf("nsis prerequisite file is empty")
+			}
+			prerequisite.If = `${IfNot} ${FileExists} "` + nsisPath(pre.Value) + `"`
+		default:
+			return fmt.Errorf("invalid nsis prerequisite type '%s', should be: windows, x64, registry, file", pre.Type)
+		}
+		if prerequisite.Message == "" {
+			if name == "" {
+				name = pre.Value
+			}
+			prerequisite.Message = "${INFO_ProductName} requires " + nsisString(name)
+		}
+		prerequisites = append(prerequisites, prerequisite)
+	}
+	data["Sections"] = enabled
+	data["Components"] = components
+	data["Associations"] = associations
+	data["Prerequisites"] = prerequisites
+	return nil
+}
+
+// 使用nsis生成安装包
+func makeNSIS(proj *project.Project) (string, error) {
+	installPackage := proj.Name + "-installer.exe"
+	term.Logger.Info("NSIS Making Installation, Almost complete", term.Logger.Args("Install Package", installPackage))
+	var args []string
+	cmd := command.NewCMD()
+	cmd.IsPrint = false
+	cmd.Dir = proj.ProjectPath
+	cmd.MessageCallback = func(bytes []byte, err error) {
+		println("makensis:", string(bytes))
+	}
+	nsisScriptPath := filepath.Join(assets.BuildOutPath(proj), windowsNsis)
+	args = append(args, nsisScriptPath)
+	cmd.Command("makensis", args...)
+	outInstall := filepath.Join(filepath.Dir(nsisScriptPath), installPackage)
+	return outInstall, nil
+}
@@ -0,0 +1,136 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testNSISProject(t *testing.T) *project.Project {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "build", "windows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	proj := &project.Project{Name: "demo", ProjectPath: dir, OS: "windows", Arch: "amd64", Clean: true}
+	proj.Info.ProductName = "Demo"
+	proj.NSIS = project.NSIS{
+		Language:      "English",
+		InstallMode:   "both",
+		Shortcuts:     project.NSISShortcuts{StartMenu: project.NSISYes, Desktop: project.NSISOptional},
+		AutoStart:     project.NSISOptional,
+		AutoStartArgs: "--hidden",
+		Associations:  []project.NSISAssociation{{Ext: ".demo", Description: `Demo "document"`}},
+		Prerequisites: []project.NSISPrerequisite{
+			{Type: "windows", Value: "10"},
+			{Type: "registry", Value: `HKLM\SOFTWARE\Microsoft\VisualStudio\14.0\VC\Runtimes\x64`, Name: "Microsoft Visual C++ Redistributable",
+				URL: "https://aka.ms/vs/17/release/vc_redist.x64.exe"},
+			{Type: "file", Value: `$SYSDIR\vc"$x.dll`, Name: "vc"},
+		},
+		Uninstall: project.NSISUninstall{RemoveUserData: project.NSISOptional, UserData: []string{`$APPDATA\demo`, `$LOCALAPPDATA\demo`}},
+	}
+	return proj
+}
+
+func TestNSISScript(t *testing.T) {
+	proj := testNSISProject(t)
+	if err := windows(proj); err != nil {
+		t.Fatal(err)
+	}
+	tools, err := os.ReadFile(filepath.Join(proj.ProjectPath, "build", filepath.FromSlash(windowsNsisTools)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nsi, err := os.ReadFile(filepath.Join(proj.ProjectPath, "build", filepath.FromSlash(windowsNsis)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		// install mode
+		"!define MULTIUSER_EXECUTIONLEVEL Highest",
+		"!define ENERGY_PAGE_INSTALLMODE",
+		`!include "MultiUser.nsh"`,
+		"!define ENERGY_PAGE_COMPONENTS",
+		`WriteRegStr SHCTX "${UNINST_KEY}" "InstallLocation" "$INSTDIR"`,
+		// shortcuts and auto start
+		"Section \"-Start Menu Shortcut\"\n    CreateShortcut \"$SMPROGRAMS\\${INFO_ProductName}.lnk\"",
+		"Section \"Desktop Shortcut\"\n    CreateShortcut \"$DESKTOP\\${INFO_ProductName}.lnk\"",
+		`WriteRegStr SHCTX "${AUTOSTART_KEY}" "${INFO_ProductName}" "$\"$INSTDIR\${PRODUCT_EXECUTABLE}$\" --hidden"`,
+		// file associations
+		`WriteRegStr SHCTX "Software\Classes\.demo" "" "demo.demo"`,
+		`WriteRegStr SHCTX "Software\Classes\demo.demo" "" "Demo $\"document$\""`,
+		`WriteRegStr SHCTX "Software\Classes\demo.demo\DefaultIcon" "" "$INSTDIR\${PRODUCT_EXECUTABLE},0"`,
+		`WriteRegStr SHCTX "Software\Classes\demo.demo\shell\open\command" "" "$\"$INSTDIR\${PRODUCT_EXECUTABLE}$\" $\"%1$\""`,
+		`    ${If} $0 == "demo.demo"`,
+		// prerequisites
+		"${IfNot} ${AtLeastWin10}\n        !insertmacro energy.prerequisiteFailed \"${INFO_ProductName} requires Windows 10 or later\" \"\"",
+		`EnumRegKey $0 HKLM "SOFTWARE\Microsoft\VisualStudio\14.0\VC\Runtimes\x64" 0`,
+		`!insertmacro energy.prerequisiteFailed "${INFO_ProductName} requires Microsoft Visual C++ Redistributable" "https://aka.ms/vs/17/release/vc_redist.x64.exe"`,
+		`${IfNot} ${FileExists} "$SYSDIR\vc$\"$$x.dll"`,
+		// uninstall cleanup
+		`IDNO energy_keepUserData`,
+		"    SetShellVarContext current\n    RMDir /r \"$APPDATA\\demo\"\n    RMDir /r \"$LOCALAPPDATA\\demo\"",
+	} {
+		if !strings.Contains(string(tools), want) {
+			t.Errorf("installer-tools.nsh does not contain:\n%s", want)
+		}
+	}
+	for _, want := range []string{
+		"!insertmacro MULTIUSER_PAGE_INSTALLMODE",
+		"!insertmacro MUI_PAGE_COMPONENTS",
+		"!insertmacro energy.onInit",
+		"!insertmacro energy.unOnInit",
+		"!insertmacro energy.associations",
+		"!insertmacro energy.sections",
+		"!insertmacro energy.removeUserData",
+		"!insertmacro energy.unregister",
+	} {
+		if !strings.Contains(string(nsi), want) {
+			t.Errorf("installer-nsis.nsi does not contain:\n%s", want)
+		}
+	}
+	if t.Failed() {
+		t.Log(string(tools))
+	}
+}
+
+func TestNSISScriptDefaults(t *testing.T) {
+	proj := testNSISProject(t)
+	proj.NSIS.InstallMode = "user"
+	proj.NSIS.Shortcuts.Desktop = project.NSISNo
+	proj.NSIS.AutoStart = project.NSISNo
+	proj.NSIS.Associations = nil
+	proj.NSIS.Prerequisites = nil
+	proj.NSIS.Uninstall.RemoveUserData = project.NSISNo
+	if err := windows(proj); err != nil {
+		t.Fatal(err)
+	}
+	tools, err := os.ReadFile(filepath.Join(proj.ProjectPath, "build", filepath.FromSlash(windowsNsisTools)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"ENERGY_PAGE_COMPONENTS", "ENERGY_PAGE_INSTALLMODE", "Desktop Shortcut", "AUTOSTART_KEY}\" \"${INFO_ProductName}\" \"", "RMDir", "SHChangeNotify"} {
+		if strings.Contains(string(tools), text) {
+			t.Errorf("installer-tools.nsh contains: %s", text)
+		}
+	}
+	if !strings.Contains(string(tools), "!define MULTIUSER_EXECUTIONLEVEL Standard") {
+		t.Error("install mode user")
+	}
+
+	proj.NSIS.AutoStart = "always"
+	if err = windows(proj); err == nil || !strings.Contains(err.Error(), "autoStart") {
+		t.Fatalf("invalid option: %v", err)
+	}
+}
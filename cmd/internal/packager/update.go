@@ -0,0 +1,182 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/assets"
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"github.com/energye/energy/v2/cmd/internal/term"
+	"github.com/energye/energy/v2/cmd/internal/tools"
+	"github.com/energye/energy/v2/pkgs/update"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// updatePrivateKeyEnv 更新包签名私钥环境变量, 优先于 energy.json update.privateKey
+const updatePrivateKeyEnv = "ENERGY_UPDATE_PRIVATE_KEY"
+
+// updateArtifacts
+//	生成应用更新包、差异包和更新源 feed.json 到 build/update
+//	更新包包含执行文件和 update.include, 差异包从 feed.json 记录的旧版本生成, 旧版本更新包需要在 build/update 内
+func updateArtifacts(proj *project.Project) error {
+	if proj.Update.Feed == "" {
+		return nil
+	}
+	term.Logger.Info("Generate update package", term.Logger.Args("feed", proj.Update.Feed))
+	privateKey, err := updatePrivateKey(proj)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(assets.BuildOutPath(proj), "update")
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create directory: %w", err)
+	}
+	feedFile := filepath.Join(dir, update.FeedFileName)
+	feed := &update.Feed{}
+	if data, err := ioutil.ReadFile(feedFile); err == nil {
+		if err = json.Unmarshal(data, feed); err != nil {
+			return fmt.Errorf("invalid %s: %w", feedFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	feed.Name = proj.Name
+	if feed.Platforms == nil {
+		feed.Platforms = make(map[string]*update.Release)
+	}
+	var (
+		platform = string(proj.OS) + "/" + string(proj.Arch)
+		version  = proj.Info.ProductVersion
+		pkgFile  = filepath.Join(dir, updatePackageName(proj, version))
+		files    = append([]string{proj.OutputFilename}, proj.Update.Include...)
+		buf      bytes.Buffer
+	)
+	if err = update.WritePackage(&buf, proj.ProjectPath, files); err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(pkgFile, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	release := &update.Release{Version: version, Notes: proj.Update.Notes, Date: time.Now().UTC().Format(time.RFC3339)}
+	statement := &update.Statement{Name: feed.Name, Version: version, Platform: platform}
+	if release.Artifact, err = signUpdateArtifact(pkgFile, privateKey, statement); err != nil {
+		return err
+	}
+	// 差异包: 新版本从上一个版本生成, 重新打包相同版本时重新生成原有的差异包
+	var froms []string
+	if prev := feed.Platforms[platform]; prev != nil {
+		if update.CompareVersion(prev.Version, version) < 0 {
+			froms = append(froms, prev.Version)
+		} else if update.CompareVersion(prev.Version, version) == 0 {
+			for _, patch := range prev.Patches {
+				froms = append(froms, patch.From)
+			}
+		}
+	}
+	for _, from := range froms {
+		fromFile := filepath.Join(dir, updatePackageName(proj, from))
+		if !tools.IsExist(fromFile) {
+			term.Logger.Warn("Previous update package not found, skip patch", term.Logger.Args("file", fromFile))
+			continue
+		}
+		buf.Reset()
+		count, err := update.WritePatch(&buf, fromFile, pkgFile)
+		if err != nil {
+			return err
+		}
+		patchFile := filepath.Join(dir, fmt.Sprintf("%s-%s-%s-%s-%s.patch.zip", proj.Name, from, version, proj.OS, proj.Arch))
+		if err = ioutil.WriteFile(patchFile, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		patch := &update.Patch{From: from}
+		statement.From = from
+		if patch.Artifact, err = signUpdateArtifact(patchFile, privateKey, statement); err != nil {
+			return err
+		}
+		release.Patches = append(release.Patches, patch)
+		term.Logger.Info("Update patch", term.Logger.Args("from", from, "files", count, "size", patch.Size, "file", patchFile))
+	}
+	feed.Platforms[platform] = release
+	data, err := json.MarshalIndent(feed, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(feedFile, data, 0644); err != nil {
+		return err
+	}
+	publicKey, err := update.PublicKey(privateKey)
+	if err != nil {
+		return err
+	}
+	term.Section.Println(fmt.Sprintf("Success \n\tUpdate package: %s\n\tFeed: %s\n\tUpload %s to %s\n\tUpdater PublicKey: %s",
+		pkgFile, feedFile, dir, proj.Update.Feed, publicKey))
+	return nil
+}
+
+// updatePackageName 更新包文件名 [name]-[version]-[os]-[arch].zip
+func updatePackageName(proj *project.Project, version string) string {
+	return fmt.Sprintf("%s-%s-%s-%s.zip", proj.Name, version, proj.OS, proj.Arch)
+}
+
+// signUpdateArtifact 计算更新包大小、sha256, 和 statement 的名称、版本、系统架构一起签名, url 为文件名, 相对 feed.json
+func signUpdateArtifact(file, privateKey string, statement *update.Statement) (update.Artifact, error) {
+	var artifact update.Artifact
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return artifact, err
+	}
+	sum := sha256.Sum256(data)
+	artifact.URL = filepath.Base(file)
+	artifact.Size = int64(len(data))
+	artifact.SHA256 = hex.EncodeToString(sum[:])
+	statement.Size, statement.SHA256 = artifact.Size, artifact.SHA256
+	artifact.Signature, err = update.Sign(privateKey, statement)
+	return artifact, err
+}
+
+// updatePrivateKey
+//	返回更新包签名私钥, 环境变量 ENERGY_UPDATE_PRIVATE_KEY 优先
+//	私钥文件不存在时生成, 公钥写入 [privateKey].pub
+func updatePrivateKey(proj *project.Project) (string, error) {
+	if key := strings.TrimSpace(os.Getenv(updatePrivateKeyEnv)); key != "" {
+		return key, nil
+	}
+	file := proj.Update.PrivateKey
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(proj.ProjectPath, file)
+	}
+	if data, err := ioutil.ReadFile(file); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	publicKey, privateKey, err := update.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+	if err = ioutil.WriteFile(file, []byte(privateKey), 0600); err != nil {
+		return "", err
+	}
+	if err = ioutil.WriteFile(file+".pub", []byte(publicKey), 0644); err != nil {
+		return "", err
+	}
+	term.Logger.Warn("Generate update signing key, keep the private key secret and out of version control",
+		term.Logger.Args("privateKey", file, "publicKey", publicKey))
+	return privateKey, nil
+}
@@ -0,0 +1,62 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package packager
+
+import (
+	"encoding/json"
+	"github.com/energye/energy/v2/cmd/internal/project"
+	"github.com/energye/energy/v2/pkgs/update"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpdateArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	proj := &project.Project{Name: "demo", ProjectPath: dir, OS: "linux", Arch: "amd64", OutputFilename: "demo"}
+	proj.Update = project.Update{Feed: "https://example.com/update/feed.json", PrivateKey: "update.key", Include: []string{"resources"}}
+	os.MkdirAll(filepath.Join(dir, "resources"), 0755)
+	os.WriteFile(filepath.Join(dir, "resources", "index.html"), []byte("index"), 0644)
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		proj.Info.ProductVersion = version
+		os.WriteFile(filepath.Join(dir, "demo"), []byte(strings.Repeat("binary ", 1000)+version), 0755)
+		if err := updateArtifacts(proj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "build", "update", update.FeedFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feed update.Feed
+	if err = json.Unmarshal(data, &feed); err != nil {
+		t.Fatal(err)
+	}
+	release := feed.Platforms["linux/amd64"]
+	if release == nil || release.Version != "1.1.0" || release.URL != "demo-1.1.0-linux-amd64.zip" {
+		t.Fatalf("release: %+v", release)
+	}
+	patch := release.Patch("1.0.0")
+	if patch == nil || patch.URL != "demo-1.0.0-1.1.0-linux-amd64.patch.zip" {
+		t.Fatalf("patch: %+v", release.Patches)
+	}
+	publicKey, err := os.ReadFile(filepath.Join(dir, "update.key.pub"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for from, artifact := range map[string]update.Artifact{"": release.Artifact, "1.0.0": patch.Artifact} {
+		statement := &update.Statement{Name: "demo", Version: "1.1.0", Platform: "linux/amd64", From: from, Size: artifact.Size, SHA256: artifact.SHA256}
+		if err = update.Verify(string(publicKey), statement, artifact.Signature); err != nil {
+			t.Fatalf("%s: %v", artifact.URL, err)
+		}
+	}
+}
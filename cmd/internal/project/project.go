@@ -12,7 +12,11 @@ package project
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/cmd/internal/command"
 	"github.com/energye/energy/v2/cmd/internal/consts"
+	"github.com/energye/energy/v2/cmd/internal/framework"
 	"github.com/energye/energy/v2/cmd/internal/tools"
 	"io/ioutil"
 	"os"
@@ -30,22 +34,28 @@ const (
 
 // Project holds the data related to a ENERGY project
 type Project struct {
-	AppType        AppType `json:"-"`              // app, helper
-	Clean          bool    `json:"-"`              // 清空配置重新生成
-	Name           string  `json:"name"`           // 应用名称
-	ProjectPath    string  `json:"projectPath"`    // 项目目录
-	FrameworkPath  string  `json:"frameworkPath"`  // 框架目录 未指定时使用环境变量 ENERGY_HOME
-	AssetsDir      string  `json:"assetsDir"`      // 构建配置所在目录 未指定使用田默认内置配置
-	OutputFilename string  `json:"outputFilename"` // 输出安装包文件名
-	LibEMFS        string  `json:"libemfs"`        // 内置libs存放目录, 以项目目录根目录开始 ProjectPath + Libs = liblcl.dll 目录, 默认libs
-	Info           Info    `json:"info"`           // 应用信息
-	NSIS           NSIS    `json:"nsis"`           // windows nsis 安装包
-	Dpkg           DPKG    `json:"dpkg"`           // linux dpkg 安装包
-	PList          PList   `json:"plist"`          // darwin plist 安装包
-	Author         Author  `json:"author"`         // 作者信息
-}
-
-func (m *Project) setDefaults() {
+	AppType        AppType      `json:"-"`              // app, helper
+	Clean          bool         `json:"-"`              // 清空配置重新生成
+	OS             command.OS   `json:"-"`              // 目标系统, 默认当前系统
+	Arch           command.Arch `json:"-"`              // 目标架构, 默认当前架构
+	Name           string       `json:"name"`           // 应用名称
+	ProjectPath    string       `json:"projectPath"`    // 项目目录
+	FrameworkPath  string       `json:"frameworkPath"`  // 框架目录 未指定时使用环境变量 ENERGY_HOME
+	CEF            string       `json:"cef"`            // 框架版本, 从 energy install 安装的框架中选择, 例如 109, frameworkPath 优先
+	AssetsDir      string       `json:"assetsDir"`      // 构建配置所在目录 未指定使用田默认内置配置
+	OutputFilename string       `json:"outputFilename"` // 输出安装包文件名
+	LibEMFS        string       `json:"libemfs"`        // 内置libs存放目录, 以项目目录根目录开始 ProjectPath + Libs = liblcl.dll 目录, 默认libs
+	Info           Info         `json:"info"`           // 应用信息
+	NSIS           NSIS         `json:"nsis"`           // windows nsis 安装包
+	Dpkg           DPKG         `json:"dpkg"`           // linux dpkg 安装包
+	PList          PList        `json:"plist"`          // darwin plist 安装包
+	Author         Author       `json:"author"`         // 作者信息
+	Build          Build        `json:"build"`          // energy build 编译
+	Dev            Dev          `json:"dev"`            // energy dev 开发模式
+	Update         Update       `json:"update"`         // 应用更新, energy package 生成更新源和更新包
+}
+
+func (m *Project) setDefaults() error {
 	if m.Name == "" {
 		m.Name = "energyapp"
 	}
@@ -53,11 +63,38 @@ func (m *Project) setDefaults() {
 		// 设置当前执行目录为项目目录
 		m.ProjectPath = tools.CurrentExecuteDir()
 	}
-	if m.FrameworkPath == "" {
+	if m.OS == "" {
+		m.OS = command.OS(runtime.GOOS)
+	}
+	if m.Arch == "" {
+		m.Arch = command.Arch(runtime.GOARCH)
+	}
+	if m.FrameworkPath == "" && m.CEF != "" {
+		// 项目指定的框架版本, energy use --project
+		path, err := framework.Resolve(m.CEF, m.OS, m.Arch)
+		if err != nil {
+			return err
+		}
+		m.FrameworkPath = path
+	} else if m.IsCross() {
+		// 交叉打包, 使用目标系统架构的框架目录 ENERGY_HOME_[OS]_[ARCH], 未设置时使用 energy use 选择的框架
+		key := FrameworkHomeKey(m.OS, m.Arch)
+		m.FrameworkPath = os.Getenv(key)
+		if m.FrameworkPath == "" {
+			m.FrameworkPath = framework.Current(m.OS, m.Arch)
+		}
+		if m.FrameworkPath == "" {
+			return fmt.Errorf("packaging %s/%s requires the %s environment variable, install the target framework: energy install --os %s --arch %s",
+				m.OS, m.Arch, key, m.OS, m.Arch)
+		}
+	} else if m.FrameworkPath == "" {
 		m.FrameworkPath = os.Getenv(consts.EnergyHomeKey)
+		if m.FrameworkPath == "" {
+			m.FrameworkPath = framework.Current(m.OS, m.Arch)
+		}
 	}
 	if !tools.IsExist(m.FrameworkPath) {
-		panic("energy framework directory does not exist: " + m.FrameworkPath)
+		return errors.New("energy framework directory does not exist: " + m.FrameworkPath)
 	}
 	if m.AssetsDir == "" {
 		m.AssetsDir = "assets"
@@ -88,10 +125,58 @@ func (m *Project) setDefaults() {
 		v := "Built using ENERGY (https://github.com/energye/energy)"
 		m.Info.FileDescription = &v
 	}
+	// 图标, 安装包未单独配置时使用 info.icon
+	if m.Info.Icon == "" {
+		m.Info.Icon = m.PList.Icon
+	}
+	if m.PList.Icon == "" {
+		m.PList.Icon = m.Info.Icon
+	}
+	if m.NSIS.Icon == "" {
+		m.NSIS.Icon = m.Info.Icon
+	}
+	if m.NSIS.UnIcon == "" {
+		m.NSIS.UnIcon = m.NSIS.Icon
+	}
+	if m.NSIS.InstallMode == "" {
+		if m.NSIS.RequestExecutionLevel == "admin" {
+			m.NSIS.InstallMode = "machine"
+		} else {
+			m.NSIS.InstallMode = "user"
+		}
+	}
+	if m.NSIS.Shortcuts.StartMenu == "" {
+		m.NSIS.Shortcuts.StartMenu = NSISYes
+	}
+	if m.NSIS.Shortcuts.Desktop == "" {
+		m.NSIS.Shortcuts.Desktop = NSISYes
+	}
+	if m.NSIS.AutoStart == "" {
+		m.NSIS.AutoStart = NSISNo
+	}
+	if m.NSIS.Uninstall.RemoveUserData == "" {
+		m.NSIS.Uninstall.RemoveUserData = NSISOptional
+	}
+	if len(m.NSIS.Uninstall.UserData) == 0 {
+		// os.UserConfigDir 和 os.UserCacheDir 内的应用目录
+		m.NSIS.Uninstall.UserData = []string{`$APPDATA\` + m.Name, `$LOCALAPPDATA\` + m.Name}
+	}
 	if m.LibEMFS == "" {
 		m.LibEMFS = "Libs"
 	}
-	switch runtime.GOOS {
+	if m.Dpkg.Package == "" {
+		m.Dpkg.Package = m.Name
+	}
+	if m.Dev.Delay <= 0 {
+		m.Dev.Delay = 300
+	}
+	if m.Update.PrivateKey == "" {
+		m.Update.PrivateKey = "update.key"
+	}
+	if len(m.Dpkg.Targets) == 0 {
+		m.Dpkg.Targets = []string{"deb"}
+	}
+	switch m.OS {
 	case "windows":
 		if !strings.HasSuffix(m.OutputFilename, ".exe") {
 			m.OutputFilename += ".exe"
@@ -99,12 +184,37 @@ func (m *Project) setDefaults() {
 	case "darwin", "linux":
 		m.OutputFilename = strings.TrimSuffix(m.OutputFilename, ".exe")
 	}
+	return nil
+}
+
+// ForTarget
+//	返回编译到目标系统架构的项目配置副本, 执行文件名按目标系统调整
+//	只用于编译, 不检查目标系统架构的框架目录
+func (m *Project) ForTarget(targetOS command.OS, targetArch command.Arch) *Project {
+	proj := *m
+	proj.OS, proj.Arch = targetOS, targetArch
+	proj.OutputFilename = strings.TrimSuffix(proj.OutputFilename, ".exe")
+	if targetOS.IsWindows() {
+		proj.OutputFilename += ".exe"
+	}
+	return &proj
+}
 
+// IsCross 目标系统架构不是当前系统架构
+func (m *Project) IsCross() bool {
+	return string(m.OS) != runtime.GOOS || string(m.Arch) != runtime.GOARCH
+}
+
+// FrameworkHomeKey
+//	返回目标系统架构的框架目录环境变量名
+//	windows amd64 => ENERGY_HOME_WINDOWS_AMD64
+func FrameworkHomeKey(targetOS command.OS, targetArch command.Arch) string {
+	return strings.ToUpper(fmt.Sprintf("%s_%s_%s", consts.EnergyHomeKey, targetOS, targetArch))
 }
 
 type Info struct {
 	Manifest        string  `json:"manifest"`        //应用 manifest
-	Icon            string  `json:"icon"`            //应用图标
+	Icon            string  `json:"icon"`            //应用图标, 所有安装包共用, png 图标自动生成 ico, icns 和 linux hicolor 图标
 	CompanyName     string  `json:"companyName"`     //公司名称
 	ProductName     string  `json:"productName"`     //产品名称
 	FileVersion     string  `json:"FileVersion"`     //文件版本
@@ -129,6 +239,9 @@ func (m *NSIS) FromSlash() {
 	for i, as := range m.Include {
 		m.Include[i] = filepath.FromSlash(as)
 	}
+	for i := range m.Associations {
+		m.Associations[i].Icon = filepath.FromSlash(m.Associations[i].Icon)
+	}
 }
 
 func (m *NSIS) ToSlash() {
@@ -138,28 +251,78 @@ func (m *NSIS) ToSlash() {
 	for i, as := range m.Include {
 		m.Include[i] = filepath.ToSlash(as)
 	}
+	for i := range m.Associations {
+		m.Associations[i].Icon = filepath.ToSlash(m.Associations[i].Icon)
+	}
 }
 
 // NSIS windows NSIS
 type NSIS struct {
-	Icon                  string   `json:"icon"`                  //安装包图标
-	Include               []string `json:"include"`               //打包资源目录、或文件 ["/to/path/file.txt", "/to/dir/*.*", "/to/dir"]
-	Exclude               []string `json:"exclude"`               //打包排除资源目录、或文件 ["/to/path/file.txt", "/to/dir/*.*", "/to/dir"]
-	UnIcon                string   `json:"unIcon"`                //安装包卸载图标
-	License               string   `json:"license"`               //安装包授权信息,(license.txt)文件路径
-	Language              string   `json:"language"`              //安装包语言, 中文: SimpChinese, 英文: English, 语言在 NSIS_HOME/Contrib/Language files
-	RequestExecutionLevel string   `json:"requestExecutionLevel"` //admin or ""
-	Compress              string   `json:"compress"`              //压缩CEF, 当前仅支持7z/a压缩，""(空)时不启用压缩 默认: 7za
-	CompressName          string   `json:"compressName"`          //压缩CEF后的7z包名称
-	UseCompress           bool     `json:"-"`                     //如果支持配置的, true=使用压缩
-	CompressFile          string   `json:"-"`                     //压缩后的文件完全目录
+	Icon                  string             `json:"icon"`                  //安装包图标
+	Include               []string           `json:"include"`               //打包资源目录、或文件 ["/to/path/file.txt", "/to/dir/*.*", "/to/dir"]
+	Exclude               []string           `json:"exclude"`               //打包排除资源目录、或文件 ["/to/path/file.txt", "/to/dir/*.*", "/to/dir"]
+	UnIcon                string             `json:"unIcon"`                //安装包卸载图标
+	License               string             `json:"license"`               //安装包授权信息,(license.txt)文件路径
+	Language              string             `json:"language"`              //安装包语言, 中文: SimpChinese, 英文: English, 语言在 NSIS_HOME/Contrib/Language files
+	RequestExecutionLevel string             `json:"requestExecutionLevel"` //admin or ""
+	Compress              string             `json:"compress"`              //压缩CEF, 当前仅支持7z/a压缩，""(空)时不启用压缩 默认: 7za
+	CompressName          string             `json:"compressName"`          //压缩CEF后的7z包名称
+	UseCompress           bool               `json:"-"`                     //如果支持配置的, true=使用压缩
+	CompressFile          string             `json:"-"`                     //压缩后的文件完全目录
+	InstallMode           string             `json:"installMode"`           //安装方式 machine: 所有用户, user: 当前用户, both: 安装时选择, 默认: requestExecutionLevel 为 admin 时 machine, 否则 user
+	Shortcuts             NSISShortcuts      `json:"shortcuts"`             //快捷方式
+	AutoStart             string             `json:"autoStart"`             //开机启动 yes, optional, no 默认: no
+	AutoStartArgs         string             `json:"autoStartArgs"`         //开机启动参数
+	Associations          []NSISAssociation  `json:"associations"`          //文件类型关联
+	Prerequisites         []NSISPrerequisite `json:"prerequisites"`         //安装前检查
+	Uninstall             NSISUninstall      `json:"uninstall"`             //卸载清理
+}
+
+// NSIS 可选项
+//	yes: 安装, optional: 安装时在组件页选择(默认选中), 卸载时询问, no: 不安装
+const (
+	NSISYes      = "yes"
+	NSISOptional = "optional"
+	NSISNo       = "no"
+)
+
+// NSISShortcuts 快捷方式 yes, optional, no
+type NSISShortcuts struct {
+	StartMenu string `json:"startMenu"` //开始菜单 默认: yes
+	Desktop   string `json:"desktop"`   //桌面 默认: yes
+}
+
+// NSISAssociation 文件类型关联, 使用应用打开, 文件路径为第一个参数
+type NSISAssociation struct {
+	Ext         string `json:"ext"`         //扩展名 .txt
+	Name        string `json:"name"`        //ProgID 默认: [name].[ext]
+	Description string `json:"description"` //文件类型描述
+	Icon        string `json:"icon"`        //文件图标 ico 或 png, 空时使用执行文件图标
+}
+
+// NSISPrerequisite 安装前检查, 不满足时提示并退出安装, 设置 url 时询问是否打开
+type NSISPrerequisite struct {
+	Type    string `json:"type"`    //windows: 最低 Windows 版本 7, 8, 8.1, 10, 11; x64: 64 位系统; registry: 注册表项存在 HKLM\SOFTWARE\...; file: 文件存在 $SYSDIR\xxx.dll
+	Value   string `json:"value"`   //
+	Name    string `json:"name"`    //提示中的名称, 例: Microsoft Visual C++ Redistributable
+	Message string `json:"message"` //提示信息 默认: [productName] requires [name]
+	URL     string `json:"url"`     //下载地址
+}
+
+// NSISUninstall 卸载清理
+type NSISUninstall struct {
+	RemoveUserData string   `json:"removeUserData"` //删除用户数据和 CEF 缓存 yes, optional: 卸载时询问, no 默认: optional
+	UserData       []string `json:"userData"`       //用户数据目录 默认: ["$APPDATA\\[name]", "$LOCALAPPDATA\\[name]"]
 }
 
 type DPKG struct {
-	Include      []string `json:"include"` //打包资源目录、或文件 ["/to/path/file.txt", "/to/dir/*.*", "/to/dir"]
-	Exclude      []string `json:"exclude"` //打包排除资源目录、或文件 ["/to/path/file.txt", "/to/dir/*.*", "/to/dir"]
-	Package      string   `json:"package"`
-	Homepage     string   `json:"homepage"`
+	Include      []string `json:"include"`  //打包资源目录、或文件 ["/to/path/file.txt", "/to/dir/*.*", "/to/dir"]
+	Exclude      []string `json:"exclude"`  //打包排除资源目录、或文件 ["/to/path/file.txt", "/to/dir/*.*", "/to/dir"]
+	Package      string   `json:"package"`  //包名, dpkg -r [package] 或 rpm -e [package]
+	Homepage     string   `json:"homepage"` //
+	Targets      []string `json:"targets"`  //安装包格式 ["deb", "rpm", "appimage", "tar.gz"] 默认: ["deb"]
+	License      string   `json:"license"`  //rpm License 默认: Proprietary
+	Release      string   `json:"release"`  //rpm Release 默认: 1
 	Compress     string   `json:"compress"` //压纹CEF, 当前仅支持7z/a压缩，""(空)时不启用压缩 默认: 7za
 	UseCompress  bool     `json:"-"`        //如果支持配置的, true=使用压缩
 	CompressFile string   `json:"-"`        //压缩后的文件完全目录
@@ -181,24 +344,68 @@ type PList struct {
 	Pkgbuild                   bool     `json:"-"`                          // 生成pkg安装包
 }
 
+// Build energy build 编译配置
+//	命令在项目目录执行, 环境变量: ENERGY_PROJECT_PATH, ENERGY_OS, ENERGY_ARCH, ENERGY_OUTPUT, ENERGY_VERSION
+type Build struct {
+	PreBuild  []string      `json:"preBuild"`  //编译前执行的命令, 在前端编译之前执行一次
+	PostBuild []string      `json:"postBuild"` //编译成功后执行的命令, 每个目标系统架构执行一次
+	Frontend  BuildFrontend `json:"frontend"`  //前端编译
+}
+
+// BuildFrontend 前端编译配置
+type BuildFrontend struct {
+	Command string `json:"command"` //前端编译命令, 例: npm run build
+	Dir     string `json:"dir"`     //执行目录, 以项目目录根目录开始, 默认项目目录
+	Output  string `json:"output"`  //编译输出目录, 以项目目录根目录开始, 编译后检查目录不为空, 例: resources/dist
+}
+
+// Dev energy dev 开发模式配置
+type Dev struct {
+	Frontend   string   `json:"frontend"`   //前端资源目录, 以项目目录根目录开始, 改变时通知应用重新加载页面
+	Embed      bool     `json:"embed"`      //前端资源内置到执行文件(go:embed, bindata), 前端改变时重新编译
+	Command    string   `json:"command"`    //前端开发命令, 和应用一起启动和停止, 例: npm run dev
+	CommandDir string   `json:"commandDir"` //前端开发命令执行目录, 以项目目录根目录开始, 默认前端资源目录
+	Watch      []string `json:"watch"`      //额外监听的Go源码目录, 例: 依赖的本地模块 ["../mylib"]
+	Exclude    []string `json:"exclude"`    //不监听的目录或文件, 以项目目录根目录开始 ["/to/dir", "*.tmp"]
+	Delay      int      `json:"delay"`      //文件改变后等待时间(毫秒), 合并连续的改变, 默认: 300
+}
+
+// Update 应用更新配置, 配置 feed 后 energy package 生成更新包到 build/update
+//	应用使用 pkgs/update 检查更新, 上传 build/update 目录内的文件到 feed 所在目录
+type Update struct {
+	Feed       string   `json:"feed"`       //更新源地址, 例: https://example.com/update/feed.json
+	PrivateKey string   `json:"privateKey"` //ed25519 签名私钥文件, 以项目目录根目录开始, 不存在时生成, 环境变量 ENERGY_UPDATE_PRIVATE_KEY 优先 默认: update.key
+	Include    []string `json:"include"`    //更新包内的文件或目录, 以项目目录根目录开始, 相对执行文件目录安装, 执行文件默认包含
+	Notes      string   `json:"notes"`      //版本说明
+}
+
 type Author struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
 
-//  APP项目配置转换到Project
-func parse(projectData []byte) (*Project, error) {
-	m := &Project{}
+// APP项目配置转换到Project
+func parse(projectData []byte, targetOS command.OS, targetArch command.Arch) (*Project, error) {
+	m := &Project{OS: targetOS, Arch: targetArch}
 	err := json.Unmarshal(projectData, m)
 	if err != nil {
 		return nil, err
 	}
-	m.setDefaults()
+	if err = m.setDefaults(); err != nil {
+		return nil, err
+	}
 	return m, nil
 }
 
 // NewProject 创建项目对象, 根据energy.json配置
 func NewProject(projectPath string) (*Project, error) {
+	return NewTargetProject(projectPath, "", "")
+}
+
+// NewTargetProject
+//	创建项目对象, 根据energy.json配置
+//	targetOS, targetArch: 目标系统架构, 空时使用当前系统架构
+func NewTargetProject(projectPath string, targetOS command.OS, targetArch command.Arch) (*Project, error) {
 	if projectPath == "" {
 		// 设置当前执行目录为项目目录
 		projectPath = tools.CurrentExecuteDir()
@@ -208,7 +415,7 @@ func NewProject(projectPath string) (*Project, error) {
 	if err != nil {
 		return nil, err
 	}
-	m, err := parse(rawBytes)
+	m, err := parse(rawBytes, targetOS, targetArch)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +155,7 @@ const (
 	LANGUAGE_hi     LANGUAGE = "hi"
 	LANGUAGE_hr     LANGUAGE = "hr"
 	LANGUAGE_hu     LANGUAGE = "hu"
-	LANGUAGE_id     LANGUAGE = "channelId"
+	LANGUAGE_id     LANGUAGE = "id"
 	LANGUAGE_it     LANGUAGE = "it"
 	LANGUAGE_ja     LANGUAGE = "ja"
 	LANGUAGE_kn     LANGUAGE = "kn"
@@ -1321,6 +1321,14 @@ const (
 // /include/internal/cef_types.h (cef_media_access_permission_types_t)
 type TCefMediaAccessPermissionTypes = types.Int32
 
+const (
+	CEF_MEDIA_PERMISSION_NONE                  TCefMediaAccessPermissionTypes = 0
+	CEF_MEDIA_PERMISSION_DEVICE_AUDIO_CAPTURE  TCefMediaAccessPermissionTypes = 1 << 0
+	CEF_MEDIA_PERMISSION_DEVICE_VIDEO_CAPTURE  TCefMediaAccessPermissionTypes = 1 << 1
+	CEF_MEDIA_PERMISSION_DESKTOP_AUDIO_CAPTURE TCefMediaAccessPermissionTypes = 1 << 2
+	CEF_MEDIA_PERMISSION_DESKTOP_VIDEO_CAPTURE TCefMediaAccessPermissionTypes = 1 << 3
+)
+
 // /include/internal/cef_types.h (cef_jsdialog_type_t)
 type TCefJsDialogType = types.Int32
 
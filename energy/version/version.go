@@ -0,0 +1,59 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// Package version 应用版本信息
+//	energy build 编译时通过 -ldflags -X 写入, 未使用 energy build 编译时为空
+//	  -X github.com/energye/energy/v2/energy/version.version=1.0.0
+//	  -X github.com/energye/energy/v2/energy/version.commit=abc1234
+//	  -X github.com/energye/energy/v2/energy/version.buildTime=2006-01-02T15:04:05Z
+package version
+
+import "time"
+
+var (
+	version   string // energy.json info.productVersion
+	commit    string // git commit
+	buildTime string // 编译时间 RFC3339 UTC
+)
+
+// Version 应用版本, energy.json info.productVersion
+func Version() string {
+	return version
+}
+
+// Commit 编译时项目的 git commit, 不是 git 仓库时为空
+func Commit() string {
+	return commit
+}
+
+// BuildTime 编译时间, 未写入时返回零值
+func BuildTime() time.Time {
+	t, _ := time.Parse(time.RFC3339, buildTime)
+	return t
+}
+
+// String 版本描述, 例: 1.0.0 (abc1234 2006-01-02T15:04:05Z)
+func String() string {
+	result := version
+	if result == "" {
+		result = "dev"
+	}
+	if commit != "" || buildTime != "" {
+		detail := commit
+		if buildTime != "" {
+			if detail != "" {
+				detail += " "
+			}
+			detail += buildTime
+		}
+		result += " (" + detail + ")"
+	}
+	return result
+}
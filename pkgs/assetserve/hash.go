@@ -0,0 +1,125 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// 内置资源内容哈希, 用于 ETag 和不可变缓存
+//	energy bindata 生成的资源实现 IHashFS
+//	go:embed 资源使用 energy bindata --manifest 生成的清单: WithManifest(assets, LoadManifest(assets, "resources/manifest.json"))
+
+package assetserve
+
+import (
+	"encoding/json"
+	"github.com/energye/golcl/energy/emfs"
+	"strings"
+)
+
+// VersionQuery
+//	资源 URL 版本参数名, 值为内容哈希前缀时资源不可变, 永久缓存
+//	示例: /js/app.js?v=bb116e2a32d8
+var VersionQuery = "v"
+
+const (
+	versionLength = 12 // Versioned 使用的哈希前缀长度
+	minVersion    = 8  // 不可变资源哈希前缀最小长度
+)
+
+// IHashFS
+//	内置资源内容哈希, sha256 hex
+type IHashFS interface {
+	Hash(name string) (string, bool)
+}
+
+// Manifest 内容哈希清单, 文件名: sha256
+type Manifest map[string]string
+
+// Hash 返回文件内容哈希
+func (m Manifest) Hash(name string) (string, bool) {
+	hash, ok := m[name]
+	return hash, ok
+}
+
+// LoadManifest
+//	读取 energy bindata --manifest 生成的内容哈希清单
+func LoadManifest(assets emfs.IEmbedFS, name string) (Manifest, error) {
+	data, err := assets.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	manifest := Manifest{}
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+type hashFS struct {
+	emfs.IEmbedFS
+	Manifest
+}
+
+// WithManifest
+//	返回使用清单内容哈希的内置资源, 同时实现 emfs.IEmbedFS 和 IHashFS
+func WithManifest(assets emfs.IEmbedFS, manifest Manifest) emfs.IEmbedFS {
+	return &hashFS{IEmbedFS: assets, Manifest: manifest}
+}
+
+// ContentHash
+//	返回资源内容哈希, 资源未实现 IHashFS 或文件不存在时返回空
+func ContentHash(assets emfs.IEmbedFS, name string) string {
+	if hash, ok := assets.(IHashFS); ok {
+		if sum, ok := hash.Hash(name); ok {
+			return sum
+		}
+	}
+	return ""
+}
+
+// Versioned
+//	返回带内容哈希版本参数的 URL, 用于更新资源时绕过缓存
+//	示例: Versioned("/js/app.js", hash) = /js/app.js?v=bb116e2a32d8
+func Versioned(url, hash string) string {
+	if len(hash) > versionLength {
+		hash = hash[:versionLength]
+	}
+	if hash == "" {
+		return url
+	}
+	if strings.Contains(url, "?") {
+		return url + "&" + VersionQuery + "=" + hash
+	}
+	return url + "?" + VersionQuery + "=" + hash
+}
+
+// ETag 返回内容哈希的 ETag
+func ETag(hash string) string {
+	return `"` + hash + `"`
+}
+
+// CacheHeader
+//	返回资源缓存响应头和是否未修改(304)
+//	hash 为空时不设置缓存, version 为请求 URL 版本参数, 和 hash 前缀相同时永久缓存, 否则每次验证 ETag
+//	ifNoneMatch 为请求头 If-None-Match
+func CacheHeader(hash, version, ifNoneMatch string) (header map[string]string, notModified bool) {
+	if hash == "" {
+		return nil, false
+	}
+	etag := ETag(hash)
+	header = map[string]string{"ETag": etag, "Cache-Control": "no-cache"}
+	if len(version) >= minVersion && strings.HasPrefix(hash, version) {
+		header["Cache-Control"] = "public, max-age=31536000, immutable"
+	}
+	for _, match := range strings.Split(ifNoneMatch, ",") {
+		match = strings.TrimPrefix(strings.TrimSpace(match), "W/")
+		if match == etag || match == "*" {
+			return header, true
+		}
+	}
+	return header, false
+}
@@ -0,0 +1,41 @@
+package assetserve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCacheHeader(t *testing.T) {
+	const hash = "bb116e2a32d8acd736c46fa3132a8f053ccf523d80dfeec79d8143c4e145a549"
+	assets := fstest.MapFS{
+		"resources/app.js":        {Data: []byte("console.log('energy')")},
+		"resources/manifest.json": {Data: []byte(`{"resources/app.js": "` + hash + `"}`)},
+	}
+	manifest, err := LoadManifest(assets, "resources/manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewAssetsHttpServer()
+	server.Assets = WithManifest(assets, manifest)
+	if ContentHash(server.Assets, "resources/app.js") != hash || ContentHash(assets, "resources/app.js") != "" {
+		t.Fatal("content hash")
+	}
+	url := Versioned("/app.js", hash)
+	if url != "/app.js?v=bb116e2a32d8" {
+		t.Fatal(url)
+	}
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != ETag(hash) || w.Header().Get("Cache-Control") != "public, max-age=31536000, immutable" {
+		t.Fatalf("%d %v", w.Code, w.Header())
+	}
+	request := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+	request.Header.Set("If-None-Match", ETag(hash))
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, request)
+	if w.Code != http.StatusNotModified || w.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("%d %v", w.Code, w.Header())
+	}
+}
@@ -201,11 +201,23 @@ func (m *assetsHttpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		path = path + "index.html"
 	}
 	var (
-		byt []byte
-		err error
+		byt    []byte
+		err    error
+		header map[string]string
 	)
 	if m.Assets != nil {
-		byt, err = m.Assets.ReadFile(m.AssetsFSName + path)
+		name := m.AssetsFSName + path
+		hash := ContentHash(m.Assets, name)
+		var notModified bool
+		header, notModified = CacheHeader(hash, r.URL.Query().Get(VersionQuery), r.Header.Get("If-None-Match"))
+		if notModified {
+			for key, value := range header {
+				w.Header().Set(key, value)
+			}
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		byt, err = m.Assets.ReadFile(name)
 	} else if m.LocalAssets != "" {
 		path = fmt.Sprintf("%s%s", m.LocalAssets, path)
 		byt, err = ioutil.ReadFile(path)
@@ -224,11 +236,20 @@ func (m *assetsHttpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				w.Header().Set("Content-Type", ct)
 			}
 		}
+		for key, value := range header {
+			w.Header().Set(key, value)
+		}
 		w.WriteHeader(200)
 		_, _ = w.Write(byt)
 	}
 }
 
+// ContentType
+//	返回扩展名的 MIME 类型, ext 格式: .js, 未知类型返回空
+func ContentType(ext string) string {
+	return contentType[strings.ToLower(ext)]
+}
+
 func extType(path string) string {
 	idx := strings.LastIndex(path, ".")
 	if idx != -1 {
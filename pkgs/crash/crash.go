@@ -0,0 +1,382 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// Package crash 崩溃报告
+//	记录 Go panic 和堆栈、底层库异常、渲染进程终止, 写入 JSON 报告文件
+//	可选上传报告, 下次启动时通知上次运行没有正常退出
+//	cef.EnableCrashReport 启用并捕获 IPC 和事件回调中的 panic
+package crash
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/energy/version"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 崩溃类型
+const (
+	KindPanic         = "panic"          // Go panic
+	KindNative        = "native"         // liblcl、CEF 底层异常
+	KindRenderProcess = "render-process" // 渲染进程终止
+)
+
+const (
+	sessionFileName = "session.json"
+	sessionEnv      = "ENERGY_CRASH_SESSION" // 子进程使用主进程的会话
+	reportSuffix    = ".json"
+	reportPrefix    = "crash-"
+)
+
+// Report 崩溃报告
+type Report struct {
+	ID         string            `json:"id"`
+	Kind       string            `json:"kind"`
+	Source     string            `json:"source"` // 发生的位置, 例: ipc, event, main
+	Message    string            `json:"message"`
+	Fatal      bool              `json:"fatal"`                // 进程因此退出
+	Stack      string            `json:"stack,omitempty"`      // 当前 goroutine 堆栈
+	Goroutines string            `json:"goroutines,omitempty"` // 所有 goroutine 堆栈, 只有 fatal
+	Time       time.Time         `json:"time"`
+	Session    string            `json:"session"`
+	Process    Process           `json:"process"`
+	App        App               `json:"app"`
+	Versions   map[string]string `json:"versions"` // go, cef, chrome 等版本
+	Extra      map[string]string `json:"extra,omitempty"`
+	Uploaded   bool              `json:"uploaded"`
+	file       string
+}
+
+// File 报告文件
+func (m *Report) File() string {
+	return m.file
+}
+
+// Process 进程信息
+type Process struct {
+	Type       string   `json:"type"` // browser, renderer, gpu-process, utility
+	Pid        int      `json:"pid"`
+	Executable string   `json:"executable"`
+	Args       []string `json:"args"`
+	OS         string   `json:"os"`
+	Arch       string   `json:"arch"`
+}
+
+// App 应用信息, 默认 energy build 写入的版本
+type App struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+}
+
+// Session 运行会话, 主进程启动时创建, Close 时删除
+type Session struct {
+	ID      string    `json:"id"`
+	Pid     int       `json:"pid"`
+	Start   time.Time `json:"start"`
+	Reports []*Report `json:"-"` // 会话内的崩溃报告, 进程被终止时可能为空
+}
+
+// Config 崩溃报告配置
+type Config struct {
+	Dir             string                   // 报告目录, 默认 [用户缓存目录]/[应用名称]/crash
+	AppName         string                   // 应用名称, 默认执行文件名
+	ProcessType     string                   // 进程类型, 空时为 browser, 只有 browser 进程记录会话
+	MaxReports      int                      // 保留的报告数, 默认 50
+	Versions        func() map[string]string // 写入报告的框架版本
+	Upload          func(report *Report) error
+	OnPreviousCrash func(session *Session) // 上次运行没有正常退出, Init 时调用
+}
+
+var (
+	lock    sync.Mutex
+	config  *Config
+	session string
+)
+
+// Init
+//	启用崩溃报告
+//	主进程检查上次运行的会话, 没有 Close 时调用 OnPreviousCrash, 然后创建新的会话
+//	后台上传未上传的报告
+func Init(cfg Config) error {
+	if cfg.AppName == "" {
+		exe := filepath.Base(os.Args[0])
+		cfg.AppName = strings.TrimSuffix(exe, filepath.Ext(exe))
+	}
+	if cfg.Dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			cache = os.TempDir()
+		}
+		cfg.Dir = filepath.Join(cache, cfg.AppName, "crash")
+	}
+	if cfg.ProcessType == "" {
+		cfg.ProcessType = "browser"
+	}
+	if cfg.MaxReports <= 0 {
+		cfg.MaxReports = 50
+	}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return err
+	}
+	var previous *Session
+	id := os.Getenv(sessionEnv)
+	if cfg.ProcessType == "browser" {
+		previous = readSession(cfg.Dir)
+		id = newID()
+		data, _ := json.Marshal(&Session{ID: id, Pid: os.Getpid(), Start: time.Now()})
+		if err := ioutil.WriteFile(filepath.Join(cfg.Dir, sessionFileName), data, 0644); err != nil {
+			return err
+		}
+		os.Setenv(sessionEnv, id)
+	}
+	lock.Lock()
+	config, session = &cfg, id
+	lock.Unlock()
+	reports, _ := Reports()
+	if previous != nil {
+		for _, report := range reports {
+			if report.Session == previous.ID {
+				previous.Reports = append(previous.Reports, report)
+			}
+		}
+		if cfg.OnPreviousCrash != nil {
+			cfg.OnPreviousCrash(previous)
+		}
+	}
+	if cfg.ProcessType == "browser" {
+		prune(reports, cfg.MaxReports)
+		if cfg.Upload != nil {
+			go func() {
+				for _, report := range reports {
+					if !report.Uploaded && tryExist(report.file) {
+						upload(&cfg, report)
+					}
+				}
+			}()
+		}
+	}
+	return nil
+}
+
+// Enabled 是否启用崩溃报告
+func Enabled() bool {
+	return current() != nil
+}
+
+// Close 应用正常退出, 删除会话记录
+func Close() {
+	cfg := current()
+	if cfg == nil || cfg.ProcessType != "browser" {
+		return
+	}
+	os.Remove(filepath.Join(cfg.Dir, sessionFileName))
+}
+
+// Recover
+//	defer crash.Recover("source") 捕获 panic 写入报告, 继续运行
+//	未启用崩溃报告时继续 panic, 和未捕获时一样
+func Recover(source string) {
+	if err := recover(); err != nil {
+		if !Enabled() {
+			panic(err)
+		}
+		Capture(KindPanic, source, fmt.Sprint(err), false, nil)
+	}
+}
+
+// RecoverWith
+//	同 Recover, 写入报告后调用 fn, 用于回复等待结果的调用者
+func RecoverWith(source string, fn func(err interface{})) {
+	if err := recover(); err != nil {
+		if !Enabled() {
+			panic(err)
+		}
+		Capture(KindPanic, source, fmt.Sprint(err), false, nil)
+		fn(err)
+	}
+}
+
+// Guard
+//	defer crash.Guard("main") 捕获 panic 写入报告并同步上传, 然后继续 panic 退出进程
+//	用于 main 函数和自己创建的 goroutine
+func Guard(source string) {
+	if err := recover(); err != nil {
+		if Enabled() {
+			Capture(KindPanic, source, fmt.Sprint(err), true, nil)
+		}
+		panic(err)
+	}
+}
+
+// Capture
+//	写入崩溃报告, 包含当前 goroutine 堆栈, fatal 时包含所有 goroutine 堆栈并同步上传
+//	未启用崩溃报告时返回 nil
+func Capture(kind, source, message string, fatal bool, extra map[string]string) *Report {
+	cfg := current()
+	if cfg == nil {
+		return nil
+	}
+	report := &Report{
+		ID:      newID(),
+		Kind:    kind,
+		Source:  source,
+		Message: message,
+		Fatal:   fatal,
+		Time:    time.Now(),
+		Session: session,
+		Process: Process{Type: cfg.ProcessType, Pid: os.Getpid(), Args: os.Args, OS: runtime.GOOS, Arch: runtime.GOARCH},
+		App: App{Name: cfg.AppName, Version: version.Version(), Commit: version.Commit(),
+			BuildTime: formatTime(version.BuildTime())},
+		Versions: map[string]string{"go": runtime.Version()},
+		Extra:    extra,
+	}
+	report.Process.Executable, _ = os.Executable()
+	if kind == KindPanic || kind == KindNative {
+		report.Stack = string(debug.Stack())
+	}
+	if fatal {
+		report.Goroutines = allStacks()
+	}
+	if cfg.Versions != nil {
+		for name, value := range cfg.Versions() {
+			report.Versions[name] = value
+		}
+	}
+	report.file = filepath.Join(cfg.Dir, fmt.Sprintf("%s%s-%s-%s%s", reportPrefix, report.Time.Format("20060102T150405"), kind, report.ID, reportSuffix))
+	if err := writeReport(report); err != nil {
+		fmt.Fprintln(os.Stderr, "crash report:", err)
+		return report
+	}
+	if cfg.Upload != nil {
+		if fatal {
+			upload(cfg, report)
+		} else {
+			go upload(cfg, report)
+		}
+	}
+	return report
+}
+
+// Reports 返回报告目录内的所有报告, 按时间排序
+func Reports() ([]*Report, error) {
+	cfg := current()
+	if cfg == nil {
+		return nil, errors.New("crash report is not enabled")
+	}
+	files, err := filepath.Glob(filepath.Join(cfg.Dir, reportPrefix+"*"+reportSuffix))
+	if err != nil {
+		return nil, err
+	}
+	var reports []*Report
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		report := &Report{}
+		if json.Unmarshal(data, report) != nil {
+			continue
+		}
+		report.file = file
+		reports = append(reports, report)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Time.Before(reports[j].Time)
+	})
+	return reports, nil
+}
+
+func current() *Config {
+	lock.Lock()
+	defer lock.Unlock()
+	return config
+}
+
+func readSession(dir string) *Session {
+	data, err := ioutil.ReadFile(filepath.Join(dir, sessionFileName))
+	if err != nil {
+		return nil
+	}
+	previous := &Session{}
+	if json.Unmarshal(data, previous) != nil {
+		return nil
+	}
+	return previous
+}
+
+func writeReport(report *Report) error {
+	data, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(report.file, data, 0644)
+}
+
+// upload 上传成功后标记为已上传
+func upload(cfg *Config, report *Report) {
+	defer func() {
+		recover()
+	}()
+	if err := cfg.Upload(report); err != nil {
+		return
+	}
+	report.Uploaded = true
+	writeReport(report)
+}
+
+// prune 删除旧的报告, 保留 max 个
+func prune(reports []*Report, max int) {
+	for i := 0; i < len(reports)-max; i++ {
+		os.Remove(reports[i].file)
+	}
+}
+
+// allStacks 所有 goroutine 堆栈
+func allStacks() string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= 8<<20 {
+			return string(buf[:n])
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
+
+func newID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func tryExist(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
@@ -0,0 +1,111 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package crash
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCrash(t *testing.T) {
+	dir := t.TempDir()
+	var (
+		wg       sync.WaitGroup
+		uploaded []string
+		mu       sync.Mutex
+	)
+	var upload = func(report *Report) error {
+		mu.Lock()
+		uploaded = append(uploaded, report.ID)
+		mu.Unlock()
+		wg.Done()
+		return nil
+	}
+	var previous *Session
+	var onPrevious = func(session *Session) {
+		previous = session
+	}
+	config := Config{Dir: dir, AppName: "demo", Upload: upload, OnPreviousCrash: onPrevious,
+		Versions: func() map[string]string { return map[string]string{"cef": "109.1.18"} }}
+	if err := Init(config); err != nil {
+		t.Fatal(err)
+	}
+	if previous != nil {
+		t.Fatal("first start has no previous session")
+	}
+	wg.Add(1)
+	func() {
+		defer Recover("ipc")
+		panic("ipc handler failed")
+	}()
+	wg.Wait()
+	reports, err := Reports()
+	if err != nil || len(reports) != 1 {
+		t.Fatalf("reports: %d %v", len(reports), err)
+	}
+	report := reports[0]
+	if report.Kind != KindPanic || report.Source != "ipc" || report.Message != "ipc handler failed" ||
+		report.Versions["cef"] != "109.1.18" || report.Versions["go"] == "" || report.Process.Type != "browser" {
+		t.Fatalf("report: %+v", report)
+	}
+	if !strings.Contains(report.Stack, "TestCrash") {
+		t.Fatalf("stack: %s", report.Stack)
+	}
+	mu.Lock()
+	if len(uploaded) != 1 || uploaded[0] != report.ID {
+		t.Fatalf("uploaded: %v", uploaded)
+	}
+	mu.Unlock()
+
+	// 没有 Close, 下次启动时通知上次运行没有正常退出
+	config.Upload = nil
+	if err = Init(config); err != nil {
+		t.Fatal(err)
+	}
+	if previous == nil || len(previous.Reports) != 1 || previous.Reports[0].ID != report.ID {
+		t.Fatalf("previous session: %+v", previous)
+	}
+	Close()
+	previous = nil
+	if err = Init(config); err != nil {
+		t.Fatal(err)
+	}
+	if previous != nil {
+		t.Fatal("previous session was closed")
+	}
+	Close()
+
+	// 恢复后回复等待结果的调用者
+	var recovered interface{}
+	func() {
+		defer RecoverWith("ipc", func(err interface{}) { recovered = err })
+		panic("reply")
+	}()
+	if recovered != "reply" {
+		t.Fatalf("recovered: %v", recovered)
+	}
+
+	// fatal 报告包含所有 goroutine 堆栈, 继续 panic
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Guard must panic again")
+			}
+		}()
+		defer Guard("main")
+		panic("fatal")
+	}()
+	reports, _ = Reports()
+	if last := reports[len(reports)-1]; !last.Fatal || last.Goroutines == "" {
+		t.Fatalf("fatal report: %+v", last)
+	}
+}
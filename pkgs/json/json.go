@@ -24,32 +24,34 @@ import (
 
 // JSON object
 type JSON interface {
-	Size() int                  //返回数据数量
-	Type() reflect.Kind         //当前对象数量类型
-	Data() interface{}          //返回原始数据
-	SetValue(value interface{}) //设置值
-	String() string             //返回 string 类型值
-	Int() int                   //返回 int 类型值, 把所有数字类型都转换 int 返回
-	Int64() int64               //返回 uint 类型值, 把所有数字类型都转换 int64 返回
-	UInt() uint                 //返回 uint 类型值, 把所有数字类型都转换 uint 返回
-	UInt64() uint64             //返回 uint 类型值, 把所有数字类型都转换 uint64 返回
-	Bytes() []byte              //转换为 '[]byte' 并返回, 任意类型都将转换
-	Float() float64             //返回 float64 类型值 把所有数字类型都转换 float64 返回
-	Bool() bool                 //返回 bool 类型值
-	JSONObject() JSONObject     //返回 JSONObject 对象类型
-	JSONArray() JSONArray       //返回 JSONArray 对象类型
-	JsonData() *JsonData        //JSON Data
-	ToJSONString() string       //转换为JSON字符串并返回
-	IsString() bool             //当前对象是否为 string
-	IsInt() bool                //当前对象是否为 int
-	IsUInt() bool               //当前对象是否为 uint
-	IsBytes() bool              //当前对象是否为 []byte
-	IsFloat() bool              //当前对象是否为 float64
-	IsBool() bool               //当前对象是否为 bool
-	IsObject() bool             //当前对象是否为 JSONObject
-	IsArray() bool              //当前对象是否为 JSONArray
-	Clear()                     //清空所有数据，保留原始数据类型
-	Free()                      //释放数据空间，且类型失效，当前对象不可用
+	Size() int                        //返回数据数量
+	Type() reflect.Kind               //当前对象数量类型
+	Data() interface{}                //返回原始数据
+	SetValue(value interface{})       //设置值
+	String() string                   //返回 string 类型值
+	Int() int                         //返回 int 类型值, 把所有数字类型都转换 int 返回
+	Int64() int64                     //返回 uint 类型值, 把所有数字类型都转换 int64 返回
+	UInt() uint                       //返回 uint 类型值, 把所有数字类型都转换 uint 返回
+	UInt64() uint64                   //返回 uint 类型值, 把所有数字类型都转换 uint64 返回
+	Bytes() []byte                    //转换为 '[]byte' 并返回, 任意类型都将转换
+	Float() float64                   //返回 float64 类型值 把所有数字类型都转换 float64 返回
+	Bool() bool                       //返回 bool 类型值
+	JSONObject() JSONObject           //返回 JSONObject 对象类型
+	JSONArray() JSONArray             //返回 JSONArray 对象类型
+	JsonData() *JsonData              //JSON Data
+	ToJSONString() string             //转换为JSON字符串并返回
+	IsString() bool                   //当前对象是否为 string
+	IsInt() bool                      //当前对象是否为 int
+	IsUInt() bool                     //当前对象是否为 uint
+	IsBytes() bool                    //当前对象是否为 []byte
+	IsFloat() bool                    //当前对象是否为 float64
+	IsBool() bool                     //当前对象是否为 bool
+	IsObject() bool                   //当前对象是否为 JSONObject
+	IsArray() bool                    //当前对象是否为 JSONArray
+	GetByPath(path string) JSON       //根据路径返回 JSON 对象, user.addresses[0].city
+	Unmarshal(into interface{}) error //转换为 Go 类型, 支持 struct json 标签
+	Clear()                           //清空所有数据，保留原始数据类型
+	Free()                            //释放数据空间，且类型失效，当前对象不可用
 }
 
 // JsonData
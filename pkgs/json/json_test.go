@@ -1,6 +1,7 @@
 package json
 
 import (
+	"bytes"
 	"testing"
 )
 
@@ -166,3 +167,87 @@ func TestJSONArray(t *testing.T) {
 	t.Log("jsonArray.4.type:", jsonArray.GetByIndex(4).Type())
 	t.Log("jsonArray.ToJSONString:", jsonArray.ToJSONString())
 }
+
+type testAddress struct {
+	City   string `json:"city"`
+	Street string `json:"street,omitempty"`
+}
+
+type testUser struct {
+	Name      string         `json:"name"`
+	Age       int            `json:"age"`
+	Addresses []*testAddress `json:"addresses"`
+	Tags      map[string]int `json:"tags"`
+}
+
+func TestGetByPath(t *testing.T) {
+	data := NewJSON([]byte(`{"user": {"name": "energy", "addresses": [{"city": "Beijing"}, {"city": "Shanghai"}], "first.name": "e"}, "list": [[1, 2], [3, 4]]}`))
+	if v := data.GetByPath("user.addresses[0].city"); v == nil || v.String() != "Beijing" {
+		t.Fatal("user.addresses[0].city", v)
+	}
+	if v := data.GetByPath("$.user.addresses[-1].city"); v == nil || v.String() != "Shanghai" {
+		t.Fatal("user.addresses[-1].city", v)
+	}
+	if v := data.GetByPath(`user["first.name"]`); v == nil || v.String() != "e" {
+		t.Fatal(`user["first.name"]`, v)
+	}
+	if v := data.GetByPath("list[1][0]"); v == nil || v.Int() != 3 {
+		t.Fatal("list[1][0]", v)
+	}
+	if v := data.GetByPath("user.addresses[5].city"); v != nil {
+		t.Fatal("user.addresses[5].city", v)
+	}
+	if v := data.GetByPath("user.name.first"); v != nil {
+		t.Fatal("user.name.first", v)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	user := &testUser{Name: "energy", Age: 3, Addresses: []*testAddress{{City: "Beijing"}}, Tags: map[string]int{"go": 1}}
+	data := NewJSONFromStruct(user)
+	if data == nil || !data.IsObject() {
+		t.Fatal("NewJSONFromStruct", data)
+	}
+	if v := data.GetByPath("addresses[0].city"); v == nil || v.String() != "Beijing" {
+		t.Fatal("addresses[0].city", v)
+	}
+	var result testUser
+	if err := data.Unmarshal(&result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "energy" || result.Age != 3 || len(result.Addresses) != 1 || result.Addresses[0].City != "Beijing" || result.Tags["go"] != 1 {
+		t.Fatal("Unmarshal", result)
+	}
+	var age int
+	if err := data.GetByPath("age").Unmarshal(&age); err != nil || age != 3 {
+		t.Fatal("Unmarshal age", age, err)
+	}
+	if err := data.Unmarshal(result); err == nil {
+		t.Fatal("Unmarshal non-pointer")
+	}
+	if err := data.Unmarshal(nil); err == nil || err.Error() != "json: Unmarshal(nil)" {
+		t.Fatal("Unmarshal nil", err)
+	}
+	if err := data.Unmarshal((*testUser)(nil)); err == nil {
+		t.Fatal("Unmarshal nil pointer")
+	}
+}
+
+func TestArrayEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := NewArrayEncoder(&buf)
+	encoder.Encode(&testAddress{City: "Beijing"})
+	encoder.Encode(NewJSONObject(`{"city": "Shanghai"}`))
+	encoder.Encode(1)
+	if err := encoder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `[{"city":"Beijing"},{"city":"Shanghai"},1]` {
+		t.Fatal("ArrayEncoder", buf.String())
+	}
+	buf.Reset()
+	NewArrayEncoder(&buf).Close()
+	if buf.String() != `[]` {
+		t.Fatal("ArrayEncoder empty", buf.String())
+	}
+}
@@ -0,0 +1,112 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// Go implements JSON serialization and JSON deserialization based on map and slice
+// key string, value any
+// JSONPath
+
+package json
+
+import (
+	"strconv"
+	"strings"
+)
+
+// pathSegment key or index of path
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// GetByPath
+//	return JSON data for path, nil if path does not exist
+//	path:
+//	 user.name
+//	 user.addresses[0].city
+//	 $.list[1][0]
+//	 user["first.name"]
+func (m *JsonData) GetByPath(path string) JSON {
+	segments, ok := parsePath(path)
+	if !ok {
+		return nil
+	}
+	var current JSON = m
+	for _, seg := range segments {
+		if current == nil {
+			return nil
+		}
+		if seg.isIndex {
+			if !current.IsArray() {
+				return nil
+			}
+			index := seg.index
+			if index < 0 {
+				// negative index from the end
+				index += current.Size()
+			}
+			if index < 0 || index >= current.Size() {
+				return nil
+			}
+			current = current.JSONArray().GetByIndex(index)
+		} else {
+			if !current.IsObject() {
+				return nil
+			}
+			current = current.JSONObject().GetByKey(seg.key)
+		}
+	}
+	return current
+}
+
+// parsePath
+//	a.b[0]["c.d"] => [a, b, 0, c.d]
+func parsePath(path string) (segments []pathSegment, ok bool) {
+	path = strings.TrimSpace(path)
+	if path == "$" || path == "" {
+		return nil, true
+	}
+	if strings.HasPrefix(path, "$.") || strings.HasPrefix(path, "$[") {
+		path = path[1:]
+	}
+	var i = 0
+	for i < len(path) {
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, false
+			}
+			end += i
+			content := strings.TrimSpace(path[i+1 : end])
+			if len(content) >= 2 && (content[0] == '"' || content[0] == '\'') && content[len(content)-1] == content[0] {
+				// ["key"] ['key']
+				segments = append(segments, pathSegment{key: content[1 : len(content)-1]})
+			} else if index, err := strconv.Atoi(content); err == nil {
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			} else {
+				return nil, false
+			}
+			i = end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end == -1 {
+				end = len(path)
+			} else {
+				end += i
+			}
+			segments = append(segments, pathSegment{key: path[i:end]})
+			i = end
+		}
+	}
+	return segments, true
+}
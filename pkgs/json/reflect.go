@@ -0,0 +1,148 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// Go implements JSON serialization and JSON deserialization based on map and slice
+// key string, value any
+// struct marshalling
+
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// NewJSONFromStruct
+//	struct, map, slice convert to JSON, respect `json` struct tags
+//	value:
+//	 struct | *struct => JSONObject
+//	 map[string][type] => JSONObject
+//	 slice | array => JSONArray
+func NewJSONFromStruct(value interface{}) JSON {
+	if value == nil {
+		return nil
+	}
+	if byt, err := json.Marshal(value); err == nil {
+		return NewJSON(byt)
+	}
+	return nil
+}
+
+// Unmarshal
+//	JSON data convert to Go value, respect `json` struct tags
+//	into: non-nil pointer
+func (m *JsonData) Unmarshal(into interface{}) error {
+	if into == nil {
+		return errors.New("json: Unmarshal(nil)")
+	}
+	rv := reflect.ValueOf(into)
+	if rv.Kind() != reflect.Ptr {
+		return errors.New("json: Unmarshal(non-pointer " + rv.Type().String() + ")")
+	} else if rv.IsNil() {
+		return errors.New("json: Unmarshal(nil " + rv.Type().String() + ")")
+	}
+	value := ReflectValue(m, rv.Elem().Type())
+	if !value.IsValid() {
+		return errors.New("json: cannot unmarshal " + m.t.String() + " into Go value of type " + rv.Elem().Type().String())
+	}
+	rv.Elem().Set(value)
+	return nil
+}
+
+// ReflectValue
+//	JSON data convert to a Go value of type t
+//	IPC argument callback and Unmarshal use the same conversion rules
+//	return invalid reflect.Value if conversion fails
+func ReflectValue(value JSON, t reflect.Type) (result reflect.Value) {
+	if value == nil {
+		return
+	}
+	switch t.Kind() {
+	case reflect.String:
+		result = reflect.ValueOf(value.String())
+	case reflect.Int:
+		result = reflect.ValueOf(value.Int())
+	case reflect.Int8:
+		result = reflect.ValueOf(int8(value.Int()))
+	case reflect.Int16:
+		result = reflect.ValueOf(int16(value.Int()))
+	case reflect.Int32:
+		result = reflect.ValueOf(int32(value.Int()))
+	case reflect.Int64:
+		result = reflect.ValueOf(int64(value.Int()))
+	case reflect.Uint:
+		result = reflect.ValueOf(uint(value.Int()))
+	case reflect.Uint8:
+		result = reflect.ValueOf(uint8(value.Int()))
+	case reflect.Uint16:
+		result = reflect.ValueOf(uint16(value.Int()))
+	case reflect.Uint32:
+		result = reflect.ValueOf(uint32(value.Int()))
+	case reflect.Uint64:
+		result = reflect.ValueOf(uint64(value.Int()))
+	case reflect.Uintptr:
+		result = reflect.ValueOf(uintptr(value.Int()))
+	case reflect.Float32:
+		result = reflect.ValueOf(float32(value.Float()))
+	case reflect.Float64:
+		result = reflect.ValueOf(value.Float())
+	case reflect.Bool:
+		result = reflect.ValueOf(value.Bool())
+	case reflect.Struct:
+		if value.IsObject() {
+			// struct
+			result = unmarshalValue(value, t)
+		}
+	case reflect.Map:
+		if value.IsObject() {
+			// map key=string : value != interface
+			if t.Elem().Kind() != reflect.Interface {
+				result = unmarshalValue(value, t)
+			} else {
+				result = reflect.ValueOf(value.Data())
+			}
+		}
+	case reflect.Slice:
+		if value.IsArray() {
+			// slice value != interface
+			if t.Elem().Kind() != reflect.Interface {
+				result = unmarshalValue(value, t)
+			} else {
+				result = reflect.ValueOf(value.Data())
+			}
+		}
+	case reflect.Ptr:
+		if elem := ReflectValue(value, t.Elem()); elem.IsValid() {
+			result = reflect.New(t.Elem())
+			result.Elem().Set(elem)
+		}
+	case reflect.Interface:
+		if data := value.JsonData().ConvertToData(); data != nil && reflect.TypeOf(data).Implements(t) {
+			result = reflect.ValueOf(data)
+		}
+	}
+	// named types: type Status string
+	if result.IsValid() && result.Type() != t && result.Type().ConvertibleTo(t) {
+		result = result.Convert(t)
+	}
+	return
+}
+
+// unmarshalValue object or array to struct, map, slice
+func unmarshalValue(value JSON, t reflect.Type) (result reflect.Value) {
+	if jsonBytes := value.Bytes(); jsonBytes != nil {
+		v := reflect.New(t)
+		if err := json.Unmarshal(jsonBytes, v.Interface()); err == nil {
+			result = v.Elem()
+		}
+	}
+	return
+}
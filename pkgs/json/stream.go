@@ -0,0 +1,101 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// Go implements JSON serialization and JSON deserialization based on map and slice
+// key string, value any
+// JSONArray stream encoder
+
+package json
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// ArrayEncoder
+//	Write a large JSON array element by element without building it in memory
+//	[elem1,elem2,...]
+type ArrayEncoder struct {
+	w      *bufio.Writer
+	count  int
+	closed bool
+}
+
+// NewArrayEncoder
+//	return JSON array stream encoder, write to w
+func NewArrayEncoder(w io.Writer) *ArrayEncoder {
+	return &ArrayEncoder{w: bufio.NewWriter(w)}
+}
+
+// Encode
+//	write one array element, respect `json` struct tags
+//	value: any type, JSON / JSONObject / JSONArray
+func (m *ArrayEncoder) Encode(value interface{}) error {
+	if m.closed {
+		return errors.New("json: ArrayEncoder closed")
+	}
+	if v, ok := value.(JSON); ok {
+		if v == nil || v.JsonData() == nil {
+			value = nil
+		} else {
+			value = v.JsonData().ConvertToData()
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if m.count == 0 {
+		err = m.w.WriteByte('[')
+	} else {
+		err = m.w.WriteByte(',')
+	}
+	if err != nil {
+		return err
+	}
+	if _, err = m.w.Write(data); err != nil {
+		return err
+	}
+	m.count++
+	return nil
+}
+
+// Count
+//	return the number of elements written
+func (m *ArrayEncoder) Count() int {
+	return m.count
+}
+
+// Flush
+//	write buffered data to the underlying writer
+func (m *ArrayEncoder) Flush() error {
+	return m.w.Flush()
+}
+
+// Close
+//	write the end of the array and flush, does not close the underlying writer
+func (m *ArrayEncoder) Close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	var err error
+	if m.count == 0 {
+		_, err = m.w.WriteString("[]")
+	} else {
+		err = m.w.WriteByte(']')
+	}
+	if err != nil {
+		return err
+	}
+	return m.w.Flush()
+}
@@ -0,0 +1,190 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// 二进制差异格式
+//	EDIF [新文件大小 uvarint] [新文件 sha256]
+//	C [旧文件偏移 uvarint] [长度 uvarint]  复制旧文件内容
+//	D [长度 uvarint] [数据]                 新数据
+//	E                                       结束
+const (
+	diffMagic      = "EDIF"
+	diffBlockSize  = 32 // 旧文件按块索引
+	diffMaxOffsets = 16 // 相同 hash 最多记录的块, 避免重复内容时过慢
+	diffPrime      = 16777619
+	diffOpCopy     = 'C'
+	diffOpData     = 'D'
+	diffOpEnd      = 'E'
+)
+
+// ErrDiffMismatch 差异应用到的旧文件不是生成差异时的文件
+var ErrDiffMismatch = errors.New("update: the diff does not match the file")
+
+// diffPow diffPrime^(diffBlockSize-1), 滚动 hash 移出第一个字节
+var diffPow = func() uint32 {
+	var pow uint32 = 1
+	for i := 1; i < diffBlockSize; i++ {
+		pow *= diffPrime
+	}
+	return pow
+}()
+
+func blockHash(block []byte) uint32 {
+	var h uint32
+	for _, b := range block {
+		h = h*diffPrime + uint32(b)
+	}
+	return h
+}
+
+// Diff
+//	生成 old 到 new 的二进制差异
+//	旧文件按块建立 hash 索引, 新文件滚动 hash 查找相同的块并向前后扩展, 其余为新数据
+func Diff(old, new []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(diffMagic)
+	writeUvarint(&buf, uint64(len(new)))
+	sum := sha256.Sum256(new)
+	buf.Write(sum[:])
+
+	index := make(map[uint32][]int)
+	for i := 0; i+diffBlockSize <= len(old); i += diffBlockSize {
+		h := blockHash(old[i : i+diffBlockSize])
+		if len(index[h]) < diffMaxOffsets {
+			index[h] = append(index[h], i)
+		}
+	}
+	var writeData = func(data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		buf.WriteByte(diffOpData)
+		writeUvarint(&buf, uint64(len(data)))
+		buf.Write(data)
+	}
+	var (
+		literal int // 未写入的新数据开始位置
+		i       int
+		h       uint32
+	)
+	if len(new) >= diffBlockSize {
+		h = blockHash(new[:diffBlockSize])
+	}
+	for i+diffBlockSize <= len(new) {
+		best, bestLen := -1, 0
+		for _, offset := range index[h] {
+			if !bytes.Equal(old[offset:offset+diffBlockSize], new[i:i+diffBlockSize]) {
+				continue
+			}
+			n := diffBlockSize
+			for offset+n < len(old) && i+n < len(new) && old[offset+n] == new[i+n] {
+				n++
+			}
+			if n > bestLen {
+				best, bestLen = offset, n
+			}
+		}
+		if best < 0 {
+			if i+diffBlockSize < len(new) {
+				h = (h-uint32(new[i])*diffPow)*diffPrime + uint32(new[i+diffBlockSize])
+			}
+			i++
+			continue
+		}
+		// 向前扩展到未写入的新数据
+		for best > 0 && i > literal && old[best-1] == new[i-1] {
+			best, i, bestLen = best-1, i-1, bestLen+1
+		}
+		writeData(new[literal:i])
+		buf.WriteByte(diffOpCopy)
+		writeUvarint(&buf, uint64(best))
+		writeUvarint(&buf, uint64(bestLen))
+		i += bestLen
+		literal = i
+		if i+diffBlockSize <= len(new) {
+			h = blockHash(new[i : i+diffBlockSize])
+		}
+	}
+	writeData(new[literal:])
+	buf.WriteByte(diffOpEnd)
+	return buf.Bytes()
+}
+
+// ApplyDiff
+//	差异应用到 old 返回新文件
+//	新文件大小或 sha256 不一致时返回 ErrDiffMismatch
+func ApplyDiff(old, diff []byte) ([]byte, error) {
+	r := bufio.NewReader(bytes.NewReader(diff))
+	magic := make([]byte, len(diffMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != diffMagic {
+		return nil, errors.New("update: invalid diff")
+	}
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, errors.New("update: invalid diff")
+	}
+	var sum [sha256.Size]byte
+	if _, err = io.ReadFull(r, sum[:]); err != nil {
+		return nil, errors.New("update: invalid diff")
+	}
+	capacity := uint64(len(old) + len(diff))
+	if size < capacity {
+		capacity = size
+	}
+	result := make([]byte, 0, capacity)
+	for {
+		op, err := r.ReadByte()
+		if err != nil {
+			return nil, errors.New("update: unexpected end of diff")
+		}
+		switch op {
+		case diffOpCopy:
+			offset, err1 := binary.ReadUvarint(r)
+			length, err2 := binary.ReadUvarint(r)
+			if err1 != nil || err2 != nil || offset > uint64(len(old)) || length > uint64(len(old))-offset ||
+				uint64(len(result))+length > size {
+				return nil, ErrDiffMismatch
+			}
+			result = append(result, old[offset:offset+length]...)
+		case diffOpData:
+			length, err := binary.ReadUvarint(r)
+			if err != nil || length > uint64(len(diff)) || uint64(len(result))+length > size {
+				return nil, errors.New("update: invalid diff data")
+			}
+			start := len(result)
+			result = append(result, make([]byte, length)...)
+			if _, err = io.ReadFull(r, result[start:]); err != nil {
+				return nil, errors.New("update: unexpected end of diff")
+			}
+		case diffOpEnd:
+			if uint64(len(result)) != size || sha256.Sum256(result) != sum {
+				return nil, ErrDiffMismatch
+			}
+			return result, nil
+		default:
+			return nil, errors.New("update: invalid diff op")
+		}
+	}
+}
+
+func writeUvarint(w io.Writer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	w.Write(b[:binary.PutUvarint(b[:], v)])
+}
@@ -0,0 +1,204 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+// Package update 应用自动更新
+//	检查更新源 feed.json, 校验 ed25519 签名后下载更新包或差异包, 校验大小和 sha256
+//	替换执行文件目录内的文件, 失败时回滚, 工作目录在用户缓存目录
+//	energy package 配置 energy.json update 后生成更新源和签名的更新包
+package update
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// FeedFileName 更新源文件名
+const FeedFileName = "feed.json"
+
+// Feed 更新源, 每个系统架构的最新版本
+type Feed struct {
+	Name      string              `json:"name"`
+	Platforms map[string]*Release `json:"platforms"` // 系统架构 windows/amd64 => 最新版本
+}
+
+// Artifact 更新包, url 相对更新源地址或完整地址
+type Artifact struct {
+	URL       string `json:"url"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`    // 文件 sha256 hex
+	Signature string `json:"signature"` // ed25519 签名 Statement, base64
+}
+
+// Patch 差异包, 只能从 From 版本更新
+type Patch struct {
+	From string `json:"from"`
+	Artifact
+}
+
+// Release 版本
+type Release struct {
+	Version string   `json:"version"`
+	Notes   string   `json:"notes"`
+	Date    string   `json:"date"` // RFC3339
+	Patches []*Patch `json:"patches"`
+	Artifact
+	name     string // 已校验签名的更新源名称和系统架构, Updater.Check 设置
+	platform string
+}
+
+// Statement
+//	更新包签名的内容, 绑定应用名称、版本、系统架构、差异包的起始版本和文件
+//	已签名的更新包不能被放到其它应用、系统架构或版本下使用
+type Statement struct {
+	Name     string // 更新源名称
+	Version  string
+	Platform string // 系统架构 windows/amd64
+	From     string // 差异包的起始版本, 完整更新包为空
+	Size     int64
+	SHA256   string // 文件 sha256 hex
+}
+
+// message 签名的规范文本, 每行一个字段
+func (m *Statement) message() ([]byte, error) {
+	if m.Name == "" || m.Version == "" || m.Platform == "" {
+		return nil, errors.New("update: name, version and platform are required")
+	}
+	for _, value := range []string{m.Name, m.Version, m.Platform, m.From} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("update: invalid value %q", value)
+		}
+	}
+	if _, err := hex.DecodeString(m.SHA256); err != nil || len(m.SHA256) != 64 {
+		return nil, errors.New("update: invalid sha256")
+	}
+	return []byte(fmt.Sprintf("energy-update\nname:%s\nversion:%s\nplatform:%s\nfrom:%s\nsize:%d\nsha256:%s\n",
+		m.Name, m.Version, m.Platform, m.From, m.Size, strings.ToLower(m.SHA256))), nil
+}
+
+// Patch 返回从 version 更新的差异包, 没有时返回 nil
+func (m *Release) Patch(version string) *Patch {
+	for _, patch := range m.Patches {
+		if CompareVersion(patch.From, version) == 0 {
+			return patch
+		}
+	}
+	return nil
+}
+
+// Platform 当前系统架构 windows/amd64
+func Platform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// GenerateKey 生成 ed25519 签名密钥, 返回 base64 公钥和私钥
+func GenerateKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv), nil
+}
+
+// Sign 使用 base64 私钥签名 statement
+func Sign(privateKey string, statement *Statement) (string, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	message, err := statement.message()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key, message)), nil
+}
+
+// PublicKey 返回 base64 私钥对应的公钥
+func PublicKey(privateKey string) (string, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)), nil
+}
+
+// parsePrivateKey 私钥可以是 64 字节私钥或 32 字节种子
+func parsePrivateKey(privateKey string) (ed25519.PrivateKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(privateKey))
+	if err != nil {
+		return nil, fmt.Errorf("update: invalid private key: %w", err)
+	}
+	switch len(key) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key), nil
+	}
+	return nil, fmt.Errorf("update: invalid private key size %d", len(key))
+}
+
+// Verify 使用 base64 公钥校验 statement 的签名
+func Verify(publicKey string, statement *Statement, signature string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("update: invalid public key")
+	}
+	message, err := statement.message()
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("update: invalid signature: %w", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), message, sig) {
+		return errors.New("update: signature verification failed")
+	}
+	return nil
+}
+
+// CompareVersion 按数字比较版本 1.10.0 > 1.9.2, 1.0 == 1.0.0, 返回 1, 0, -1
+func CompareVersion(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y = "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		if xerr == nil && yerr == nil {
+			if xn != yn {
+				if xn > yn {
+					return 1
+				}
+				return -1
+			}
+			continue
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
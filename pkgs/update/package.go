@@ -0,0 +1,223 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package update
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DiffSuffix 更新包内的二进制差异文件后缀, [file].edif 应用到执行文件目录内的 [file]
+const DiffSuffix = ".edif"
+
+// WritePackage
+//	写入 zip 更新包, 文件路径相对执行文件目录
+//	files 为 root 目录内的文件或目录, 目录包含所有文件
+func WritePackage(w io.Writer, root string, files []string) error {
+	names, err := packageFiles(root, files)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err = writeZipFile(zw, name, info.Mode(), data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// WritePatch
+//	写入从 oldPackage 到 newPackage 的差异包
+//	改变的文件写入二进制差异 [file].edif, 差异不小于文件时写入完整文件, 新增的文件写入完整文件
+//	返回写入的文件数
+func WritePatch(w io.Writer, oldPackage, newPackage string) (int, error) {
+	oldReader, err := zip.OpenReader(oldPackage)
+	if err != nil {
+		return 0, err
+	}
+	defer oldReader.Close()
+	newReader, err := zip.OpenReader(newPackage)
+	if err != nil {
+		return 0, err
+	}
+	defer newReader.Close()
+	var oldFiles = make(map[string]*zip.File)
+	for _, file := range oldReader.File {
+		oldFiles[file.Name] = file
+	}
+	zw := zip.NewWriter(w)
+	var count int
+	for _, file := range newReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		data, err := readZipFile(file)
+		if err != nil {
+			return 0, err
+		}
+		name := file.Name
+		if oldFile, ok := oldFiles[file.Name]; ok {
+			oldData, err := readZipFile(oldFile)
+			if err != nil {
+				return 0, err
+			}
+			if bytes.Equal(oldData, data) {
+				continue
+			}
+			if diff := Diff(oldData, data); len(diff) < len(data) {
+				name, data = name+DiffSuffix, diff
+			}
+		}
+		if err = writeZipFile(zw, name, file.Mode(), data); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, zw.Close()
+}
+
+// packageFiles 返回排序的文件相对路径, 使用 / 分隔
+func packageFiles(root string, files []string) ([]string, error) {
+	var names = make(map[string]bool)
+	for _, file := range files {
+		name, err := cleanName(filepath.ToSlash(file))
+		if err != nil {
+			return nil, err
+		}
+		err = filepath.WalkDir(filepath.Join(root, filepath.FromSlash(name)), func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				return err
+			}
+			names[filepath.ToSlash(rel)] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var result = make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// cleanName 检查更新包内的文件路径, 不能是绝对路径或在执行文件目录之外
+func cleanName(name string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(name, "./"))
+	if clean == "." || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") ||
+		strings.ContainsAny(clean, `:\`) {
+		return "", fmt.Errorf("update: invalid file name %q", name)
+	}
+	return clean, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func writeZipFile(zw *zip.Writer, name string, mode os.FileMode, data []byte) error {
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	header.SetMode(mode)
+	fw, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(data)
+	return err
+}
+
+// stage
+//	解压更新包到 staging 目录, 差异文件应用到 appDir 内的旧文件
+//	返回更新的文件, 使用 / 分隔
+func stage(file, appDir, staging string) ([]string, error) {
+	reader, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	var names []string
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		name, err := cleanName(file.Name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(name, DiffSuffix) {
+			name = strings.TrimSuffix(name, DiffSuffix)
+			old, err := ioutil.ReadFile(filepath.Join(appDir, filepath.FromSlash(name)))
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil, ErrDiffMismatch
+				}
+				return nil, err
+			}
+			if data, err = ApplyDiff(old, data); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		target := filepath.Join(staging, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		mode := file.Mode().Perm()
+		if mode == 0 {
+			mode = 0644
+		}
+		if err = ioutil.WriteFile(target, data, mode); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("update: empty package")
+	}
+	return names, nil
+}
@@ -0,0 +1,274 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package update
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	old := make([]byte, 64*1024)
+	rnd.Read(old)
+	var tests = [][]byte{
+		old,
+		nil,
+		[]byte("small"),
+		append(append(append([]byte{}, old[:1000]...), []byte("inserted data")...), old[1000:]...),
+		append(append([]byte{}, old[30000:]...), old[:20000]...),
+	}
+	changed := append([]byte{}, old...)
+	for i := 0; i < len(changed); i += 4096 {
+		changed[i] ^= 0xff
+	}
+	tests = append(tests, changed)
+	for i, data := range tests {
+		diff := Diff(old, data)
+		result, err := ApplyDiff(old, diff)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if !bytes.Equal(result, data) {
+			t.Fatalf("%d: result mismatch", i)
+		}
+		if len(data) > 1000 && len(diff) > len(data)/4 {
+			t.Errorf("%d: diff size %d, data size %d", i, len(diff), len(data))
+		}
+	}
+	if _, err := ApplyDiff(old[1:], Diff(old, changed)); !errors.Is(err, ErrDiffMismatch) {
+		t.Fatalf("apply to another file: %v", err)
+	}
+}
+
+// testApp 创建执行文件目录和新版本文件
+func testApp(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(file), 0755)
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// testCacheDir 工作目录使用临时的用户缓存目录
+func testCacheDir(t *testing.T) string {
+	dir := t.TempDir()
+	for _, env := range []string{"XDG_CACHE_HOME", "LocalAppData", "HOME"} {
+		old, ok := os.LookupEnv(env)
+		os.Setenv(env, dir)
+		t.Cleanup(func() {
+			if ok {
+				os.Setenv(env, old)
+			} else {
+				os.Unsetenv(env)
+			}
+		})
+	}
+	return dir
+}
+
+func testArtifact(t *testing.T, privateKey, file, version, from string) Artifact {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	artifact := Artifact{URL: filepath.Base(file), Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}
+	statement := &Statement{Name: "app", Version: version, Platform: Platform(), From: from, Size: artifact.Size, SHA256: artifact.SHA256}
+	if artifact.Signature, err = Sign(privateKey, statement); err != nil {
+		t.Fatal(err)
+	}
+	return artifact
+}
+
+func TestUpdate(t *testing.T) {
+	cacheDir := testCacheDir(t)
+	publicKey, privateKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		app  = bytes.Repeat([]byte("energy application binary "), 1000)
+		app2 = append(append([]byte{}, app...), "version 2"...)
+	)
+	appDir := testApp(t, map[string]string{"app": string(app), "resources/index.html": "v1", "data.db": "user data"})
+	oldDir := testApp(t, map[string]string{"app": string(app), "resources/index.html": "v1"})
+	newDir := testApp(t, map[string]string{"app": string(app2), "resources/index.html": "v2", "resources/app.js": "new"})
+
+	feedDir := t.TempDir()
+	var writePackage = func(name, root string) string {
+		file := filepath.Join(feedDir, name)
+		var buf bytes.Buffer
+		if err := WritePackage(&buf, root, []string{"app", "resources"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	oldPackage := writePackage("app-1.0.0.zip", oldDir)
+	newPackage := writePackage("app-1.1.0.zip", newDir)
+	var patch bytes.Buffer
+	count, err := WritePatch(&patch, oldPackage, newPackage)
+	if err != nil || count != 3 {
+		t.Fatalf("patch: %d %v", count, err)
+	}
+	patchFile := filepath.Join(feedDir, "app-1.0.0-1.1.0.patch.zip")
+	ioutil.WriteFile(patchFile, patch.Bytes(), 0644)
+	reader, _ := zip.NewReader(bytes.NewReader(patch.Bytes()), int64(patch.Len()))
+	if reader.File[0].Name != "app"+DiffSuffix {
+		t.Fatalf("patch entry: %s", reader.File[0].Name)
+	}
+
+	release := &Release{Version: "1.1.0", Artifact: testArtifact(t, privateKey, newPackage, "1.1.0", "")}
+	release.Patches = []*Patch{{From: "1.0.0", Artifact: testArtifact(t, privateKey, patchFile, "1.1.0", "1.0.0")}}
+	var downloads []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/update/"+FeedFileName, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&Feed{Name: "app", Platforms: map[string]*Release{Platform(): release}})
+	})
+	mux.HandleFunc("/update/", func(w http.ResponseWriter, r *http.Request) {
+		downloads = append(downloads, filepath.Base(r.URL.Path))
+		http.ServeFile(w, r, filepath.Join(feedDir, filepath.Base(r.URL.Path)))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var stages = make(map[string]bool)
+	updater := &Updater{
+		FeedURL:   server.URL + "/update/" + FeedFileName,
+		PublicKey: publicKey,
+		Version:   "1.0.0",
+		AppDir:    appDir,
+		OnProgress: func(progress Progress) {
+			stages[progress.Stage] = true
+		},
+	}
+	result, err := updater.Update()
+	if err != nil || result == nil || result.Version != "1.1.0" {
+		t.Fatalf("update: %v %v", result, err)
+	}
+	if len(downloads) != 1 || downloads[0] != filepath.Base(patchFile) {
+		t.Fatalf("downloads: %v", downloads)
+	}
+	for _, stage := range []string{StageCheck, StageDownload, StageApply, StageDone} {
+		if !stages[stage] {
+			t.Errorf("progress stage %s not reported", stage)
+		}
+	}
+	var check = func(want map[string]string) {
+		for name, content := range want {
+			data, err := ioutil.ReadFile(filepath.Join(appDir, filepath.FromSlash(name)))
+			if content == "" {
+				if !os.IsNotExist(err) {
+					t.Errorf("%s should not exist", name)
+				}
+			} else if string(data) != content {
+				t.Errorf("%s: %q", name, data)
+			}
+		}
+	}
+	check(map[string]string{"app": string(app2), "resources/index.html": "v2", "resources/app.js": "new", "data.db": "user data"})
+	if names, _ := filepath.Glob(filepath.Join(cacheDir, workDirName, "*", "backup")); len(names) != 1 {
+		t.Fatalf("backup not in the user cache dir: %v", names)
+	}
+	if _, err = os.Stat(filepath.Join(appDir, ".energy-update")); !os.IsNotExist(err) {
+		t.Fatal("work dir in the application directory")
+	}
+
+	// 已是最新版本, 不能重新安装或降级
+	checked, err := updater.Check()
+	updater.Version = "1.1.0"
+	if result, err = updater.Check(); err != nil || result != nil {
+		t.Fatalf("check latest: %v %v", result, err)
+	}
+	if err = updater.Install(checked); err == nil {
+		t.Fatal("install the current version must fail")
+	}
+	if err = updater.Install(&Release{Version: "1.2.0", Artifact: release.Artifact}); err == nil {
+		t.Fatal("install an unverified release must fail")
+	}
+
+	if err = updater.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	check(map[string]string{"app": string(app), "resources/index.html": "v1", "resources/app.js": "", "data.db": "user data"})
+
+	// 本地文件被修改, 差异包不匹配时使用完整更新包
+	ioutil.WriteFile(filepath.Join(appDir, "app"), []byte("modified"), 0644)
+	updater.Version = "1.0.0"
+	downloads = nil
+	if _, err = updater.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if len(downloads) != 2 || downloads[1] != filepath.Base(newPackage) {
+		t.Fatalf("downloads: %v", downloads)
+	}
+	check(map[string]string{"app": string(app2), "resources/index.html": "v2"})
+
+	// 签名绑定更新源名称、版本和系统架构, 修改任意一项都不能通过校验
+	updater.Version = "1.0.0"
+	updater.Name = "other"
+	if _, err = updater.Check(); err == nil {
+		t.Fatal("feed name mismatch must fail")
+	}
+	updater.Name = ""
+	var sign = func(name, version, platform, sha256Hex string) string {
+		signature, _ := Sign(privateKey, &Statement{Name: name, Version: version, Platform: platform, Size: release.Size, SHA256: sha256Hex})
+		return signature
+	}
+	for _, signature := range []string{
+		sign("other", "1.1.0", Platform(), release.SHA256),
+		sign("app", "1.1.0", "other/arch", release.SHA256),
+		sign("app", "1.0.5", Platform(), release.SHA256),
+		sign("app", "1.1.0", Platform(), hex.EncodeToString(make([]byte, 32))),
+	} {
+		release.Signature = signature
+		if _, err = updater.Update(); err == nil {
+			t.Fatal("invalid signature must fail")
+		}
+	}
+}
+
+func TestApplyInvalidName(t *testing.T) {
+	testCacheDir(t)
+	appDir := t.TempDir()
+	file := filepath.Join(t.TempDir(), "evil.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("../evil")
+	w.Write([]byte("evil"))
+	zw.Close()
+	ioutil.WriteFile(file, buf.Bytes(), 0644)
+	updater := &Updater{AppDir: appDir, Version: "1.0.0"}
+	if err := updater.Apply(file); err == nil {
+		t.Fatal("zip slip must fail")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(appDir), "evil")); !os.IsNotExist(err) {
+		t.Fatal("file written outside the application directory")
+	}
+}
@@ -0,0 +1,500 @@
+//----------------------------------------
+//
+// Copyright © yanghy. All Rights Reserved.
+//
+// Licensed under Apache License Version 2.0, January 2004
+//
+// https://www.apache.org/licenses/LICENSE-2.0
+//
+//----------------------------------------
+
+package update
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/energye/energy/v2/energy/version"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// workDirName 用户缓存目录内的更新工作目录, 下载、解压和备份
+const workDirName = "energy-update"
+
+const journalFileName = "update.json"
+
+// 更新进度阶段
+const (
+	StageCheck    = "check"
+	StageDownload = "download"
+	StageApply    = "apply"
+	StageDone     = "done"
+)
+
+// executable 启动时的执行文件, 更新后执行文件被替换, 重新启动使用该路径
+var executable, _ = os.Executable()
+
+// Progress 更新进度, Total 未知时为 0
+type Progress struct {
+	Stage   string `json:"stage"`
+	Current int64  `json:"current"`
+	Total   int64  `json:"total"`
+}
+
+// Updater 应用更新
+//	更新包内的文件替换执行文件目录内的文件, 不删除旧版本的文件
+//	替换前备份到工作目录 backup, 失败时回滚
+type Updater struct {
+	FeedURL    string                  // 更新源地址 https://example.com/update/feed.json
+	PublicKey  string                  // ed25519 公钥 base64, energy package 生成
+	Name       string                  // 应用名称, 设置时更新源名称必须相同
+	Version    string                  // 当前版本, 默认 energy build 写入的版本
+	AppDir     string                  // 执行文件目录, 默认当前执行文件所在目录
+	Client     *http.Client            // 默认 http.DefaultClient
+	OnProgress func(progress Progress) // 更新进度
+}
+
+// journal 备份记录, 用于回滚
+type journal struct {
+	Version string        `json:"version"` // 更新前的版本
+	Files   []journalFile `json:"files"`
+}
+
+type journalFile struct {
+	Name   string `json:"name"`
+	Backup bool   `json:"backup"` // 更新前文件存在, 已备份
+}
+
+func (m *Updater) version() string {
+	if m.Version != "" {
+		return m.Version
+	}
+	return version.Version()
+}
+
+func (m *Updater) appDir() (string, error) {
+	if m.AppDir != "" {
+		return m.AppDir, nil
+	}
+	if executable == "" {
+		return "", errors.New("update: executable path is unknown")
+	}
+	exe, err := filepath.EvalSymlinks(executable)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(exe), nil
+}
+
+// workDir
+//	返回更新工作目录 [用户缓存目录]/energy-update/[执行文件目录 sha256 前 16 位]
+//	执行文件目录可能只读或被签名, 不在其中写入工作文件
+func (m *Updater) workDir(appDir string) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("update: %w", err)
+	}
+	abs, err := filepath.Abs(appDir)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(cache, workDirName, hex.EncodeToString(sum[:8])), nil
+}
+
+// verify 校验更新包签名, from 为差异包的起始版本
+func (m *Updater) verify(release *Release, from string, artifact *Artifact) error {
+	if m.PublicKey == "" {
+		return errors.New("update: public key is empty")
+	}
+	statement := &Statement{Name: release.name, Version: release.Version, Platform: release.platform, From: from, Size: artifact.Size, SHA256: artifact.SHA256}
+	return Verify(m.PublicKey, statement, artifact.Signature)
+}
+
+func (m *Updater) client() *http.Client {
+	if m.Client != nil {
+		return m.Client
+	}
+	return http.DefaultClient
+}
+
+func (m *Updater) progress(stage string, current, total int64) {
+	if m.OnProgress != nil {
+		m.OnProgress(Progress{Stage: stage, Current: current, Total: total})
+	}
+}
+
+// Check
+//	检查更新, 没有新版本时返回 nil
+//	新版本的签名绑定更新源名称、版本和当前系统架构, 校验后才使用其中的内容
+func (m *Updater) Check() (*Release, error) {
+	if m.FeedURL == "" {
+		return nil, errors.New("update: feed url is empty")
+	}
+	current := m.version()
+	if current == "" {
+		return nil, errors.New("update: current version is unknown, build with energy build or set Updater.Version")
+	}
+	m.progress(StageCheck, 0, 0)
+	resp, err := m.client().Get(m.FeedURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("update: %s: %s", m.FeedURL, resp.Status)
+	}
+	var feed Feed
+	if err = json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("update: invalid feed: %w", err)
+	}
+	if m.Name != "" && feed.Name != m.Name {
+		return nil, fmt.Errorf("update: feed name %q, expected %q", feed.Name, m.Name)
+	}
+	platform := Platform()
+	release := feed.Platforms[platform]
+	if release == nil {
+		return nil, nil
+	}
+	release.name, release.platform = feed.Name, platform
+	if err = m.verify(release, "", &release.Artifact); err != nil {
+		return nil, err
+	}
+	if CompareVersion(release.Version, current) <= 0 {
+		return nil, nil
+	}
+	return release, nil
+}
+
+// download
+//	下载已校验签名的更新包到工作目录 download, 校验大小和 sha256
+//	返回下载的文件
+func (m *Updater) download(artifact *Artifact) (string, error) {
+	base, err := url.Parse(m.FeedURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(artifact.URL)
+	if err != nil {
+		return "", err
+	}
+	u := base.ResolveReference(ref)
+	appDir, err := m.appDir()
+	if err != nil {
+		return "", err
+	}
+	work, err := m.workDir(appDir)
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(work, "download")
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "update.zip"
+	}
+	file := filepath.Join(dir, name)
+	resp, err := m.client().Get(u.String())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("update: %s: %s", u, resp.Status)
+	}
+	total := artifact.Size
+	if total <= 0 {
+		total = resp.ContentLength
+	}
+	tmp, err := ioutil.TempFile(dir, name+".*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	hasher := sha256.New()
+	w := &progressWriter{hash: hasher, total: total, progress: m.progress}
+	var body io.Reader = resp.Body
+	if artifact.Size > 0 {
+		body = io.LimitReader(resp.Body, artifact.Size+1)
+	}
+	_, err = io.Copy(io.MultiWriter(tmp, w), body)
+	if e := tmp.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		return "", err
+	}
+	if artifact.Size > 0 && w.current != artifact.Size {
+		return "", fmt.Errorf("update: %s size %d, expected %d", name, w.current, artifact.Size)
+	}
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if !strings.EqualFold(sum, artifact.SHA256) {
+		return "", fmt.Errorf("update: %s sha256 mismatch", name)
+	}
+	os.Remove(file)
+	if err = os.Rename(tmp.Name(), file); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// Install
+//	下载并应用 Check 返回的新版本, 不安装当前或更旧的版本
+//	有当前版本签名正确的差异包时先使用差异包, 失败时使用完整更新包
+func (m *Updater) Install(release *Release) error {
+	if release.name == "" || release.platform == "" {
+		return errors.New("update: release is not verified, use Updater.Check")
+	}
+	if err := m.verify(release, "", &release.Artifact); err != nil {
+		return err
+	}
+	current := m.version()
+	if CompareVersion(release.Version, current) <= 0 {
+		return fmt.Errorf("update: version %s is not newer than %s", release.Version, current)
+	}
+	if patch := release.Patch(current); patch != nil && m.verify(release, patch.From, &patch.Artifact) == nil {
+		file, err := m.download(&patch.Artifact)
+		if err == nil {
+			err = m.Apply(file)
+			os.Remove(file)
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	file, err := m.download(&release.Artifact)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file)
+	return m.Apply(file)
+}
+
+// Update 检查并安装新版本, 没有新版本时返回 nil, 安装后需要重新启动应用
+func (m *Updater) Update() (*Release, error) {
+	release, err := m.Check()
+	if err != nil || release == nil {
+		return nil, err
+	}
+	if err = m.Install(release); err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// Apply
+//	应用已下载的更新包
+//	先解压到工作目录 staging, 再逐个替换文件, 被替换的文件移动到工作目录 backup
+//	替换失败时回滚已替换的文件
+func (m *Updater) Apply(file string) error {
+	appDir, err := m.appDir()
+	if err != nil {
+		return err
+	}
+	work, err := m.workDir(appDir)
+	if err != nil {
+		return err
+	}
+	var (
+		staging = filepath.Join(work, "staging")
+		backup  = filepath.Join(work, "backup")
+	)
+	os.RemoveAll(staging)
+	defer os.RemoveAll(staging)
+	names, err := stage(file, appDir, staging)
+	if err != nil {
+		return err
+	}
+	if err = os.RemoveAll(backup); err != nil {
+		return err
+	}
+	if err = os.MkdirAll(backup, 0755); err != nil {
+		return err
+	}
+	record := &journal{Version: m.version()}
+	for _, name := range names {
+		_, err := os.Lstat(filepath.Join(appDir, filepath.FromSlash(name)))
+		record.Files = append(record.Files, journalFile{Name: name, Backup: err == nil})
+	}
+	if err = writeJournal(backup, record); err != nil {
+		return err
+	}
+	total := int64(len(names))
+	m.progress(StageApply, 0, total)
+	for i, item := range record.Files {
+		if err = replace(appDir, staging, backup, item); err != nil {
+			if e := rollback(appDir, backup, record.Files[:i+1]); e != nil {
+				return fmt.Errorf("update: %v, rollback: %v", err, e)
+			}
+			os.RemoveAll(backup)
+			return fmt.Errorf("update: %w, rolled back", err)
+		}
+		m.progress(StageApply, int64(i+1), total)
+	}
+	m.progress(StageDone, total, total)
+	return nil
+}
+
+// Rollback 恢复最后一次更新前的文件, 新版本启动后发现问题时使用, 需要重新启动应用
+func (m *Updater) Rollback() error {
+	appDir, err := m.appDir()
+	if err != nil {
+		return err
+	}
+	work, err := m.workDir(appDir)
+	if err != nil {
+		return err
+	}
+	backup := filepath.Join(work, "backup")
+	data, err := ioutil.ReadFile(filepath.Join(backup, journalFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("update: no update to roll back")
+		}
+		return err
+	}
+	var record journal
+	if err = json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	if err = rollback(appDir, backup, record.Files); err != nil {
+		return err
+	}
+	os.RemoveAll(backup)
+	return nil
+}
+
+// Restart
+//	使用相同参数启动执行文件, 调用者随后退出应用
+//	执行文件为启动时的路径, 不受更新替换的影响
+func Restart() error {
+	if executable == "" {
+		return errors.New("update: executable path is unknown")
+	}
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Start()
+}
+
+// replace 备份旧文件, 移动新文件到执行文件目录
+func replace(appDir, staging, backup string, item journalFile) error {
+	var (
+		target = filepath.Join(appDir, filepath.FromSlash(item.Name))
+		source = filepath.Join(staging, filepath.FromSlash(item.Name))
+	)
+	if item.Backup {
+		dst := filepath.Join(backup, filepath.FromSlash(item.Name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := move(target, dst); err != nil {
+			return err
+		}
+	} else if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return move(source, target)
+}
+
+// move
+//	移动文件, 工作目录和执行文件目录不在同一个分区时复制后删除
+//	windows 运行中的执行文件不能删除, 但可以重命名, 复制后重命名为 [file].old
+func move(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+	if err := os.Remove(src); err != nil {
+		os.Remove(src + ".old")
+		return os.Rename(src, src+".old")
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	if e := w.Close(); err == nil {
+		err = e
+	}
+	if err != nil {
+		os.Remove(dst)
+	}
+	return err
+}
+
+// rollback 按相反顺序删除新文件, 恢复备份的文件
+func rollback(appDir, backup string, files []journalFile) error {
+	var result error
+	for i := len(files) - 1; i >= 0; i-- {
+		var (
+			item   = files[i]
+			target = filepath.Join(appDir, filepath.FromSlash(item.Name))
+			src    = filepath.Join(backup, filepath.FromSlash(item.Name))
+		)
+		if item.Backup {
+			if _, err := os.Lstat(src); err != nil {
+				// 未备份, 文件没有被替换
+				continue
+			}
+			// windows 运行中的执行文件不能删除, 先移动到备份目录
+			move(target, src+".rollback")
+			if err := move(src, target); err != nil && result == nil {
+				result = err
+			}
+		} else if err := os.Remove(target); err != nil && !os.IsNotExist(err) && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func writeJournal(backup string, record *journal) error {
+	data, err := json.MarshalIndent(record, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(backup, journalFileName), data, 0644)
+}
+
+// progressWriter 计算 sha256 和下载进度
+type progressWriter struct {
+	hash     hash.Hash
+	current  int64
+	total    int64
+	progress func(stage string, current, total int64)
+}
+
+func (m *progressWriter) Write(p []byte) (int, error) {
+	m.hash.Write(p)
+	m.current += int64(len(p))
+	m.progress(StageDownload, m.current, m.total)
+	return len(p), nil
+}
@@ -40,26 +40,14 @@ func GenerateIcon(r io.Reader, w io.Writer, sizes []int) error {
 			return fmt.Errorf("a size of 0 is not valid")
 		}
 
-		// Scale image
-		rect := image.Rect(0, 0, size, size)
-		rawdata := image.NewRGBA(rect)
-		scale := draw.CatmullRom
-		scale.Scale(rawdata, rect, imagedata, imagedata.Bounds(), draw.Over, nil)
-
-		// Convert back to PNG
-		icondata := new(bytes.Buffer)
-		writer := bufio.NewWriter(icondata)
-		err = png.Encode(writer, rawdata)
-		if err != nil {
-			return err
-		}
-		err = writer.Flush()
+		// Scale image and convert back to PNG
+		icondata, err := scalePNG(imagedata, size)
 		if err != nil {
 			return err
 		}
 
 		// Save image data
-		imageData.Write(icondata.Bytes())
+		imageData.Write(icondata)
 
 		// Save header information
 		if size >= 256 {
@@ -68,7 +56,7 @@ func GenerateIcon(r io.Reader, w io.Writer, sizes []int) error {
 		iconheaders[index].Width = (uint8)(size)
 		iconheaders[index].Height = (uint8)(size)
 		iconheaders[index].BitsPerPixel = 32
-		iconheaders[index].Size = uint32(len(icondata.Bytes()))
+		iconheaders[index].Size = uint32(len(icondata))
 	}
 
 	// Update the offsets. Start by skipping header+icon headers
@@ -100,3 +88,21 @@ func GenerateIcon(r io.Reader, w io.Writer, sizes []int) error {
 	}
 	return nil
 }
+
+// scalePNG scales the image to size x size and returns it encoded as PNG
+func scalePNG(img image.Image, size int) ([]byte, error) {
+	rect := image.Rect(0, 0, size, size)
+	rawdata := image.NewRGBA(rect)
+	scale := draw.CatmullRom
+	scale.Scale(rawdata, rect, img, img.Bounds(), draw.Over, nil)
+
+	icondata := new(bytes.Buffer)
+	writer := bufio.NewWriter(icondata)
+	if err := png.Encode(writer, rawdata); err != nil {
+		return nil, err
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	return icondata.Bytes(), nil
+}
@@ -0,0 +1,47 @@
+package winicon
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// HicolorSizes are the sizes of the freedesktop hicolor icon theme
+// generated by default
+var HicolorSizes = []int{16, 22, 24, 32, 48, 64, 128, 256, 512}
+
+// GenerateHicolor reads image data from the given reader and writes
+// the freedesktop hicolor icon theme tree for the given sizes:
+// dir/<size>x<size>/apps/<name>.png. It returns the files written.
+func GenerateHicolor(r io.Reader, dir, name string, sizes []int) ([]string, error) {
+	imagedata, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(sizes) == 0 {
+		sizes = HicolorSizes
+	}
+	var files []string
+	for _, size := range sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("a size of %d is not valid", size)
+		}
+		data, err := scalePNG(imagedata, size)
+		if err != nil {
+			return nil, err
+		}
+		appsDir := filepath.Join(dir, fmt.Sprintf("%dx%d", size, size), "apps")
+		if err = os.MkdirAll(appsDir, 0755); err != nil {
+			return nil, err
+		}
+		file := filepath.Join(appsDir, name+".png")
+		if err = ioutil.WriteFile(file, data, 0644); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
@@ -0,0 +1,69 @@
+package winicon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io"
+)
+
+// icnsTypes are the PNG based icon types written to an .icns file,
+// covering 16x16 to 512x512@2x
+var icnsTypes = []struct {
+	osType string
+	size   int
+}{
+	{"icp4", 16},   // 16x16
+	{"icp5", 32},   // 32x32
+	{"icp6", 64},   // 64x64
+	{"ic07", 128},  // 128x128
+	{"ic08", 256},  // 256x256
+	{"ic09", 512},  // 512x512
+	{"ic10", 1024}, // 512x512@2x
+	{"ic11", 32},   // 16x16@2x
+	{"ic12", 64},   // 32x32@2x
+	{"ic13", 256},  // 128x128@2x
+	{"ic14", 512},  // 256x256@2x
+}
+
+// GenerateICNS reads image data from the given reader and generates
+// a macOS .icns file that is written to the given writer. The .icns file
+// includes PNG images at all standard sizes, so no macOS tools are needed.
+func GenerateICNS(r io.Reader, w io.Writer) error {
+	imagedata, _, err := image.Decode(r)
+	if err != nil {
+		return err
+	}
+
+	// Each size is encoded once, retina types share the data
+	encoded := make(map[int][]byte)
+	var body bytes.Buffer
+	for _, icnsType := range icnsTypes {
+		data, ok := encoded[icnsType.size]
+		if !ok {
+			data, err = scalePNG(imagedata, icnsType.size)
+			if err != nil {
+				return err
+			}
+			encoded[icnsType.size] = data
+		}
+		// Icon element: type, length including the 8 byte element header, data
+		body.WriteString(icnsType.osType)
+		if err = binary.Write(&body, binary.BigEndian, uint32(8+len(data))); err != nil {
+			return err
+		}
+		body.Write(data)
+	}
+
+	// File header: magic, file length including the 8 byte header
+	var header bytes.Buffer
+	header.WriteString("icns")
+	if err = binary.Write(&header, binary.BigEndian, uint32(8+body.Len())); err != nil {
+		return err
+	}
+	if _, err = w.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err = w.Write(body.Bytes())
+	return err
+}
@@ -0,0 +1,73 @@
+package winicon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testPNG(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 4), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGenerateICNS(t *testing.T) {
+	var out bytes.Buffer
+	if err := GenerateICNS(bytes.NewReader(testPNG(t)), &out); err != nil {
+		t.Fatal(err)
+	}
+	data := out.Bytes()
+	if string(data[:4]) != "icns" || int(binary.BigEndian.Uint32(data[4:8])) != len(data) {
+		t.Fatalf("invalid icns header: %q %d", data[:4], binary.BigEndian.Uint32(data[4:8]))
+	}
+	var index int
+	for offset := 8; offset < len(data); index++ {
+		osType := string(data[offset : offset+4])
+		length := int(binary.BigEndian.Uint32(data[offset+4 : offset+8]))
+		if index >= len(icnsTypes) || osType != icnsTypes[index].osType {
+			t.Fatalf("element %d: type %s", index, osType)
+		}
+		img, err := png.Decode(bytes.NewReader(data[offset+8 : offset+length]))
+		if err != nil {
+			t.Fatalf("%s: %v", osType, err)
+		}
+		if size := icnsTypes[index].size; img.Bounds().Dx() != size || img.Bounds().Dy() != size {
+			t.Fatalf("%s: size %v, want %d", osType, img.Bounds(), size)
+		}
+		offset += length
+	}
+	if index != len(icnsTypes) {
+		t.Fatalf("elements: %d, want %d", index, len(icnsTypes))
+	}
+}
+
+func TestGenerateHicolor(t *testing.T) {
+	dir := t.TempDir()
+	files, err := GenerateHicolor(bytes.NewReader(testPNG(t)), dir, "demo", []int{16, 48})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, size := range []string{"16x16", "48x48"} {
+		want := filepath.Join(dir, size, "apps", "demo.png")
+		if files[i] != want {
+			t.Fatalf("file %s, want %s", files[i], want)
+		}
+		if _, err = os.Stat(want); err != nil {
+			t.Fatal(err)
+		}
+	}
+}